@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
@@ -53,6 +54,9 @@ func main() {
 		}
 		os.Exit(cli.RunDaemon(context.Background(), st, dir, rest, os.Stdout, os.Stderr))
 	}
+	if len(args) >= 1 && args[0] == "agent" {
+		os.Exit(cli.RunAgent(context.Background(), args[1:], os.Stdout, os.Stderr))
+	}
 	if len(args) >= 1 && args[0] == "session" {
 		dir, err := store.ResolveDataDir("")
 		if err != nil {
@@ -64,7 +68,7 @@ func main() {
 			fmt.Fprintf(os.Stderr, "store: %v\n", err)
 			os.Exit(cli.ExitRunnerError)
 		}
-		os.Exit(cli.RunSession(context.Background(), st, args[1:], os.Stdout, os.Stderr))
+		os.Exit(cli.RunSession(context.Background(), st, dir, args[1:], os.Stdout, os.Stderr))
 	}
 	if len(args) >= 1 && args[0] == "set" {
 		dir, err := store.ResolveDataDir("")
@@ -81,6 +85,9 @@ func main() {
 	}
 	if err := cli.Run(args); err != nil {
 		fmt.Fprintln(os.Stderr, "error:", err)
+		if errors.Is(err, cli.ErrRegression) {
+			os.Exit(cli.ExitRegression)
+		}
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,161 @@
+// Package baseline compares a run's latency and error rate against an earlier
+// run and decides whether it regressed.
+package baseline
+
+import (
+	"sort"
+
+	"github.com/torosent/crankfire/internal/metrics"
+)
+
+// Verdicts for a single delta and for a whole comparison.
+const (
+	VerdictImproved  = "improved"
+	VerdictUnchanged = "unchanged"
+	VerdictRegressed = "regressed"
+)
+
+// Default tolerances used when none are configured.
+const (
+	DefaultLatencyTolerance   = 0.10
+	DefaultErrorRateTolerance = 0.01
+)
+
+// Tolerance is how much worse a run may be than its baseline before it
+// counts as a regression.
+type Tolerance struct {
+	// Latency is the allowed relative increase of each percentile, e.g. 0.1
+	// for 10%.
+	Latency float64 `json:"latency"`
+	// ErrorRate is the allowed absolute increase of the error rate, e.g.
+	// 0.01 for one percentage point.
+	ErrorRate float64 `json:"error_rate"`
+}
+
+// withDefaults replaces unset tolerances with the defaults.
+func (t Tolerance) withDefaults() Tolerance {
+	if t.Latency <= 0 {
+		t.Latency = DefaultLatencyTolerance
+	}
+	if t.ErrorRate <= 0 {
+		t.ErrorRate = DefaultErrorRateTolerance
+	}
+	return t
+}
+
+// Delta is the change of one metric between the baseline and the current run.
+type Delta struct {
+	// Endpoint is empty for the overall stats.
+	Endpoint string  `json:"endpoint,omitempty"`
+	Metric   string  `json:"metric"`
+	Baseline float64 `json:"baseline"`
+	Current  float64 `json:"current"`
+	Change   float64 `json:"change"`
+	// Percent is the relative change; it is 0 when the baseline is 0.
+	Percent float64 `json:"percent"`
+	Verdict string  `json:"verdict"`
+}
+
+// Comparison is the result of comparing a run against its baseline.
+type Comparison struct {
+	Baseline  string    `json:"baseline"`
+	Tolerance Tolerance `json:"tolerance"`
+	Deltas    []Delta   `json:"deltas"`
+	// Verdict is regressed if any delta regressed, improved if any improved
+	// and none regressed, and unchanged otherwise.
+	Verdict string `json:"verdict"`
+}
+
+// Regressed reports whether the run exceeded a tolerance.
+func (c *Comparison) Regressed() bool {
+	return c != nil && c.Verdict == VerdictRegressed
+}
+
+// Compare computes per-percentile and error rate deltas for the overall stats
+// and every endpoint present in both runs.
+func Compare(base Baseline, current metrics.Stats, tol Tolerance) *Comparison {
+	tol = tol.withDefaults()
+	c := &Comparison{Baseline: base.Ref, Tolerance: tol}
+	c.compareScope("", base.Stats.EndpointStats, current.EndpointStats)
+
+	names := make([]string, 0, len(current.Endpoints))
+	for name := range current.Endpoints {
+		if _, ok := base.Stats.Endpoints[name]; ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		c.compareScope(name, base.Stats.Endpoints[name], current.Endpoints[name])
+	}
+
+	c.Verdict = VerdictUnchanged
+	for _, d := range c.Deltas {
+		switch d.Verdict {
+		case VerdictRegressed:
+			c.Verdict = VerdictRegressed
+		case VerdictImproved:
+			if c.Verdict == VerdictUnchanged {
+				c.Verdict = VerdictImproved
+			}
+		}
+	}
+	return c
+}
+
+func (c *Comparison) compareScope(endpoint string, base, current metrics.EndpointStats) {
+	percentiles := []struct {
+		metric        string
+		base, current float64
+	}{
+		{"p50_ms", base.P50LatencyMs, current.P50LatencyMs},
+		{"p90_ms", base.P90LatencyMs, current.P90LatencyMs},
+		{"p95_ms", base.P95LatencyMs, current.P95LatencyMs},
+		{"p99_ms", base.P99LatencyMs, current.P99LatencyMs},
+	}
+	for _, p := range percentiles {
+		d := newDelta(endpoint, p.metric, p.base, p.current)
+		if p.base > 0 {
+			d.Verdict = verdict(d.Change/p.base, c.Tolerance.Latency)
+		}
+		c.Deltas = append(c.Deltas, d)
+	}
+
+	d := newDelta(endpoint, "error_rate", errorRate(base), errorRate(current))
+	d.Verdict = verdict(d.Change, c.Tolerance.ErrorRate)
+	c.Deltas = append(c.Deltas, d)
+}
+
+func newDelta(endpoint, metric string, base, current float64) Delta {
+	d := Delta{
+		Endpoint: endpoint,
+		Metric:   metric,
+		Baseline: base,
+		Current:  current,
+		Change:   current - base,
+		Verdict:  VerdictUnchanged,
+	}
+	if base != 0 {
+		d.Percent = d.Change / base * 100
+	}
+	return d
+}
+
+// verdict classifies change, where larger is worse, against tolerance.
+func verdict(change, tolerance float64) string {
+	switch {
+	case change > tolerance:
+		return VerdictRegressed
+	case change < -tolerance:
+		return VerdictImproved
+	default:
+		return VerdictUnchanged
+	}
+}
+
+func errorRate(s metrics.EndpointStats) float64 {
+	if s.Total == 0 {
+		return 0
+	}
+	return float64(s.Failures) / float64(s.Total)
+}
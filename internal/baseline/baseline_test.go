@@ -0,0 +1,194 @@
+package baseline
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/torosent/crankfire/internal/metrics"
+	"github.com/torosent/crankfire/internal/store"
+)
+
+func stats(total, failures int64, p50, p95 float64) metrics.EndpointStats {
+	return metrics.EndpointStats{
+		Total:        total,
+		Failures:     failures,
+		P50LatencyMs: p50,
+		P90LatencyMs: p95,
+		P95LatencyMs: p95,
+		P99LatencyMs: p95,
+	}
+}
+
+func findDelta(t *testing.T, c *Comparison, endpoint, metric string) Delta {
+	t.Helper()
+	for _, d := range c.Deltas {
+		if d.Endpoint == endpoint && d.Metric == metric {
+			return d
+		}
+	}
+	t.Fatalf("no delta for %q %s", endpoint, metric)
+	return Delta{}
+}
+
+func TestCompareVerdicts(t *testing.T) {
+	base := Baseline{Ref: "base", Stats: metrics.Stats{EndpointStats: stats(1000, 10, 100, 200)}}
+	tests := []struct {
+		name    string
+		current metrics.EndpointStats
+		want    string
+	}{
+		{"within tolerance", stats(1000, 12, 105, 215), VerdictUnchanged},
+		{"latency regressed", stats(1000, 10, 100, 250), VerdictRegressed},
+		{"error rate regressed", stats(1000, 30, 100, 200), VerdictRegressed},
+		{"improved", stats(1000, 10, 80, 150), VerdictImproved},
+		{"improved and regressed", stats(1000, 30, 80, 150), VerdictRegressed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Compare(base, metrics.Stats{EndpointStats: tt.current}, Tolerance{})
+			if c.Verdict != tt.want {
+				t.Errorf("Verdict = %s, want %s (deltas %+v)", c.Verdict, tt.want, c.Deltas)
+			}
+			if c.Regressed() != (tt.want == VerdictRegressed) {
+				t.Errorf("Regressed() = %v", c.Regressed())
+			}
+		})
+	}
+}
+
+func TestCompareTolerance(t *testing.T) {
+	base := Baseline{Stats: metrics.Stats{EndpointStats: stats(100, 0, 100, 100)}}
+	current := metrics.Stats{EndpointStats: stats(100, 0, 100, 125)}
+	if c := Compare(base, current, Tolerance{}); !c.Regressed() {
+		t.Error("expected a 25% increase to regress with the default tolerance")
+	}
+	c := Compare(base, current, Tolerance{Latency: 0.3})
+	if c.Regressed() {
+		t.Error("expected a 25% increase to pass with a 30% tolerance")
+	}
+	if c.Tolerance.ErrorRate != DefaultErrorRateTolerance {
+		t.Errorf("ErrorRate tolerance = %v, want default", c.Tolerance.ErrorRate)
+	}
+	d := findDelta(t, c, "", "p95_ms")
+	if d.Change != 25 || d.Percent != 25 {
+		t.Errorf("p95 delta = %+v, want change 25 and percent 25", d)
+	}
+}
+
+func TestCompareEndpoints(t *testing.T) {
+	base := Baseline{Stats: metrics.Stats{
+		EndpointStats: stats(200, 0, 100, 100),
+		Endpoints: map[string]metrics.EndpointStats{
+			"list":   stats(100, 0, 100, 100),
+			"delete": stats(100, 0, 100, 100),
+		},
+	}}
+	current := metrics.Stats{
+		EndpointStats: stats(200, 0, 100, 100),
+		Endpoints: map[string]metrics.EndpointStats{
+			"list":   stats(100, 0, 100, 300),
+			"create": stats(100, 50, 900, 900),
+		},
+	}
+	c := Compare(base, current, Tolerance{})
+	if !c.Regressed() {
+		t.Fatal("expected a regression from the list endpoint")
+	}
+	if d := findDelta(t, c, "list", "p95_ms"); d.Verdict != VerdictRegressed {
+		t.Errorf("list p95 verdict = %s", d.Verdict)
+	}
+	for _, d := range c.Deltas {
+		if d.Endpoint == "create" || d.Endpoint == "delete" {
+			t.Errorf("unexpected delta for endpoint missing from one run: %+v", d)
+		}
+	}
+}
+
+func writeJSON(t *testing.T, path string, v any) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	full := metrics.Stats{EndpointStats: stats(100, 1, 10, 20)}
+	writeJSON(t, filepath.Join(dir, "report.json"), full)
+
+	runA := filepath.Join(dir, "runs", "sess1", "2026-01-01T00:00:00Z")
+	writeJSON(t, filepath.Join(runA, "run.json"), store.Run{SessionID: "sess1"})
+	writeJSON(t, filepath.Join(runA, "result.json"), full)
+
+	runB := filepath.Join(dir, "runs", "sess1", "2026-01-02T00:00:00Z")
+	writeJSON(t, filepath.Join(runB, "run.json"), store.Run{
+		SessionID: "sess1",
+		Summary:   store.RunSummary{TotalRequests: 50, Errors: 5, P95Ms: 42},
+	})
+
+	t.Run("file", func(t *testing.T) {
+		b, err := Load(dir, filepath.Join(dir, "report.json"))
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if b.Stats.P95LatencyMs != 20 {
+			t.Errorf("P95 = %v, want 20", b.Stats.P95LatencyMs)
+		}
+	})
+	t.Run("run result", func(t *testing.T) {
+		b, err := Load(dir, "2026-01-01T00:00:00Z")
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if b.Stats.Total != 100 || b.Ref != "2026-01-01T00:00:00Z" {
+			t.Errorf("Load() = %+v", b)
+		}
+	})
+	t.Run("run summary fallback", func(t *testing.T) {
+		b, err := Load(dir, "sess1/2026-01-02T00:00:00Z")
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if b.Stats.Total != 50 || b.Stats.Failures != 5 || b.Stats.P95LatencyMs != 42 {
+			t.Errorf("Load() = %+v", b.Stats)
+		}
+	})
+	t.Run("missing", func(t *testing.T) {
+		if _, err := Load(dir, "nope"); err == nil {
+			t.Error("expected an error for an unknown run")
+		}
+	})
+}
+
+func TestResolveRunDirRejectsAmbiguousAndInvalidRefs(t *testing.T) {
+	dir := t.TempDir()
+	for _, sess := range []string{"a", "b"} {
+		writeJSON(t, filepath.Join(dir, "runs", sess, "run1", "run.json"), store.Run{SessionID: sess})
+	}
+	_, err := ResolveRunDir(dir, "run1")
+	if err == nil || !strings.Contains(err.Error(), "matches 2 sessions") {
+		t.Errorf("ResolveRunDir(run1) error = %v, want ambiguity error", err)
+	}
+	got, err := ResolveRunDir(dir, "b/run1")
+	if err != nil {
+		t.Fatalf("ResolveRunDir(b/run1) error = %v", err)
+	}
+	if want := filepath.Join(dir, "runs", "b", "run1"); got != want {
+		t.Errorf("ResolveRunDir(b/run1) = %s, want %s", got, want)
+	}
+	for _, ref := range []string{"../run1", "run*", "a/../b/run1"} {
+		if _, err := ResolveRunDir(dir, ref); err == nil {
+			t.Errorf("ResolveRunDir(%q) expected an error", ref)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package baseline
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/torosent/crankfire/internal/metrics"
+	"github.com/torosent/crankfire/internal/store"
+)
+
+// Baseline is the reference run a comparison is made against.
+type Baseline struct {
+	// Ref is how the baseline was identified: a file path or a run ID.
+	Ref   string
+	Stats metrics.Stats
+}
+
+// Load reads a baseline from a JSON report file, as written by --json-output,
+// or from a stored session run identified as <run-id> or
+// <session-id>/<run-id>. Runs are read from their result.json when present,
+// then from their stored histograms, and from the run summary otherwise.
+func Load(dataDir, ref string) (Baseline, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return Baseline{}, errors.New("baseline: empty reference")
+	}
+	if info, err := os.Stat(ref); err == nil && !info.IsDir() {
+		stats, err := readStats(ref)
+		if err != nil {
+			return Baseline{}, fmt.Errorf("baseline %s: %w", ref, err)
+		}
+		return Baseline{Ref: ref, Stats: stats}, nil
+	}
+
+	dir, err := ResolveRunDir(dataDir, ref)
+	if err != nil {
+		return Baseline{}, fmt.Errorf("baseline %s: %w", ref, err)
+	}
+	stats, err := readStats(filepath.Join(dir, "result.json"))
+	if errors.Is(err, os.ErrNotExist) {
+		stats, err = readMeasurements(dir)
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		stats, err = readSummary(filepath.Join(dir, "run.json"))
+	}
+	if err != nil {
+		return Baseline{}, fmt.Errorf("baseline %s: %w", ref, err)
+	}
+	return Baseline{Ref: ref, Stats: stats}, nil
+}
+
+// ResolveRunDir returns the directory of the session run identified by ref,
+// either <run-id> or <session-id>/<run-id>.
+func ResolveRunDir(dataDir, ref string) (string, error) {
+	if strings.Contains(ref, "..") || strings.ContainsAny(ref, `*?[\`) {
+		return "", fmt.Errorf("invalid run id %q", ref)
+	}
+	pattern := filepath.Join(dataDir, "runs", "*", ref, "run.json")
+	if sessionID, runID, ok := strings.Cut(ref, "/"); ok {
+		pattern = filepath.Join(dataDir, "runs", sessionID, runID, "run.json")
+	}
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return "", err
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no file or session run named %q", ref)
+	case 1:
+		return filepath.Dir(matches[0]), nil
+	default:
+		return "", fmt.Errorf("run id %q matches %d sessions; use <session-id>/<run-id>", ref, len(matches))
+	}
+}
+
+func readStats(path string) (metrics.Stats, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return metrics.Stats{}, err
+	}
+	var stats metrics.Stats
+	if err := json.Unmarshal(data, &stats); err != nil {
+		return metrics.Stats{}, fmt.Errorf("decode %s: %w", filepath.Base(path), err)
+	}
+	return stats, nil
+}
+
+// readMeasurements rebuilds stats from the histograms stored with a run.
+func readMeasurements(dir string) (metrics.Stats, error) {
+	m, err := store.ReadMeasurements(dir)
+	if err != nil {
+		return metrics.Stats{}, err
+	}
+	return m.Stats(0)
+}
+
+// readSummary builds overall stats from a run.json summary, for runs that
+// did not write a full result.json.
+func readSummary(path string) (metrics.Stats, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return metrics.Stats{}, err
+	}
+	var run store.Run
+	if err := json.Unmarshal(data, &run); err != nil {
+		return metrics.Stats{}, fmt.Errorf("decode %s: %w", filepath.Base(path), err)
+	}
+	s := run.Summary
+	return metrics.Stats{
+		EndpointStats: metrics.EndpointStats{
+			Total:        s.TotalRequests,
+			Failures:     s.Errors,
+			Successes:    s.TotalRequests - s.Errors,
+			P50LatencyMs: s.P50Ms,
+			P90LatencyMs: s.P90Ms,
+			P95LatencyMs: s.P95Ms,
+			P99LatencyMs: s.P99Ms,
+		},
+	}, nil
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/torosent/crankfire/internal/httpclient"
 	"github.com/torosent/crankfire/internal/metrics"
 	"github.com/torosent/crankfire/internal/placeholders"
+	"github.com/torosent/crankfire/internal/runner"
 	"github.com/torosent/crankfire/internal/tracing"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -56,10 +57,15 @@ func (b *baseRequesterHelper) prepareContext(ctx context.Context) context.Contex
 }
 
 // initRequest initializes common request state including context, timing, and metadata.
+// When the runner attached an intended start time, the time spent queued behind
+// busy workers is recorded as the request's queue delay.
 func (b *baseRequesterHelper) initRequest(ctx context.Context, protocol string) (context.Context, time.Time, *metrics.RequestMetadata) {
 	ctx = b.prepareContext(ctx)
 	start := time.Now()
 	meta := &metrics.RequestMetadata{Protocol: protocol}
+	if intended, ok := runner.IntendedStartFromContext(ctx); ok && start.After(intended) {
+		meta.QueueDelay = start.Sub(intended)
+	}
 	return ctx, start, meta
 }
 
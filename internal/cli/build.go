@@ -9,14 +9,16 @@ import (
 	"github.com/torosent/crankfire/internal/config"
 	"github.com/torosent/crankfire/internal/httpclient"
 	"github.com/torosent/crankfire/internal/metrics"
+	"github.com/torosent/crankfire/internal/resolver"
 	"github.com/torosent/crankfire/internal/runner"
+	"github.com/torosent/crankfire/internal/tlsconfig"
 	"github.com/torosent/crankfire/internal/tracing"
 )
 
 // BuildRunner constructs the full runner dependency graph for an in-process
 // run, mirroring the wiring used by the CLI Run() entrypoint. The returned
-// cleanup func releases auth/data feeder/tracing resources and is safe to
-// call once.
+// cleanup func releases auth/data feeder/tracing resources, flushes OTLP
+// metrics, and is safe to call once.
 //
 // The provided ctx is used to initialize the tracing provider (matching the
 // CLI behavior).
@@ -43,6 +45,18 @@ func BuildRunner(ctx context.Context, cfg config.Config) (*runner.Runner, *metri
 
 	collector := metrics.NewCollector()
 
+	metricsProvider, err := tracing.InitMetrics(ctx, cfg.OTLPMetrics, cfg.Tracing.ServiceName, collector)
+	if err != nil {
+		if dataFeeder != nil {
+			dataFeeder.Close()
+		}
+		if authProvider != nil {
+			authProvider.Close()
+		}
+		shutdownTracing(tracingProvider)
+		return nil, nil, nil, fmt.Errorf("otlp metrics init: %w", err)
+	}
+
 	baseRequester, err := buildRequester(&cfg, collector, authProvider, dataFeeder, tracingProvider)
 	if err != nil {
 		if dataFeeder != nil {
@@ -52,6 +66,7 @@ func BuildRunner(ctx context.Context, cfg config.Config) (*runner.Runner, *metri
 			authProvider.Close()
 		}
 		shutdownTracing(tracingProvider)
+		shutdownMetrics(metricsProvider)
 		return nil, nil, nil, err
 	}
 
@@ -63,6 +78,8 @@ func BuildRunner(ctx context.Context, cfg config.Config) (*runner.Runner, *metri
 		GracefulShutdown: cfg.GracefulShutdown,
 		Requester:        baseRequester,
 		ArrivalModel:     toRunnerArrivalModel(cfg.Arrival.Model),
+		ThinkTime:        toRunnerThinkTime(cfg.Arrival.ThinkTime),
+		Pacing:           cfg.Arrival.Pacing,
 		LoadPatterns:     toRunnerLoadPatterns(cfg.LoadPatterns),
 	}
 
@@ -81,6 +98,7 @@ func BuildRunner(ctx context.Context, cfg config.Config) (*runner.Runner, *metri
 			authProvider.Close()
 		}
 		shutdownTracing(tracingProvider)
+		shutdownMetrics(metricsProvider)
 	}
 
 	return r, collector, cleanup, nil
@@ -95,6 +113,17 @@ func shutdownTracing(p *tracing.Provider) {
 	_ = p.Shutdown(ctx)
 }
 
+// shutdownMetrics exports the final measurements before the run's resources
+// are released.
+func shutdownMetrics(p *tracing.MetricsProvider) {
+	if p == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = p.Shutdown(ctx)
+}
+
 // buildRequester constructs the protocol-specific requester chain (auth,
 // retries, logging, endpoint selection) shared by the CLI Run() entrypoint
 // and BuildRunner.
@@ -105,13 +134,35 @@ func buildRequester(
 	dataFeeder httpclient.Feeder,
 	tracingProvider *tracing.Provider,
 ) (runner.Requester, error) {
+	tlsConfig, err := tlsconfig.New(cfg.TLS)
+	if err != nil {
+		return nil, err
+	}
+	res, err := resolver.New(cfg.DNS)
+	if err != nil {
+		return nil, err
+	}
+
 	switch cfg.Protocol {
 	case config.ProtocolWebSocket:
-		return newWebSocketRequester(cfg, collector, authProvider, dataFeeder, tracingProvider), nil
+		wsReq := newWebSocketRequester(cfg, collector, authProvider, dataFeeder, tracingProvider)
+		wsReq.tlsConfig = tlsConfig
+		wsReq.resolver = res
+		return wsReq, nil
 	case config.ProtocolSSE:
-		return newSSERequester(cfg, collector, authProvider, dataFeeder, tracingProvider), nil
+		sseReq := newSSERequester(cfg, collector, authProvider, dataFeeder, tracingProvider)
+		sseReq.tlsConfig = tlsConfig
+		sseReq.resolver = res
+		return sseReq, nil
 	case config.ProtocolGRPC:
-		return newGRPCRequester(cfg, collector, authProvider, dataFeeder, tracingProvider), nil
+		grpcReq := newGRPCRequester(cfg, collector, authProvider, dataFeeder, tracingProvider)
+		grpcReq.tlsConfig = tlsConfig
+		grpcReq.resolver = res
+		if err := grpcReq.validateMethod(context.Background()); err != nil {
+			grpcReq.Close()
+			return nil, fmt.Errorf("grpc: %w", err)
+		}
+		return grpcReq, nil
 	case config.ProtocolHTTP:
 		fallthrough
 	default:
@@ -128,12 +179,24 @@ func buildRequester(
 		if err != nil {
 			return nil, err
 		}
+		scenarios, err := newScenarioSelector(cfg)
+		if err != nil {
+			return nil, err
+		}
 
-		if builder == nil && selector == nil {
+		if builder == nil && selector == nil && scenarios == nil {
 			return nil, fmt.Errorf("target URL is required")
 		}
 
-		client := httpclient.NewClient(cfg.Timeout)
+		client, err := httpclient.NewClientWithOptions(httpclient.ClientOptions{
+			Timeout:     cfg.Timeout,
+			TLSConfig:   tlsConfig,
+			HTTPVersion: cfg.HTTPVersion,
+			Resolver:    res,
+		})
+		if err != nil {
+			return nil, err
+		}
 		httpReq := &httpRequester{
 			client:    client,
 			builder:   builder,
@@ -144,6 +207,7 @@ func buildRequester(
 				feeder:    dataFeeder,
 				tracing:   tracingProvider,
 			},
+			cookieExtract: cfg.Cookies.Extract,
 		}
 
 		var wrapped runner.Requester = httpReq
@@ -156,6 +220,16 @@ func buildRequester(
 		if selector != nil {
 			wrapped = selector.Wrap(wrapped)
 		}
+		if scenarios != nil {
+			wrapped = scenarios.Wrap(wrapped, collector)
+		}
+		if cfg.Cookies.Enabled() {
+			jars, err := newCookieJars(cfg)
+			if err != nil {
+				return nil, err
+			}
+			wrapped = &cookieJarRequester{next: wrapped, jars: jars}
+		}
 		return wrapped, nil
 	}
 }
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/torosent/crankfire/internal/config"
+	"github.com/torosent/crankfire/internal/httpclient"
+	"github.com/torosent/crankfire/internal/runner"
+)
+
+// newCookieJars prepares the per-iteration jars of cfg. Seeded cookies
+// without a domain belong to the host of the target URL.
+func newCookieJars(cfg *config.Config) (*httpclient.CookieJars, error) {
+	target := resolveDashboardTargetURL(*cfg)
+	if u, err := url.Parse(target); err != nil || u.Host == "" {
+		target = ""
+	}
+	return httpclient.NewCookieJars(cfg.Cookies.Seed, target)
+}
+
+// cookieJarRequester gives every iteration a fresh cookie jar. It wraps the
+// whole requester chain, so the steps of a scenario and the retries of a
+// request share the jar of their iteration.
+type cookieJarRequester struct {
+	next runner.Requester
+	jars *httpclient.CookieJars
+}
+
+func (r *cookieJarRequester) Do(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return r.next.Do(httpclient.WithCookieJar(ctx, r.jars.New()))
+}
+
+// extractCookies returns the configured cookie values visible after resp:
+// the cookies the jar would send to the final request URL, overridden by
+// those the response itself set, which may be scoped to another path.
+func extractCookies(jar http.CookieJar, resp *http.Response, extract []config.CookieExtract) map[string]string {
+	if len(extract) == 0 {
+		return nil
+	}
+	current := make(map[string]string)
+	if resp.Request != nil && resp.Request.URL != nil {
+		for _, c := range jar.Cookies(resp.Request.URL) {
+			current[c.Name] = c.Value
+		}
+	}
+	for _, c := range resp.Cookies() {
+		if c.MaxAge < 0 {
+			delete(current, c.Name)
+			continue
+		}
+		current[c.Name] = c.Value
+	}
+	values := make(map[string]string)
+	for _, ex := range extract {
+		if v, ok := current[ex.Cookie]; ok {
+			values[ex.Variable] = v
+		}
+	}
+	return values
+}
@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/torosent/crankfire/internal/config"
+	"github.com/torosent/crankfire/internal/metrics"
+)
+
+func TestCookieJarRequester_ResetsJarEveryIteration(t *testing.T) {
+	var mu sync.Mutex
+	logins := 0
+	var seen []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/login":
+			logins++
+			if logins == 1 {
+				http.SetCookie(w, &http.Cookie{Name: "session_id", Value: "s1", Path: "/"})
+			}
+		case "/profile":
+			var cookies []string
+			for _, c := range r.Cookies() {
+				cookies = append(cookies, c.Name+"="+c.Value)
+			}
+			seen = append(seen, strings.Join(cookies, ";")+" session:"+r.Header.Get("X-Session"))
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		TargetURL: server.URL,
+		Cookies: config.CookiesConfig{
+			Seed:    []config.Cookie{{Name: "consent", Value: "yes"}},
+			Extract: []config.CookieExtract{{Cookie: "session_id", Variable: "session"}},
+		},
+		Scenarios: []config.Scenario{{
+			Name:   "browse",
+			Weight: 1,
+			Steps: []config.ScenarioStep{
+				{Endpoint: config.Endpoint{Name: "login", Method: http.MethodPost, Path: "/login"}},
+				{Endpoint: config.Endpoint{
+					Path:    "/profile",
+					Headers: map[string]string{"X-Session": "{{session}}"},
+				}},
+			},
+		}},
+	}
+
+	requester, err := buildRequester(cfg, metrics.NewCollector(), nil, nil, nil)
+	if err != nil {
+		t.Fatalf("buildRequester() error = %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := requester.Do(context.Background()); err != nil {
+			t.Fatalf("iteration %d: Do() error = %v", i, err)
+		}
+	}
+
+	// Only the first login set a session, so the second iteration starts
+	// from the seeded jar again.
+	want := []string{"consent=yes;session_id=s1 session:s1", "consent=yes session:"}
+	if strings.Join(seen, "|") != strings.Join(want, "|") {
+		t.Fatalf("profile requests = %q, want %q", seen, want)
+	}
+}
+
+func TestHTTPRequester_NoCookiesWithoutJar(t *testing.T) {
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("Cookie"))
+		http.SetCookie(w, &http.Cookie{Name: "session_id", Value: "s1"})
+	}))
+	defer server.Close()
+
+	requester, err := buildRequester(&config.Config{TargetURL: server.URL}, metrics.NewCollector(), nil, nil, nil)
+	if err != nil {
+		t.Fatalf("buildRequester() error = %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := requester.Do(context.Background()); err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+	}
+	if got[1] != "" {
+		t.Errorf("second request sent Cookie %q without a jar", got[1])
+	}
+}
@@ -69,7 +69,7 @@ func BuildDashboardContext(targetURL string, cfg config.Config) runview.RequestC
 
 	if parsed, err := threshold.ParseMultiple(cfg.Thresholds); err == nil {
 		for _, candidate := range parsed {
-			if candidate.Metric == "http_req_duration" && candidate.Aggregate == "p95" && (candidate.Operator == "<" || candidate.Operator == "<=") {
+			if candidate.Metric == "http_req_duration" && len(candidate.Labels) == 0 && candidate.Aggregate == "p95" && (candidate.Operator == "<" || candidate.Operator == "<=") {
 				ctx.LatencySLOMs = candidate.Value
 				break
 			}
@@ -84,7 +84,13 @@ func resolveDashboardTargetURL(cfg config.Config) string {
 		return cfg.TargetURL
 	}
 	if len(cfg.Endpoints) == 0 {
-		return ""
+		if len(cfg.Scenarios) == 0 || len(cfg.Scenarios[0].Steps) == 0 {
+			return ""
+		}
+		if step := cfg.Scenarios[0].Steps[0]; step.URL != "" {
+			return step.URL
+		}
+		return cfg.Scenarios[0].Steps[0].Path
 	}
 	if cfg.Endpoints[0].URL != "" {
 		return cfg.Endpoints[0].URL
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/torosent/crankfire/internal/config"
+	"github.com/torosent/crankfire/internal/distributed"
+)
+
+// defaultAgentListen keeps agents off the network unless asked: the agent API
+// runs any test it is sent.
+const defaultAgentListen = "127.0.0.1:7070"
+
+// runDistributed splits the test across cfg.Agents and reports the merged
+// results like a local run.
+func runDistributed(cfg *config.Config, criteria runCriteria) error {
+	for _, t := range criteria.thresholds {
+		if t.AbortOnFail {
+			return fmt.Errorf("threshold %q: abort_on_fail is not supported with agents", t.Raw)
+		}
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	if !cfg.JSONOutput {
+		fmt.Fprintf(os.Stderr, "[crankfire] running across %d agents\n", len(cfg.Agents))
+	}
+	coordinator := &distributed.Coordinator{Agents: cfg.Agents}
+	result, collector, err := coordinator.Run(ctx, *cfg)
+	if err != nil {
+		return fmt.Errorf("distributed run: %w", err)
+	}
+	return reportResults(cfg, collector, result, criteria)
+}
+
+// RunAgent is the entry point for `crankfire agent`. It serves the agent API
+// until ctx is cancelled or the process receives SIGINT/SIGTERM.
+func RunAgent(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := pflag.NewFlagSet("agent", pflag.ContinueOnError)
+	fs.SetOutput(stderr)
+	listen := fs.String("listen", defaultAgentListen, "address to serve the agent API on")
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	ln, err := net.Listen("tcp", *listen)
+	if err != nil {
+		fmt.Fprintf(stderr, "listen %s: %v\n", *listen, err)
+		return ExitUsage
+	}
+
+	agent := distributed.NewAgent(BuildRunner)
+	defer agent.Close()
+	srv := &http.Server{Handler: agent, ReadHeaderTimeout: 10 * time.Second}
+
+	served := make(chan error, 1)
+	go func() { served <- srv.Serve(ln) }()
+	fmt.Fprintf(stdout, "crankfire agent listening on %s\n", ln.Addr())
+
+	select {
+	case err := <-served:
+		if !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintf(stderr, "serve: %v\n", err)
+			return ExitRunnerError
+		}
+	case <-ctx.Done():
+		// Stop the current run first so a coordinator waiting on results is
+		// answered before the server goes away.
+		agent.Close()
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), daemonDrainTimeout)
+		defer shutdownCancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}
+	return ExitOK
+}
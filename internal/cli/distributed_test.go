@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/torosent/crankfire/internal/distributed"
+)
+
+func TestRun_DistributedAcrossAgents(t *testing.T) {
+	var mu sync.Mutex
+	seen := map[string]int{}
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen[strings.TrimPrefix(r.URL.Path, "/users/")]++
+		mu.Unlock()
+	}))
+	defer target.Close()
+
+	var agents []string
+	for i := 0; i < 2; i++ {
+		agent := distributed.NewAgent(BuildRunner)
+		srv := httptest.NewServer(agent)
+		defer srv.Close()
+		defer agent.Close()
+		agents = append(agents, srv.URL)
+	}
+
+	feederPath := filepath.Join(t.TempDir(), "users.json")
+	if err := os.WriteFile(feederPath, []byte(`[{"id":"a"},{"id":"b"},{"id":"c"},{"id":"d"}]`), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	err := Run([]string{
+		"--target", target.URL + "/users/{{id}}",
+		"--total", "8",
+		"--concurrency", "2",
+		"--feeder-path", feederPath,
+		"--feeder-type", "json",
+		"--agents", strings.Join(agents, ","),
+		"--json-output",
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	var ids []string
+	total := 0
+	for id, n := range seen {
+		ids = append(ids, id)
+		total += n
+	}
+	sort.Strings(ids)
+	if total != 8 {
+		t.Fatalf("expected 8 requests across agents, got %d (%v)", total, seen)
+	}
+	// Each agent reads its own half of the records, so every record is used
+	// exactly twice.
+	if strings.Join(ids, ",") != "a,b,c,d" || seen["a"] != 2 || seen["d"] != 2 {
+		t.Fatalf("expected disjoint feeder partitions, got %v", seen)
+	}
+}
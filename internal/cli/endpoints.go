@@ -22,6 +22,7 @@ type endpointTemplate struct {
 	weight     int
 	builder    *httpclient.RequestBuilder
 	extractors []extractor.Extractor
+	checks     []httpCheck
 }
 
 type endpointSelector struct {
@@ -117,11 +118,17 @@ func buildEndpointTemplate(cfg *config.Config, ep config.Endpoint) (*endpointTem
 	// Convert config.Extractor to extractor.Extractor
 	extractors := convertExtractors(ep.Extractors)
 
+	checks, err := compileChecks(name, ep.Checks)
+	if err != nil {
+		return nil, err
+	}
+
 	return &endpointTemplate{
 		name:       name,
 		weight:     weight,
 		builder:    builder,
 		extractors: extractors,
+		checks:     checks,
 	}, nil
 }
 
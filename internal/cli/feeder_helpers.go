@@ -39,6 +39,14 @@ func buildDataFeeder(cfg *config.Config) (httpclient.Feeder, error) {
 	if err != nil {
 		return nil, err
 	}
+	if cfg.Feeder.Partitions > 1 {
+		partitioned, err := feederpkg.NewPartitionedFeeder(inner, cfg.Feeder.Partition, cfg.Feeder.Partitions)
+		if err != nil {
+			inner.Close()
+			return nil, fmt.Errorf("feeder: %w", err)
+		}
+		inner = partitioned
+	}
 
 	return &sharedFeeder{inner: inner}, nil
 }
@@ -0,0 +1,225 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/desc/protoparse"
+	"github.com/jhump/protoreflect/grpcreflect"
+	"github.com/torosent/crankfire/internal/config"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// reflectionServices are the reflection service's own names, which are never
+// load-test targets and are left out of service listings.
+var reflectionServices = map[string]bool{
+	"grpc.reflection.v1.ServerReflection":      true,
+	"grpc.reflection.v1alpha.ServerReflection": true,
+}
+
+// loadMethodDescriptor resolves the configured method from file-based
+// descriptor sources (proto_file, protosets or an existing reflection cache).
+func loadMethodDescriptor(cfg *config.GRPCConfig) (*desc.MethodDescriptor, error) {
+	return resolveMethodDescriptor(context.Background(), cfg, nil)
+}
+
+// resolveMethodDescriptor resolves the configured method from whichever
+// descriptor source cfg selects. conn is only used in reflection mode and may
+// be nil otherwise.
+func resolveMethodDescriptor(ctx context.Context, cfg *config.GRPCConfig, conn grpc.ClientConnInterface) (*desc.MethodDescriptor, error) {
+	files, err := loadDescriptorFiles(ctx, cfg, conn)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no service descriptors found")
+	}
+	return findMethod(files, strings.TrimSpace(cfg.Service), strings.TrimSpace(cfg.Method))
+}
+
+// needsReflectionConn reports whether resolving descriptors for cfg requires
+// a live connection, i.e. reflection is enabled and no cached set exists yet.
+func needsReflectionConn(cfg *config.GRPCConfig) bool {
+	if !cfg.Reflection {
+		return false
+	}
+	cache := strings.TrimSpace(cfg.ReflectionCache)
+	if cache == "" {
+		return true
+	}
+	_, err := os.Stat(cache)
+	return err != nil
+}
+
+func loadDescriptorFiles(ctx context.Context, cfg *config.GRPCConfig, conn grpc.ClientConnInterface) ([]*desc.FileDescriptor, error) {
+	switch {
+	case cfg.Reflection:
+		if !needsReflectionConn(cfg) {
+			return loadProtosets([]string{cfg.ReflectionCache})
+		}
+		if conn == nil {
+			return nil, fmt.Errorf("grpc reflection requires a connection to the target")
+		}
+		files, err := fetchReflectionDescriptors(ctx, conn)
+		if err != nil {
+			return nil, fmt.Errorf("grpc reflection: %w", err)
+		}
+		if cache := strings.TrimSpace(cfg.ReflectionCache); cache != "" {
+			if err := writeProtoset(cache, files); err != nil {
+				return nil, fmt.Errorf("write reflection cache: %w", err)
+			}
+		}
+		return files, nil
+	case len(cfg.Protosets) > 0:
+		return loadProtosets(cfg.Protosets)
+	default:
+		return parseProtoFile(cfg.ProtoFile)
+	}
+}
+
+func parseProtoFile(path string) ([]*desc.FileDescriptor, error) {
+	protoPath := strings.TrimSpace(path)
+	if protoPath == "" {
+		return nil, fmt.Errorf("grpc proto_file, protosets or reflection is required")
+	}
+	parser := protoparse.Parser{
+		ImportPaths: []string{filepath.Dir(protoPath)},
+	}
+	files, err := parser.ParseFiles(filepath.Base(protoPath))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no descriptors parsed from %s", protoPath)
+	}
+	return files, nil
+}
+
+// loadProtosets reads precompiled FileDescriptorSet files, as produced by
+// `protoc --include_imports --descriptor_set_out` or `buf build -o`.
+func loadProtosets(paths []string) ([]*desc.FileDescriptor, error) {
+	var files []*desc.FileDescriptor
+	for _, path := range paths {
+		data, err := os.ReadFile(strings.TrimSpace(path))
+		if err != nil {
+			return nil, fmt.Errorf("read protoset: %w", err)
+		}
+		var set descriptorpb.FileDescriptorSet
+		if err := proto.Unmarshal(data, &set); err != nil {
+			return nil, fmt.Errorf("parse protoset %s: %w", path, err)
+		}
+		byName, err := desc.CreateFileDescriptorsFromSet(&set)
+		if err != nil {
+			return nil, fmt.Errorf("build descriptors from %s: %w", path, err)
+		}
+		for _, fd := range byName {
+			files = append(files, fd)
+		}
+	}
+	return files, nil
+}
+
+// fetchReflectionDescriptors asks the server for every exposed service and
+// the files that define them.
+func fetchReflectionDescriptors(ctx context.Context, conn grpc.ClientConnInterface) ([]*desc.FileDescriptor, error) {
+	client := grpcreflect.NewClientAuto(ctx, conn)
+	defer client.Reset()
+
+	services, err := client.ListServices()
+	if err != nil {
+		return nil, fmt.Errorf("list services: %w", err)
+	}
+	var files []*desc.FileDescriptor
+	seen := map[string]bool{}
+	for _, svc := range services {
+		if reflectionServices[svc] {
+			continue
+		}
+		fd, err := client.FileContainingSymbol(svc)
+		if err != nil {
+			return nil, fmt.Errorf("resolve %s: %w", svc, err)
+		}
+		if !seen[fd.GetName()] {
+			seen[fd.GetName()] = true
+			files = append(files, fd)
+		}
+	}
+	return files, nil
+}
+
+// writeProtoset saves files, including their imports, as a FileDescriptorSet
+// so later runs can skip reflection.
+func writeProtoset(path string, files []*desc.FileDescriptor) error {
+	data, err := proto.Marshal(desc.ToFileDescriptorSet(files...))
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// findMethod looks up serviceName/methodName in files. When it is missing the
+// error lists what is available: the service's methods if only the method is
+// wrong, otherwise every service and method found.
+func findMethod(files []*desc.FileDescriptor, serviceName, methodName string) (*desc.MethodDescriptor, error) {
+	var matched []*desc.ServiceDescriptor
+	for _, file := range files {
+		for _, svc := range file.GetServices() {
+			if matchesServiceName(svc, serviceName) {
+				if method := svc.FindMethodByName(methodName); method != nil {
+					return method, nil
+				}
+				matched = append(matched, svc)
+			}
+		}
+	}
+	if len(matched) > 0 {
+		return nil, fmt.Errorf("method %s not found in service %s (available methods: %s)",
+			methodName, serviceName, strings.Join(methodNames(matched), ", "))
+	}
+	var all []*desc.ServiceDescriptor
+	for _, file := range files {
+		for _, svc := range file.GetServices() {
+			if !reflectionServices[svc.GetFullyQualifiedName()] {
+				all = append(all, svc)
+			}
+		}
+	}
+	available := "none"
+	if names := methodNames(all); len(names) > 0 {
+		available = strings.Join(names, ", ")
+	}
+	return nil, fmt.Errorf("service %s not found (available: %s)", serviceName, available)
+}
+
+func methodNames(services []*desc.ServiceDescriptor) []string {
+	var names []string
+	for _, svc := range services {
+		for _, method := range svc.GetMethods() {
+			names = append(names, svc.GetFullyQualifiedName()+"/"+method.GetName())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func matchesServiceName(svc *desc.ServiceDescriptor, target string) bool {
+	if target == "" {
+		return false
+	}
+	if svc.GetFullyQualifiedName() == target {
+		return true
+	}
+	return svc.GetName() == target || strings.HasSuffix(target, "."+svc.GetName())
+}
@@ -0,0 +1,211 @@
+package cli
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/torosent/crankfire/internal/config"
+	"github.com/torosent/crankfire/internal/grpcclient"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+const greeterProto = `
+syntax = "proto3";
+package test;
+service Greeter {
+  rpc SayHello (HelloRequest) returns (HelloReply) {}
+  rpc SayBye (HelloRequest) returns (HelloReply) {}
+}
+message HelloRequest {
+  string name = 1;
+}
+message HelloReply {
+  string message = 1;
+}
+`
+
+func writeGreeterProto(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "greeter.proto")
+	if err := os.WriteFile(path, []byte(greeterProto), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	return path
+}
+
+// startReflectionServer serves the standard health service with reflection
+// enabled and returns its address.
+func startReflectionServer(t *testing.T) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	srv := grpc.NewServer()
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+	reflection.Register(srv)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+	return lis.Addr().String()
+}
+
+func TestFindMethodListsAvailable(t *testing.T) {
+	protoPath := writeGreeterProto(t)
+
+	tests := []struct {
+		name    string
+		service string
+		method  string
+		want    string
+	}{
+		{
+			name:    "unknown method lists service methods",
+			service: "test.Greeter",
+			method:  "SayNothing",
+			want:    "available methods: test.Greeter/SayBye, test.Greeter/SayHello",
+		},
+		{
+			name:    "unknown service lists all methods",
+			service: "test.Missing",
+			method:  "SayHello",
+			want:    "service test.Missing not found (available: test.Greeter/SayBye, test.Greeter/SayHello)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadMethodDescriptor(&config.GRPCConfig{
+				ProtoFile: protoPath,
+				Service:   tt.service,
+				Method:    tt.method,
+			})
+			if err == nil {
+				t.Fatal("loadMethodDescriptor() error = nil, want not found error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %q, want substring %q", err.Error(), tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadMethodDescriptorFromProtoset(t *testing.T) {
+	files, err := parseProtoFile(writeGreeterProto(t))
+	if err != nil {
+		t.Fatalf("parseProtoFile() error = %v", err)
+	}
+	protoset := filepath.Join(t.TempDir(), "greeter.protoset")
+	if err := writeProtoset(protoset, files); err != nil {
+		t.Fatalf("writeProtoset() error = %v", err)
+	}
+
+	md, err := loadMethodDescriptor(&config.GRPCConfig{
+		Protosets: []string{protoset},
+		Service:   "test.Greeter",
+		Method:    "SayBye",
+	})
+	if err != nil {
+		t.Fatalf("loadMethodDescriptor() error = %v", err)
+	}
+	if md.GetFullyQualifiedName() != "test.Greeter.SayBye" {
+		t.Errorf("method = %q, want test.Greeter.SayBye", md.GetFullyQualifiedName())
+	}
+
+	if _, err := loadMethodDescriptor(&config.GRPCConfig{
+		Protosets: []string{filepath.Join(t.TempDir(), "missing.pb")},
+		Service:   "test.Greeter",
+		Method:    "SayBye",
+	}); err == nil {
+		t.Error("loadMethodDescriptor(missing protoset) error = nil, want error")
+	}
+}
+
+func TestResolveMethodDescriptorReflection(t *testing.T) {
+	addr := startReflectionServer(t)
+	conn, err := grpcclient.Dial(context.Background(), grpcclient.Config{Target: addr})
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer conn.Close()
+
+	cache := filepath.Join(t.TempDir(), "cache", "reflected.protoset")
+	cfg := &config.GRPCConfig{
+		Reflection:      true,
+		ReflectionCache: cache,
+		Service:         "grpc.health.v1.Health",
+		Method:          "Check",
+	}
+
+	md, err := resolveMethodDescriptor(context.Background(), cfg, conn)
+	if err != nil {
+		t.Fatalf("resolveMethodDescriptor() error = %v", err)
+	}
+	if md.GetInputType().GetFullyQualifiedName() != "grpc.health.v1.HealthCheckRequest" {
+		t.Errorf("input type = %q, want grpc.health.v1.HealthCheckRequest", md.GetInputType().GetFullyQualifiedName())
+	}
+	if needsReflectionConn(cfg) {
+		t.Fatal("needsReflectionConn() = true after cache was written, want false")
+	}
+
+	// The cached set answers without a connection.
+	if _, err := resolveMethodDescriptor(context.Background(), cfg, nil); err != nil {
+		t.Fatalf("resolveMethodDescriptor(cached) error = %v", err)
+	}
+
+	cfg = &config.GRPCConfig{Reflection: true, Service: "grpc.health.v1.Health", Method: "Ping"}
+	_, err = resolveMethodDescriptor(context.Background(), cfg, conn)
+	if err == nil || !strings.Contains(err.Error(), "grpc.health.v1.Health/Check") {
+		t.Errorf("resolveMethodDescriptor(unknown method) error = %v, want listing of available methods", err)
+	}
+}
+
+func TestGRPCRequesterValidateMethod(t *testing.T) {
+	addr := startReflectionServer(t)
+
+	tests := []struct {
+		name    string
+		grpc    config.GRPCConfig
+		wantErr string
+	}{
+		{
+			name: "reflection finds method",
+			grpc: config.GRPCConfig{Reflection: true, Service: "grpc.health.v1.Health", Method: "Check"},
+		},
+		{
+			name:    "reflection lists services",
+			grpc:    config.GRPCConfig{Reflection: true, Service: "acme.Orders", Method: "Create"},
+			wantErr: "available: grpc.health.v1.Health/Check",
+		},
+		{
+			name:    "proto file lists methods",
+			grpc:    config.GRPCConfig{ProtoFile: writeGreeterProto(t), Service: "test.Greeter", Method: "Wave"},
+			wantErr: "available methods: test.Greeter/SayBye",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{TargetURL: addr, Protocol: config.ProtocolGRPC, GRPC: tt.grpc}
+			req := newGRPCRequester(cfg, nil, nil, nil, nil)
+			defer req.Close()
+
+			err := req.validateMethod(context.Background())
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("validateMethod() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateMethod() error = %v, want substring %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,14 +2,16 @@ package cli
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
-	"path/filepath"
+	"net"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/jhump/protoreflect/desc"
-	"github.com/jhump/protoreflect/desc/protoparse"
 	"github.com/jhump/protoreflect/dynamic"
 	"github.com/torosent/crankfire/internal/auth"
 	"github.com/torosent/crankfire/internal/config"
@@ -17,10 +19,12 @@ import (
 	"github.com/torosent/crankfire/internal/httpclient"
 	"github.com/torosent/crankfire/internal/metrics"
 	"github.com/torosent/crankfire/internal/placeholders"
+	"github.com/torosent/crankfire/internal/resolver"
 	"github.com/torosent/crankfire/internal/tracing"
 	"google.golang.org/grpc"
 	grpcmd "google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/protoadapt"
 )
 
@@ -30,11 +34,12 @@ type grpcRequester struct {
 	collector  *metrics.Collector
 	auth       auth.Provider
 	feeder     httpclient.Feeder
-	methodOnce sync.Once
-	methodDesc *desc.MethodDescriptor
-	methodErr  error
+	methodMu   sync.Mutex
+	methodDesc atomic.Pointer[desc.MethodDescriptor]
 	conns      sync.Map // map[string]*grpc.ClientConn
 	helper     baseRequesterHelper
+	tlsConfig  *tls.Config
+	resolver   *resolver.Resolver
 }
 
 func newGRPCRequester(cfg *config.Config, collector *metrics.Collector, provider auth.Provider, feeder httpclient.Feeder, tp *tracing.Provider) *grpcRequester {
@@ -66,12 +71,6 @@ func (g *grpcRequester) Do(ctx context.Context) error {
 		return g.helper.recordError(start, meta, "grpc", "feeder", err)
 	}
 
-	methodDesc, err := g.methodDescriptor()
-	if err != nil {
-		spanErr = err
-		return g.helper.recordError(start, meta, "grpc", "load proto descriptor", err)
-	}
-
 	target := g.target
 	if len(record) > 0 {
 		target = placeholders.Apply(target, record)
@@ -93,18 +92,6 @@ func (g *grpcRequester) Do(ctx context.Context) error {
 		}
 	}
 
-	messagePayload := g.cfg.Message
-	if len(record) > 0 {
-		messagePayload = placeholders.Apply(messagePayload, record)
-	}
-
-	reqMsg, err := buildDynamicRequest(methodDesc, messagePayload)
-	if err != nil {
-		spanErr = err
-		return g.helper.recordError(start, meta, "grpc", "grpc request payload", err)
-	}
-	respMsg := dynamic.NewMessage(methodDesc.GetOutputType())
-
 	grpcCfg := grpcclient.Config{
 		Target:   target,
 		Service:  g.cfg.Service,
@@ -115,33 +102,44 @@ func (g *grpcRequester) Do(ctx context.Context) error {
 		Insecure: g.cfg.Insecure,
 	}
 
-	// Get or create connection
-	var conn *grpc.ClientConn
-	if v, ok := g.conns.Load(target); ok {
-		conn = v.(*grpc.ClientConn)
-	} else {
-		// Create new connection
-		// Note: In high concurrency with dynamic targets, this might create duplicates
-		// which are thrown away by LoadOrStore, but that's acceptable.
-		newConn, err := grpcclient.Dial(ctx, grpcCfg)
+	conn, err := g.connFor(ctx, grpcCfg)
+	if err != nil {
+		spanErr = err
+		meta = annotateStatus(meta, "grpc", grpcStatusCode(err))
+		g.collector.RecordRequest(time.Since(start), err, meta)
+		return fmt.Errorf("grpc connect: %w", err)
+	}
+
+	methodDesc, err := g.methodDescriptor(grpcmd.NewOutgoingContext(ctx, grpcmd.New(metadata)), conn)
+	if err != nil {
+		spanErr = err
+		return g.helper.recordError(start, meta, "grpc", "load proto descriptor", err)
+	}
+
+	streaming := methodDesc.IsClientStreaming() || methodDesc.IsServerStreaming()
+
+	var reqMsgs []*dynamic.Message
+	for _, payload := range g.requestPayloads(methodDesc.IsClientStreaming()) {
+		if len(record) > 0 {
+			payload = placeholders.Apply(payload, record)
+		}
+		reqMsg, err := buildDynamicRequest(methodDesc, payload)
 		if err != nil {
 			spanErr = err
-			meta = annotateStatus(meta, "grpc", grpcStatusCode(err))
-			g.collector.RecordRequest(time.Since(start), err, meta)
-			return fmt.Errorf("grpc connect: %w", err)
-		}
-
-		if actual, loaded := g.conns.LoadOrStore(target, newConn); loaded {
-			newConn.Close() // Close duplicate
-			conn = actual.(*grpc.ClientConn)
-		} else {
-			conn = newConn
+			return g.helper.recordError(start, meta, "grpc", "grpc request payload", err)
 		}
+		reqMsgs = append(reqMsgs, reqMsg)
 	}
 
 	client := grpcclient.NewClientWithConn(conn, grpcCfg)
 	// Do NOT close client, as it would close the shared connection
 
+	if streaming {
+		err := g.doStream(ctx, client, methodDesc, reqMsgs, start, meta)
+		spanErr = err
+		return err
+	}
+
 	callCtx := ctx
 	if g.cfg.Timeout > 0 {
 		var cancel context.CancelFunc
@@ -149,12 +147,14 @@ func (g *grpcRequester) Do(ctx context.Context) error {
 		defer cancel()
 	}
 
-	reqProto := protoadapt.MessageV2Of(reqMsg)
+	respMsg := dynamic.NewMessage(methodDesc.GetOutputType())
+	reqProto := protoadapt.MessageV2Of(reqMsgs[0])
 	respProto := protoadapt.MessageV2Of(respMsg)
 	if err := client.Invoke(callCtx, reqProto, respProto); err != nil {
 		latency := time.Since(start)
 		spanErr = err
 		meta = annotateStatus(meta, "grpc", grpcStatusCode(err))
+		meta.CustomMetrics = map[string]interface{}{"calls": int64(1)}
 		g.collector.RecordRequest(latency, err, meta)
 		return fmt.Errorf("grpc invoke: %w", err)
 	}
@@ -168,56 +168,162 @@ func (g *grpcRequester) Do(ctx context.Context) error {
 		"bytes_sent":        grpcMetrics.BytesSent,
 		"bytes_received":    grpcMetrics.BytesRecv,
 		"status_code":       grpcMetrics.StatusCode,
+		"calls":             int64(1),
 	}
 
 	g.collector.RecordRequest(latency, nil, meta)
 	return nil
 }
 
-func (g *grpcRequester) methodDescriptor() (*desc.MethodDescriptor, error) {
-	g.methodOnce.Do(func() {
-		g.methodDesc, g.methodErr = loadMethodDescriptor(g.cfg)
-	})
-	return g.methodDesc, g.methodErr
+// connFor returns the shared connection for cfg.Target, dialing it on first use.
+func (g *grpcRequester) connFor(ctx context.Context, cfg grpcclient.Config) (*grpc.ClientConn, error) {
+	if v, ok := g.conns.Load(cfg.Target); ok {
+		return v.(*grpc.ClientConn), nil
+	}
+	// Note: In high concurrency with dynamic targets, this might create duplicates
+	// which are thrown away by LoadOrStore, but that's acceptable.
+	cfg.TLSConfig = g.tlsConfig
+	cfg.OnHandshake = func(d time.Duration, state tls.ConnectionState) {
+		g.collector.RecordTLSHandshake("grpc", d, state.Version)
+	}
+	if g.resolver != nil {
+		cfg.DialContext = func(ctx context.Context, addr string) (net.Conn, error) {
+			return g.resolver.DialContext(ctx, "tcp", addr)
+		}
+	}
+	newConn, err := grpcclient.Dial(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if actual, loaded := g.conns.LoadOrStore(cfg.Target, newConn); loaded {
+		newConn.Close() // Close duplicate
+		return actual.(*grpc.ClientConn), nil
+	}
+	return newConn, nil
 }
 
-func loadMethodDescriptor(cfg *config.GRPCConfig) (*desc.MethodDescriptor, error) {
-	protoPath := strings.TrimSpace(cfg.ProtoFile)
-	if protoPath == "" {
-		return nil, fmt.Errorf("grpc proto_file is required")
+// validateMethod resolves the method descriptor before the run starts so a
+// misconfigured service or method fails fast with the list of what the
+// descriptors actually contain. Reflection against a templated target is left
+// to the first request, which knows the concrete address.
+func (g *grpcRequester) validateMethod(ctx context.Context) error {
+	if !needsReflectionConn(g.cfg) {
+		_, err := g.methodDescriptor(ctx, nil)
+		return err
 	}
-	parser := protoparse.Parser{
-		ImportPaths: []string{filepath.Dir(protoPath)},
+	if strings.Contains(g.target, "{{") {
+		return nil
 	}
-	files, err := parser.ParseFiles(filepath.Base(protoPath))
+	metadata, err := injectGRPCAuth(ctx, g.auth, buildGRPCMetadata(g.cfg.Metadata, nil))
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("grpc auth metadata: %w", err)
 	}
-	if len(files) == 0 {
-		return nil, fmt.Errorf("no descriptors parsed from %s", protoPath)
-	}
-	serviceName := strings.TrimSpace(cfg.Service)
-	methodName := strings.TrimSpace(cfg.Method)
-	for _, file := range files {
-		for _, svc := range file.GetServices() {
-			if matchesServiceName(svc, serviceName) {
-				if method := svc.FindMethodByName(methodName); method != nil {
-					return method, nil
-				}
-			}
-		}
+	conn, err := g.connFor(ctx, grpcclient.Config{
+		Target:   g.target,
+		UseTLS:   g.cfg.TLS,
+		Insecure: g.cfg.Insecure,
+	})
+	if err != nil {
+		return fmt.Errorf("grpc connect: %w", err)
 	}
-	return nil, fmt.Errorf("method %s not found in service %s", methodName, serviceName)
+	if g.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, g.cfg.Timeout)
+		defer cancel()
+	}
+	_, err = g.methodDescriptor(grpcmd.NewOutgoingContext(ctx, grpcmd.New(metadata)), conn)
+	return err
 }
 
-func matchesServiceName(svc *desc.ServiceDescriptor, target string) bool {
-	if target == "" {
-		return false
+// requestPayloads returns the JSON payloads to encode for one call. Client and
+// bidirectional streams send every configured message. Unary and server
+// streaming methods take exactly one request, so they send the message
+// payload, or the first of messages when no message is set.
+func (g *grpcRequester) requestPayloads(clientStreaming bool) []string {
+	if clientStreaming && len(g.cfg.Messages) > 0 {
+		return g.cfg.Messages
 	}
-	if svc.GetFullyQualifiedName() == target {
-		return true
+	if g.cfg.Message == "" && len(g.cfg.Messages) > 0 {
+		return g.cfg.Messages[:1]
 	}
-	return svc.GetName() == target || strings.HasSuffix(target, "."+svc.GetName())
+	return []string{g.cfg.Message}
+}
+
+// doStream drives a client, server or bidirectional streaming call and records
+// it as a single request. Stream metrics are recorded on failure too so
+// incomplete streams still report how far they got.
+func (g *grpcRequester) doStream(ctx context.Context, client *grpcclient.Client, methodDesc *desc.MethodDescriptor, reqMsgs []*dynamic.Message, start time.Time, meta *metrics.RequestMetadata) error {
+	streamTimeout := g.cfg.StreamTimeout
+	if streamTimeout <= 0 {
+		streamTimeout = g.cfg.Timeout
+	}
+	streamCtx := ctx
+	if streamTimeout > 0 {
+		var cancel context.CancelFunc
+		streamCtx, cancel = context.WithTimeout(ctx, streamTimeout)
+		defer cancel()
+	}
+
+	reqs := make([]proto.Message, len(reqMsgs))
+	for i, msg := range reqMsgs {
+		reqs[i] = protoadapt.MessageV2Of(msg)
+	}
+	outputType := methodDesc.GetOutputType()
+	newResp := func() proto.Message {
+		return protoadapt.MessageV2Of(dynamic.NewMessage(outputType))
+	}
+
+	result, err := client.Stream(streamCtx, reqs, newResp, grpcclient.StreamOptions{
+		ClientStreams:   methodDesc.IsClientStreaming(),
+		ServerStreams:   methodDesc.IsServerStreaming(),
+		Interval:        g.cfg.MessageInterval,
+		ExpectResponses: g.cfg.ExpectResponses,
+	})
+	latency := time.Since(start)
+
+	var completed int64
+	if err == nil {
+		completed = 1
+	}
+	meta = ensureProtocolMeta(meta, "grpc")
+	meta.CustomMetrics = map[string]interface{}{
+		"messages_sent":            result.MessagesSent,
+		"messages_received":        result.MessagesRecv,
+		"bytes_sent":               result.BytesSent,
+		"bytes_received":           result.BytesRecv,
+		"status_code":              result.StatusCode,
+		"streams":                  int64(1),
+		"streams_completed":        completed,
+		"time_to_first_message_ms": result.TimeToFirstMessage.Milliseconds(),
+	}
+
+	if err != nil {
+		meta = annotateStatus(meta, "grpc", grpcStreamStatusCode(err))
+		g.collector.RecordRequest(latency, err, meta)
+		return fmt.Errorf("grpc stream: %w", err)
+	}
+	g.collector.RecordRequest(latency, nil, meta)
+	return nil
+}
+
+// methodDescriptor returns the cached method descriptor, resolving it on
+// first use. Failures are not cached so a transient reflection error does not
+// poison the rest of the run.
+func (g *grpcRequester) methodDescriptor(ctx context.Context, conn grpc.ClientConnInterface) (*desc.MethodDescriptor, error) {
+	if md := g.methodDesc.Load(); md != nil {
+		return md, nil
+	}
+	g.methodMu.Lock()
+	defer g.methodMu.Unlock()
+	if md := g.methodDesc.Load(); md != nil {
+		return md, nil
+	}
+	md, err := resolveMethodDescriptor(ctx, g.cfg, conn)
+	if err != nil {
+		return nil, err
+	}
+	g.methodDesc.Store(md)
+	return md, nil
 }
 
 func buildDynamicRequest(method *desc.MethodDescriptor, payload string) (*dynamic.Message, error) {
@@ -286,3 +392,12 @@ func grpcStatusCode(err error) string {
 	}
 	return fallbackStatusCode(err)
 }
+
+// grpcStreamStatusCode maps a stream error to a status bucket, giving streams
+// that ended short of expect_responses their own INCOMPLETE bucket.
+func grpcStreamStatusCode(err error) string {
+	if errors.Is(err, grpcclient.ErrStreamIncomplete) {
+		return "INCOMPLETE"
+	}
+	return grpcStatusCode(err)
+}
@@ -1,14 +1,21 @@
 package cli
 
 import (
+	"context"
 	"errors"
+	"io"
+	"net"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/torosent/crankfire/internal/config"
+	"github.com/torosent/crankfire/internal/metrics"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 func TestMatchesServiceName(t *testing.T) {
@@ -228,3 +235,155 @@ message HelloReply {
 		}
 	})
 }
+
+func TestGRPCRequesterStreaming(t *testing.T) {
+	// Chat's messages are wire-compatible with wrapperspb.StringValue, so the
+	// test server can decode them without generated code.
+	protoContent := `
+syntax = "proto3";
+package test;
+service Chat {
+  rpc Talk (stream Line) returns (stream Line) {}
+  rpc Watch (Line) returns (stream Line) {}
+}
+message Line {
+  string value = 1;
+}
+`
+	dir := t.TempDir()
+	protoPath := filepath.Join(dir, "chat.proto")
+	if err := os.WriteFile(protoPath, []byte(protoContent), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	srv := grpc.NewServer(grpc.UnknownServiceHandler(func(_ interface{}, stream grpc.ServerStream) error {
+		method, _ := grpc.MethodFromServerStream(stream)
+		for {
+			line := &wrapperspb.StringValue{}
+			if err := stream.RecvMsg(line); err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				return err
+			}
+			replies := 1
+			if method == "/test.Chat/Watch" {
+				replies = 3
+			}
+			for i := 0; i < replies; i++ {
+				if err := stream.SendMsg(line); err != nil {
+					return err
+				}
+			}
+		}
+	}))
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	tests := []struct {
+		name       string
+		grpc       config.GRPCConfig
+		wantErr    bool
+		wantSent   int64
+		wantRecv   int64
+		wantStatus string
+	}{
+		{
+			name: "bidi",
+			grpc: config.GRPCConfig{
+				Method:   "Talk",
+				Messages: []string{`{"value":"hi"}`, `{"value":"{{user}}"}`},
+			},
+			wantSent: 2,
+			wantRecv: 2,
+		},
+		{
+			name: "server stream with expected responses",
+			grpc: config.GRPCConfig{
+				Method:          "Watch",
+				Message:         `{"value":"tick"}`,
+				ExpectResponses: 2,
+			},
+			wantSent: 1,
+			wantRecv: 2,
+		},
+		{
+			name: "server stream sends only the first of messages",
+			grpc: config.GRPCConfig{
+				Method:          "Watch",
+				Messages:        []string{`{"value":"a"}`, `{"value":"b"}`},
+				ExpectResponses: 3,
+			},
+			wantSent: 1,
+			wantRecv: 3,
+		},
+		{
+			name: "server stream ends early",
+			grpc: config.GRPCConfig{
+				Method:          "Watch",
+				Message:         `{"value":"tick"}`,
+				ExpectResponses: 5,
+			},
+			wantErr:    true,
+			wantSent:   1,
+			wantRecv:   3,
+			wantStatus: "INCOMPLETE",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				TargetURL:   lis.Addr().String(),
+				Protocol:    config.ProtocolGRPC,
+				Concurrency: 1,
+				GRPC:        tt.grpc,
+			}
+			cfg.GRPC.ProtoFile = protoPath
+			cfg.GRPC.Service = "test.Chat"
+			cfg.GRPC.StreamTimeout = 5 * time.Second
+
+			collector := metrics.NewCollector()
+			feeder := &staticFeeder{record: map[string]string{"user": "alice"}}
+			req := newGRPCRequester(cfg, collector, nil, feeder, nil)
+			defer req.Close()
+
+			err := req.Do(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Do() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			stats := collector.Stats(time.Second)
+			got := stats.ProtocolMetrics["grpc"]
+			if got["messages_sent"] != tt.wantSent {
+				t.Errorf("messages_sent = %v, want %d", got["messages_sent"], tt.wantSent)
+			}
+			if got["messages_received"] != tt.wantRecv {
+				t.Errorf("messages_received = %v, want %d", got["messages_received"], tt.wantRecv)
+			}
+			if got["streams"] != int64(1) {
+				t.Errorf("streams = %v, want 1", got["streams"])
+			}
+			if _, ok := got["time_to_first_message_ms"]; !ok {
+				t.Error("time_to_first_message_ms missing from protocol metrics")
+			}
+			if tt.wantStatus != "" {
+				if n := stats.StatusBuckets["grpc"][tt.wantStatus]; n != 1 {
+					t.Errorf("StatusBuckets[grpc][%s] = %d, want 1", tt.wantStatus, n)
+				}
+			}
+		})
+	}
+}
+
+type staticFeeder struct {
+	record map[string]string
+}
+
+func (f *staticFeeder) Next(context.Context) (map[string]string, error) { return f.record, nil }
+func (f *staticFeeder) Close() error                                    { return nil }
+func (f *staticFeeder) Len() int                                        { return 1 }
@@ -23,6 +23,12 @@ func loadHAREndpoints(cfg *config.Config) error {
 
 	// Parse and apply filter
 	opts := parseHARFilterToOptions(cfg.HARFilter)
+	if cfg.Cookies.Enabled() {
+		// Recorded cookies seed the jar instead of being replayed as
+		// headers; configured seeds come later so they win.
+		opts.CookieJar = true
+		cfg.Cookies.Seed = append(har.Cookies(harData, opts), cfg.Cookies.Seed...)
+	}
 
 	// Convert HAR to endpoints
 	endpoints, err := har.Convert(harData, opts)
@@ -0,0 +1,172 @@
+package cli
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/torosent/crankfire/internal/config"
+	"github.com/torosent/crankfire/internal/extractor"
+	"github.com/torosent/crankfire/internal/metrics"
+)
+
+// checkFailedStatus is the status bucket for responses that failed a check.
+const checkFailedStatus = "check"
+
+// checkFailedError reports the first check a response failed.
+type checkFailedError struct {
+	Name   string
+	Reason string
+}
+
+func (e *checkFailedError) Error() string {
+	return fmt.Sprintf("check %s failed: %s", e.Name, e.Reason)
+}
+
+// httpCheck is a config.Check with its patterns compiled.
+type httpCheck struct {
+	name    string
+	kind    string
+	check   config.Check
+	status  map[int]struct{}
+	pattern *regexp.Regexp // header matches or body regex
+}
+
+func compileChecks(endpoint string, checks []config.Check) ([]httpCheck, error) {
+	if len(checks) == 0 {
+		return nil, nil
+	}
+	compiled := make([]httpCheck, 0, len(checks))
+	for i, check := range checks {
+		hc := httpCheck{name: check.Name, kind: check.Kind(), check: check}
+		if hc.name == "" {
+			hc.name = defaultCheckName(endpoint, hc.kind, check)
+		}
+		if len(check.Status) > 0 {
+			hc.status = make(map[int]struct{}, len(check.Status))
+			for _, code := range check.Status {
+				hc.status[code] = struct{}{}
+			}
+		}
+		pattern := check.Matches
+		if hc.kind == "regex" {
+			pattern = check.Regex
+		}
+		if pattern != "" {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("checks[%d]: invalid regex: %w", i, err)
+			}
+			hc.pattern = re
+		}
+		compiled = append(compiled, hc)
+	}
+	return compiled, nil
+}
+
+func defaultCheckName(endpoint, kind string, check config.Check) string {
+	switch kind {
+	case "header":
+		return fmt.Sprintf("%s header %s", endpoint, check.Header)
+	case "jsonpath":
+		return fmt.Sprintf("%s jsonpath %s", endpoint, check.JSONPath)
+	}
+	return fmt.Sprintf("%s %s", endpoint, kind)
+}
+
+// evaluate reports whether the response passes the check and, if not, why.
+func (c *httpCheck) evaluate(resp *http.Response, body []byte, size int64) (bool, string) {
+	check := c.check
+	switch c.kind {
+	case "status":
+		if _, ok := c.status[resp.StatusCode]; ok {
+			return true, ""
+		}
+		return false, fmt.Sprintf("status %d not in [%s]", resp.StatusCode, formatStatusSet(check.Status))
+	case "header":
+		values := resp.Header.Values(check.Header)
+		if len(values) == 0 {
+			return false, fmt.Sprintf("header %s missing", check.Header)
+		}
+		value := values[0]
+		switch {
+		case check.Equals != "" && value != check.Equals:
+			return false, fmt.Sprintf("header %s is %q, want %q", check.Header, value, check.Equals)
+		case c.pattern != nil && !c.pattern.MatchString(value):
+			return false, fmt.Sprintf("header %s %q does not match %q", check.Header, value, check.Matches)
+		}
+		return true, ""
+	case "jsonpath":
+		value, ok := extractor.LookupJSONPath(body, check.JSONPath)
+		if !ok {
+			return false, fmt.Sprintf("jsonpath %s not found", check.JSONPath)
+		}
+		switch {
+		case check.Equals != "" && value != check.Equals:
+			return false, fmt.Sprintf("jsonpath %s is %q, want %q", check.JSONPath, value, check.Equals)
+		case check.Contains != "" && !strings.Contains(value, check.Contains):
+			return false, fmt.Sprintf("jsonpath %s %q does not contain %q", check.JSONPath, value, check.Contains)
+		}
+		return true, ""
+	case "regex":
+		if c.pattern.Match(body) {
+			return true, ""
+		}
+		return false, fmt.Sprintf("body does not match %q", check.Regex)
+	case "body_size":
+		if check.MinBodySize > 0 && size < int64(check.MinBodySize) {
+			return false, fmt.Sprintf("body size %d < %d", size, check.MinBodySize)
+		}
+		if check.MaxBodySize > 0 && size > int64(check.MaxBodySize) {
+			return false, fmt.Sprintf("body size %d > %d", size, check.MaxBodySize)
+		}
+		return true, ""
+	}
+	return false, fmt.Sprintf("unknown check kind %q", c.kind)
+}
+
+// runChecks evaluates every check, recording each outcome, and returns the
+// first failure.
+func runChecks(collector *metrics.Collector, checks []httpCheck, resp *http.Response, body []byte, size int64) error {
+	var first error
+	for i := range checks {
+		check := &checks[i]
+		ok, reason := check.evaluate(resp, body, size)
+		collector.RecordCheck(check.name, ok)
+		if !ok && first == nil {
+			first = &checkFailedError{Name: check.name, Reason: reason}
+		}
+	}
+	return first
+}
+
+// checksStatus reports whether any check asserts on the status code, in which
+// case the checks decide which statuses are acceptable.
+func checksStatus(checks []httpCheck) bool {
+	for _, check := range checks {
+		if check.kind == "status" {
+			return true
+		}
+	}
+	return false
+}
+
+// checksBodySize reports whether any check needs the full body size.
+func checksBodySize(checks []httpCheck) bool {
+	for _, check := range checks {
+		if check.kind == "body_size" {
+			return true
+		}
+	}
+	return false
+}
+
+func formatStatusSet(codes []int) string {
+	parts := make([]string, len(codes))
+	for i, code := range codes {
+		parts[i] = strconv.Itoa(code)
+	}
+	return strings.Join(parts, ",")
+}
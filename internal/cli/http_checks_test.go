@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/torosent/crankfire/internal/config"
+	"github.com/torosent/crankfire/internal/metrics"
+)
+
+func TestHTTPCheckEvaluate(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusCreated,
+		Header:     http.Header{"Content-Type": []string{"application/json; charset=utf-8"}},
+	}
+	body := []byte(`{"user":{"id":"42","name":"Alice Smith"}}`)
+
+	tests := []struct {
+		name  string
+		check config.Check
+		want  bool
+	}{
+		{"status in set", config.Check{Status: []int{200, 201}}, true},
+		{"status not in set", config.Check{Status: []int{200}}, false},
+		{"header present", config.Check{Header: "Content-Type"}, true},
+		{"header missing", config.Check{Header: "X-Request-Id"}, false},
+		{"header equals", config.Check{Header: "Content-Type", Equals: "application/json"}, false},
+		{"header matches", config.Check{Header: "Content-Type", Matches: `^application/json`}, true},
+		{"jsonpath exists", config.Check{JSONPath: "user.id"}, true},
+		{"jsonpath missing", config.Check{JSONPath: "user.email"}, false},
+		{"jsonpath equals", config.Check{JSONPath: "$.user.id", Equals: "42"}, true},
+		{"jsonpath contains", config.Check{JSONPath: "user.name", Contains: "Smith"}, true},
+		{"jsonpath does not contain", config.Check{JSONPath: "user.name", Contains: "Jones"}, false},
+		{"regex matches", config.Check{Regex: `"id":"\d+"`}, true},
+		{"regex does not match", config.Check{Regex: `error`}, false},
+		{"body within bounds", config.Check{MinBodySize: 10, MaxBodySize: 100}, true},
+		{"body too small", config.Check{MinBodySize: 1000}, false},
+		{"body too large", config.Check{MaxBodySize: 10}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checks, err := compileChecks("users", []config.Check{tt.check})
+			if err != nil {
+				t.Fatalf("compileChecks() error = %v", err)
+			}
+			got, reason := checks[0].evaluate(resp, body, int64(len(body)))
+			if got != tt.want {
+				t.Errorf("evaluate() = %v (%s), want %v", got, reason, tt.want)
+			}
+			if !got && reason == "" {
+				t.Error("expected a reason for the failed check")
+			}
+		})
+	}
+}
+
+func TestCompileChecksDefaultNames(t *testing.T) {
+	checks, err := compileChecks("login", []config.Check{
+		{Status: []int{200}},
+		{Header: "Set-Cookie"},
+		{JSONPath: "token"},
+		{Name: "fast body", MaxBodySize: 512},
+	})
+	if err != nil {
+		t.Fatalf("compileChecks() error = %v", err)
+	}
+	want := []string{"login status", "login header Set-Cookie", "login jsonpath token", "fast body"}
+	for i, check := range checks {
+		if check.name != want[i] {
+			t.Errorf("checks[%d].name = %q, want %q", i, check.name, want[i])
+		}
+	}
+}
+
+func TestHTTPRequester_Checks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("missing") != "" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"not found"}`))
+			return
+		}
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer server.Close()
+
+	collector := metrics.NewCollector()
+	requester := &httpRequester{client: http.DefaultClient, collector: collector}
+
+	run := func(target string, checks []config.Check) error {
+		t.Helper()
+		tmpl, err := buildEndpointTemplate(&config.Config{}, config.Endpoint{Name: "probe", URL: target, Checks: checks})
+		if err != nil {
+			t.Fatalf("buildEndpointTemplate() error = %v", err)
+		}
+		ctx := context.WithValue(context.Background(), endpointContextKey, tmpl)
+		return requester.Do(ctx)
+	}
+
+	if err := run(server.URL, []config.Check{{JSONPath: "status", Equals: "ok"}}); err != nil {
+		t.Fatalf("expected passing check, got %v", err)
+	}
+
+	err := run(server.URL, []config.Check{{Name: "degraded", JSONPath: "status", Equals: "degraded"}})
+	var checkErr *checkFailedError
+	if !errors.As(err, &checkErr) || checkErr.Name != "degraded" {
+		t.Fatalf("expected check failure, got %v", err)
+	}
+
+	// A status check accepts listed error statuses instead of failing them.
+	if err := run(server.URL+"?missing=1", []config.Check{{Name: "gone", Status: []int{404}}}); err != nil {
+		t.Fatalf("expected 404 to satisfy status check, got %v", err)
+	}
+
+	stats := collector.Stats(time.Second)
+	if stats.Total != 3 || stats.Failures != 1 {
+		t.Fatalf("unexpected totals: total=%d failures=%d", stats.Total, stats.Failures)
+	}
+	if got := stats.StatusBuckets["http"]["CHECK"]; got != 1 {
+		t.Fatalf("expected 1 CHECK status bucket, got %v", stats.StatusBuckets)
+	}
+	if got := stats.Checks["degraded"]; got.Fails != 1 || got.Passes != 0 {
+		t.Fatalf("unexpected degraded check stats %+v", got)
+	}
+	if got := stats.Checks["probe jsonpath status"]; got.PassRate != 1 {
+		t.Fatalf("unexpected default-named check stats %+v", got)
+	}
+	if !strings.Contains(err.Error(), `want "degraded"`) {
+		t.Fatalf("expected reason in error, got %v", err)
+	}
+}
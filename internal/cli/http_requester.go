@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/torosent/crankfire/internal/auth"
@@ -32,6 +33,14 @@ type httpRequester struct {
 	builder   *httpclient.RequestBuilder
 	collector *metrics.Collector
 	helper    baseRequesterHelper
+	// cookieExtract lists the cookies copied into variables after each
+	// response when the iteration has a cookie jar.
+	cookieExtract []config.CookieExtract
+
+	// Running totals behind the connection reuse gauges.
+	started     atomic.Int64 // UnixNano of the first response
+	responses   atomic.Int64
+	connections atomic.Int64
 }
 
 // Do executes an HTTP request and records metrics.
@@ -57,7 +66,8 @@ func (r *httpRequester) Do(ctx context.Context) error {
 		tracing.EndSpan(span, spanErr)
 	}()
 
-	req, err := builder.Build(ctx)
+	phases := &phaseTimer{}
+	req, err := builder.Build(phases.trace(withTLSTrace(ctx, r.collector, "http")))
 	if err != nil {
 		spanErr = err
 		return r.helper.recordError(start, meta, "http", "build", err)
@@ -67,10 +77,19 @@ func (r *httpRequester) Do(ctx context.Context) error {
 		tracing.InjectHTTPHeaders(ctx, req.Header)
 	}
 
-	resp, err := r.client.Do(req)
+	client := r.client
+	jar := httpclient.CookieJarFromContext(ctx)
+	if jar != nil {
+		c := *r.client
+		c.Jar = jar
+		client = &c
+	}
+
+	resp, err := client.Do(req)
 	latency := time.Since(start)
 	if err != nil {
 		spanErr = err
+		phases.record(r.collector, meta.Endpoint, time.Time{})
 		return r.helper.recordError(start, meta, "http", "execute", err)
 	}
 	defer resp.Body.Close()
@@ -83,9 +102,15 @@ func (r *httpRequester) Do(ctx context.Context) error {
 	if bodyErr != nil {
 		body = nil // Ensure body is nil on error for consistent behavior
 	}
+	phases.record(r.collector, meta.Endpoint, time.Now())
+
+	var checks []httpCheck
+	if tmpl != nil {
+		checks = tmpl.checks
+	}
 
 	var resultErr error
-	if resp.StatusCode >= 400 {
+	if resp.StatusCode >= 400 && !checksStatus(checks) {
 		snippet := body
 		if len(snippet) > maxLoggedBodyBytes {
 			snippet = snippet[:maxLoggedBodyBytes]
@@ -125,14 +150,56 @@ func (r *httpRequester) Do(ctx context.Context) error {
 		}
 	}
 
+	if jar != nil {
+		storeExtractedValues(ctx, extractCookies(jar, resp, r.cookieExtract))
+	}
+
+	if len(checks) > 0 {
+		size := int64(len(body))
+		if checksBodySize(checks) && size == maxBodyReadSize {
+			// Count the remainder without buffering it.
+			rest, _ := io.Copy(io.Discard, resp.Body)
+			size += rest
+		}
+		if err := runChecks(r.collector, checks, resp, body, size); err != nil && resultErr == nil {
+			resultErr = err
+			meta = annotateStatus(meta, "http", checkFailedStatus)
+		}
+	}
+
 	if resultErr != nil && meta.StatusCode == "" {
 		meta = annotateStatus(meta, "http", httpStatusCodeFromError(resultErr))
 	}
+	meta = ensureProtocolMeta(meta, "http")
+	meta.CustomMetrics = r.connectionMetrics(phases.connectionOpened())
 	spanErr = resultErr
 	r.collector.RecordRequest(latency, resultErr, meta)
 	return resultErr
 }
 
+// connectionMetrics counts the connection a response arrived on and updates
+// the reuse gauges: responses per connection (streams per connection for
+// HTTP/2 and HTTP/3) and connections opened per second since the first
+// response.
+func (r *httpRequester) connectionMetrics(newConn bool) map[string]interface{} {
+	now := time.Now().UnixNano()
+	r.started.CompareAndSwap(0, now)
+	responses := r.responses.Add(1)
+	opened := int64(0)
+	if newConn {
+		opened = 1
+		r.connections.Add(1)
+	}
+	m := map[string]interface{}{"connections_opened": opened}
+	if conns := r.connections.Load(); conns > 0 {
+		m["streams_per_connection"] = metrics.Gauge(float64(responses) / float64(conns))
+		if elapsed := time.Duration(now - r.started.Load()).Seconds(); elapsed > 0 {
+			m["new_connections_per_sec"] = metrics.Gauge(float64(conns) / elapsed)
+		}
+	}
+	return m
+}
+
 // filterExtractorsOnError returns only extractors with OnError=true.
 func filterExtractorsOnError(extractors []extractor.Extractor) []extractor.Extractor {
 	result := make([]extractor.Extractor, 0, len(extractors))
@@ -167,6 +234,10 @@ func httpStatusCodeFromError(err error) string {
 	if errors.As(err, &httpErr) && httpErr.StatusCode > 0 {
 		return strconv.Itoa(httpErr.StatusCode)
 	}
+	var checkErr *checkFailedError
+	if errors.As(err, &checkErr) {
+		return sanitizeStatusCode(checkFailedStatus)
+	}
 	return fallbackStatusCode(err)
 }
 
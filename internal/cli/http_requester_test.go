@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/torosent/crankfire/internal/config"
+	"github.com/torosent/crankfire/internal/metrics"
+)
+
+func TestHTTPRequester_ConnectionReuseMetrics(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor != 2 {
+			w.WriteHeader(http.StatusHTTPVersionNotSupported)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	server.Config.Protocols = new(http.Protocols)
+	server.Config.Protocols.SetUnencryptedHTTP2(true)
+	server.Start()
+	defer server.Close()
+
+	cfg := &config.Config{TargetURL: server.URL, HTTPVersion: config.HTTPVersionH2C}
+	collector := metrics.NewCollector()
+	requester, err := buildRequester(cfg, collector, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("buildRequester() error = %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := requester.Do(context.Background()); err != nil {
+			t.Fatalf("request %d: Do() error = %v", i, err)
+		}
+	}
+
+	got := collector.Stats(time.Second).ProtocolMetrics["http"]
+	if got["connections_opened"] != int64(1) {
+		t.Errorf("connections_opened = %v, want 1", got["connections_opened"])
+	}
+	if got["streams_per_connection"] != metrics.Gauge(3) {
+		t.Errorf("streams_per_connection = %v, want 3", got["streams_per_connection"])
+	}
+	if rate, _ := got["new_connections_per_sec"].(metrics.Gauge); rate <= 0 {
+		t.Errorf("new_connections_per_sec = %v, want > 0", got["new_connections_per_sec"])
+	}
+}
+
+func TestHTTPRequester_RecordsConnectionPhases(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{TargetURL: strings.Replace(server.URL, "127.0.0.1", "localhost", 1)}
+	collector := metrics.NewCollector()
+	requester, err := buildRequester(cfg, collector, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("buildRequester() error = %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := requester.Do(context.Background()); err != nil {
+			t.Fatalf("request %d: Do() error = %v", i, err)
+		}
+	}
+
+	// The second request reuses the connection, so only the first one
+	// resolves and connects.
+	phases := collector.Stats(time.Second).Phases
+	want := map[string]int64{
+		metrics.PhaseDNS:      1,
+		metrics.PhaseConnect:  1,
+		metrics.PhaseTTFB:     2,
+		metrics.PhaseTransfer: 2,
+	}
+	for phase, samples := range want {
+		if got := phases[phase].Total; got != samples {
+			t.Errorf("%s samples = %d, want %d", phase, got, samples)
+		}
+	}
+	if _, ok := phases[metrics.PhaseTLS]; ok {
+		t.Error("tls phase recorded for a plain HTTP target")
+	}
+	if ttfb := phases[metrics.PhaseTTFB].MinLatency; ttfb < 20*time.Millisecond {
+		t.Errorf("ttfb min = %s, want at least the 20ms the server waits", ttfb)
+	}
+}
+
+func TestHTTPRequester_ResolveOverride(t *testing.T) {
+	hosts := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hosts <- r.Host
+	}))
+	defer server.Close()
+
+	_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	host := net.JoinHostPort("api.example.invalid", port)
+	cfg := &config.Config{
+		TargetURL: "http://" + host + "/",
+		DNS:       config.DNSConfig{Resolve: map[string][]string{host: {server.Listener.Addr().String()}}},
+	}
+	requester, err := buildRequester(cfg, metrics.NewCollector(), nil, nil, nil)
+	if err != nil {
+		t.Fatalf("buildRequester() error = %v", err)
+	}
+	if err := requester.Do(context.Background()); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if got := <-hosts; got != host {
+		t.Errorf("Host = %q, want %q", got, host)
+	}
+}
@@ -213,6 +213,39 @@ func TestBuildLoadPattern(t *testing.T) {
 	}
 }
 
+func TestHTTPRequester_RecordsQueueDelay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	builder, err := createTestRequestBuilder(&config.Config{TargetURL: server.URL})
+	if err != nil {
+		t.Fatalf("failed to create request builder: %v", err)
+	}
+
+	collector := metrics.NewCollector()
+	requester := &httpRequester{
+		client:    http.DefaultClient,
+		builder:   builder,
+		collector: collector,
+	}
+
+	// The request was due 300ms ago but only now reached a free worker.
+	ctx := runner.WithIntendedStart(context.Background(), time.Now().Add(-300*time.Millisecond))
+	if err := requester.Do(ctx); err != nil {
+		t.Fatalf("Do() returned error: %v", err)
+	}
+
+	stats := collector.Stats(time.Second)
+	if stats.MaxLatency >= 300*time.Millisecond {
+		t.Fatalf("expected uncorrected latency to exclude queueing, got %s", stats.MaxLatency)
+	}
+	if stats.Corrected == nil || stats.Corrected.MaxLatency < 300*time.Millisecond {
+		t.Fatalf("expected corrected latency to include queueing, got %+v", stats.Corrected)
+	}
+}
+
 func TestHTTPRequester_ExtractsJSONPath(t *testing.T) {
 	// Test extracting a JSON path from a successful response
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
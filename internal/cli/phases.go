@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/torosent/crankfire/internal/metrics"
+)
+
+// phaseTimer captures the connection phases of one HTTP request through
+// httptrace. The transport may call the hooks from its dial goroutines, so
+// the timestamps are guarded by a mutex.
+type phaseTimer struct {
+	mu           sync.Mutex
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	wroteRequest time.Time
+	firstByte    time.Time
+	newConn      bool
+}
+
+// trace returns a context whose requests report their phases to p.
+func (p *phaseTimer) trace(ctx context.Context) context.Context {
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { p.mark(&p.dnsStart) },
+		DNSDone: func(info httptrace.DNSDoneInfo) {
+			if info.Err == nil {
+				p.mark(&p.dnsDone)
+			}
+		},
+		// Dialing may try several addresses; the phase runs from the first
+		// attempt to the first connection established.
+		ConnectStart: func(_, _ string) { p.markFirst(&p.connectStart) },
+		ConnectDone: func(_, _ string, err error) {
+			if err == nil {
+				p.markFirst(&p.connectDone)
+			}
+		},
+		TLSHandshakeStart: func() { p.mark(&p.tlsStart) },
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			if err == nil {
+				p.mark(&p.tlsDone)
+			}
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			p.mu.Lock()
+			p.newConn = !info.Reused
+			p.mu.Unlock()
+		},
+		WroteRequest: func(info httptrace.WroteRequestInfo) {
+			if info.Err == nil {
+				p.mark(&p.wroteRequest)
+			}
+		},
+		GotFirstResponseByte: func() { p.mark(&p.firstByte) },
+	})
+}
+
+func (p *phaseTimer) mark(t *time.Time) {
+	now := time.Now()
+	p.mu.Lock()
+	*t = now
+	p.mu.Unlock()
+}
+
+func (p *phaseTimer) markFirst(t *time.Time) {
+	now := time.Now()
+	p.mu.Lock()
+	if t.IsZero() {
+		*t = now
+	}
+	p.mu.Unlock()
+}
+
+// connectionOpened reports whether the request was sent on a new connection.
+func (p *phaseTimer) connectionOpened() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.newConn
+}
+
+// record adds every phase that completed to the collector. bodyDone ends the
+// transfer phase; a zero bodyDone records no transfer.
+func (p *phaseTimer) record(collector *metrics.Collector, endpoint string, bodyDone time.Time) {
+	p.mu.Lock()
+	spans := []struct {
+		phase      string
+		start, end time.Time
+	}{
+		{metrics.PhaseDNS, p.dnsStart, p.dnsDone},
+		{metrics.PhaseConnect, p.connectStart, p.connectDone},
+		{metrics.PhaseTLS, p.tlsStart, p.tlsDone},
+		{metrics.PhaseTTFB, p.wroteRequest, p.firstByte},
+		{metrics.PhaseTransfer, p.firstByte, bodyDone},
+	}
+	p.mu.Unlock()
+	for _, s := range spans {
+		if s.start.IsZero() || s.end.IsZero() || s.end.Before(s.start) {
+			continue
+		}
+		collector.RecordPhase(endpoint, s.phase, s.end.Sub(s.start))
+	}
+}
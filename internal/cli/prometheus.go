@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/torosent/crankfire/internal/config"
+	"github.com/torosent/crankfire/internal/metrics"
+)
+
+// prometheusShutdownTimeout bounds how long a scrape in flight may delay the
+// end of the run.
+const prometheusShutdownTimeout = 2 * time.Second
+
+// prometheusHandler serves the collector's live measurements at /metrics.
+func prometheusHandler(collector *metrics.Collector, labels map[string]string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", metrics.PrometheusContentType)
+		_ = collector.WritePrometheus(w, labels)
+	})
+	return mux
+}
+
+// startPrometheus serves /metrics on cfg.Listen until the returned stop func
+// is called.
+func startPrometheus(cfg config.PrometheusConfig, collector *metrics.Collector) (string, func(), error) {
+	ln, err := net.Listen("tcp", cfg.Listen)
+	if err != nil {
+		return "", nil, fmt.Errorf("prometheus: %w", err)
+	}
+	srv := &http.Server{
+		Handler:           prometheusHandler(collector, cfg.Labels),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() { _ = srv.Serve(ln) }()
+
+	stop := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), prometheusShutdownTimeout)
+		defer cancel()
+		_ = srv.Shutdown(ctx)
+	}
+	return ln.Addr().String(), stop, nil
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/torosent/crankfire/internal/config"
+	"github.com/torosent/crankfire/internal/metrics"
+)
+
+func TestStartPrometheus_ServesLiveMetrics(t *testing.T) {
+	collector := metrics.NewCollector()
+	collector.Start()
+
+	addr, stop, err := startPrometheus(config.PrometheusConfig{
+		Listen: "127.0.0.1:0",
+		Labels: map[string]string{"test": "checkout"},
+	}, collector)
+	if err != nil {
+		t.Fatalf("startPrometheus: %v", err)
+	}
+	defer stop()
+
+	scrape := func() string {
+		t.Helper()
+		resp, err := http.Get("http://" + addr + "/metrics")
+		if err != nil {
+			t.Fatalf("scrape: %v", err)
+		}
+		defer resp.Body.Close()
+		if ct := resp.Header.Get("Content-Type"); ct != metrics.PrometheusContentType {
+			t.Errorf("Content-Type = %q, want %q", ct, metrics.PrometheusContentType)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	if body := scrape(); !strings.Contains(body, `crankfire_requests_total{test="checkout",result="success"} 0`) {
+		t.Fatalf("initial scrape missing zero counter:\n%s", body)
+	}
+
+	collector.RecordRequest(10*time.Millisecond, nil, &metrics.RequestMetadata{Endpoint: "checkout", Protocol: "http"})
+	body := scrape()
+	for _, want := range []string{
+		`crankfire_requests_total{test="checkout",result="success"} 1`,
+		`crankfire_endpoint_requests_total{test="checkout",endpoint="checkout",result="success"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("second scrape missing %q\n%s", want, body)
+		}
+	}
+
+	stop()
+	if _, err := http.Get("http://" + addr + "/metrics"); err == nil {
+		t.Error("expected server to be stopped")
+	}
+}
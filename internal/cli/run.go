@@ -13,11 +13,14 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/torosent/crankfire/internal/baseline"
 	"github.com/torosent/crankfire/internal/cli/livedash"
+	"github.com/torosent/crankfire/internal/cli/webdash"
 	"github.com/torosent/crankfire/internal/config"
 	"github.com/torosent/crankfire/internal/metrics"
 	"github.com/torosent/crankfire/internal/output"
 	"github.com/torosent/crankfire/internal/runner"
+	"github.com/torosent/crankfire/internal/store"
 	"github.com/torosent/crankfire/internal/threshold"
 	"github.com/torosent/crankfire/internal/tui/runview"
 )
@@ -61,6 +64,24 @@ func Run(args []string) error {
 		return err
 	}
 
+	thresholds, err := threshold.ParseMultiple(cfg.Thresholds)
+	if err != nil {
+		return fmt.Errorf("threshold parsing failed: %w", err)
+	}
+
+	criteria := runCriteria{thresholds: thresholds}
+	if cfg.Baseline.Enabled() {
+		base, err := loadBaseline(cfg.Baseline)
+		if err != nil {
+			return err
+		}
+		criteria.baseline = &base
+	}
+
+	if len(cfg.Agents) > 0 {
+		return runDistributed(cfg, criteria)
+	}
+
 	// Initialize OpenTelemetry tracing, auth, feeder, requester, and runner
 	// via the shared BuildRunner helper so the TUI and CLI share wiring.
 	r, collector, cleanup, err := BuildRunner(context.Background(), *cfg)
@@ -72,6 +93,38 @@ func Run(args []string) error {
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 
+	if cfg.Prometheus.Enabled() {
+		addr, stop, err := startPrometheus(cfg.Prometheus, collector)
+		if err != nil {
+			return err
+		}
+		defer stop()
+		if !cfg.JSONOutput && !cfg.Dashboard {
+			fmt.Fprintf(os.Stderr, "[crankfire] serving Prometheus metrics on http://%s/metrics\n", addr)
+		}
+	}
+
+	if cfg.WebDashboard != "" {
+		var header []string
+		if target := resolveDashboardTargetURL(*cfg); target != "" {
+			header = append(header, "Target: "+target)
+		}
+		web := webdash.New(collector, webdash.Opts{
+			Title:  "Crankfire",
+			Header: header,
+			Total:  int64(cfg.Total),
+			// The terminal dashboard and the HTML report already snapshot
+			// the collector.
+			Snapshot: !cfg.Dashboard && cfg.HTMLOutput == "",
+		}, cancel)
+		addr, err := web.Start(cfg.WebDashboard)
+		if err != nil {
+			return err
+		}
+		defer web.Stop()
+		fmt.Fprintf(os.Stderr, "[crankfire] serving web dashboard on http://%s/\n", addr)
+	}
+
 	var dash *livedash.Driver
 	var dashStats metrics.Stats
 	if cfg.Dashboard {
@@ -110,58 +163,100 @@ func Run(args []string) error {
 	collector.Start()
 
 	// Start periodic snapshots for HTML report history (if enabled)
-	var snapshotTicker *time.Ticker
-	var snapshotDone chan struct{}
-	var snapshotStop chan struct{}
+	var stopSnapshots func()
 	if cfg.HTMLOutput != "" {
-		snapshotTicker = time.NewTicker(1 * time.Second)
-		snapshotDone = make(chan struct{})
-		snapshotStop = make(chan struct{})
-		go func() {
-			defer close(snapshotDone)
-			for {
-				select {
-				case <-snapshotTicker.C:
-					collector.Snapshot()
-				case <-snapshotStop:
-					return
-				case <-ctx.Done():
-					return
-				}
-			}
-		}()
+		stopSnapshots = startSnapshots(ctx, collector)
 	}
 
-	result := r.Run(ctx)
+	result, breach := RunWithThresholds(ctx, r, collector, thresholds)
+	criteria.breach = breach
+
+	if stopSnapshots != nil {
+		stopSnapshots()
+	}
 
-	// Stop snapshot collection
-	if snapshotTicker != nil {
-		snapshotTicker.Stop()
-		close(snapshotStop)
-		<-snapshotDone
-		// Take one final snapshot after the test completes
+	return reportResults(cfg, collector, result, criteria)
+}
+
+// startSnapshots records a history data point every second until the
+// returned func is called, which takes one final snapshot once the ticker
+// has stopped.
+func startSnapshots(ctx context.Context, collector *metrics.Collector) func() {
+	ticker := time.NewTicker(1 * time.Second)
+	done := make(chan struct{})
+	stop := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case <-ticker.C:
+				collector.Snapshot()
+			case <-stop:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return func() {
+		ticker.Stop()
+		close(stop)
+		<-done
 		collector.Snapshot()
 	}
+}
+
+// ErrRegression is returned when a run regressed against its baseline.
+var ErrRegression = errors.New("performance regressed against the baseline")
+
+// runCriteria are the pass/fail criteria applied to a finished run.
+type runCriteria struct {
+	thresholds []threshold.Threshold
+	// breach is the abort-on-fail threshold that stopped the run, if any.
+	breach   *threshold.Breach
+	baseline *baseline.Baseline
+}
+
+// loadBaseline reads the configured baseline run from the data directory or
+// a report file.
+func loadBaseline(cfg config.BaselineConfig) (baseline.Baseline, error) {
+	dataDir, err := store.ResolveDataDir("")
+	if err != nil {
+		return baseline.Baseline{}, err
+	}
+	return baseline.Load(dataDir, cfg.Run)
+}
 
+// reportResults prints the report for a finished run, writes the HTML and
+// JUnit reports, evaluates thresholds and compares against the baseline. It
+// returns an error when the run was aborted, thresholds or requests failed,
+// or it regressed.
+func reportResults(cfg *config.Config, collector *metrics.Collector, result runner.Result, criteria runCriteria) error {
 	stats := collector.Stats(result.Duration)
 
-	// Parse and evaluate thresholds
+	// Evaluate thresholds
 	var thresholdResults []threshold.Result
-	if len(cfg.Thresholds) > 0 {
-		thresholds, err := threshold.ParseMultiple(cfg.Thresholds)
-		if err != nil {
-			return fmt.Errorf("threshold parsing failed: %w", err)
-		}
-		evaluator := threshold.NewEvaluator(thresholds)
+	if len(criteria.thresholds) > 0 {
+		evaluator := threshold.NewEvaluator(criteria.thresholds)
 		thresholdResults = evaluator.Evaluate(stats)
+		criteria.breach.Apply(thresholdResults)
+	}
+
+	var comparison *baseline.Comparison
+	if criteria.baseline != nil {
+		comparison = baseline.Compare(*criteria.baseline, stats, baseline.Tolerance{
+			Latency:   cfg.Baseline.LatencyTolerance,
+			ErrorRate: cfg.Baseline.ErrorRateTolerance,
+		})
 	}
 
 	if cfg.JSONOutput {
-		if err := output.PrintJSONReport(os.Stdout, stats, thresholdResults); err != nil {
+		if err := output.PrintJSONReport(os.Stdout, stats, thresholdResults, comparison); err != nil {
 			return err
 		}
 	} else {
 		output.PrintReport(os.Stdout, stats, thresholdResults)
+		output.PrintComparison(os.Stdout, comparison)
 	}
 
 	// Generate HTML report if requested
@@ -185,10 +280,24 @@ func Run(args []string) error {
 				testedEndpoints[i].URL = ep.Path
 			}
 		}
+		for _, sc := range cfg.Scenarios {
+			for idx, step := range sc.Steps {
+				tested := output.TestedEndpoint{
+					Name:   sc.Name + "/" + scenarioStepName(cfg, idx, step),
+					Method: step.Method,
+					URL:    step.URL,
+				}
+				if tested.URL == "" {
+					tested.URL = step.Path
+				}
+				testedEndpoints = append(testedEndpoints, tested)
+			}
+		}
 
 		metadata := output.ReportMetadata{
 			TargetURL:       cfg.TargetURL,
 			TestedEndpoints: testedEndpoints,
+			Baseline:        comparison,
 		}
 
 		if err := output.GenerateHTMLReport(file, stats, history, thresholdResults, metadata); err != nil {
@@ -197,6 +306,22 @@ func Run(args []string) error {
 		fmt.Fprintf(os.Stderr, "\nHTML report generated: %s\n", cfg.HTMLOutput)
 	}
 
+	if cfg.JUnitOutput != "" {
+		file, err := os.Create(cfg.JUnitOutput)
+		if err != nil {
+			return fmt.Errorf("failed to create JUnit report file: %w", err)
+		}
+		defer file.Close()
+		if err := output.WriteJUnitReport(file, stats, thresholdResults); err != nil {
+			return fmt.Errorf("failed to write JUnit report: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "JUnit report written: %s\n", cfg.JUnitOutput)
+	}
+
+	if criteria.breach != nil {
+		return criteria.breach
+	}
+
 	// Check if any thresholds failed
 	thresholdsFailed := false
 	for _, tr := range thresholdResults {
@@ -210,6 +335,10 @@ func Run(args []string) error {
 		return fmt.Errorf("one or more thresholds failed")
 	}
 
+	if comparison.Regressed() {
+		return fmt.Errorf("%w %s", ErrRegression, comparison.Baseline)
+	}
+
 	if result.Errors > 0 {
 		return fmt.Errorf("%d requests failed", result.Errors)
 	}
@@ -220,11 +349,29 @@ func toRunnerArrivalModel(model config.ArrivalModel) runner.ArrivalModel {
 	switch strings.ToLower(string(model)) {
 	case string(config.ArrivalModelPoisson):
 		return runner.ArrivalModelPoisson
+	case string(config.ArrivalModelClosed):
+		return runner.ArrivalModelClosed
 	default:
 		return runner.ArrivalModelUniform
 	}
 }
 
+func toRunnerThinkTime(think config.ThinkTimeConfig) runner.ThinkTime {
+	dist := runner.ThinkTimeConstant
+	switch think.Distribution {
+	case config.ThinkTimeUniform:
+		dist = runner.ThinkTimeUniform
+	case config.ThinkTimeExponential:
+		dist = runner.ThinkTimeExponential
+	}
+	return runner.ThinkTime{
+		Distribution: dist,
+		Mean:         think.Duration,
+		Min:          think.Min,
+		Max:          think.Max,
+	}
+}
+
 func toRunnerLoadPatterns(patterns []config.LoadPattern) []runner.LoadPattern {
 	if len(patterns) == 0 {
 		return nil
@@ -0,0 +1,168 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/torosent/crankfire/internal/config"
+	"github.com/torosent/crankfire/internal/metrics"
+	"github.com/torosent/crankfire/internal/runner"
+	"github.com/torosent/crankfire/internal/variables"
+)
+
+type scenarioStep struct {
+	endpoint  *endpointTemplate
+	thinkTime time.Duration
+}
+
+type scenarioTemplate struct {
+	name   string
+	weight int
+	steps  []scenarioStep
+}
+
+// scenarioSelector picks a weighted scenario for each iteration.
+type scenarioSelector struct {
+	scenarios   []*scenarioTemplate
+	totalWeight int
+	rnd         *rand.Rand
+	mu          sync.Mutex
+}
+
+func newScenarioSelector(cfg *config.Config) (*scenarioSelector, error) {
+	if len(cfg.Scenarios) == 0 {
+		return nil, nil
+	}
+
+	scenarios := make([]*scenarioTemplate, 0, len(cfg.Scenarios))
+	total := 0
+	for _, sc := range cfg.Scenarios {
+		tmpl, err := buildScenarioTemplate(cfg, sc)
+		if err != nil {
+			return nil, fmt.Errorf("scenario %s: %w", sc.Name, err)
+		}
+		scenarios = append(scenarios, tmpl)
+		total += tmpl.weight
+	}
+
+	return &scenarioSelector{
+		scenarios:   scenarios,
+		totalWeight: total,
+		rnd:         rand.New(rand.NewSource(time.Now().UnixNano())),
+	}, nil
+}
+
+func buildScenarioTemplate(cfg *config.Config, sc config.Scenario) (*scenarioTemplate, error) {
+	weight := sc.Weight
+	if weight <= 0 {
+		weight = 1
+	}
+	tmpl := &scenarioTemplate{name: sc.Name, weight: weight}
+	for idx, step := range sc.Steps {
+		ep := step.Endpoint
+		ep.Name = sc.Name + "/" + scenarioStepName(cfg, idx, step)
+		endpoint, err := buildEndpointTemplate(cfg, ep)
+		if err != nil {
+			return nil, fmt.Errorf("step %s: %w", ep.Name, err)
+		}
+		thinkTime := sc.ThinkTime
+		if step.ThinkTime != nil {
+			thinkTime = *step.ThinkTime
+		}
+		tmpl.steps = append(tmpl.steps, scenarioStep{endpoint: endpoint, thinkTime: thinkTime})
+	}
+	return tmpl, nil
+}
+
+// scenarioStepName returns the step's name, defaulting to its position,
+// method and path, e.g. "02-POST /cart".
+func scenarioStepName(cfg *config.Config, idx int, step config.ScenarioStep) string {
+	if name := strings.TrimSpace(step.Name); name != "" {
+		return name
+	}
+	method := step.Method
+	if method == "" {
+		method = cfg.Method
+	}
+	if method == "" {
+		method = http.MethodGet
+	}
+	target := strings.TrimSpace(step.URL)
+	if target == "" {
+		target = strings.TrimSpace(step.Path)
+	}
+	return fmt.Sprintf("%02d-%s %s", idx+1, strings.ToUpper(method), target)
+}
+
+func (s *scenarioSelector) pick() *scenarioTemplate {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := s.rnd.Intn(s.totalWeight)
+	cumulative := 0
+	for _, sc := range s.scenarios {
+		cumulative += sc.weight
+		if n < cumulative {
+			return sc
+		}
+	}
+	return s.scenarios[len(s.scenarios)-1]
+}
+
+// Wrap turns next, which executes a single request, into a requester that
+// runs one full scenario iteration per call.
+func (s *scenarioSelector) Wrap(next runner.Requester, collector *metrics.Collector) runner.Requester {
+	if s == nil || len(s.scenarios) == 0 || next == nil {
+		return next
+	}
+	return &scenarioRequester{next: next, selector: s, collector: collector}
+}
+
+type scenarioRequester struct {
+	next      runner.Requester
+	selector  *scenarioSelector
+	collector *metrics.Collector
+}
+
+// Do runs every step of one scenario in order for a single virtual user.
+// Each iteration gets a fresh variable store, so values extracted by a step
+// are visible to the following steps of the same iteration only. The
+// iteration stops at the first failing step.
+func (r *scenarioRequester) Do(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	sc := r.selector.pick()
+	ctx = variables.NewContext(ctx, variables.NewStore())
+
+	start := time.Now()
+	for idx, step := range sc.steps {
+		if idx == 1 {
+			// Only the first step was scheduled by the arrival rate; later
+			// steps start when the previous one finishes.
+			ctx = runner.WithIntendedStart(ctx, time.Time{})
+		}
+		stepCtx := context.WithValue(ctx, endpointContextKey, step.endpoint)
+		if err := r.next.Do(stepCtx); err != nil {
+			if ctx.Err() == nil {
+				meta := &metrics.RequestMetadata{Protocol: "http", StatusCode: httpStatusCodeFromError(err)}
+				r.collector.RecordJourney(sc.name, time.Since(start), err, meta)
+			}
+			return fmt.Errorf("scenario %s: step %s: %w", sc.name, step.endpoint.name, err)
+		}
+		if idx == len(sc.steps)-1 || step.thinkTime <= 0 {
+			continue
+		}
+		select {
+		case <-time.After(step.thinkTime):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	r.collector.RecordJourney(sc.name, time.Since(start), nil, nil)
+	return nil
+}
@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/torosent/crankfire/internal/config"
+	"github.com/torosent/crankfire/internal/metrics"
+)
+
+func TestScenarioRequester_ChainsStepsPerIteration(t *testing.T) {
+	var mu sync.Mutex
+	logins := 0
+	var seen []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/login":
+			logins++
+			if logins == 1 {
+				w.Write([]byte(`{"token":"t1"}`))
+				return
+			}
+			w.Write([]byte(`{}`))
+		case "/profile":
+			seen = append(seen, r.Header.Get("Authorization"))
+		}
+	}))
+	defer server.Close()
+
+	thinkTime := 30 * time.Millisecond
+	cfg := &config.Config{
+		TargetURL: server.URL,
+		Scenarios: []config.Scenario{{
+			Name:      "browse",
+			Weight:    1,
+			ThinkTime: thinkTime,
+			Steps: []config.ScenarioStep{
+				{Endpoint: config.Endpoint{
+					Name:       "login",
+					Method:     http.MethodPost,
+					Path:       "/login",
+					Extractors: []config.Extractor{{JSONPath: "token", Variable: "token"}},
+				}},
+				{Endpoint: config.Endpoint{
+					Path:    "/profile",
+					Headers: map[string]string{"Authorization": "Bearer {{token}}"},
+				}},
+			},
+		}},
+	}
+
+	collector := metrics.NewCollector()
+	requester, err := buildRequester(cfg, collector, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("buildRequester() error = %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := requester.Do(context.Background()); err != nil {
+			t.Fatalf("iteration %d: Do() error = %v", i, err)
+		}
+	}
+
+	// The second login returned no token, so its profile request must not
+	// see the token extracted in the first iteration.
+	want := []string{"Bearer t1", "Bearer"}
+	if strings.Join(seen, "|") != strings.Join(want, "|") {
+		t.Fatalf("Authorization headers = %q, want %q", seen, want)
+	}
+
+	stats := collector.Stats(time.Second)
+	if stats.Total != 4 {
+		t.Fatalf("expected 4 requests, got %d", stats.Total)
+	}
+	if got := stats.Endpoints["browse/login"].Total; got != 2 {
+		t.Fatalf("expected 2 login requests, got %d (%v)", got, stats.Endpoints)
+	}
+	if got := stats.Endpoints["browse/02-GET /profile"].Total; got != 2 {
+		t.Fatalf("expected default step name, got %v", stats.Endpoints)
+	}
+	journey := stats.Journeys["browse"]
+	if journey.Total != 2 || journey.Failures != 0 {
+		t.Fatalf("unexpected journey stats %+v", journey)
+	}
+	if journey.MinLatency < thinkTime {
+		t.Fatalf("expected journey latency to include think time, got %s", journey.MinLatency)
+	}
+}
+
+func TestScenarioRequester_StopsAtFailingStep(t *testing.T) {
+	var calls []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.URL.Path)
+		if r.URL.Path == "/login" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		TargetURL: server.URL,
+		Scenarios: []config.Scenario{{
+			Name:   "checkout",
+			Weight: 1,
+			Steps: []config.ScenarioStep{
+				{Endpoint: config.Endpoint{Name: "login", Path: "/login"}},
+				{Endpoint: config.Endpoint{Name: "cart", Path: "/cart"}},
+			},
+		}},
+	}
+
+	collector := metrics.NewCollector()
+	requester, err := buildRequester(cfg, collector, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("buildRequester() error = %v", err)
+	}
+
+	err = requester.Do(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "scenario checkout: step checkout/login") {
+		t.Fatalf("expected step failure, got %v", err)
+	}
+	if len(calls) != 1 {
+		t.Fatalf("expected iteration to stop after login, got calls %v", calls)
+	}
+
+	journey := collector.Stats(time.Second).Journeys["checkout"]
+	if journey.Failures != 1 || journey.StatusBuckets["http"]["401"] != 1 {
+		t.Fatalf("unexpected journey stats %+v", journey)
+	}
+}
@@ -2,21 +2,27 @@ package cli
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"path/filepath"
 	"sort"
 	"strings"
 
 	"github.com/spf13/pflag"
 
+	"github.com/torosent/crankfire/internal/baseline"
+	"github.com/torosent/crankfire/internal/output"
+	"github.com/torosent/crankfire/internal/regression"
 	"github.com/torosent/crankfire/internal/store"
 	"github.com/torosent/crankfire/internal/tagfilter"
 )
 
-// RunSession is the entry point for `crankfire session ...`.
-func RunSession(ctx context.Context, st store.Store, args []string, stdout, stderr io.Writer) int {
+// RunSession is the entry point for `crankfire session ...`. dataDir is the
+// store's data directory, where `session compare` resolves run IDs.
+func RunSession(ctx context.Context, st store.Store, dataDir string, args []string, stdout, stderr io.Writer) int {
 	if len(args) == 0 {
-		fmt.Fprintln(stderr, "usage: crankfire session <list|edit> [args]")
+		fmt.Fprintln(stderr, "usage: crankfire session <list|edit|runs|compare|trend> [args]")
 		return ExitUsage
 	}
 	switch args[0] {
@@ -24,6 +30,12 @@ func RunSession(ctx context.Context, st store.Store, args []string, stdout, stde
 		return sessionList(ctx, st, args[1:], stdout, stderr)
 	case "edit":
 		return sessionEdit(ctx, st, args[1:], stdout, stderr)
+	case "runs":
+		return sessionRuns(ctx, st, args[1:], stdout, stderr)
+	case "compare":
+		return sessionCompare(ctx, st, dataDir, args[1:], stdout, stderr)
+	case "trend":
+		return sessionTrend(ctx, st, args[1:], stdout, stderr)
 	default:
 		fmt.Fprintf(stderr, "unknown subcommand: %s\n", args[0])
 		return ExitUsage
@@ -120,6 +132,162 @@ func sessionEdit(ctx context.Context, st store.Store, args []string, stdout, std
 	return ExitOK
 }
 
+func sessionRuns(ctx context.Context, st store.Store, args []string, stdout, stderr io.Writer) int {
+	if len(args) != 1 {
+		fmt.Fprintln(stderr, "usage: crankfire session runs <session-id>")
+		return ExitUsage
+	}
+	runs, err := st.ListRuns(ctx, args[0])
+	if err != nil {
+		fmt.Fprintf(stderr, "list runs: %v\n", err)
+		return ExitRunnerError
+	}
+	fmt.Fprintf(stdout, "%-36s  %-10s  %10s  %8s  %10s\n", "RUN", "STATUS", "REQUESTS", "ERRORS", "P95 (ms)")
+	for _, r := range runs {
+		s := r.Summary
+		fmt.Fprintf(stdout, "%-36s  %-10s  %10d  %8d  %10.2f\n", filepath.Base(r.Dir), r.Status, s.TotalRequests, s.Errors, s.P95Ms)
+	}
+	return ExitOK
+}
+
+// sessionTrend shows p95, p99, error rate and RPS over the last runs of a
+// session, each run checked against the runs before it.
+func sessionTrend(ctx context.Context, st store.Store, args []string, stdout, stderr io.Writer) int {
+	fs := pflag.NewFlagSet("session trend", pflag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var f trendFlags
+	f.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+	if fs.NArg() != 1 || f.last < 1 || f.window < 1 {
+		fmt.Fprintln(stderr, "usage: crankfire session trend <session-id> [--last N] [--history N] [--json | --html PATH]")
+		return ExitUsage
+	}
+	id := fs.Arg(0)
+	name := id
+	if sess, err := st.GetSession(ctx, id); err == nil && sess.Name != "" {
+		name = sess.Name
+	}
+	runs, err := st.ListRuns(ctx, id)
+	if err != nil {
+		fmt.Fprintf(stderr, "list runs: %v\n", err)
+		return ExitRunnerError
+	}
+	series := regression.Trend(name, regression.SessionSamples(runs), f.last, f.window)
+	return writeTrend(name, []regression.Series{series}, f, stdout, stderr)
+}
+
+// sessionCompare compares a run against a baseline run, or with a single run
+// against the runs before it, and exits with ExitRegression when it regressed.
+func sessionCompare(ctx context.Context, st store.Store, dataDir string, args []string, stdout, stderr io.Writer) int {
+	fs := pflag.NewFlagSet("session compare", pflag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var jsonOut bool
+	var tol baseline.Tolerance
+	var history int
+	fs.BoolVar(&jsonOut, "json", false, "print the comparison as JSON")
+	fs.IntVar(&history, "history", regression.DefaultWindow, "with a single run, how many previous runs to compare against")
+	fs.Float64Var(&tol.Latency, "latency-tolerance", 0, "allowed relative latency increase per percentile (default 0.10)")
+	fs.Float64Var(&tol.ErrorRate, "error-tolerance", 0, "allowed absolute error rate increase (default 0.01)")
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+	if fs.NArg() == 1 && history > 0 {
+		return sessionHistory(ctx, st, dataDir, fs.Arg(0), history, jsonOut, stdout, stderr)
+	}
+	if fs.NArg() != 2 {
+		fmt.Fprintln(stderr, "usage: crankfire session compare <baseline-run> <run> [--json] [--latency-tolerance F] [--error-tolerance F]")
+		fmt.Fprintln(stderr, "       crankfire session compare <run> [--history N] [--json]")
+		return ExitUsage
+	}
+	if tol.Latency < 0 || tol.ErrorRate < 0 {
+		fmt.Fprintln(stderr, "tolerances must be non-negative")
+		return ExitUsage
+	}
+	base, err := baseline.Load(dataDir, fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return ExitUsage
+	}
+	current, err := baseline.Load(dataDir, fs.Arg(1))
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return ExitUsage
+	}
+
+	comparison := baseline.Compare(base, current.Stats, tol)
+	if jsonOut {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(comparison); err != nil {
+			fmt.Fprintf(stderr, "encode: %v\n", err)
+			return ExitRunnerError
+		}
+	} else {
+		output.PrintComparison(stdout, comparison)
+	}
+	if comparison.Regressed() {
+		return ExitRegression
+	}
+	return ExitOK
+}
+
+// sessionHistory checks a session run against up to window completed runs
+// of the same session that started before it.
+func sessionHistory(ctx context.Context, st store.Store, dataDir, ref string, window int, jsonOut bool, stdout, stderr io.Writer) int {
+	dir, err := baseline.ResolveRunDir(dataDir, ref)
+	if err != nil {
+		fmt.Fprintf(stderr, "run %s: %v\n", ref, err)
+		return ExitUsage
+	}
+	runs, err := st.ListRuns(ctx, filepath.Base(filepath.Dir(dir)))
+	if err != nil {
+		fmt.Fprintf(stderr, "list runs: %v\n", err)
+		return ExitRunnerError
+	}
+	// ListRuns is newest first, so the history follows the current run.
+	var current *store.Run
+	var samples []store.RunSummary
+	for i := range runs {
+		r := runs[i]
+		if current == nil {
+			if filepath.Base(r.Dir) == filepath.Base(dir) {
+				current = &runs[i]
+			}
+			continue
+		}
+		if r.Status == store.RunStatusCompleted && r.Summary.TotalRequests > 0 && len(samples) < window {
+			samples = append(samples, r.Summary)
+		}
+	}
+	if current == nil {
+		fmt.Fprintf(stderr, "run %s: not found\n", ref)
+		return ExitUsage
+	}
+
+	res := regression.Detect(current.Summary, samples)
+	res.Name = ref
+	if jsonOut {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(res); err != nil {
+			fmt.Fprintf(stderr, "encode: %v\n", err)
+			return ExitRunnerError
+		}
+	} else {
+		fmt.Fprintf(stdout, "History (last %d runs): %s\n", res.Samples, res.Verdict)
+		fmt.Fprintf(stdout, "%-12s  %10s  %10s  %10s  %8s  %s\n", "METRIC", "CURRENT", "MEDIAN", "MAD", "Z", "VERDICT")
+		for _, m := range res.Metrics {
+			fmt.Fprintf(stdout, "%-12s  %10.4g  %10.4g  %10.4g  %+8.2f  %s\n", m.Metric, m.Current, m.Median, m.MAD, m.Score, m.Verdict)
+		}
+	}
+	if res.Verdict == regression.VerdictRegressed {
+		return ExitRegression
+	}
+	return ExitOK
+}
+
 // buildMatchers parses each --tag value into a Matcher; the resulting
 // list is AND-joined (a session must satisfy every Matcher).
 func buildMatchers(exprs []string) ([]tagfilter.Matcher, error) {
@@ -3,10 +3,16 @@ package cli_test
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/torosent/crankfire/internal/baseline"
 	"github.com/torosent/crankfire/internal/cli"
+	"github.com/torosent/crankfire/internal/metrics"
 	"github.com/torosent/crankfire/internal/store"
 )
 
@@ -32,7 +38,7 @@ func TestSessionListFiltersByTag(t *testing.T) {
 		}
 	}
 	var out, errBuf bytes.Buffer
-	code := cli.RunSession(ctx, st, []string{"list", "--tag", "prod"}, &out, &errBuf)
+	code := cli.RunSession(ctx, st, "", []string{"list", "--tag", "prod"}, &out, &errBuf)
 	if code != cli.ExitOK {
 		t.Fatalf("code=%d stderr=%s", code, errBuf.String())
 	}
@@ -53,7 +59,7 @@ func TestSessionListAndOfOrTags(t *testing.T) {
 	}
 	var out, errBuf bytes.Buffer
 	// Two --tag flags = AND of two groups; comma inside = OR
-	code := cli.RunSession(ctx, st, []string{"list", "--tag", "prod", "--tag", "smoke,regression"}, &out, &errBuf)
+	code := cli.RunSession(ctx, st, "", []string{"list", "--tag", "prod", "--tag", "smoke,regression"}, &out, &errBuf)
 	if code != cli.ExitOK {
 		t.Fatalf("code=%d", code)
 	}
@@ -70,7 +76,7 @@ func TestSessionEditAddRemoveTag(t *testing.T) {
 		t.Fatalf("save: %v", err)
 	}
 	var out, errBuf bytes.Buffer
-	code := cli.RunSession(ctx, st, []string{"edit", "01F8MECHZX3TBDSZ7XR9PFE7M0", "--add-tag", "prod", "--add-tag", "smoke", "--remove-tag", "old"}, &out, &errBuf)
+	code := cli.RunSession(ctx, st, "", []string{"edit", "01F8MECHZX3TBDSZ7XR9PFE7M0", "--add-tag", "prod", "--add-tag", "smoke", "--remove-tag", "old"}, &out, &errBuf)
 	if code != cli.ExitOK {
 		t.Fatalf("code=%d stderr=%s", code, errBuf.String())
 	}
@@ -89,8 +95,180 @@ func TestSessionEditInvalidTagExitsUsage(t *testing.T) {
 	ctx := context.Background()
 	_ = st.SaveSession(ctx, store.Session{ID: "01F8MECHZX3TBDSZ7XR9PFE7M0", Name: "a"})
 	var out, errBuf bytes.Buffer
-	code := cli.RunSession(ctx, st, []string{"edit", "01F8MECHZX3TBDSZ7XR9PFE7M0", "--add-tag", "bad tag!"}, &out, &errBuf)
+	code := cli.RunSession(ctx, st, "", []string{"edit", "01F8MECHZX3TBDSZ7XR9PFE7M0", "--add-tag", "bad tag!"}, &out, &errBuf)
 	if code != cli.ExitUsage {
 		t.Errorf("code=%d, want ExitUsage", code)
 	}
 }
+
+func writeRunResult(t *testing.T, dataDir, sessionID, runID string, stats metrics.Stats) {
+	t.Helper()
+	dir := filepath.Join(dataDir, "runs", sessionID, runID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	run, _ := json.Marshal(store.Run{SessionID: sessionID, Status: store.RunStatusCompleted})
+	result, _ := json.Marshal(stats)
+	if err := os.WriteFile(filepath.Join(dir, "run.json"), run, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "result.json"), result, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSessionCompare(t *testing.T) {
+	dataDir := t.TempDir()
+	st, err := store.NewFS(dataDir)
+	if err != nil {
+		t.Fatalf("NewFS: %v", err)
+	}
+	ctx := context.Background()
+	latency := func(p95 float64) metrics.Stats {
+		return metrics.Stats{EndpointStats: metrics.EndpointStats{Total: 100, P50LatencyMs: 50, P90LatencyMs: p95, P95LatencyMs: p95, P99LatencyMs: p95}}
+	}
+	writeRunResult(t, dataDir, "sess", "run1", latency(100))
+	writeRunResult(t, dataDir, "sess", "run2", latency(105))
+	writeRunResult(t, dataDir, "sess", "run3", latency(200))
+
+	var out, errBuf bytes.Buffer
+	code := cli.RunSession(ctx, st, dataDir, []string{"compare", "run1", "run2"}, &out, &errBuf)
+	if code != cli.ExitOK {
+		t.Fatalf("code=%d stderr=%s", code, errBuf.String())
+	}
+	if !strings.Contains(out.String(), "Verdict: unchanged") {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+
+	out.Reset()
+	code = cli.RunSession(ctx, st, dataDir, []string{"compare", "sess/run1", "run3", "--json"}, &out, &errBuf)
+	if code != cli.ExitRegression {
+		t.Fatalf("code=%d, want ExitRegression; stderr=%s", code, errBuf.String())
+	}
+	var comparison baseline.Comparison
+	if err := json.Unmarshal(out.Bytes(), &comparison); err != nil {
+		t.Fatalf("decode: %v\n%s", err, out.String())
+	}
+	if comparison.Verdict != baseline.VerdictRegressed || comparison.Baseline != "sess/run1" {
+		t.Errorf("comparison = %+v", comparison)
+	}
+
+	code = cli.RunSession(ctx, st, dataDir, []string{"compare", "run1", "run3", "--latency-tolerance", "1.5"}, &out, &errBuf)
+	if code != cli.ExitOK {
+		t.Errorf("code=%d with a 150%% tolerance, want ExitOK", code)
+	}
+	if code := cli.RunSession(ctx, st, dataDir, []string{"compare"}, &out, &errBuf); code != cli.ExitUsage {
+		t.Errorf("code=%d for a missing run argument, want ExitUsage", code)
+	}
+}
+
+func TestSessionCompareHistory(t *testing.T) {
+	dataDir := t.TempDir()
+	st, err := store.NewFS(dataDir)
+	if err != nil {
+		t.Fatalf("NewFS: %v", err)
+	}
+	ctx := context.Background()
+	var last string
+	for i, p95 := range []float64{100, 102, 98, 101, 180} {
+		last = time.Date(2026, 4, 19, 10, i, 0, 0, time.UTC).Format(time.RFC3339Nano)
+		dir := filepath.Join(dataDir, "runs", "sess", last)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		run, _ := json.Marshal(store.Run{
+			SessionID: "sess",
+			StartedAt: time.Date(2026, 4, 19, 10, i, 0, 0, time.UTC),
+			Status:    store.RunStatusCompleted,
+			Summary:   store.RunSummary{TotalRequests: 1000, P50Ms: 50, P95Ms: p95, P99Ms: 200},
+		})
+		if err := os.WriteFile(filepath.Join(dir, "run.json"), run, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out, errBuf bytes.Buffer
+	code := cli.RunSession(ctx, st, dataDir, []string{"compare", last}, &out, &errBuf)
+	if code != cli.ExitRegression {
+		t.Fatalf("code=%d, want ExitRegression; stderr=%s\n%s", code, errBuf.String(), out.String())
+	}
+	if !strings.Contains(out.String(), "History (last 4 runs): regressed") {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+
+	out.Reset()
+	code = cli.RunSession(ctx, st, dataDir, []string{"compare", last, "--history", "2", "--json"}, &out, &errBuf)
+	if code != cli.ExitOK {
+		t.Fatalf("code=%d stderr=%s", code, errBuf.String())
+	}
+	var res store.RegressionResult
+	if err := json.Unmarshal(out.Bytes(), &res); err != nil {
+		t.Fatalf("decode: %v\n%s", err, out.String())
+	}
+	if res.Samples != 2 || res.Verdict != "inconclusive" {
+		t.Errorf("result = %+v, want 2 samples and an inconclusive verdict", res)
+	}
+}
+
+func TestSessionTrend(t *testing.T) {
+	dataDir := t.TempDir()
+	st, err := store.NewFS(dataDir)
+	if err != nil {
+		t.Fatalf("NewFS: %v", err)
+	}
+	ctx := context.Background()
+	for i, p95 := range []float64{100, 102, 98, 101, 180} {
+		started := time.Date(2026, 4, 19, 10, i, 0, 0, time.UTC)
+		dir := filepath.Join(dataDir, "runs", "sess", started.Format(time.RFC3339Nano))
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		run, _ := json.Marshal(store.Run{
+			SessionID: "sess",
+			StartedAt: started,
+			Status:    store.RunStatusCompleted,
+			Summary:   store.RunSummary{TotalRequests: 1000, DurationSec: 10, P50Ms: 50, P95Ms: p95, P99Ms: 200},
+		})
+		if err := os.WriteFile(filepath.Join(dir, "run.json"), run, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out, errBuf bytes.Buffer
+	if code := cli.RunSession(ctx, st, dataDir, []string{"trend", "sess"}, &out, &errBuf); code != cli.ExitOK {
+		t.Fatalf("code=%d stderr=%s", code, errBuf.String())
+	}
+	if !strings.Contains(out.String(), "sess (5 runs, 1 regressed)") || !strings.Contains(out.String(), "! 2026-04-19 10:04") {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+
+	out.Reset()
+	if code := cli.RunSession(ctx, st, dataDir, []string{"trend", "sess", "--last", "2", "--json"}, &out, &errBuf); code != cli.ExitOK {
+		t.Fatalf("code=%d stderr=%s", code, errBuf.String())
+	}
+	var series []struct {
+		Points []struct {
+			RPS     float64 `json:"rps"`
+			Verdict string  `json:"verdict"`
+		} `json:"points"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &series); err != nil {
+		t.Fatalf("decode: %v\n%s", err, out.String())
+	}
+	if len(series) != 1 || len(series[0].Points) != 2 || series[0].Points[1].Verdict != "regressed" || series[0].Points[1].RPS != 100 {
+		t.Errorf("series = %+v", series)
+	}
+
+	htmlPath := filepath.Join(t.TempDir(), "trend.html")
+	out.Reset()
+	if code := cli.RunSession(ctx, st, dataDir, []string{"trend", "sess", "--html", htmlPath}, &out, &errBuf); code != cli.ExitOK {
+		t.Fatalf("code=%d stderr=%s", code, errBuf.String())
+	}
+	if data, err := os.ReadFile(htmlPath); err != nil || !strings.Contains(string(data), "1 regressed") {
+		t.Errorf("html report: %v\n%s", err, data)
+	}
+
+	if code := cli.RunSession(ctx, st, dataDir, []string{"trend"}, &out, &errBuf); code != cli.ExitUsage {
+		t.Errorf("code=%d for a missing session argument, want ExitUsage", code)
+	}
+}
@@ -17,10 +17,13 @@ import (
 	"gopkg.in/yaml.v3"
 
 	"github.com/torosent/crankfire/internal/config"
+	"github.com/torosent/crankfire/internal/metrics"
 	"github.com/torosent/crankfire/internal/output/setreport"
+	"github.com/torosent/crankfire/internal/regression"
 	"github.com/torosent/crankfire/internal/setrunner"
 	"github.com/torosent/crankfire/internal/store"
 	tplrender "github.com/torosent/crankfire/internal/template"
+	"github.com/torosent/crankfire/internal/threshold"
 )
 
 // Exit codes.
@@ -29,13 +32,14 @@ const (
 	ExitUsage           = 1
 	ExitThresholdFailed = 2
 	ExitRunnerError     = 3
+	ExitRegression      = 4
 )
 
 // RunSet is the entry point invoked from cmd/crankfire/main.go for `set ...`.
 // stdout / stderr are injected so tests can capture them.
 func RunSet(ctx context.Context, st store.Store, args []string, stdout, stderr io.Writer) int {
 	if len(args) == 0 {
-		fmt.Fprintln(stderr, "usage: crankfire set <list|show|run|new|diff> [args]")
+		fmt.Fprintln(stderr, "usage: crankfire set <list|show|run|new|diff|trend> [args]")
 		return ExitUsage
 	}
 	switch args[0] {
@@ -49,6 +53,8 @@ func RunSet(ctx context.Context, st store.Store, args []string, stdout, stderr i
 		return setNew(ctx, st, args[1:], stdout, stderr)
 	case "diff":
 		return setDiff(ctx, st, args[1:], stdout, stderr)
+	case "trend":
+		return setTrend(ctx, st, args[1:], stdout, stderr)
 	default:
 		fmt.Fprintf(stderr, "unknown subcommand: %s\n", args[0])
 		return ExitUsage
@@ -140,6 +146,7 @@ func setRun(ctx context.Context, st store.Store, args []string, stdout, stderr i
 	fs.SetOutput(stderr)
 	jsonOut := fs.Bool("json", false, "emit final SetRun as JSON to stdout")
 	htmlPath := fs.String("html", "", "write HTML report to this path")
+	junitPath := fs.String("junit", "", "write JUnit XML report to this path")
 	thresholds := fs.StringArray("threshold", nil, "extra threshold (metric:op:value[:scope]); repeatable")
 	overrides := fs.StringArray("override", nil, "override (item.field=value); repeatable")
 	if err := fs.Parse(args); err != nil {
@@ -209,6 +216,9 @@ func setRun(ctx context.Context, st store.Store, args []string, stdout, stderr i
 			}
 			fmt.Fprintf(stdout, "  %s %s %s %v (actual %.3f) [%s]\n", mark, th.Metric, th.Op, th.Value, th.Actual, th.Scope)
 		}
+		if h := run.Regression; h != nil {
+			fmt.Fprintf(stdout, "  History (last %d runs): %s\n", h.Window, h.Verdict)
+		}
 	}
 	if *htmlPath != "" {
 		data, err := setreport.Render(run)
@@ -226,6 +236,22 @@ func setRun(ctx context.Context, st store.Store, args []string, stdout, stderr i
 		}
 	}
 
+	if *junitPath != "" {
+		data, err := setreport.RenderJUnit(run)
+		if err != nil {
+			fmt.Fprintf(stderr, "render junit: %v\n", err)
+			return ExitRunnerError
+		}
+		if err := os.MkdirAll(filepath.Dir(*junitPath), 0o755); err != nil {
+			fmt.Fprintf(stderr, "mkdir junit: %v\n", err)
+			return ExitRunnerError
+		}
+		if err := os.WriteFile(*junitPath, data, 0o644); err != nil {
+			fmt.Fprintf(stderr, "write junit: %v\n", err)
+			return ExitRunnerError
+		}
+	}
+
 	if !run.AllThresholdsPassed {
 		return ExitThresholdFailed
 	}
@@ -238,22 +264,36 @@ func setRun(ctx context.Context, st store.Store, args []string, stdout, stderr i
 type cliBuilderAdapter struct{}
 
 func (cliBuilderAdapter) Build(ctx context.Context, cfg config.Config, _ string) (setrunner.ItemRun, error) {
+	thresholds, err := threshold.ParseMultiple(cfg.Thresholds)
+	if err != nil {
+		return setrunner.ItemRun{}, fmt.Errorf("threshold parsing failed: %w", err)
+	}
 	rnr, collector, cleanup, err := BuildRunner(ctx, cfg)
 	if err != nil {
 		return setrunner.ItemRun{}, err
 	}
 	return setrunner.ItemRun{
 		Run: func(ctx context.Context) (store.RunSummary, error) {
-			result := rnr.Run(ctx)
+			// Start the clock for rate thresholds and abort delays, and
+			// record the time series persisted with the item's artifacts.
+			collector.Start()
+			stopSnapshots := startSnapshots(ctx, collector)
+			result, breach := RunWithThresholds(ctx, rnr, collector, thresholds)
+			stopSnapshots()
 			stats := collector.Stats(result.Duration)
-			return store.RunSummary{
+			summary := store.RunSummary{
 				TotalRequests: stats.Total,
 				Errors:        stats.Failures,
 				DurationSec:   result.Duration.Seconds(),
 				P50Ms:         stats.P50LatencyMs,
 				P95Ms:         stats.P95LatencyMs,
 				P99Ms:         stats.P99LatencyMs,
-			}, nil
+			}
+			if breach != nil {
+				summary.ErrorMessage = breach.Error()
+				return summary, breach
+			}
+			return summary, nil
 		},
 		Snapshot: func() setrunner.MetricSnapshot {
 			stats := collector.Stats(0)
@@ -274,6 +314,10 @@ func (cliBuilderAdapter) Build(ctx context.Context, cfg config.Config, _ string)
 				TotalErrors: float64(stats.Failures),
 			}
 		},
+		Artifacts: func() (*metrics.Measurements, []metrics.DataPoint, error) {
+			m, err := collector.Measurements()
+			return m, collector.History(), err
+		},
 		Cleanup: cleanup,
 	}, nil
 }
@@ -470,41 +514,62 @@ func stripTemplateMarker(in []byte) []byte {
 	return []byte(strings.Join(out, "\n"))
 }
 
-// setDiff implements `crankfire set diff <run-id-a> <run-id-b>`.
+// setDiff implements `crankfire set diff <run-id-a> <run-id-b>` and
+// `crankfire set diff <run-id>`. Both check the (last) run against the
+// previous runs of its set; the two-run form also diffs the runs directly.
 // Outputs a text table by default; --json for JSON, --html PATH for standalone HTML.
-func setDiff(ctx context.Context, _ store.Store, args []string, stdout, stderr io.Writer) int {
+func setDiff(ctx context.Context, st store.Store, args []string, stdout, stderr io.Writer) int {
 	fs := pflag.NewFlagSet("set diff", pflag.ContinueOnError)
 	fs.SetOutput(stderr)
 	jsonOut := fs.Bool("json", false, "emit JSON instead of text table")
 	htmlPath := fs.String("html", "", "write standalone HTML to PATH")
+	history := fs.Int("history", 0, "number of previous runs to check for regressions (0 = set's regression_window or 5)")
 	if err := fs.Parse(args); err != nil {
 		return ExitUsage
 	}
-	if fs.NArg() != 2 {
-		fmt.Fprintln(stderr, "usage: crankfire set diff <run-id-a> <run-id-b> [--json | --html PATH]")
+	if fs.NArg() < 1 || fs.NArg() > 2 || *history < 0 {
+		fmt.Fprintln(stderr, "usage: crankfire set diff [<run-id-a>] <run-id-b> [--history N] [--json | --html PATH]")
 		return ExitUsage
 	}
-	idA, idB := fs.Arg(0), fs.Arg(1)
 	dataDir, err := store.ResolveDataDir("")
 	if err != nil {
 		fmt.Fprintf(stderr, "data dir: %v\n", err)
 		return ExitRunnerError
 	}
-	runA, setA, err := resolveRunID(dataDir, idA)
-	if err != nil {
-		fmt.Fprintf(stderr, "resolve %s: %v\n", idA, err)
-		return ExitUsage
-	}
-	runB, setB, err := resolveRunID(dataDir, idB)
+	idB := fs.Arg(fs.NArg() - 1)
+	runB, setID, err := resolveRunID(dataDir, idB)
 	if err != nil {
 		fmt.Fprintf(stderr, "resolve %s: %v\n", idB, err)
 		return ExitUsage
 	}
-	if setA != setB {
-		fmt.Fprintf(stderr, "runs belong to different sets (%s vs %s)\n", setA, setB)
-		return ExitUsage
+	res := setrunner.DiffResult{B: runB}
+	if fs.NArg() == 2 {
+		idA := fs.Arg(0)
+		runA, setA, err := resolveRunID(dataDir, idA)
+		if err != nil {
+			fmt.Fprintf(stderr, "resolve %s: %v\n", idA, err)
+			return ExitUsage
+		}
+		if setA != setID {
+			fmt.Fprintf(stderr, "runs belong to different sets (%s vs %s)\n", setA, setID)
+			return ExitUsage
+		}
+		res = setrunner.Diff(runA, runB)
 	}
-	res := setrunner.Diff(runA, runB)
+
+	window := *history
+	if window == 0 {
+		if set, err := st.GetSet(ctx, setID); err == nil {
+			window = set.RegressionWindow
+		}
+	}
+	runs, err := st.ListSetRuns(ctx, setID)
+	if err != nil {
+		fmt.Fprintf(stderr, "list runs: %v\n", err)
+		return ExitRunnerError
+	}
+	res.History = setrunner.DetectRegression(runB, setrunner.PreviousRuns(runs, runB, window))
+
 	if *jsonOut {
 		enc := json.NewEncoder(stdout)
 		enc.SetIndent("", "  ")
@@ -526,6 +591,37 @@ func setDiff(ctx context.Context, _ store.Store, args []string, stdout, stderr i
 	return ExitOK
 }
 
+// setTrend implements `crankfire set trend <set-id>`: the p95, p99, error
+// rate and RPS of every item over the set's last runs, each run checked
+// against the runs before it.
+func setTrend(ctx context.Context, st store.Store, args []string, stdout, stderr io.Writer) int {
+	fs := pflag.NewFlagSet("set trend", pflag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var f trendFlags
+	f.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+	if fs.NArg() != 1 || f.last < 1 || f.window < 1 {
+		fmt.Fprintln(stderr, "usage: crankfire set trend <set-id> [--last N] [--history N] [--json | --html PATH]")
+		return ExitUsage
+	}
+	set, err := st.GetSet(ctx, fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "get set: %v\n", err)
+		return ExitUsage
+	}
+	if !fs.Changed("history") && set.RegressionWindow > 0 {
+		f.window = set.RegressionWindow
+	}
+	runs, err := st.ListSetRuns(ctx, set.ID)
+	if err != nil {
+		fmt.Fprintf(stderr, "list runs: %v\n", err)
+		return ExitRunnerError
+	}
+	return writeTrend(set.Name, setrunner.ItemTrends(runs, f.last, f.window), f, stdout, stderr)
+}
+
 func resolveRunID(dataDir, runID string) (store.SetRun, string, error) {
 	root := filepath.Join(dataDir, "runs", "sets")
 	matches, err := filepath.Glob(filepath.Join(root, "*", runID, "set-run.json"))
@@ -552,20 +648,48 @@ func resolveRunID(dataDir, runID string) (store.SetRun, string, error) {
 }
 
 func writeDiffText(w io.Writer, r setrunner.DiffResult) {
-	fmt.Fprintf(w, "verdict: %s\n", r.OverallVerdict)
-	fmt.Fprintf(w, "%-20s  %-10s  %12s  %12s  %12s  %12s  %12s\n",
-		"item", "stage", "dP50ms", "dP95ms", "dP99ms", "dErrRate", "dRPS")
-	for _, row := range r.Rows {
-		marker := ""
-		if !row.APresent {
-			marker = " (B-only)"
-		} else if !row.BPresent {
-			marker = " (A-only)"
-		}
-		fmt.Fprintf(w, "%-20s  %-10s  %12.2f  %12.2f  %12.2f  %12.4f  %12.2f%s\n",
-			row.ItemName, row.Stage,
-			row.P50DeltaMs, row.P95DeltaMs, row.P99DeltaMs,
-			row.ErrRateDelta, row.RPSDelta, marker)
+	if r.OverallVerdict != "" {
+		fmt.Fprintf(w, "verdict: %s\n", r.OverallVerdict)
+		fmt.Fprintf(w, "%-20s  %-10s  %12s  %12s  %12s  %12s  %12s\n",
+			"item", "stage", "dP50ms", "dP95ms", "dP99ms", "dErrRate", "dRPS")
+		for _, row := range r.Rows {
+			marker := ""
+			if !row.APresent {
+				marker = " (B-only)"
+			} else if !row.BPresent {
+				marker = " (A-only)"
+			}
+			fmt.Fprintf(w, "%-20s  %-10s  %12.2f  %12.2f  %12.2f  %12.4f  %12.2f%s\n",
+				row.ItemName, row.Stage,
+				row.P50DeltaMs, row.P95DeltaMs, row.P99DeltaMs,
+				row.ErrRateDelta, row.RPSDelta, marker)
+		}
+		fmt.Fprintln(w)
+	}
+	writeHistoryText(w, r.History)
+}
+
+// writeHistoryText prints each item's regression verdict with the robust
+// z-score of every metric against its history.
+func writeHistoryText(w io.Writer, h *store.SetRegression) {
+	if h == nil {
+		fmt.Fprintln(w, "history: no previous runs")
+		return
+	}
+	fmt.Fprintf(w, "history verdict (last %d runs): %s\n", h.Window, h.Verdict)
+	fmt.Fprintf(w, "%-20s  %-12s  %7s", "item", "verdict", "samples")
+	if len(h.Items) > 0 {
+		for _, m := range h.Items[0].Metrics {
+			fmt.Fprintf(w, "  %12s", "z "+m.Metric)
+		}
+	}
+	fmt.Fprintln(w)
+	for _, it := range h.Items {
+		fmt.Fprintf(w, "%-20s  %-12s  %7d", it.Name, it.Verdict, it.Samples)
+		for _, m := range it.Metrics {
+			fmt.Fprintf(w, "  %+12.2f", m.Score)
+		}
+		fmt.Fprintln(w)
 	}
 }
 
@@ -579,18 +703,28 @@ body{font:14px -apple-system,BlinkMacSystemFont,sans-serif;padding:24px;color:#2
 .verdict.regressed{background:#f8d7da;color:#721c24}
 .verdict.mixed{background:#fff3cd;color:#856404}
 .verdict.unchanged{background:#e2e3e5;color:#383d41}
+.verdict.inconclusive{background:#d6e4f0;color:#1b4965}
 table{border-collapse:collapse;margin-top:16px;width:100%}
 th,td{padding:6px 10px;border-bottom:1px solid #eee;text-align:right}
 th:first-child,td:first-child,th:nth-child(2),td:nth-child(2){text-align:left}
 .bad{color:#c42;font-weight:600}
 .good{color:#262;font-weight:600}
 </style></head><body>`)
-	fmt.Fprintf(&buf, "<h1>Crankfire diff &mdash; <span class=\"verdict %s\">%s</span></h1>\n",
-		html.EscapeString(r.OverallVerdict), html.EscapeString(r.OverallVerdict))
+	if r.OverallVerdict != "" {
+		fmt.Fprintf(&buf, "<h1>Crankfire diff &mdash; <span class=\"verdict %s\">%s</span></h1>\n",
+			html.EscapeString(r.OverallVerdict), html.EscapeString(r.OverallVerdict))
+		writeDiffRowsHTML(&buf, r.Rows)
+	}
+	writeHistoryHTML(&buf, r.History)
+	buf.WriteString("</body></html>\n")
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+func writeDiffRowsHTML(buf *bytes.Buffer, rows []setrunner.DiffRow) {
 	buf.WriteString("<table><thead><tr><th>item</th><th>stage</th><th>dP50ms</th><th>dP95ms</th><th>dP99ms</th><th>dErrRate</th><th>dRPS</th></tr></thead><tbody>\n")
-	for _, row := range r.Rows {
+	for _, row := range rows {
 		buf.WriteString("<tr>")
-		fmt.Fprintf(&buf, "<td>%s</td><td>%s</td>", html.EscapeString(row.ItemName), html.EscapeString(row.Stage))
+		fmt.Fprintf(buf, "<td>%s</td><td>%s</td>", html.EscapeString(row.ItemName), html.EscapeString(row.Stage))
 		for _, v := range []float64{row.P50DeltaMs, row.P95DeltaMs, row.P99DeltaMs} {
 			cls := ""
 			if v > 0 {
@@ -598,7 +732,7 @@ th:first-child,td:first-child,th:nth-child(2),td:nth-child(2){text-align:left}
 			} else if v < 0 {
 				cls = "good"
 			}
-			fmt.Fprintf(&buf, "<td class=\"%s\">%+.2f</td>", cls, v)
+			fmt.Fprintf(buf, "<td class=\"%s\">%+.2f</td>", cls, v)
 		}
 		cls := ""
 		if row.ErrRateDelta > 0 {
@@ -606,16 +740,47 @@ th:first-child,td:first-child,th:nth-child(2),td:nth-child(2){text-align:left}
 		} else if row.ErrRateDelta < 0 {
 			cls = "good"
 		}
-		fmt.Fprintf(&buf, "<td class=\"%s\">%+.4f</td>", cls, row.ErrRateDelta)
+		fmt.Fprintf(buf, "<td class=\"%s\">%+.4f</td>", cls, row.ErrRateDelta)
 		cls = ""
 		if row.RPSDelta > 0 {
 			cls = "good"
 		} else if row.RPSDelta < 0 {
 			cls = "bad"
 		}
-		fmt.Fprintf(&buf, "<td class=\"%s\">%+.2f</td>", cls, row.RPSDelta)
+		fmt.Fprintf(buf, "<td class=\"%s\">%+.2f</td>", cls, row.RPSDelta)
 		buf.WriteString("</tr>\n")
 	}
-	buf.WriteString("</tbody></table></body></html>\n")
-	return os.WriteFile(path, buf.Bytes(), 0o644)
+	buf.WriteString("</tbody></table>\n")
+}
+
+func writeHistoryHTML(buf *bytes.Buffer, h *store.SetRegression) {
+	if h == nil {
+		buf.WriteString("<h2>History</h2><p>No previous runs.</p>\n")
+		return
+	}
+	fmt.Fprintf(buf, "<h2>History &mdash; last %d runs <span class=\"verdict %s\">%s</span></h2>\n",
+		h.Window, html.EscapeString(h.Verdict), html.EscapeString(h.Verdict))
+	buf.WriteString("<table><thead><tr><th>item</th><th>verdict</th><th>samples</th>")
+	if len(h.Items) > 0 {
+		for _, m := range h.Items[0].Metrics {
+			fmt.Fprintf(buf, "<th>z %s</th>", html.EscapeString(m.Metric))
+		}
+	}
+	buf.WriteString("</tr></thead><tbody>\n")
+	for _, it := range h.Items {
+		fmt.Fprintf(buf, "<tr><td>%s</td><td><span class=\"verdict %s\">%s</span></td><td>%d</td>",
+			html.EscapeString(it.Name), html.EscapeString(it.Verdict), html.EscapeString(it.Verdict), it.Samples)
+		for _, m := range it.Metrics {
+			cls := ""
+			switch m.Verdict {
+			case regression.VerdictRegressed:
+				cls = "bad"
+			case regression.VerdictImproved:
+				cls = "good"
+			}
+			fmt.Fprintf(buf, "<td class=\"%s\">%+.2f</td>", cls, m.Score)
+		}
+		buf.WriteString("</tr>\n")
+	}
+	buf.WriteString("</tbody></table>\n")
 }
@@ -3,6 +3,9 @@ package cli_test
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
@@ -10,6 +13,8 @@ import (
 	"time"
 
 	"github.com/torosent/crankfire/internal/cli"
+	"github.com/torosent/crankfire/internal/config"
+	"github.com/torosent/crankfire/internal/setrunner"
 	"github.com/torosent/crankfire/internal/store"
 )
 
@@ -222,3 +227,167 @@ if !strings.Contains(errBuf.String(), "ambiguous") {
 t.Errorf("stderr should mention ambiguity: %q", errBuf.String())
 }
 }
+
+func TestSetDiffSingleRunChecksHistory(t *testing.T) {
+	dir := t.TempDir()
+	st, _ := store.NewFS(dir)
+	ctx := context.Background()
+	setID := "01F8MECHZX3TBDSZ7XR9PFE7S5"
+	var last string
+	for i, p95 := range []float64{100, 102, 98, 101, 180} {
+		started := time.Date(2026, 4, 19, 10, i, 0, 0, time.UTC)
+		last = started.Format("2006-01-02T15-04-05.000Z")
+		run := store.SetRun{SetID: setID, StartedAt: started, Status: store.SetRunCompleted, Stages: []store.StageResult{{
+			Name: "s",
+			Items: []store.ItemResult{{Name: "api", Status: store.RunStatusCompleted,
+				Summary: store.RunSummary{TotalRequests: 1000, P50Ms: 50, P95Ms: p95, P99Ms: 200}}},
+		}}}
+		data, _ := json.Marshal(run)
+		d := filepath.Join(dir, "runs", "sets", setID, last)
+		_ = os.MkdirAll(d, 0o755)
+		_ = os.WriteFile(filepath.Join(d, "set-run.json"), data, 0o644)
+	}
+	t.Setenv("CRANKFIRE_DATA_DIR", dir)
+
+	var out, errBuf bytes.Buffer
+	code := cli.RunSet(ctx, st, []string{"diff", last}, &out, &errBuf)
+	if code != cli.ExitOK {
+		t.Fatalf("code=%d stderr=%s", code, errBuf.String())
+	}
+	if !strings.Contains(out.String(), "history verdict (last 4 runs): regressed") {
+		t.Errorf("missing history verdict:\n%s", out.String())
+	}
+
+	out.Reset()
+	code = cli.RunSet(ctx, st, []string{"diff", "--history", "2", last}, &out, &errBuf)
+	if code != cli.ExitOK {
+		t.Fatalf("code=%d stderr=%s", code, errBuf.String())
+	}
+	if !strings.Contains(out.String(), "history verdict (last 2 runs): inconclusive") {
+		t.Errorf("expected an inconclusive verdict with 2 runs:\n%s", out.String())
+	}
+}
+
+func TestSetTrend(t *testing.T) {
+	dir := t.TempDir()
+	st, _ := store.NewFS(dir)
+	ctx := context.Background()
+	setID := "01F8MECHZX3TBDSZ7XR9PFE7S6"
+	_ = st.SaveSession(ctx, store.Session{ID: "01F8MECHZX3TBDSZ7XR9PFE7M1", Name: "s"})
+	if err := st.SaveSet(ctx, store.Set{ID: setID, Name: "checkout", Stages: []store.Stage{{Name: "s", Items: []store.SetItem{{Name: "api", SessionID: "01F8MECHZX3TBDSZ7XR9PFE7M1"}}}}}); err != nil {
+		t.Fatal(err)
+	}
+	for i, p95 := range []float64{100, 102, 98, 101, 180} {
+		started := time.Date(2026, 4, 19, 10, i, 0, 0, time.UTC)
+		run := store.SetRun{SetID: setID, StartedAt: started, Status: store.SetRunCompleted, Stages: []store.StageResult{{
+			Name: "s",
+			Items: []store.ItemResult{{Name: "api", Status: store.RunStatusCompleted,
+				Summary: store.RunSummary{TotalRequests: 1000, DurationSec: 10, P50Ms: 50, P95Ms: p95, P99Ms: 200}}},
+		}}}
+		data, _ := json.Marshal(run)
+		d := filepath.Join(dir, "runs", "sets", setID, started.Format("2006-01-02T15-04-05.000Z"))
+		_ = os.MkdirAll(d, 0o755)
+		_ = os.WriteFile(filepath.Join(d, "set-run.json"), data, 0o644)
+	}
+
+	var out, errBuf bytes.Buffer
+	if code := cli.RunSet(ctx, st, []string{"trend", setID}, &out, &errBuf); code != cli.ExitOK {
+		t.Fatalf("code=%d stderr=%s", code, errBuf.String())
+	}
+	if !strings.Contains(out.String(), "api (5 runs, 1 regressed)") {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+
+	out.Reset()
+	if code := cli.RunSet(ctx, st, []string{"trend", setID, "--history", "2"}, &out, &errBuf); code != cli.ExitOK {
+		t.Fatalf("code=%d stderr=%s", code, errBuf.String())
+	}
+	if !strings.Contains(out.String(), "api (5 runs, 0 regressed)") {
+		t.Errorf("with 2 runs of history every verdict should be inconclusive:\n%s", out.String())
+	}
+
+	if code := cli.RunSet(ctx, st, []string{"trend", "missing"}, &out, &errBuf); code != cli.ExitUsage {
+		t.Errorf("code=%d for an unknown set, want ExitUsage", code)
+	}
+}
+
+// runSetItem runs a one-item set of cfg with the production builder.
+func runSetItem(t *testing.T, cfg config.Config) store.ItemResult {
+	t.Helper()
+	ctx := context.Background()
+	st, err := store.NewFS(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFS: %v", err)
+	}
+	if err := st.SaveSession(ctx, store.Session{Name: "item", Config: cfg}); err != nil {
+		t.Fatalf("SaveSession: %v", err)
+	}
+	sessions, _ := st.ListSessions(ctx)
+	set := store.Set{
+		Name:   "live",
+		Stages: []store.Stage{{Name: "s1", Items: []store.SetItem{{Name: "a", SessionID: sessions[0].ID}}}},
+	}
+	if err := st.SaveSet(ctx, set); err != nil {
+		t.Fatalf("SaveSet: %v", err)
+	}
+	sets, _ := st.ListSets(ctx)
+	run, err := setrunner.New(st, cli.NewSetBuilder()).Run(ctx, sets[0].ID, nil)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(run.Stages) != 1 || len(run.Stages[0].Items) != 1 {
+		t.Fatalf("stages: %+v", run.Stages)
+	}
+	return run.Stages[0].Items[0]
+}
+
+func TestSetItemAbortsOnDelayedThreshold(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	item := runSetItem(t, config.Config{
+		TargetURL:   server.URL,
+		Concurrency: 1,
+		Rate:        50,
+		Duration:    10 * time.Second,
+		Timeout:     time.Second,
+		Thresholds:  []string{"http_req_failed:rate < 0.1 abort_on_fail delay=1s"},
+	})
+	if item.Status != store.RunStatusFailed {
+		t.Errorf("status = %s, want %s", item.Status, store.RunStatusFailed)
+	}
+	if !strings.Contains(item.Error, "aborted after") {
+		t.Errorf("error = %q, want the abort breach", item.Error)
+	}
+	if item.Summary.DurationSec >= 5 {
+		t.Errorf("item ran %.1fs, want it stopped soon after the 1s delay", item.Summary.DurationSec)
+	}
+}
+
+func TestSetItemPersistsTimeSeries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	item := runSetItem(t, config.Config{
+		TargetURL:   server.URL,
+		Concurrency: 1,
+		Rate:        20,
+		Duration:    1500 * time.Millisecond,
+		Timeout:     time.Second,
+	})
+	if item.RunDir == "" {
+		t.Fatalf("no artifacts written for item: %+v", item)
+	}
+	series, err := store.ReadTimeSeries(item.RunDir)
+	if err != nil {
+		t.Fatalf("ReadTimeSeries: %v", err)
+	}
+	if len(series) == 0 {
+		t.Fatal("time series is empty")
+	}
+	if last := series[len(series)-1]; last.TotalRequests == 0 {
+		t.Errorf("final data point has no requests: %+v", last)
+	}
+}
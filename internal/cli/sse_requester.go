@@ -2,20 +2,29 @@ package cli
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/torosent/crankfire/internal/auth"
 	"github.com/torosent/crankfire/internal/config"
+	"github.com/torosent/crankfire/internal/extractor"
 	"github.com/torosent/crankfire/internal/httpclient"
 	"github.com/torosent/crankfire/internal/metrics"
 	"github.com/torosent/crankfire/internal/placeholders"
 	"github.com/torosent/crankfire/internal/pool"
+	"github.com/torosent/crankfire/internal/resolver"
 	"github.com/torosent/crankfire/internal/sse"
 	"github.com/torosent/crankfire/internal/tracing"
 )
 
+// maxSSEEventTypes caps the distinct event_type_<type> metrics when
+// event_types is unset; later types are counted under event_type_other.
+const maxSSEEventTypes = 32
+
 type sseRequester struct {
 	cfg       *config.SSEConfig
 	target    string
@@ -25,6 +34,11 @@ type sseRequester struct {
 	feeder    httpclient.Feeder
 	connPool  *pool.ConnectionPool
 	helper    baseRequesterHelper
+	tlsConfig *tls.Config
+	resolver  *resolver.Resolver
+
+	typesMu   sync.Mutex
+	seenTypes map[string]struct{}
 }
 
 func newSSERequester(cfg *config.Config, collector *metrics.Collector, provider auth.Provider, feeder httpclient.Feeder, tp *tracing.Provider) *sseRequester {
@@ -78,9 +92,15 @@ func (s *sseRequester) Do(ctx context.Context) error {
 
 	factory := func() pool.Poolable {
 		sseCfg := sse.Config{
-			URL:     target,
-			Headers: requestHeaders,
-			Timeout: s.cfg.ReadTimeout,
+			URL:        target,
+			Headers:    requestHeaders,
+			Timeout:    s.cfg.ReadTimeout,
+			Stream:     s.cfg.Subscribe,
+			RetryDelay: s.cfg.ReconnectDelay,
+			TLSConfig:  s.tlsConfig,
+		}
+		if s.resolver != nil {
+			sseCfg.DialContext = s.resolver.DialContext
 		}
 		return sse.NewClient(sseCfg)
 	}
@@ -90,7 +110,7 @@ func (s *sseRequester) Do(ctx context.Context) error {
 
 	// Connect if not reused
 	if !reused {
-		if err := client.Connect(ctx); err != nil {
+		if err := client.Connect(withTLSTrace(ctx, s.collector, "sse")); err != nil {
 			spanErr = err
 			return s.helper.recordError(start, meta, "sse", "connect", err)
 		}
@@ -99,10 +119,10 @@ func (s *sseRequester) Do(ctx context.Context) error {
 	// Capture metrics before operation to calculate delta
 	startMetrics := client.Metrics()
 
-	// Read events until max events reached or timeout
-	eventsRead := 0
+	// Read events until max events reached or timeout. Subscribers treat
+	// read_timeout as a reporting window and keep the stream open across windows.
 	maxEvents := s.cfg.MaxEvents
-	if maxEvents <= 0 {
+	if maxEvents <= 0 && !s.cfg.Subscribe {
 		maxEvents = 100 // Default to prevent infinite reads
 	}
 
@@ -113,11 +133,36 @@ func (s *sseRequester) Do(ctx context.Context) error {
 		defer cancel()
 	}
 
-	var opErr error
+	var (
+		opErr      error
+		eventsRead int
+		matched    int
+		reconnects int64
+		idGaps     int64
+		missed     int64
+		filtered   int64
+		lastAt     time.Time
+		typeCounts = map[string]int64{}
+	)
 
-	for eventsRead < maxEvents {
-		_, err := client.ReadEvent(readCtx)
+	for maxEvents <= 0 || matched < maxEvents {
+		prevID := client.LastEventID()
+		event, err := client.ReadEvent(readCtx)
 		if err != nil {
+			// Check if it's a context error (expected timeout/cancellation)
+			if readCtx.Err() != nil {
+				break
+			}
+
+			if s.cfg.Subscribe {
+				if err := s.resubscribe(readCtx, client); err != nil {
+					opErr = fmt.Errorf("reconnect: %w", err)
+					break
+				}
+				reconnects++
+				continue
+			}
+
 			// If this is a reused connection and we failed on the first read,
 			// it's likely a stale connection. Try to reconnect once.
 			if reused && eventsRead == 0 {
@@ -131,16 +176,39 @@ func (s *sseRequester) Do(ctx context.Context) error {
 				continue
 			}
 
-			// Check if it's a context error (expected timeout/cancellation)
-			if readCtx.Err() != nil {
-				break
-			}
 			// Other errors are failures
 			opErr = fmt.Errorf("read event: %w", err)
 			break
 		}
 		eventsRead++
 
+		if gap := missedEventIDs(prevID, event.ID); gap > 0 {
+			idGaps++
+			missed += gap
+		}
+
+		eventType := event.Event
+		if eventType == "" {
+			eventType = "message"
+		}
+		typeCounts[s.eventTypeKey(eventType)]++
+		if !s.wantsEvent(eventType) {
+			filtered++
+			continue
+		}
+		matched++
+
+		now := time.Now()
+		if !lastAt.IsZero() {
+			s.collector.RecordTiming("sse_inter_event", now.Sub(lastAt))
+		}
+		lastAt = now
+		if s.cfg.LagField != "" {
+			if published, ok := eventTimestamp(event.Data, s.cfg.LagField); ok {
+				s.collector.RecordTiming("sse_event_lag", now.Sub(published))
+			}
+		}
+
 		if ctx.Err() != nil {
 			opErr = ctx.Err()
 			break
@@ -156,12 +224,21 @@ func (s *sseRequester) Do(ctx context.Context) error {
 		"connection_duration_ms": endMetrics.ConnectionDuration.Milliseconds(),
 		"events_received":        endMetrics.EventsReceived - startMetrics.EventsReceived,
 		"bytes_received":         endMetrics.BytesReceived - startMetrics.BytesReceived,
+		"reconnects":             reconnects,
+		"id_gaps":                idGaps,
+		"missed_events":          missed,
+	}
+	if len(s.cfg.EventTypes) > 0 {
+		meta.CustomMetrics["events_filtered"] = filtered
+	}
+	for eventType, count := range typeCounts {
+		meta.CustomMetrics["event_type_"+eventType] = count
 	}
 
 	if opErr != nil {
 		// If error occurred, close client and do not return to pool
 		client.Close()
-		meta = annotateStatus(meta, "sse", fallbackStatusCode(opErr))
+		meta = annotateStatus(meta, "sse", sseStatusCode(opErr))
 		s.collector.RecordRequest(latency, opErr, meta)
 		spanErr = opErr
 		return opErr
@@ -183,8 +260,100 @@ func sseStatusCode(err error) string {
 	if err == nil {
 		return ""
 	}
-	if statusErr, ok := err.(*sse.StatusError); ok {
+	var statusErr *sse.StatusError
+	if errors.As(err, &statusErr) {
 		return strconv.Itoa(statusErr.Code)
 	}
 	return fallbackStatusCode(err)
 }
+
+// resubscribe reconnects a dropped stream, retrying transport failures until
+// ctx ends. Non-200 responses are final, as the SSE spec requires.
+func (s *sseRequester) resubscribe(ctx context.Context, client *sse.Client) error {
+	for {
+		err := client.Reconnect(ctx)
+		if err == nil {
+			return nil
+		}
+		var statusErr *sse.StatusError
+		if errors.As(err, &statusErr) || ctx.Err() != nil {
+			return err
+		}
+	}
+}
+
+// wantsEvent reports whether events of the given type count towards the run.
+func (s *sseRequester) wantsEvent(eventType string) bool {
+	if len(s.cfg.EventTypes) == 0 {
+		return true
+	}
+	for _, want := range s.cfg.EventTypes {
+		if want == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// eventTypeKey returns the metric suffix counting events of the given type.
+// Types outside event_types, or beyond the first maxSSEEventTypes seen when it
+// is unset, share "other" so a chatty server cannot grow the metric set.
+func (s *sseRequester) eventTypeKey(eventType string) string {
+	if len(s.cfg.EventTypes) > 0 {
+		if s.wantsEvent(eventType) {
+			return eventType
+		}
+		return "other"
+	}
+	s.typesMu.Lock()
+	defer s.typesMu.Unlock()
+	if _, ok := s.seenTypes[eventType]; ok {
+		return eventType
+	}
+	if len(s.seenTypes) >= maxSSEEventTypes {
+		return "other"
+	}
+	if s.seenTypes == nil {
+		s.seenTypes = make(map[string]struct{})
+	}
+	s.seenTypes[eventType] = struct{}{}
+	return eventType
+}
+
+// missedEventIDs returns how many numeric event IDs were skipped between prev
+// and next. Non-numeric IDs cannot be checked and report no gap.
+func missedEventIDs(prev, next string) int64 {
+	if prev == "" || next == "" {
+		return 0
+	}
+	p, err := strconv.ParseInt(prev, 10, 64)
+	if err != nil {
+		return 0
+	}
+	n, err := strconv.ParseInt(next, 10, 64)
+	if err != nil || n <= p+1 {
+		return 0
+	}
+	return n - p - 1
+}
+
+// eventTimestamp reads the publish time at path in the event data. Numbers are
+// taken as Unix seconds, or milliseconds when too large to be seconds;
+// strings must be RFC 3339.
+func eventTimestamp(data, path string) (time.Time, bool) {
+	raw, ok := extractor.LookupJSONPath([]byte(data), path)
+	if !ok {
+		return time.Time{}, false
+	}
+	if num, err := strconv.ParseFloat(raw, 64); err == nil {
+		if num > 1e11 {
+			return time.UnixMilli(int64(num)), true
+		}
+		return time.Unix(0, int64(num*float64(time.Second))), true
+	}
+	ts, err := time.Parse(time.RFC3339Nano, raw)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return ts, true
+}
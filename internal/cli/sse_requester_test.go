@@ -142,3 +142,126 @@ func TestSSERequester_Placeholders(t *testing.T) {
 		t.Fatalf("Do() failed: %v", err)
 	}
 }
+
+func TestSSERequester_Subscribe(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		flusher := w.(http.Flusher)
+
+		if r.Header.Get("Last-Event-ID") == "" {
+			// id 3 never arrives, then the stream drops.
+			fmt.Fprintf(w, "retry: 10\n\n")
+			fmt.Fprintf(w, "id: 1\nevent: update\ndata: {}\n\n")
+			fmt.Fprintf(w, "id: 2\nevent: heartbeat\ndata: {}\n\n")
+			fmt.Fprintf(w, "id: 4\nevent: update\ndata: {}\n\n")
+			flusher.Flush()
+			return
+		}
+		if r.Header.Get("Last-Event-ID") != "4" {
+			http.Error(w, "bad resume point", http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintf(w, "id: 5\nevent: update\ndata: {\"ts\":%d}\n\n", time.Now().Add(-50*time.Millisecond).UnixMilli())
+		flusher.Flush()
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	cfg := &config.Config{
+		TargetURL:   srv.URL,
+		Concurrency: 1,
+		SSE: config.SSEConfig{
+			ReadTimeout: 300 * time.Millisecond,
+			Subscribe:   true,
+			EventTypes:  []string{"update"},
+			LagField:    "$.ts",
+		},
+	}
+	collector := metrics.NewCollector()
+	sr := newSSERequester(cfg, collector, nil, nil, nil)
+	defer sr.Close()
+
+	if err := sr.Do(context.Background()); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+
+	stats := collector.Stats(time.Second)
+	got := stats.ProtocolMetrics["sse"]
+	want := map[string]int64{
+		"events_received":   4,
+		"reconnects":        1,
+		"id_gaps":           1,
+		"missed_events":     1,
+		"events_filtered":   1,
+		"event_type_update": 3,
+		"event_type_other":  1,
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("%s = %v, want %d", key, got[key], value)
+		}
+	}
+
+	if n := stats.Timings["sse_inter_event"].Total; n != 2 {
+		t.Errorf("sse_inter_event samples = %d, want 2", n)
+	}
+	lag := stats.Timings["sse_event_lag"]
+	if lag.Total != 1 || lag.MaxLatency < 50*time.Millisecond {
+		t.Errorf("sse_event_lag = %d samples, max %v; want 1 sample >= 50ms", lag.Total, lag.MaxLatency)
+	}
+}
+
+func TestSSEEventTypeKey(t *testing.T) {
+	t.Run("configured types", func(t *testing.T) {
+		sr := &sseRequester{cfg: &config.SSEConfig{EventTypes: []string{"update"}}}
+		if got := sr.eventTypeKey("update"); got != "update" {
+			t.Errorf("eventTypeKey(update) = %q, want update", got)
+		}
+		if got := sr.eventTypeKey("heartbeat"); got != "other" {
+			t.Errorf("eventTypeKey(heartbeat) = %q, want other", got)
+		}
+	})
+
+	t.Run("capped", func(t *testing.T) {
+		sr := &sseRequester{cfg: &config.SSEConfig{}}
+		for i := 0; i < maxSSEEventTypes; i++ {
+			eventType := fmt.Sprintf("type-%d", i)
+			if got := sr.eventTypeKey(eventType); got != eventType {
+				t.Fatalf("eventTypeKey(%s) = %q, want %s", eventType, got, eventType)
+			}
+		}
+		if got := sr.eventTypeKey("overflow"); got != "other" {
+			t.Errorf("eventTypeKey(overflow) = %q, want other", got)
+		}
+		if got := sr.eventTypeKey("type-0"); got != "type-0" {
+			t.Errorf("eventTypeKey(type-0) = %q, want type-0 after the cap", got)
+		}
+	})
+}
+
+func TestEventTimestamp(t *testing.T) {
+	want := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name string
+		data string
+		ok   bool
+	}{
+		{"unix seconds", fmt.Sprintf(`{"ts":%d}`, want.Unix()), true},
+		{"unix millis", fmt.Sprintf(`{"ts":%d}`, want.UnixMilli()), true},
+		{"rfc3339", `{"ts":"2026-01-02T03:04:05Z"}`, true},
+		{"missing", `{"other":1}`, false},
+		{"unparseable", `{"ts":"yesterday"}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := eventTimestamp(tt.data, "$.ts")
+			if ok != tt.ok {
+				t.Fatalf("eventTimestamp() ok = %v, want %v", ok, tt.ok)
+			}
+			if ok && !got.Equal(want) {
+				t.Errorf("eventTimestamp() = %v, want %v", got, want)
+			}
+		})
+	}
+}
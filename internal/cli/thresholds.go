@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"context"
+
+	"github.com/torosent/crankfire/internal/metrics"
+	"github.com/torosent/crankfire/internal/runner"
+	"github.com/torosent/crankfire/internal/threshold"
+)
+
+// RunWithThresholds runs r while evaluating the abort-on-fail thresholds
+// among thresholds against the collector's live stats. The first failure
+// cancels the run and is returned as the breach; it is nil otherwise.
+func RunWithThresholds(ctx context.Context, r *runner.Runner, collector *metrics.Collector, thresholds []threshold.Threshold) (runner.Result, *threshold.Breach) {
+	monitor := threshold.NewMonitor(thresholds, collector)
+	if monitor == nil {
+		return r.Run(ctx), nil
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		monitor.Run(runCtx, cancel)
+	}()
+
+	result := r.Run(runCtx)
+	cancel()
+	<-done
+	return result, monitor.Breach()
+}
@@ -0,0 +1,25 @@
+package cli
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http/httptrace"
+	"time"
+
+	"github.com/torosent/crankfire/internal/metrics"
+)
+
+// withTLSTrace returns a context that records the duration and negotiated
+// version of every TLS handshake made on its behalf. Reused connections
+// make no handshake and record nothing.
+func withTLSTrace(ctx context.Context, collector *metrics.Collector, protocol string) context.Context {
+	var start time.Time
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		TLSHandshakeStart: func() { start = time.Now() },
+		TLSHandshakeDone: func(state tls.ConnectionState, err error) {
+			if err == nil && !start.IsZero() {
+				collector.RecordTLSHandshake(protocol, time.Since(start), state.Version)
+			}
+		},
+	})
+}
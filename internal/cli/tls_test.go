@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/torosent/crankfire/internal/config"
+	"github.com/torosent/crankfire/internal/metrics"
+)
+
+func TestHTTPRequester_MutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	clientCert, clientKey, clientPool := writeClientCertificate(t, dir)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientPool}
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+
+	caFile := filepath.Join(dir, "server-ca.pem")
+	serverPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, serverPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{
+		TargetURL: server.URL,
+		TLS: config.TLSConfig{
+			CertFile:   clientCert,
+			KeyFile:    clientKey,
+			CAFile:     caFile,
+			MinVersion: "1.3",
+		},
+	}
+	collector := metrics.NewCollector()
+	requester, err := buildRequester(cfg, collector, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("buildRequester() error = %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := requester.Do(context.Background()); err != nil {
+			t.Fatalf("request %d: Do() error = %v", i, err)
+		}
+	}
+
+	// Both requests share one connection, so there is one handshake.
+	stats := collector.Stats(time.Second)
+	if got := stats.Timings["tls_handshake"].Total; got != 1 {
+		t.Errorf("tls_handshake samples = %d, want 1", got)
+	}
+	if got := stats.ProtocolMetrics["http"]["tls_version_1.3"]; got != int64(1) {
+		t.Errorf("tls_version_1.3 = %v, want 1", got)
+	}
+
+	cfg.TLS.CertFile, cfg.TLS.KeyFile = "", ""
+	requester, err = buildRequester(cfg, metrics.NewCollector(), nil, nil, nil)
+	if err != nil {
+		t.Fatalf("buildRequester() error = %v", err)
+	}
+	if err := requester.Do(context.Background()); err == nil {
+		t.Error("request without a client certificate succeeded")
+	}
+}
+
+func TestBuildRequester_InvalidTLSFiles(t *testing.T) {
+	cfg := &config.Config{
+		TargetURL: "https://example.com",
+		TLS:       config.TLSConfig{CAFile: filepath.Join(t.TempDir(), "missing.pem")},
+	}
+	if _, err := buildRequester(cfg, metrics.NewCollector(), nil, nil, nil); err == nil {
+		t.Fatal("buildRequester() accepted a missing CA bundle")
+	}
+}
+
+// writeClientCertificate writes a self-signed client certificate and key and
+// returns a pool that trusts it.
+func writeClientCertificate(t *testing.T, dir string) (certFile, keyFile string, pool *x509.CertPool) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "crankfire client"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile = filepath.Join(dir, "client.pem")
+	keyFile = filepath.Join(dir, "client.key")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	pool = x509.NewCertPool()
+	pool.AddCert(cert)
+	return certFile, keyFile, pool
+}
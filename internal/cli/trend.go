@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/torosent/crankfire/internal/output/trendreport"
+	"github.com/torosent/crankfire/internal/regression"
+)
+
+// trendFlags are the flags shared by `session trend` and `set trend`.
+type trendFlags struct {
+	last     int
+	window   int
+	jsonOut  bool
+	htmlPath string
+}
+
+func (f *trendFlags) register(fs *pflag.FlagSet) {
+	fs.IntVar(&f.last, "last", regression.DefaultTrendRuns, "number of most recent runs to show")
+	fs.IntVar(&f.window, "history", regression.DefaultWindow, "number of earlier runs each run is checked against")
+	fs.BoolVar(&f.jsonOut, "json", false, "emit JSON instead of text")
+	fs.StringVar(&f.htmlPath, "html", "", "write a standalone HTML report to PATH")
+}
+
+// writeTrend prints series as JSON, as an HTML report or as text, depending
+// on flags.
+func writeTrend(title string, series []regression.Series, f trendFlags, stdout, stderr io.Writer) int {
+	if f.jsonOut {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(series); err != nil {
+			fmt.Fprintf(stderr, "encode: %v\n", err)
+			return ExitRunnerError
+		}
+		return ExitOK
+	}
+	if f.htmlPath != "" {
+		out, err := trendreport.Render(title, series, time.Now())
+		if err == nil {
+			err = os.WriteFile(f.htmlPath, out, 0o644)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "write html: %v\n", err)
+			return ExitRunnerError
+		}
+		fmt.Fprintf(stdout, "wrote %s\n", f.htmlPath)
+		return ExitOK
+	}
+	writeTrendText(stdout, series)
+	return ExitOK
+}
+
+// writeTrendText prints one table per series, oldest run first; regressed
+// runs are flagged with "!".
+func writeTrendText(w io.Writer, series []regression.Series) {
+	for i, s := range series {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "%s (%d runs, %d regressed)\n", s.Name, len(s.Points), s.Regressions())
+		fmt.Fprintf(w, "  %-16s  %10s  %10s  %9s  %10s  %s\n", "STARTED", "P95 (ms)", "P99 (ms)", "ERR %", "RPS", "VERDICT")
+		for _, p := range s.Points {
+			flag := " "
+			if p.Verdict == regression.VerdictRegressed {
+				flag = "!"
+			}
+			fmt.Fprintf(w, "%s %-16s  %10.2f  %10.2f  %9.2f  %10.1f  %s\n",
+				flag, p.StartedAt.Format("2006-01-02 15:04"), p.P95Ms, p.P99Ms, p.ErrorRate*100, p.RPS, p.Verdict)
+		}
+	}
+}
@@ -0,0 +1,318 @@
+// Package webdash serves a live dashboard for a run over HTTP: an embedded
+// single-page UI fed by a server-sent event stream of the collector's
+// snapshots, with a stop button that invokes the same shutdown callback as
+// the terminal dashboard.
+package webdash
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/torosent/crankfire/internal/cli/livedash"
+	"github.com/torosent/crankfire/internal/metrics"
+)
+
+const (
+	defaultTickInterval = time.Second
+	// shutdownTimeout bounds how long open streams may delay the end of the
+	// run.
+	shutdownTimeout = 2 * time.Second
+)
+
+//go:embed index.html
+var indexHTML []byte
+
+// Opts configures the Server.
+type Opts struct {
+	Title string
+	// Header lines describe the run, e.g. the target and load profile.
+	Header []string
+	// Total is the configured request count, 0 when the run is bounded by
+	// duration.
+	Total    int64
+	Interval time.Duration
+	// Snapshot makes the server record a metrics.DataPoint every tick. Set
+	// it when no other reporter snapshots the collector, so the history
+	// does not get duplicate points.
+	Snapshot bool
+}
+
+// Snapshot is one event on the stream.
+type Snapshot struct {
+	ElapsedSec float64           `json:"elapsed_sec"`
+	Point      metrics.DataPoint `json:"point"`
+	Stats      metrics.Stats     `json:"stats"`
+	// Stopping is set once a stop was requested; Done on the final event.
+	Stopping bool `json:"stopping"`
+	Done     bool `json:"done"`
+}
+
+// info is the static description of the run served at /api/info.
+type info struct {
+	Title  string   `json:"title"`
+	Header []string `json:"header,omitempty"`
+	Total  int64    `json:"total,omitempty"`
+}
+
+// Server streams snapshots of a collector to every connected browser.
+type Server struct {
+	collector *metrics.Collector
+	opts      Opts
+	shutdown  func()
+	stopOnce  sync.Once
+
+	mu       sync.Mutex
+	started  time.Time
+	stopping bool
+	latest   []byte
+	clients  map[chan []byte]struct{}
+	closed   bool
+
+	srv  *http.Server
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New constructs a Server. shutdown is invoked at most once, when a viewer
+// presses the stop button.
+func New(c *metrics.Collector, opts Opts, shutdown func()) *Server {
+	if opts.Interval <= 0 {
+		opts.Interval = defaultTickInterval
+	}
+	if opts.Title == "" {
+		opts.Title = "Crankfire"
+	}
+	return &Server{
+		collector: c,
+		opts:      opts,
+		shutdown:  shutdown,
+		clients:   map[chan []byte]struct{}{},
+		done:      make(chan struct{}),
+	}
+}
+
+// Handler returns the dashboard's routes: the UI at /, run details at
+// /api/info, the event stream at /api/events, the latest snapshot at
+// /api/snapshot and the stop button's endpoint at /api/stop.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write(indexHTML)
+	})
+	mux.HandleFunc("GET /api/info", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, info{Title: s.opts.Title, Header: s.opts.Header, Total: s.opts.Total})
+	})
+	mux.HandleFunc("GET /api/snapshot", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(s.snapshot(false))
+	})
+	mux.HandleFunc("GET /api/events", s.serveEvents)
+	mux.HandleFunc("POST /api/stop", s.serveStop)
+	return mux
+}
+
+// Start listens on addr and begins ticking. It returns the address actually
+// bound, which differs from addr when addr has port 0.
+func (s *Server) Start(addr string) (string, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return "", fmt.Errorf("web dashboard: %w", err)
+	}
+	s.mu.Lock()
+	s.started = time.Now()
+	s.mu.Unlock()
+	s.srv = &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	s.wg.Add(2)
+	go func() {
+		defer s.wg.Done()
+		_ = s.srv.Serve(ln)
+	}()
+	go func() {
+		defer s.wg.Done()
+		s.tick()
+	}()
+	return ln.Addr().String(), nil
+}
+
+// Stop sends a final snapshot marked done to every viewer, closes their
+// streams and shuts the server down.
+func (s *Server) Stop() {
+	close(s.done)
+	final := s.snapshot(true)
+	s.mu.Lock()
+	s.closed = true
+	for ch := range s.clients {
+		// Replace any undelivered tick so the final event always fits.
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- final
+		close(ch)
+		delete(s.clients, ch)
+	}
+	s.mu.Unlock()
+	if s.srv != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		_ = s.srv.Shutdown(ctx)
+	}
+	s.wg.Wait()
+}
+
+func (s *Server) tick() {
+	ticker := time.NewTicker(s.opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			if s.opts.Snapshot {
+				s.collector.Snapshot()
+			}
+			s.broadcast(s.snapshot(false))
+		}
+	}
+}
+
+// snapshot encodes the collector's current state and remembers it as the
+// latest event for viewers that connect between ticks.
+func (s *Server) snapshot(done bool) []byte {
+	s.mu.Lock()
+	elapsed := time.Since(s.started)
+	if s.started.IsZero() {
+		elapsed = 0
+	}
+	stopping := s.stopping
+	s.mu.Unlock()
+
+	snap := livedash.BuildSnapshot(s.collector, elapsed)
+	data, _ := json.Marshal(Snapshot{
+		ElapsedSec: elapsed.Seconds(),
+		Point:      snap.Snap,
+		Stats:      *snap.Stats,
+		Stopping:   stopping,
+		Done:       done,
+	})
+	s.mu.Lock()
+	s.latest = data
+	s.mu.Unlock()
+	return data
+}
+
+// broadcast hands data to every viewer. A viewer that has not consumed the
+// previous event misses this one rather than stalling the others.
+func (s *Server) broadcast(data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for ch := range s.clients {
+		select {
+		case ch <- data:
+		default:
+		}
+	}
+}
+
+func (s *Server) subscribe() (chan []byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, false
+	}
+	ch := make(chan []byte, 1)
+	s.clients[ch] = struct{}{}
+	return ch, true
+}
+
+func (s *Server) unsubscribe(ch chan []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.clients[ch]; ok {
+		delete(s.clients, ch)
+		close(ch)
+	}
+}
+
+// serveEvents streams a "history" event with the data points recorded so
+// far, followed by a "snapshot" event every tick until the run ends.
+func (s *Server) serveEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	ch, ok := s.subscribe()
+	if !ok {
+		http.Error(w, "run finished", http.StatusServiceUnavailable)
+		return
+	}
+	defer s.unsubscribe(ch)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	history, _ := json.Marshal(s.collector.History())
+	writeEvent(w, "history", history)
+	s.mu.Lock()
+	latest := s.latest
+	s.mu.Unlock()
+	if latest != nil {
+		writeEvent(w, "snapshot", latest)
+	}
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case data, ok := <-ch:
+			if !ok {
+				return
+			}
+			writeEvent(w, "snapshot", data)
+			flusher.Flush()
+		}
+	}
+}
+
+// serveStop cancels the run. Requests from other origins are refused so a
+// page on another site cannot stop the run through a viewer's browser.
+func (s *Server) serveStop(w http.ResponseWriter, r *http.Request) {
+	if origin := r.Header.Get("Origin"); origin != "" {
+		if u, err := url.Parse(origin); err != nil || u.Host != r.Host {
+			http.Error(w, "cross-origin stop refused", http.StatusForbidden)
+			return
+		}
+	}
+	s.mu.Lock()
+	s.stopping = true
+	s.mu.Unlock()
+	s.stopOnce.Do(func() {
+		if s.shutdown != nil {
+			s.shutdown()
+		}
+	})
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func writeEvent(w http.ResponseWriter, event string, data []byte) {
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,192 @@
+package webdash_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/torosent/crankfire/internal/cli/webdash"
+	"github.com/torosent/crankfire/internal/metrics"
+)
+
+func startServer(t *testing.T, shutdown func()) (*webdash.Server, string) {
+	t.Helper()
+	c := metrics.NewCollector()
+	c.Start()
+	c.RecordRequest(40*time.Millisecond, nil, &metrics.RequestMetadata{Endpoint: "GET /users", Protocol: "http", StatusCode: "200"})
+	s := webdash.New(c, webdash.Opts{Title: "T", Header: []string{"Target: http://example.com"}, Interval: 20 * time.Millisecond, Snapshot: true}, shutdown)
+	addr, err := s.Start("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	return s, "http://" + addr
+}
+
+func TestServesEmbeddedUIAndInfo(t *testing.T) {
+	s, base := startServer(t, nil)
+	defer s.Stop()
+
+	resp, err := http.Get(base + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(resp.Header.Get("Content-Type"), "text/html") || !strings.Contains(string(body), "EventSource") {
+		t.Errorf("unexpected index: %s\n%s", resp.Header.Get("Content-Type"), body)
+	}
+
+	resp, err = http.Get(base + "/api/info")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var info struct {
+		Title  string   `json:"title"`
+		Header []string `json:"header"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		t.Fatal(err)
+	}
+	if info.Title != "T" || len(info.Header) != 1 {
+		t.Errorf("info = %+v", info)
+	}
+}
+
+func TestEventsStreamSnapshotsUntilStop(t *testing.T) {
+	s, base := startServer(t, nil)
+	resp, err := http.Get(base + "/api/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+
+	events := make(chan [2]string, 64)
+	go func() {
+		defer close(events)
+		sc := bufio.NewScanner(resp.Body)
+		sc.Buffer(make([]byte, 1<<20), 1<<20)
+		var name string
+		for sc.Scan() {
+			line := sc.Text()
+			switch {
+			case strings.HasPrefix(line, "event: "):
+				name = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				events <- [2]string{name, strings.TrimPrefix(line, "data: ")}
+			}
+		}
+	}()
+
+	next := func() (string, string) {
+		t.Helper()
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				t.Fatal("stream closed")
+			}
+			return ev[0], ev[1]
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for an event")
+		}
+		return "", ""
+	}
+
+	if name, _ := next(); name != "history" {
+		t.Fatalf("first event = %q, want history", name)
+	}
+	name, data := next()
+	if name != "snapshot" {
+		t.Fatalf("event = %q, want snapshot", name)
+	}
+	var snap webdash.Snapshot
+	if err := json.Unmarshal([]byte(data), &snap); err != nil {
+		t.Fatalf("decode: %v\n%s", err, data)
+	}
+	if snap.Stats.Total != 1 || snap.Stats.Endpoints["GET /users"].Total != 1 || snap.Done {
+		t.Errorf("snapshot = %+v", snap)
+	}
+
+	go s.Stop()
+	for {
+		name, data := next()
+		if name != "snapshot" {
+			t.Fatalf("event = %q, want snapshot", name)
+		}
+		if err := json.Unmarshal([]byte(data), &snap); err != nil {
+			t.Fatal(err)
+		}
+		if snap.Done {
+			break
+		}
+	}
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("expected the stream to end after the final event")
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("stream still open after Stop")
+	}
+}
+
+func TestStopButtonCancelsRunOnce(t *testing.T) {
+	var calls atomic.Int32
+	s, base := startServer(t, func() { calls.Add(1) })
+	defer s.Stop()
+
+	req, _ := http.NewRequest(http.MethodPost, base+"/api/stop", nil)
+	req.Header.Set("Origin", "http://evil.example")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden || calls.Load() != 0 {
+		t.Fatalf("cross-origin stop: status %d, calls %d", resp.StatusCode, calls.Load())
+	}
+
+	for i := 0; i < 2; i++ {
+		resp, err := http.Post(base+"/api/stop", "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusAccepted {
+			t.Fatalf("status = %d, want 202", resp.StatusCode)
+		}
+	}
+	if calls.Load() != 1 {
+		t.Errorf("shutdown called %d times, want 1", calls.Load())
+	}
+
+	resp, err = http.Get(base + "/api/snapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var snap webdash.Snapshot
+	if err := json.NewDecoder(resp.Body).Decode(&snap); err != nil {
+		t.Fatal(err)
+	}
+	if !snap.Stopping {
+		t.Error("snapshot should report the run as stopping")
+	}
+
+	resp, err = http.Get(base + "/api/stop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("GET /api/stop status = %d, want 405", resp.StatusCode)
+	}
+}
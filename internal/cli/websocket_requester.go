@@ -2,10 +2,14 @@ package cli
 
 import (
 	"context"
+	"crypto/tls"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"net"
+	"os"
 	"strconv"
+	"sync/atomic"
 	"time"
 
 	gws "github.com/gorilla/websocket"
@@ -15,7 +19,9 @@ import (
 	"github.com/torosent/crankfire/internal/metrics"
 	"github.com/torosent/crankfire/internal/placeholders"
 	"github.com/torosent/crankfire/internal/pool"
+	"github.com/torosent/crankfire/internal/resolver"
 	"github.com/torosent/crankfire/internal/tracing"
+	"github.com/torosent/crankfire/internal/variables"
 	ws "github.com/torosent/crankfire/internal/websocket"
 )
 
@@ -28,9 +34,23 @@ type websocketRequester struct {
 	feeder    httpclient.Feeder
 	connPool  *pool.ConnectionPool
 	helper    baseRequesterHelper
+	script    []wsScriptStep
+	binary    [][]byte
+	setupErr  error
+	tlsConfig *tls.Config
+	resolver  *resolver.Resolver
+
+	// Running totals behind the compression_ratio gauge.
+	payloadBytes atomic.Int64
+	wireBytes    atomic.Int64
 }
 
 func newWebSocketRequester(cfg *config.Config, collector *metrics.Collector, provider auth.Provider, feeder httpclient.Feeder, tp *tracing.Provider) *websocketRequester {
+	script, setupErr := compileWebSocketScript(cfg.WebSocket.Steps)
+	binary, err := loadBinaryFrames(cfg.WebSocket.BinaryMessages, cfg.WebSocket.MessageFiles)
+	if setupErr == nil {
+		setupErr = err
+	}
 	return &websocketRequester{
 		cfg:       &cfg.WebSocket,
 		target:    cfg.TargetURL,
@@ -45,6 +65,9 @@ func newWebSocketRequester(cfg *config.Config, collector *metrics.Collector, pro
 			feeder:    feeder,
 			tracing:   tp,
 		},
+		script:   script,
+		binary:   binary,
+		setupErr: setupErr,
 	}
 }
 
@@ -55,6 +78,11 @@ func (w *websocketRequester) Do(ctx context.Context) error {
 	var spanErr error
 	defer func() { tracing.EndSpan(span, spanErr) }()
 
+	if w.setupErr != nil {
+		spanErr = w.setupErr
+		return w.helper.recordError(start, meta, "websocket", "setup", w.setupErr)
+	}
+
 	record, err := w.helper.getFeederRecord(ctx)
 	if err != nil {
 		spanErr = err
@@ -86,6 +114,13 @@ func (w *websocketRequester) Do(ctx context.Context) error {
 			HandshakeTimeout: w.cfg.HandshakeTimeout,
 			ReadTimeout:      w.cfg.ReceiveTimeout,
 			WriteTimeout:     5 * time.Second,
+
+			EnableCompression: w.cfg.Compression,
+			CompressionLevel:  w.cfg.CompressionLevel,
+			TLSConfig:         w.tlsConfig,
+		}
+		if w.resolver != nil {
+			wsCfg.DialContext = w.resolver.DialContext
 		}
 		return ws.NewClient(wsCfg)
 	}
@@ -95,7 +130,7 @@ func (w *websocketRequester) Do(ctx context.Context) error {
 
 	// Connect if not reused
 	if !reused {
-		if err := client.Connect(ctx); err != nil {
+		if err := client.Connect(withTLSTrace(ctx, w.collector, "websocket")); err != nil {
 			spanErr = err
 			return w.helper.recordError(start, meta, "websocket", "connect", err)
 		}
@@ -104,26 +139,88 @@ func (w *websocketRequester) Do(ctx context.Context) error {
 	// Capture metrics before operation to calculate delta
 	startMetrics := client.Metrics()
 
-	messages := append([]string(nil), w.cfg.Messages...)
-	if len(record) > 0 {
-		for i, msg := range messages {
-			messages[i] = placeholders.Apply(msg, record)
+	store := variables.FromContext(ctx)
+	var opErr error
+	closed := false
+	if len(w.script) > 0 {
+		if store == nil {
+			store = variables.NewStore()
 		}
+		conv := &wsConversation{client: client, reused: reused, record: record, store: store}
+		opErr = w.runScript(ctx, conv, factory)
+		client, closed = conv.client, conv.closed
+	} else {
+		client, opErr = w.sendMessages(ctx, client, reused, factory, record, store)
+	}
+
+	// Calculate delta metrics
+	endMetrics := client.Metrics()
+	latency := time.Since(start)
+
+	bytesSent := endMetrics.BytesSent - startMetrics.BytesSent
+	bytesReceived := endMetrics.BytesReceived - startMetrics.BytesReceived
+	wireSent := endMetrics.WireBytesSent - startMetrics.WireBytesSent
+	wireReceived := endMetrics.WireBytesReceived - startMetrics.WireBytesReceived
+	meta.CustomMetrics = map[string]interface{}{
+		"connection_duration_ms": endMetrics.ConnectionDuration.Milliseconds(),
+		"messages_sent":          endMetrics.MessagesSent - startMetrics.MessagesSent,
+		"messages_received":      endMetrics.MessagesReceived - startMetrics.MessagesReceived,
+		"bytes_sent":             bytesSent,
+		"bytes_received":         bytesReceived,
+		"wire_bytes_sent":        wireSent,
+		"wire_bytes_received":    wireReceived,
+	}
+	payloadTotal := w.payloadBytes.Add(bytesSent + bytesReceived)
+	wireTotal := w.wireBytes.Add(wireSent + wireReceived)
+	if wireTotal > 0 {
+		// Payload bytes per wire byte across the run; above 1 means compression pays off.
+		meta.CustomMetrics["compression_ratio"] = metrics.Gauge(float64(payloadTotal) / float64(wireTotal))
+	}
+
+	if opErr != nil {
+		// If error occurred, close client and do not return to pool
+		client.Close()
+		meta = annotateStatus(meta, "websocket", websocketStatusFromError(opErr))
+		w.collector.RecordRequest(latency, opErr, meta)
+		spanErr = opErr
+		return opErr
+	}
+
+	// If successful, return client to pool unless the script closed it
+	if !closed {
+		w.connPool.Put(poolKey, client)
+	}
+
+	w.collector.RecordRequest(latency, nil, meta)
+	return nil
+}
+
+// sendMessages sends the configured text messages followed by the binary
+// frames, then optionally drains replies until the receive timeout. It returns
+// the client in use, which differs from the one passed in when a stale pooled
+// connection was replaced.
+func (w *websocketRequester) sendMessages(ctx context.Context, client *ws.Client, reused bool, factory func() pool.Poolable, record map[string]string, store variables.Store) (*ws.Client, error) {
+	frames := make([]ws.Message, 0, len(w.cfg.Messages)+len(w.binary))
+	for _, msg := range w.cfg.Messages {
+		frames = append(frames, ws.Message{
+			Type: gws.TextMessage,
+			Data: []byte(placeholders.Apply(msg, record, store)),
+		})
+	}
+	for _, data := range w.binary {
+		frames = append(frames, ws.Message{Type: gws.BinaryMessage, Data: data})
 	}
 
 	var opErr error
 
-	// Send configured messages
-	for i, msg := range messages {
+	// Send configured frames
+	for i, frame := range frames {
 		if ctx.Err() != nil {
 			opErr = ctx.Err()
 			break
 		}
 
-		if err := client.SendMessage(ctx, ws.Message{
-			Type: 1, // TextMessage
-			Data: []byte(msg),
-		}); err != nil {
+		if err := client.SendMessage(ctx, frame); err != nil {
 			// If this is a reused connection and we failed on the first message,
 			// it's likely a stale connection. Try to reconnect once.
 			if reused && i == 0 {
@@ -136,10 +233,7 @@ func (w *websocketRequester) Do(ctx context.Context) error {
 				reused = false
 
 				// Retry sending the message
-				if err := client.SendMessage(ctx, ws.Message{
-					Type: 1, // TextMessage
-					Data: []byte(msg),
-				}); err != nil {
+				if err := client.SendMessage(ctx, frame); err != nil {
 					opErr = fmt.Errorf("send message: %w", err)
 					break
 				}
@@ -150,7 +244,7 @@ func (w *websocketRequester) Do(ctx context.Context) error {
 		}
 
 		// Wait between messages if configured
-		if w.cfg.MessageInterval > 0 && len(w.cfg.Messages) > 1 {
+		if w.cfg.MessageInterval > 0 && len(frames) > 1 {
 			select {
 			case <-time.After(w.cfg.MessageInterval):
 			case <-ctx.Done():
@@ -189,32 +283,28 @@ func (w *websocketRequester) Do(ctx context.Context) error {
 		}
 	}
 
-	// Calculate delta metrics
-	endMetrics := client.Metrics()
-	latency := time.Since(start)
+	return client, opErr
+}
 
-	meta.CustomMetrics = map[string]interface{}{
-		"connection_duration_ms": endMetrics.ConnectionDuration.Milliseconds(),
-		"messages_sent":          endMetrics.MessagesSent - startMetrics.MessagesSent,
-		"messages_received":      endMetrics.MessagesReceived - startMetrics.MessagesReceived,
-		"bytes_sent":             endMetrics.BytesSent - startMetrics.BytesSent,
-		"bytes_received":         endMetrics.BytesReceived - startMetrics.BytesReceived,
+// loadBinaryFrames decodes base64 payloads and reads payload files up front so
+// iterations do not touch the filesystem.
+func loadBinaryFrames(encoded, files []string) ([][]byte, error) {
+	frames := make([][]byte, 0, len(encoded)+len(files))
+	for i, msg := range encoded {
+		data, err := base64.StdEncoding.DecodeString(msg)
+		if err != nil {
+			return nil, fmt.Errorf("binary_messages[%d]: %w", i, err)
+		}
+		frames = append(frames, data)
 	}
-
-	if opErr != nil {
-		// If error occurred, close client and do not return to pool
-		client.Close()
-		meta = annotateStatus(meta, "websocket", websocketStatusFromError(opErr))
-		w.collector.RecordRequest(latency, opErr, meta)
-		spanErr = opErr
-		return opErr
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("message file: %w", err)
+		}
+		frames = append(frames, data)
 	}
-
-	// If successful, return client to pool
-	w.connPool.Put(poolKey, client)
-
-	w.collector.RecordRequest(latency, nil, meta)
-	return nil
+	return frames, nil
 }
 
 // Close releases all WebSocket connections held in the connection pool.
@@ -226,6 +316,9 @@ func websocketStatusFromError(err error) string {
 	if err == nil {
 		return ""
 	}
+	if errors.Is(err, errExpectFailed) {
+		return expectFailedStatus
+	}
 	var closeErr *gws.CloseError
 	if errors.As(err, &closeErr) && closeErr.Code != 0 {
 		return strconv.Itoa(closeErr.Code)
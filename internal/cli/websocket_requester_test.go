@@ -2,14 +2,21 @@ package cli
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/torosent/crankfire/internal/config"
 	"github.com/torosent/crankfire/internal/metrics"
+	"github.com/torosent/crankfire/internal/variables"
 )
 
 // Mock Auth Provider
@@ -214,3 +221,208 @@ func TestWebsocketRequester_Placeholders(t *testing.T) {
 		t.Error("Timeout waiting for message")
 	}
 }
+
+func TestWebsocketRequester_Script(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	closeCodes := make(chan int, 4)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		for {
+			_, message, err := c.ReadMessage()
+			if err != nil {
+				var closeErr *websocket.CloseError
+				if errors.As(err, &closeErr) {
+					closeCodes <- closeErr.Code
+				}
+				return
+			}
+			var req map[string]string
+			_ = json.Unmarshal(message, &req)
+			switch req["op"] {
+			case "subscribe":
+				_ = c.WriteMessage(websocket.TextMessage, []byte(`{"type":"heartbeat"}`))
+				_ = c.WriteMessage(websocket.TextMessage, []byte(`{"type":"quote","symbol":"`+req["symbol"]+`","id":"q-7"}`))
+			case "ack":
+				_ = c.WriteMessage(websocket.TextMessage, []byte(`ack:`+req["id"]))
+			}
+		}
+	}))
+	defer srv.Close()
+	wsURL := "ws" + srv.URL[4:]
+
+	subscribe := []config.WebSocketStep{
+		{Name: "subscribe", Send: `{"op":"subscribe","symbol":"{{symbol}}"}`},
+		{
+			Name:    "quote",
+			Expect:  &config.WebSocketExpect{JSONPath: "$.type", Equals: "quote"},
+			Extract: []config.Extractor{{JSONPath: "$.id", Variable: "quote_id"}},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		steps      []config.WebSocketStep
+		wantErr    bool
+		wantClose  int
+		wantFailed string
+	}{
+		{
+			name: "conversation with extraction",
+			steps: append(append([]config.WebSocketStep(nil), subscribe...),
+				config.WebSocketStep{Send: `{"op":"ack","id":"{{quote_id}}"}`},
+				config.WebSocketStep{Expect: &config.WebSocketExpect{Regex: `^ack:(.+)$`, Equals: "q-7"}},
+				config.WebSocketStep{SendBinary: "AAEC"},
+				config.WebSocketStep{Sleep: time.Millisecond},
+				config.WebSocketStep{Close: 4000},
+			),
+			wantClose: 4000,
+		},
+		{
+			name: "expectation times out",
+			steps: append(append([]config.WebSocketStep(nil), subscribe...),
+				config.WebSocketStep{
+					Name:    "trade",
+					Expect:  &config.WebSocketExpect{JSONPath: "$.type", Equals: "trade"},
+					Timeout: 100 * time.Millisecond,
+				},
+			),
+			wantErr:    true,
+			wantFailed: "trade",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				TargetURL:   wsURL,
+				Concurrency: 1,
+				WebSocket:   config.WebSocketConfig{Steps: tt.steps},
+			}
+			collector := metrics.NewCollector()
+			feeder := &mockFeeder{data: map[string]string{"symbol": "ACME"}}
+			wr := newWebSocketRequester(cfg, collector, nil, feeder, nil)
+			defer wr.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			err := wr.Do(ctx)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Do() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			stats := collector.Stats(time.Second)
+			if len(stats.Steps) != len(tt.steps) {
+				t.Fatalf("len(Steps) = %d, want %d (%v)", len(stats.Steps), len(tt.steps), stats.Steps)
+			}
+			if got := stats.Steps["quote"].Successes; got != 1 {
+				t.Errorf("Steps[quote].Successes = %d, want 1", got)
+			}
+
+			if tt.wantFailed != "" {
+				if n := stats.Steps[tt.wantFailed].StatusBuckets["websocket"][expectFailedStatus]; n != 1 {
+					t.Errorf("Steps[%s] EXPECT_FAILED = %d, want 1", tt.wantFailed, n)
+				}
+				if n := stats.StatusBuckets["websocket"][expectFailedStatus]; n != 1 {
+					t.Errorf("StatusBuckets[websocket][EXPECT_FAILED] = %d, want 1", n)
+				}
+			}
+			if tt.wantClose != 0 {
+				select {
+				case code := <-closeCodes:
+					if code != tt.wantClose {
+						t.Errorf("close code = %d, want %d", code, tt.wantClose)
+					}
+				case <-time.After(time.Second):
+					t.Error("server did not receive close frame")
+				}
+			}
+		})
+	}
+}
+
+func TestWebsocketRequester_BinaryAndCompression(t *testing.T) {
+	upgrader := websocket.Upgrader{EnableCompression: true}
+	type frame struct {
+		mt   int
+		data string
+	}
+	frames := make(chan frame, 8)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		for {
+			mt, message, err := c.ReadMessage()
+			if err != nil {
+				return
+			}
+			frames <- frame{mt: mt, data: string(message)}
+			if err := c.WriteMessage(mt, message); err != nil {
+				return
+			}
+		}
+	}))
+	defer srv.Close()
+
+	payloadFile := filepath.Join(t.TempDir(), "frame.bin")
+	bulk := strings.Repeat("tick ", 2000)
+	if err := os.WriteFile(payloadFile, []byte(bulk), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	cfg := &config.Config{
+		TargetURL:   "ws" + srv.URL[4:],
+		Concurrency: 1,
+		WebSocket: config.WebSocketConfig{
+			Messages:       []string{`{"user":"{{user}}","session":"{{session}}"}`},
+			BinaryMessages: []string{base64.StdEncoding.EncodeToString([]byte{0x00, 0xff, 0x10})},
+			MessageFiles:   []string{payloadFile},
+			ReceiveTimeout: 100 * time.Millisecond,
+			Compression:    true,
+		},
+	}
+	collector := metrics.NewCollector()
+	wr := newWebSocketRequester(cfg, collector, nil, &mockFeeder{data: map[string]string{"user": "alice"}}, nil)
+	defer wr.Close()
+
+	store := variables.NewStore()
+	store.Set("session", "s-1")
+	ctx, cancel := context.WithTimeout(variables.NewContext(context.Background(), store), 2*time.Second)
+	defer cancel()
+
+	if err := wr.Do(ctx); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+
+	want := []frame{
+		{mt: websocket.TextMessage, data: `{"user":"alice","session":"s-1"}`},
+		{mt: websocket.BinaryMessage, data: "\x00\xff\x10"},
+		{mt: websocket.BinaryMessage, data: bulk},
+	}
+	for i, w := range want {
+		got := <-frames
+		if got != w {
+			t.Errorf("frame %d = {%d %.40q}, want {%d %.40q}", i, got.mt, got.data, w.mt, w.data)
+		}
+	}
+
+	got := collector.Stats(time.Second).ProtocolMetrics["websocket"]
+	if got["messages_sent"] != int64(3) {
+		t.Errorf("messages_sent = %v, want 3", got["messages_sent"])
+	}
+	wireSent, _ := got["wire_bytes_sent"].(int64)
+	bytesSent, _ := got["bytes_sent"].(int64)
+	if wireSent <= 0 || wireSent >= bytesSent {
+		t.Errorf("wire_bytes_sent = %d, want compressed below bytes_sent %d", wireSent, bytesSent)
+	}
+	if ratio, _ := got["compression_ratio"].(metrics.Gauge); ratio <= 1 {
+		t.Errorf("compression_ratio = %v, want > 1", got["compression_ratio"])
+	}
+}
@@ -0,0 +1,239 @@
+package cli
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"time"
+
+	gws "github.com/gorilla/websocket"
+	"github.com/torosent/crankfire/internal/config"
+	"github.com/torosent/crankfire/internal/extractor"
+	"github.com/torosent/crankfire/internal/metrics"
+	"github.com/torosent/crankfire/internal/placeholders"
+	"github.com/torosent/crankfire/internal/pool"
+	"github.com/torosent/crankfire/internal/variables"
+	ws "github.com/torosent/crankfire/internal/websocket"
+)
+
+// defaultWebSocketExpectTimeout bounds expect steps when neither the step nor
+// the websocket config sets a timeout.
+const defaultWebSocketExpectTimeout = 10 * time.Second
+
+// expectFailedStatus is the status bucket for expect steps that never saw a
+// matching message.
+const expectFailedStatus = "EXPECT_FAILED"
+
+var errExpectFailed = errors.New("expectation not met")
+
+// wsScriptStep is a config.WebSocketStep with its predicate compiled.
+type wsScriptStep struct {
+	name       string
+	action     string
+	step       config.WebSocketStep
+	regex      *regexp.Regexp
+	extractors []extractor.Extractor
+	binary     []byte // decoded send_binary or send_file payload
+}
+
+func compileWebSocketScript(steps []config.WebSocketStep) ([]wsScriptStep, error) {
+	script := make([]wsScriptStep, 0, len(steps))
+	for i, step := range steps {
+		compiled := wsScriptStep{
+			name:       step.Name,
+			action:     step.Action(),
+			step:       step,
+			extractors: convertExtractors(step.Extract),
+		}
+		if compiled.name == "" {
+			compiled.name = fmt.Sprintf("%02d-%s", i+1, compiled.action)
+		}
+		switch {
+		case step.SendBinary != "":
+			data, err := base64.StdEncoding.DecodeString(step.SendBinary)
+			if err != nil {
+				return nil, fmt.Errorf("steps[%d]: send_binary: %w", i, err)
+			}
+			compiled.binary = data
+		case step.SendFile != "":
+			data, err := os.ReadFile(step.SendFile)
+			if err != nil {
+				return nil, fmt.Errorf("steps[%d]: send_file: %w", i, err)
+			}
+			compiled.binary = data
+		}
+		if step.Expect != nil && step.Expect.Regex != "" {
+			re, err := regexp.Compile(step.Expect.Regex)
+			if err != nil {
+				return nil, fmt.Errorf("steps[%d]: invalid expect regex: %w", i, err)
+			}
+			compiled.regex = re
+		}
+		script = append(script, compiled)
+	}
+	return script, nil
+}
+
+// matches reports whether data satisfies the step's expect predicate.
+func (s *wsScriptStep) matches(data []byte) bool {
+	exp := s.step.Expect
+	var value string
+	if s.regex != nil {
+		match := s.regex.FindSubmatch(data)
+		if match == nil {
+			return false
+		}
+		value = string(match[0])
+		if len(match) > 1 {
+			value = string(match[1])
+		}
+	} else {
+		var ok bool
+		value, ok = extractor.LookupJSONPath(data, exp.JSONPath)
+		if !ok {
+			return false
+		}
+	}
+	return exp.Equals == "" || value == exp.Equals
+}
+
+// wsConversation holds the state of one scripted run over a connection.
+type wsConversation struct {
+	client *ws.Client
+	reused bool
+	record map[string]string
+	store  variables.Store
+	last   []byte
+	closed bool // connection was closed and must not return to the pool
+	used   bool // a frame has been exchanged on this connection
+}
+
+// runScript executes the configured steps in order, recording each step's
+// latency separately. It stops at the first failing step.
+func (w *websocketRequester) runScript(ctx context.Context, conv *wsConversation, factory func() pool.Poolable) error {
+	for i := range w.script {
+		step := &w.script[i]
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		stepStart := time.Now()
+		err := w.runStep(ctx, conv, step, factory)
+		stepMeta := &metrics.RequestMetadata{Protocol: "websocket"}
+		if err != nil {
+			stepMeta.StatusCode = websocketStatusFromError(err)
+		}
+		w.collector.RecordStep(step.name, time.Since(stepStart), err, stepMeta)
+		if err != nil {
+			return fmt.Errorf("step %s: %w", step.name, err)
+		}
+	}
+	return nil
+}
+
+func (w *websocketRequester) runStep(ctx context.Context, conv *wsConversation, step *wsScriptStep, factory func() pool.Poolable) error {
+	switch step.action {
+	case "send":
+		msg := ws.Message{Type: gws.BinaryMessage, Data: step.binary}
+		if step.binary == nil {
+			msg = ws.Message{
+				Type: gws.TextMessage,
+				Data: []byte(placeholders.Apply(step.step.Send, conv.record, conv.store)),
+			}
+		}
+		return w.sendScripted(ctx, conv, msg, factory)
+	case "expect":
+		data, err := w.awaitMatch(ctx, conv, step)
+		if err != nil {
+			return err
+		}
+		conv.last = data
+		w.extractInto(conv, step, data)
+		return nil
+	case "extract":
+		if conv.last == nil {
+			return fmt.Errorf("extract: no message received yet")
+		}
+		w.extractInto(conv, step, conv.last)
+		return nil
+	case "sleep":
+		select {
+		case <-time.After(step.step.Sleep):
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	case "close":
+		conv.closed = true
+		if err := conv.client.CloseWithCode(step.step.Close, ""); err != nil {
+			return fmt.Errorf("close: %w", err)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown step action %q", step.action)
+}
+
+// sendScripted writes out, reconnecting once if the first write on a pooled
+// connection fails because the connection went stale.
+func (w *websocketRequester) sendScripted(ctx context.Context, conv *wsConversation, out ws.Message, factory func() pool.Poolable) error {
+	err := conv.client.SendMessage(ctx, out)
+	if err != nil && conv.reused && !conv.used {
+		fresh, ok := w.connPool.RetryStaleConnection(ctx, conv.client, factory)
+		if !ok {
+			return fmt.Errorf("reconnect failed")
+		}
+		conv.client = fresh.(*ws.Client)
+		conv.reused = false
+		err = conv.client.SendMessage(ctx, out)
+	}
+	if err != nil {
+		return fmt.Errorf("send message: %w", err)
+	}
+	conv.used = true
+	return nil
+}
+
+// awaitMatch reads messages until one satisfies the step's predicate. Other
+// messages are skipped; running out of time yields errExpectFailed.
+func (w *websocketRequester) awaitMatch(ctx context.Context, conv *wsConversation, step *wsScriptStep) ([]byte, error) {
+	timeout := step.step.Timeout
+	if timeout <= 0 {
+		timeout = w.cfg.ReceiveTimeout
+	}
+	if timeout <= 0 {
+		timeout = defaultWebSocketExpectTimeout
+	}
+	expectCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		msg, err := conv.client.ReceiveMessage(expectCtx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			var netErr net.Error
+			if expectCtx.Err() != nil || errors.As(err, &netErr) && netErr.Timeout() {
+				return nil, fmt.Errorf("%w: no matching message within %s", errExpectFailed, timeout)
+			}
+			return nil, fmt.Errorf("receive message: %w", err)
+		}
+		conv.used = true
+		if step.matches(msg.Data) {
+			return msg.Data, nil
+		}
+	}
+}
+
+func (w *websocketRequester) extractInto(conv *wsConversation, step *wsScriptStep, data []byte) {
+	if len(step.extractors) == 0 {
+		return
+	}
+	for key, value := range extractor.ExtractAll(data, step.extractors, &stderrLogger{}) {
+		conv.store.Set(key, value)
+	}
+}
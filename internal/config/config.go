@@ -1,8 +1,14 @@
 package config
 
 import (
+	"crypto/tls"
+	"encoding/base64"
 	"fmt"
+	"net"
 	"os"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -16,6 +22,15 @@ const (
 	ProtocolGRPC      Protocol = "grpc"
 )
 
+// HTTP versions selectable with http_version. Empty negotiates HTTP/1.1 or
+// HTTP/2 over TLS per ALPN, as Go's default transport does.
+const (
+	HTTPVersion1   = "1.1" // HTTP/1.1 only
+	HTTPVersion2   = "2"   // HTTP/2 over TLS only
+	HTTPVersionH2C = "h2c" // HTTP/2 over cleartext TCP with prior knowledge
+	HTTPVersion3   = "3"   // HTTP/3 over QUIC
+)
+
 type Config struct {
 	TargetURL        string            `mapstructure:"target"`
 	Method           string            `mapstructure:"method"`
@@ -31,15 +46,19 @@ type Config struct {
 	Retries          int               `mapstructure:"retries"`
 	JSONOutput       bool              `mapstructure:"json_output"`
 	Dashboard        bool              `mapstructure:"dashboard"`
+	WebDashboard     string            `mapstructure:"web_dashboard"` // address for the live web dashboard (e.g., :8089)
 	LogErrors        bool              `mapstructure:"log_errors"`
 	HTMLOutput       string            `mapstructure:"html_output"`
+	JUnitOutput      string            `mapstructure:"junit_output"`
 	ConfigFile       string            `mapstructure:"-"`
 	LoadPatterns     []LoadPattern     `mapstructure:"load_patterns"`
 	Arrival          ArrivalConfig     `mapstructure:"arrival"`
 	Endpoints        []Endpoint        `mapstructure:"endpoints"`
+	Scenarios        []Scenario        `mapstructure:"scenarios"`
 	Auth             AuthConfig        `mapstructure:"auth"`
 	Feeder           FeederConfig      `mapstructure:"feeder"`
 	Protocol         Protocol          `mapstructure:"protocol"`
+	HTTPVersion      string            `mapstructure:"http_version"` // 1.1, 2, h2c or 3 (empty = negotiate)
 	WebSocket        WebSocketConfig   `mapstructure:"websocket"`
 	SSE              SSEConfig         `mapstructure:"sse"`
 	GRPC             GRPCConfig        `mapstructure:"grpc"`
@@ -47,6 +66,151 @@ type Config struct {
 	HARFile          string            `mapstructure:"har_file"`
 	HARFilter        string            `mapstructure:"har_filter"`
 	Tracing          TracingConfig     `mapstructure:"tracing"`
+	Agents           []string          `mapstructure:"agents"` // distributed run: agent addresses (host:port)
+	Prometheus       PrometheusConfig  `mapstructure:"prometheus"`
+	OTLPMetrics      OTLPMetricsConfig `mapstructure:"otlp_metrics"`
+	Baseline         BaselineConfig    `mapstructure:"baseline"`
+	Cookies          CookiesConfig     `mapstructure:"cookies"`
+	TLS              TLSConfig         `mapstructure:"tls"`
+	DNS              DNSConfig         `mapstructure:"dns"`
+}
+
+// TLSConfig controls the client side of TLS connections for every protocol.
+type TLSConfig struct {
+	CertFile     string   `mapstructure:"cert_file"`     // PEM client certificate for mutual TLS
+	KeyFile      string   `mapstructure:"key_file"`      // PEM private key of CertFile
+	CAFile       string   `mapstructure:"ca_file"`       // PEM bundle trusted instead of the system roots
+	ServerName   string   `mapstructure:"server_name"`   // SNI and verification name override
+	MinVersion   string   `mapstructure:"min_version"`   // 1.0, 1.1, 1.2 or 1.3
+	MaxVersion   string   `mapstructure:"max_version"`   // 1.0, 1.1, 1.2 or 1.3
+	CipherSuites []string `mapstructure:"cipher_suites"` // crypto/tls names; TLS 1.3 suites are not configurable
+	ALPN         []string `mapstructure:"alpn"`          // protocols offered during the handshake
+}
+
+// Enabled returns true when any TLS setting differs from the defaults.
+func (t TLSConfig) Enabled() bool {
+	return t.CertFile != "" || t.KeyFile != "" || t.CAFile != "" || t.ServerName != "" ||
+		t.MinVersion != "" || t.MaxVersion != "" || len(t.CipherSuites) > 0 || len(t.ALPN) > 0
+}
+
+// DNSConfig controls how the hosts of every protocol are resolved when
+// connecting. Overrides keep the Host header, SNI and gRPC authority of the
+// original name, like curl's --resolve.
+type DNSConfig struct {
+	Resolve    map[string][]string `mapstructure:"resolve"`     // host:port -> ip:port addresses, rotated per connection
+	Server     string              `mapstructure:"server"`      // DNS server as ip or ip:port (default port 53)
+	RoundRobin bool                `mapstructure:"round_robin"` // rotate through every resolved address
+}
+
+// Enabled returns true when any DNS setting differs from the defaults.
+func (d DNSConfig) Enabled() bool {
+	return len(d.Resolve) > 0 || d.Server != "" || d.RoundRobin
+}
+
+// AddResolve maps the host:port key to addrs. A bare IP address connects to
+// the port of the key.
+func (d *DNSConfig) AddResolve(key string, addrs ...string) {
+	key = strings.ToLower(strings.TrimSpace(key))
+	_, port, _ := net.SplitHostPort(key)
+	if d.Resolve == nil {
+		d.Resolve = map[string][]string{}
+	}
+	if _, ok := d.Resolve[key]; !ok {
+		d.Resolve[key] = nil
+	}
+	for _, addr := range addrs {
+		addr = strings.TrimSpace(addr)
+		if ip := strings.TrimSuffix(strings.TrimPrefix(addr, "["), "]"); net.ParseIP(ip) != nil && port != "" {
+			addr = net.JoinHostPort(ip, port)
+		}
+		d.Resolve[key] = append(d.Resolve[key], addr)
+	}
+}
+
+// TLSVersion returns the crypto/tls constant for a version such as "1.2" or
+// "TLS1.3".
+func TLSVersion(name string) (uint16, bool) {
+	v := strings.ToLower(strings.TrimSpace(name))
+	v = strings.TrimSpace(strings.TrimPrefix(v, "tls"))
+	switch v {
+	case "1.0":
+		return tls.VersionTLS10, true
+	case "1.1":
+		return tls.VersionTLS11, true
+	case "1.2":
+		return tls.VersionTLS12, true
+	case "1.3":
+		return tls.VersionTLS13, true
+	}
+	return 0, false
+}
+
+// CipherSuite returns the ID of a cipher suite named as in crypto/tls, e.g.
+// TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256.
+func CipherSuite(name string) (uint16, bool) {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	for _, list := range [][]*tls.CipherSuite{tls.CipherSuites(), tls.InsecureCipherSuites()} {
+		for _, cs := range list {
+			if cs.Name == name {
+				return cs.ID, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// CookiesConfig gives every virtual user its own cookie jar for HTTP
+// requests. The jar starts each iteration with only the seeded cookies, so
+// cookies set by one iteration never leak into the next.
+type CookiesConfig struct {
+	Jar     bool            `mapstructure:"jar"`     // keep a per-iteration cookie jar (implied by seed and extract)
+	Seed    []Cookie        `mapstructure:"seed"`    // cookies every iteration starts with
+	Extract []CookieExtract `mapstructure:"extract"` // cookies copied into variables after each response
+}
+
+// Enabled returns true when requests should go through a cookie jar.
+func (c CookiesConfig) Enabled() bool {
+	return c.Jar || len(c.Seed) > 0 || len(c.Extract) > 0
+}
+
+// Cookie is a cookie placed in the jar at the start of each iteration.
+type Cookie struct {
+	Name   string `mapstructure:"name" yaml:"name"`
+	Value  string `mapstructure:"value" yaml:"value"`
+	Domain string `mapstructure:"domain" yaml:"domain"` // default: host of the target URL
+	Path   string `mapstructure:"path" yaml:"path"`     // default: /
+	Secure bool   `mapstructure:"secure" yaml:"secure"`
+}
+
+// CookieExtract stores the value of a cookie from the jar in a variable, for
+// use in placeholders of later requests in the same iteration.
+type CookieExtract struct {
+	Cookie   string `mapstructure:"cookie" yaml:"cookie"`
+	Variable string `mapstructure:"var" yaml:"var"`
+}
+
+// BaselineConfig compares the run against an earlier one and fails it when a
+// latency percentile or the error rate regresses beyond the tolerances.
+type BaselineConfig struct {
+	Run                string  `mapstructure:"run"`                  // session run ID (<run-id> or <session-id>/<run-id>) or JSON report file
+	LatencyTolerance   float64 `mapstructure:"latency_tolerance"`    // allowed relative percentile increase (default: 0.10)
+	ErrorRateTolerance float64 `mapstructure:"error_rate_tolerance"` // allowed absolute error rate increase (default: 0.01)
+}
+
+// Enabled returns true when a baseline run is configured.
+func (b BaselineConfig) Enabled() bool {
+	return strings.TrimSpace(b.Run) != ""
+}
+
+// PrometheusConfig exposes live metrics for Prometheus to scrape.
+type PrometheusConfig struct {
+	Listen string            `mapstructure:"listen"` // address for the /metrics endpoint (e.g., :9464)
+	Labels map[string]string `mapstructure:"labels"` // constant labels added to every series
+}
+
+// Enabled returns true when a listen address is configured.
+func (p PrometheusConfig) Enabled() bool {
+	return strings.TrimSpace(p.Listen) != ""
 }
 
 type TracingConfig struct {
@@ -72,6 +236,47 @@ func (t TracingConfig) ShouldPropagate() bool {
 	return t.Enabled()
 }
 
+// Resource attributes that identify the test run in exported OTLP metrics.
+const (
+	ResourceSessionID = "crankfire.session.id"
+	ResourceRunID     = "crankfire.run.id"
+	ResourceSetID     = "crankfire.set.id"
+	ResourceSetRunID  = "crankfire.set_run.id"
+	ResourceSetItem   = "crankfire.set_item"
+)
+
+// DefaultOTLPMetricsInterval is how often metrics are exported when no
+// interval is configured.
+const DefaultOTLPMetricsInterval = 10 * time.Second
+
+// OTLPMetricsConfig exports live metrics to an OpenTelemetry collector.
+type OTLPMetricsConfig struct {
+	Endpoint   string            `mapstructure:"endpoint"`   // OTLP endpoint (e.g., localhost:4317)
+	Protocol   string            `mapstructure:"protocol"`   // "grpc" or "http" (OTLP transport, default: grpc)
+	Insecure   bool              `mapstructure:"insecure"`   // skip TLS for exporter connection
+	Interval   time.Duration     `mapstructure:"interval"`   // export interval (default: 10s)
+	Attributes map[string]string `mapstructure:"attributes"` // resource attributes added to every metric
+}
+
+// Enabled returns true when a metrics endpoint is configured.
+func (m OTLPMetricsConfig) Enabled() bool {
+	return strings.TrimSpace(m.Endpoint) != ""
+}
+
+// WithAttributes returns a copy of m with the given key/value pairs added to
+// its resource attributes. The receiver's map is not modified.
+func (m OTLPMetricsConfig) WithAttributes(kv ...string) OTLPMetricsConfig {
+	attrs := make(map[string]string, len(m.Attributes)+len(kv)/2)
+	for k, v := range m.Attributes {
+		attrs[k] = v
+	}
+	for i := 0; i+1 < len(kv); i += 2 {
+		attrs[kv[i]] = kv[i+1]
+	}
+	m.Attributes = attrs
+	return m
+}
+
 type LoadPatternType string
 
 const (
@@ -101,10 +306,33 @@ type ArrivalModel string
 const (
 	ArrivalModelUniform ArrivalModel = "uniform"
 	ArrivalModelPoisson ArrivalModel = "poisson"
+	ArrivalModelClosed  ArrivalModel = "closed"
 )
 
 type ArrivalConfig struct {
-	Model ArrivalModel `mapstructure:"model"`
+	Model     ArrivalModel    `mapstructure:"model"`
+	ThinkTime ThinkTimeConfig `mapstructure:"think_time"` // closed model: pause after each iteration
+	Pacing    time.Duration   `mapstructure:"pacing"`     // closed model: minimum time between iteration starts per VU
+}
+
+type ThinkTimeDistribution string
+
+const (
+	ThinkTimeConstant    ThinkTimeDistribution = "constant"
+	ThinkTimeUniform     ThinkTimeDistribution = "uniform"
+	ThinkTimeExponential ThinkTimeDistribution = "exponential"
+)
+
+type ThinkTimeConfig struct {
+	Distribution ThinkTimeDistribution `mapstructure:"distribution"` // constant (default), uniform or exponential
+	Duration     time.Duration         `mapstructure:"duration"`     // constant value or exponential mean
+	Min          time.Duration         `mapstructure:"min"`          // uniform lower bound
+	Max          time.Duration         `mapstructure:"max"`          // uniform upper bound
+}
+
+// IsZero reports whether no think time is configured.
+func (t ThinkTimeConfig) IsZero() bool {
+	return t == ThinkTimeConfig{}
 }
 
 type Extractor struct {
@@ -121,6 +349,55 @@ type Extractor struct {
 	OnError bool `mapstructure:"on_error" yaml:"on_error"`
 }
 
+// Check is an assertion evaluated against every response of an endpoint.
+// Each check tests exactly one of status, header, jsonpath, body size or regex.
+type Check struct {
+	// Name identifies the check in reports and thresholds (default: endpoint and kind)
+	Name string `mapstructure:"name" yaml:"name"`
+
+	// Status passes when the response status code is one of the listed codes
+	Status []int `mapstructure:"status" yaml:"status"`
+
+	// Header names a response header; without Equals or Matches it must be present
+	Header string `mapstructure:"header" yaml:"header"`
+
+	// JSONPath selects a body value; without Equals or Contains it must exist
+	JSONPath string `mapstructure:"jsonpath" yaml:"jsonpath"`
+
+	// Regex passes when the response body matches the pattern
+	Regex string `mapstructure:"regex" yaml:"regex"`
+
+	// Equals compares the header or JSONPath value exactly
+	Equals string `mapstructure:"equals" yaml:"equals"`
+
+	// Contains requires the JSONPath value to contain the substring
+	Contains string `mapstructure:"contains" yaml:"contains"`
+
+	// Matches requires the header value to match the pattern
+	Matches string `mapstructure:"matches" yaml:"matches"`
+
+	// MinBodySize and MaxBodySize bound the response body in bytes (0 = no bound)
+	MinBodySize int `mapstructure:"min_body_size" yaml:"min_body_size"`
+	MaxBodySize int `mapstructure:"max_body_size" yaml:"max_body_size"`
+}
+
+// Kind reports what the check tests, or "" when nothing is set.
+func (c Check) Kind() string {
+	switch {
+	case len(c.Status) > 0:
+		return "status"
+	case c.Header != "":
+		return "header"
+	case c.JSONPath != "":
+		return "jsonpath"
+	case c.Regex != "":
+		return "regex"
+	case c.MinBodySize != 0 || c.MaxBodySize != 0:
+		return "body_size"
+	}
+	return ""
+}
+
 type Endpoint struct {
 	Name       string            `mapstructure:"name"`
 	Weight     int               `mapstructure:"weight"`
@@ -131,34 +408,113 @@ type Endpoint struct {
 	Body       string            `mapstructure:"body"`
 	BodyFile   string            `mapstructure:"body_file"`
 	Extractors []Extractor       `mapstructure:"extractors" yaml:"extractors"`
+	Checks     []Check           `mapstructure:"checks" yaml:"checks"`
+}
+
+// Scenario is a user journey: each iteration runs its steps in order for one
+// virtual user, with variables scoped to that iteration.
+type Scenario struct {
+	Name      string         `mapstructure:"name"`
+	Weight    int            `mapstructure:"weight"`
+	ThinkTime time.Duration  `mapstructure:"think_time"` // Pause between steps unless a step overrides it
+	Steps     []ScenarioStep `mapstructure:"steps"`
+}
+
+// ScenarioStep is one request in a scenario. It accepts every endpoint field
+// except weight.
+type ScenarioStep struct {
+	Endpoint  `mapstructure:",squash"`
+	ThinkTime *time.Duration `mapstructure:"think_time"` // Pause after this step (overrides the scenario default)
 }
 
 type FeederConfig struct {
 	Path string `mapstructure:"path"`
 	Type string `mapstructure:"type"` // "csv" or "json"
+
+	// Partition and Partitions restrict the feeder to every Partitions-th
+	// record starting at Partition. The distributed coordinator sets them so
+	// agents read disjoint records; Partitions 0 means the whole dataset.
+	Partition  int `mapstructure:"-"`
+	Partitions int `mapstructure:"-"`
 }
 
 type WebSocketConfig struct {
-	Messages         []string      `mapstructure:"messages"`          // Messages to send
-	MessageInterval  time.Duration `mapstructure:"message_interval"`  // Interval between messages
-	ReceiveTimeout   time.Duration `mapstructure:"receive_timeout"`   // Timeout for receiving responses
-	HandshakeTimeout time.Duration `mapstructure:"handshake_timeout"` // WebSocket handshake timeout
+	Messages         []string        `mapstructure:"messages"`          // Messages to send
+	BinaryMessages   []string        `mapstructure:"binary_messages"`   // Base64-encoded binary frames sent after messages
+	MessageFiles     []string        `mapstructure:"message_files"`     // Files sent as binary frames after binary_messages
+	MessageInterval  time.Duration   `mapstructure:"message_interval"`  // Interval between messages
+	ReceiveTimeout   time.Duration   `mapstructure:"receive_timeout"`   // Timeout for receiving responses
+	HandshakeTimeout time.Duration   `mapstructure:"handshake_timeout"` // WebSocket handshake timeout
+	Compression      bool            `mapstructure:"compression"`       // Negotiate permessage-deflate
+	CompressionLevel int             `mapstructure:"compression_level"` // Deflate level -2..9 (0 = library default)
+	Steps            []WebSocketStep `mapstructure:"steps"`             // Scripted conversation (replaces messages)
+}
+
+// WebSocketStep is one action in a scripted WebSocket conversation. Exactly one
+// of Send (or SendBinary/SendFile), Expect, Extract, Sleep or Close is set;
+// Extract may also accompany Expect to capture values from the matched message.
+type WebSocketStep struct {
+	Name       string           `mapstructure:"name"`
+	Send       string           `mapstructure:"send"`        // Text frame
+	SendBinary string           `mapstructure:"send_binary"` // Base64-encoded binary frame
+	SendFile   string           `mapstructure:"send_file"`   // File sent as a binary frame
+	Expect     *WebSocketExpect `mapstructure:"expect"`
+	Extract    []Extractor      `mapstructure:"extract"`
+	Sleep      time.Duration    `mapstructure:"sleep"`
+	Close      int              `mapstructure:"close"`   // Close code (e.g. 1000)
+	Timeout    time.Duration    `mapstructure:"timeout"` // Expect timeout (default: receive_timeout)
+}
+
+// WebSocketExpect is the predicate an incoming message must satisfy.
+// Messages that do not match are skipped until the step times out.
+type WebSocketExpect struct {
+	Regex    string `mapstructure:"regex"`
+	JSONPath string `mapstructure:"jsonpath"`
+	Equals   string `mapstructure:"equals"` // Optional exact value for the regex capture or JSONPath result
+}
+
+// Action reports which action the step performs, or "" when none is set.
+func (s WebSocketStep) Action() string {
+	switch {
+	case s.Send != "" || s.SendBinary != "" || s.SendFile != "":
+		return "send"
+	case s.Expect != nil:
+		return "expect"
+	case len(s.Extract) > 0:
+		return "extract"
+	case s.Sleep > 0:
+		return "sleep"
+	case s.Close != 0:
+		return "close"
+	}
+	return ""
 }
 
 type SSEConfig struct {
-	ReadTimeout time.Duration `mapstructure:"read_timeout"` // Timeout for reading events
-	MaxEvents   int           `mapstructure:"max_events"`   // Max events to read (0=unlimited)
+	ReadTimeout    time.Duration `mapstructure:"read_timeout"`    // Timeout for reading events
+	MaxEvents      int           `mapstructure:"max_events"`      // Max events to read (0=unlimited)
+	Subscribe      bool          `mapstructure:"subscribe"`       // Long-lived subscriber that reconnects per the SSE spec
+	ReconnectDelay time.Duration `mapstructure:"reconnect_delay"` // Reconnect delay until the server sends retry (default 3s)
+	EventTypes     []string      `mapstructure:"event_types"`     // Only count these event types (default: all)
+	LagField       string        `mapstructure:"lag_field"`       // JSONPath to the event's publish timestamp in data
 }
 
 type GRPCConfig struct {
-	ProtoFile string            `mapstructure:"proto_file"` // Path to .proto file
-	Service   string            `mapstructure:"service"`    // Service name (e.g., "helloworld.Greeter")
-	Method    string            `mapstructure:"method"`     // Method name (e.g., "SayHello")
-	Message   string            `mapstructure:"message"`    // JSON message payload (supports templates)
-	Metadata  map[string]string `mapstructure:"metadata"`   // gRPC metadata (headers)
-	Timeout   time.Duration     `mapstructure:"timeout"`    // Per-call timeout
-	TLS       bool              `mapstructure:"tls"`        // Use TLS
-	Insecure  bool              `mapstructure:"insecure"`   // Skip TLS verification
+	ProtoFile       string            `mapstructure:"proto_file"`       // Path to .proto file
+	Protosets       []string          `mapstructure:"protosets"`        // Precompiled FileDescriptorSet files (.protoset/.pb)
+	Reflection      bool              `mapstructure:"reflection"`       // Fetch descriptors via the server reflection service
+	ReflectionCache string            `mapstructure:"reflection_cache"` // FileDescriptorSet file caching reflected descriptors across runs
+	Service         string            `mapstructure:"service"`          // Service name (e.g., "helloworld.Greeter")
+	Method          string            `mapstructure:"method"`           // Method name (e.g., "SayHello")
+	Message         string            `mapstructure:"message"`          // JSON message payload (supports templates)
+	Messages        []string          `mapstructure:"messages"`         // JSON messages sent on client/bidi streams (supports templates)
+	MessageInterval time.Duration     `mapstructure:"message_interval"` // Interval between streamed messages
+	ExpectResponses int               `mapstructure:"expect_responses"` // Responses to receive before ending a stream (0=until server closes)
+	StreamTimeout   time.Duration     `mapstructure:"stream_timeout"`   // Per-stream deadline (0=use timeout)
+	Metadata        map[string]string `mapstructure:"metadata"`         // gRPC metadata (headers)
+	Timeout         time.Duration     `mapstructure:"timeout"`          // Per-call timeout
+	TLS             bool              `mapstructure:"tls"`              // Use TLS
+	Insecure        bool              `mapstructure:"insecure"`         // Skip TLS verification
 }
 
 type AuthType string
@@ -217,6 +573,18 @@ func (c Config) Validate() error {
 			if allProvideURL {
 				targetSatisfied = true
 			}
+		} else if len(c.Scenarios) > 0 {
+			allProvideURL := true
+			for _, sc := range c.Scenarios {
+				for _, step := range sc.Steps {
+					if strings.TrimSpace(step.URL) == "" {
+						allProvideURL = false
+					}
+				}
+			}
+			if allProvideURL {
+				targetSatisfied = true
+			}
 		}
 		if !targetSatisfied {
 			issues = append(issues, "target is required (use --help for usage information)")
@@ -264,8 +632,30 @@ func (c Config) Validate() error {
 	if c.Dashboard && c.JSONOutput {
 		issues = append(issues, "dashboard and json-output are mutually exclusive")
 	}
-
-	arrivalIssues := validateArrivalConfig(c.Arrival)
+	issues = append(issues, validateHTTPVersion(c.HTTPVersion, c.Protocol, c.TargetURL)...)
+	if len(c.Agents) > 0 {
+		issues = append(issues, validateAgents(c.Agents)...)
+		if c.Dashboard {
+			issues = append(issues, "dashboard is not supported with agents")
+		}
+		if c.WebDashboard != "" {
+			issues = append(issues, "web_dashboard is not supported with agents")
+		}
+		if c.Prometheus.Enabled() {
+			issues = append(issues, "prometheus is not supported with agents")
+		}
+		if c.OTLPMetrics.Enabled() {
+			issues = append(issues, "otlp_metrics is not supported with agents")
+		}
+	}
+	issues = append(issues, validatePrometheusConfig(c.Prometheus)...)
+	issues = append(issues, validateOTLPMetricsConfig(c.OTLPMetrics)...)
+	issues = append(issues, validateBaselineConfig(c.Baseline)...)
+	issues = append(issues, validateCookiesConfig(c.Cookies, c.Protocol)...)
+	issues = append(issues, validateTLSConfig(c.TLS)...)
+	issues = append(issues, validateDNSConfig(c.DNS)...)
+
+	arrivalIssues := validateArrivalConfig(c.Arrival, c.Rate)
 	if len(arrivalIssues) > 0 {
 		issues = append(issues, arrivalIssues...)
 	}
@@ -280,6 +670,16 @@ func (c Config) Validate() error {
 		issues = append(issues, endpointIssues...)
 	}
 
+	if len(c.Scenarios) > 0 {
+		if len(c.Endpoints) > 0 {
+			issues = append(issues, "scenarios and endpoints are mutually exclusive")
+		}
+		if c.Protocol != "" && c.Protocol != ProtocolHTTP {
+			issues = append(issues, "scenarios are only supported for the http protocol")
+		}
+		issues = append(issues, validateScenarios(c.Scenarios)...)
+	}
+
 	authIssues := validateAuthConfig(c.Auth)
 	if len(authIssues) > 0 {
 		issues = append(issues, authIssues...)
@@ -317,17 +717,274 @@ func (c Config) Validate() error {
 	return nil
 }
 
-func validateArrivalConfig(arr ArrivalConfig) []string {
+func validateAgents(agents []string) []string {
+	var issues []string
+	seen := make(map[string]struct{}, len(agents))
+	for i, agent := range agents {
+		addr := strings.TrimSpace(agent)
+		if addr == "" {
+			issues = append(issues, fmt.Sprintf("agents[%d]: address is required", i))
+			continue
+		}
+		if _, dup := seen[addr]; dup {
+			issues = append(issues, fmt.Sprintf("agents[%d]: duplicate address %q", i, addr))
+		}
+		seen[addr] = struct{}{}
+	}
+	return issues
+}
+
+// prometheusReservedLabels are the label names crankfire's series already use.
+var prometheusReservedLabels = map[string]bool{
+	"le": true, "result": true, "endpoint": true, "step": true, "scenario": true,
+	"timing": true, "check": true, "protocol": true, "status": true, "metric": true,
+}
+
+func validatePrometheusConfig(p PrometheusConfig) []string {
+	var issues []string
+	if len(p.Labels) > 0 && !p.Enabled() {
+		issues = append(issues, "prometheus: labels require listen")
+	}
+	names := make([]string, 0, len(p.Labels))
+	for name := range p.Labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		switch {
+		case !isValidIdentifier(name) || strings.HasPrefix(name, "__"):
+			issues = append(issues, fmt.Sprintf("prometheus: invalid label name %q", name))
+		case prometheusReservedLabels[name]:
+			issues = append(issues, fmt.Sprintf("prometheus: label %q is used by crankfire's own series", name))
+		}
+	}
+	return issues
+}
+
+func validateOTLPMetricsConfig(m OTLPMetricsConfig) []string {
+	var issues []string
+	if !m.Enabled() {
+		if len(m.Attributes) > 0 {
+			issues = append(issues, "otlp_metrics: attributes require endpoint")
+		}
+		return issues
+	}
+	switch strings.ToLower(m.Protocol) {
+	case "", "grpc", "http":
+	default:
+		issues = append(issues, fmt.Sprintf("otlp_metrics: unsupported protocol %q: use \"grpc\" or \"http\"", m.Protocol))
+	}
+	if m.Interval < 0 {
+		issues = append(issues, "otlp_metrics: interval must be non-negative")
+	}
+	for name := range m.Attributes {
+		if strings.TrimSpace(name) == "" {
+			issues = append(issues, "otlp_metrics: attribute names must not be empty")
+			break
+		}
+	}
+	return issues
+}
+
+func validateBaselineConfig(b BaselineConfig) []string {
+	var issues []string
+	if b.LatencyTolerance < 0 {
+		issues = append(issues, "baseline: latency_tolerance must be non-negative")
+	}
+	if b.ErrorRateTolerance < 0 {
+		issues = append(issues, "baseline: error_rate_tolerance must be non-negative")
+	}
+	if !b.Enabled() && (b.LatencyTolerance != 0 || b.ErrorRateTolerance != 0) {
+		issues = append(issues, "baseline: tolerances require run")
+	}
+	return issues
+}
+
+func validateHTTPVersion(version string, protocol Protocol, target string) []string {
+	switch version {
+	case "":
+		return nil
+	case HTTPVersion1, HTTPVersion2, HTTPVersionH2C, HTTPVersion3:
+	default:
+		return []string{fmt.Sprintf("http_version must be one of 1.1, 2, h2c or 3, got %q", version)}
+	}
+	var issues []string
+	if protocol != "" && protocol != ProtocolHTTP {
+		issues = append(issues, fmt.Sprintf("http_version: not supported with protocol %q", protocol))
+	}
+	scheme, _, _ := strings.Cut(strings.ToLower(target), "://")
+	switch {
+	case (version == HTTPVersion2 || version == HTTPVersion3) && scheme == "http":
+		issues = append(issues, fmt.Sprintf("http_version %s requires an https target", version))
+	case version == HTTPVersionH2C && scheme == "https":
+		issues = append(issues, "http_version h2c requires an http target")
+	}
+	return issues
+}
+
+func validateTLSConfig(t TLSConfig) []string {
+	var issues []string
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		issues = append(issues, "tls: cert_file and key_file must be set together")
+	}
+	var minVersion, maxVersion uint16
+	if t.MinVersion != "" {
+		v, ok := TLSVersion(t.MinVersion)
+		if !ok {
+			issues = append(issues, fmt.Sprintf("tls: min_version: unsupported version %q (use 1.0, 1.1, 1.2 or 1.3)", t.MinVersion))
+		}
+		minVersion = v
+	}
+	if t.MaxVersion != "" {
+		v, ok := TLSVersion(t.MaxVersion)
+		if !ok {
+			issues = append(issues, fmt.Sprintf("tls: max_version: unsupported version %q (use 1.0, 1.1, 1.2 or 1.3)", t.MaxVersion))
+		}
+		maxVersion = v
+	}
+	if minVersion != 0 && maxVersion != 0 && minVersion > maxVersion {
+		issues = append(issues, "tls: min_version must not exceed max_version")
+	}
+	for _, name := range t.CipherSuites {
+		if _, ok := CipherSuite(name); !ok {
+			issues = append(issues, fmt.Sprintf("tls: cipher_suites: unknown cipher suite %q", name))
+		}
+	}
+	for i, proto := range t.ALPN {
+		if strings.TrimSpace(proto) == "" {
+			issues = append(issues, fmt.Sprintf("tls: alpn[%d]: protocol must not be empty", i))
+		}
+	}
+	return issues
+}
+
+func validateDNSConfig(d DNSConfig) []string {
+	var issues []string
+	keys := make([]string, 0, len(d.Resolve))
+	for key := range d.Resolve {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		host, port, err := net.SplitHostPort(key)
+		if err != nil || host == "" || !validPort(port) {
+			issues = append(issues, fmt.Sprintf("dns: resolve: key %q must be host:port", key))
+			continue
+		}
+		addrs := d.Resolve[key]
+		if len(addrs) == 0 {
+			issues = append(issues, fmt.Sprintf("dns: resolve[%s]: at least one address is required", key))
+		}
+		for _, addr := range addrs {
+			ip, port, err := net.SplitHostPort(addr)
+			if err != nil || net.ParseIP(ip) == nil || !validPort(port) {
+				issues = append(issues, fmt.Sprintf("dns: resolve[%s]: address %q must be ip:port", key, addr))
+			}
+		}
+	}
+	if d.Server != "" {
+		server := d.Server
+		if host, port, err := net.SplitHostPort(server); err == nil {
+			if !validPort(port) {
+				issues = append(issues, fmt.Sprintf("dns: server: invalid port in %q", d.Server))
+			}
+			server = host
+		}
+		if net.ParseIP(server) == nil {
+			issues = append(issues, fmt.Sprintf("dns: server: %q must be an IP address, optionally with a port", d.Server))
+		}
+	}
+	return issues
+}
+
+func validPort(port string) bool {
+	n, err := strconv.Atoi(port)
+	return err == nil && n > 0 && n <= 65535
+}
+
+func validateCookiesConfig(c CookiesConfig, protocol Protocol) []string {
+	var issues []string
+	if c.Enabled() && protocol != "" && protocol != ProtocolHTTP {
+		issues = append(issues, fmt.Sprintf("cookies: not supported with protocol %q", protocol))
+	}
+	for i, ck := range c.Seed {
+		switch {
+		case !isValidCookieName(ck.Name):
+			issues = append(issues, fmt.Sprintf("cookies: seed[%d]: invalid name %q", i, ck.Name))
+		case strings.ContainsAny(ck.Value, "\";\r\n"):
+			issues = append(issues, fmt.Sprintf("cookies: seed[%d]: invalid value for %s", i, ck.Name))
+		}
+		if ck.Path != "" && !strings.HasPrefix(ck.Path, "/") {
+			issues = append(issues, fmt.Sprintf("cookies: seed[%d]: path must start with /", i))
+		}
+	}
+	for i, ex := range c.Extract {
+		if strings.TrimSpace(ex.Cookie) == "" {
+			issues = append(issues, fmt.Sprintf("cookies: extract[%d]: cookie is required", i))
+		}
+		if strings.TrimSpace(ex.Variable) == "" {
+			issues = append(issues, fmt.Sprintf("cookies: extract[%d]: var is required", i))
+		}
+	}
+	return issues
+}
+
+// isValidCookieName reports whether name is an RFC 6265 token.
+func isValidCookieName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if r <= ' ' || r >= 0x7f || strings.ContainsRune("()<>@,;:\\\"/[]?={}", r) {
+			return false
+		}
+	}
+	return true
+}
+
+func validateArrivalConfig(arr ArrivalConfig, rate int) []string {
 	model := arr.Model
 	if model == "" {
 		model = ArrivalModelUniform
 	}
 	switch model {
 	case ArrivalModelUniform, ArrivalModelPoisson:
+		if !arr.ThinkTime.IsZero() || arr.Pacing != 0 {
+			return []string{"arrival: think_time and pacing require the closed model"}
+		}
 		return nil
+	case ArrivalModelClosed:
 	default:
 		return []string{fmt.Sprintf("arrival model %q is not supported", model)}
 	}
+
+	var issues []string
+	if rate > 0 {
+		issues = append(issues, "arrival: rate is not supported by the closed model (set concurrency or load_patterns to size the virtual users)")
+	}
+	if arr.Pacing < 0 {
+		issues = append(issues, "arrival: pacing must be >= 0")
+	}
+	think := arr.ThinkTime
+	if think.Duration < 0 || think.Min < 0 || think.Max < 0 {
+		issues = append(issues, "arrival: think_time durations must be >= 0")
+	}
+	switch think.Distribution {
+	case "", ThinkTimeConstant, ThinkTimeExponential:
+		if think.Min != 0 || think.Max != 0 {
+			issues = append(issues, "arrival: think_time min and max require the uniform distribution")
+		}
+	case ThinkTimeUniform:
+		if think.Duration != 0 {
+			issues = append(issues, "arrival: think_time duration is not used by the uniform distribution (set min and max)")
+		}
+		if think.Max < think.Min {
+			issues = append(issues, "arrival: think_time max must be >= min")
+		}
+	default:
+		issues = append(issues, fmt.Sprintf("arrival: think_time distribution %q is not supported (use constant, uniform or exponential)", think.Distribution))
+	}
+	return issues
 }
 
 func validateLoadPatterns(patterns []LoadPattern) []string {
@@ -386,9 +1043,7 @@ func validateEndpoints(endpoints []Endpoint) []string {
 		if ep.Weight <= 0 {
 			issues = append(issues, fmt.Sprintf("endpoints[%d]: weight must be >= 1", idx))
 		}
-		if strings.TrimSpace(ep.Body) != "" && strings.TrimSpace(ep.BodyFile) != "" {
-			issues = append(issues, fmt.Sprintf("endpoints[%d]: body and bodyFile are mutually exclusive", idx))
-		}
+		issues = append(issues, validateEndpointRequest(fmt.Sprintf("endpoints[%d]", idx), ep)...)
 		name := strings.TrimSpace(ep.Name)
 		if name != "" {
 			key := strings.ToLower(name)
@@ -398,17 +1053,126 @@ func validateEndpoints(endpoints []Endpoint) []string {
 				seenNames[key] = idx
 			}
 		}
+	}
+	return issues
+}
 
-		// Validate extractors
-		extractorIssues := validateExtractors(idx, ep.Extractors)
-		if len(extractorIssues) > 0 {
-			issues = append(issues, extractorIssues...)
+// validateEndpointRequest checks the request fields shared by endpoints and
+// scenario steps.
+func validateEndpointRequest(prefix string, ep Endpoint) []string {
+	var issues []string
+	if strings.TrimSpace(ep.Body) != "" && strings.TrimSpace(ep.BodyFile) != "" {
+		issues = append(issues, fmt.Sprintf("%s: body and bodyFile are mutually exclusive", prefix))
+	}
+
+	// Validate extractors
+	extractorIssues := validateExtractors(prefix+".extractors", ep.Extractors)
+	if len(extractorIssues) > 0 {
+		issues = append(issues, extractorIssues...)
+	}
+	issues = append(issues, validateChecks(prefix+".checks", ep.Checks)...)
+	return issues
+}
+
+func validateScenarios(scenarios []Scenario) []string {
+	var issues []string
+	seenNames := map[string]int{}
+	for idx, sc := range scenarios {
+		prefix := fmt.Sprintf("scenarios[%d]", idx)
+		name := strings.TrimSpace(sc.Name)
+		if name == "" {
+			issues = append(issues, fmt.Sprintf("%s: name is required", prefix))
+		} else if prev, ok := seenNames[strings.ToLower(name)]; ok {
+			issues = append(issues, fmt.Sprintf("%s: duplicate name also defined at index %d", prefix, prev))
+		} else {
+			seenNames[strings.ToLower(name)] = idx
+		}
+		if sc.Weight <= 0 {
+			issues = append(issues, fmt.Sprintf("%s: weight must be >= 1", prefix))
+		}
+		if sc.ThinkTime < 0 {
+			issues = append(issues, fmt.Sprintf("%s: think_time must be >= 0", prefix))
+		}
+		if len(sc.Steps) == 0 {
+			issues = append(issues, fmt.Sprintf("%s: at least one step is required", prefix))
+		}
+		stepNames := map[string]int{}
+		for stepIdx, step := range sc.Steps {
+			stepPrefix := fmt.Sprintf("%s.steps[%d]", prefix, stepIdx)
+			if stepName := strings.ToLower(strings.TrimSpace(step.Name)); stepName != "" {
+				if prev, ok := stepNames[stepName]; ok {
+					issues = append(issues, fmt.Sprintf("%s: duplicate name also defined at index %d", stepPrefix, prev))
+				} else {
+					stepNames[stepName] = stepIdx
+				}
+			}
+			if step.ThinkTime != nil && *step.ThinkTime < 0 {
+				issues = append(issues, fmt.Sprintf("%s: think_time must be >= 0", stepPrefix))
+			}
+			issues = append(issues, validateEndpointRequest(stepPrefix, step.Endpoint)...)
+		}
+	}
+	return issues
+}
+
+func validateChecks(prefix string, checks []Check) []string {
+	var issues []string
+	for idx, check := range checks {
+		at := fmt.Sprintf("%s[%d]", prefix, idx)
+		kinds := 0
+		if len(check.Status) > 0 {
+			kinds++
+		}
+		for _, set := range []bool{check.Header != "", check.JSONPath != "", check.Regex != "", check.MinBodySize != 0 || check.MaxBodySize != 0} {
+			if set {
+				kinds++
+			}
+		}
+		switch {
+		case kinds == 0:
+			issues = append(issues, fmt.Sprintf("%s: one of status, header, jsonpath, regex, min_body_size or max_body_size is required", at))
+			continue
+		case kinds > 1:
+			issues = append(issues, fmt.Sprintf("%s: status, header, jsonpath, regex and body size checks are mutually exclusive", at))
+			continue
+		}
+
+		kind := check.Kind()
+		for _, code := range check.Status {
+			if code < 100 || code > 599 {
+				issues = append(issues, fmt.Sprintf("%s: status %d is not a valid HTTP status code", at, code))
+			}
+		}
+		if check.Equals != "" && kind != "header" && kind != "jsonpath" {
+			issues = append(issues, fmt.Sprintf("%s: equals requires header or jsonpath", at))
+		}
+		if check.Contains != "" && kind != "jsonpath" {
+			issues = append(issues, fmt.Sprintf("%s: contains requires jsonpath", at))
+		}
+		if check.Matches != "" && kind != "header" {
+			issues = append(issues, fmt.Sprintf("%s: matches requires header", at))
+		}
+		if check.Equals != "" && (check.Contains != "" || check.Matches != "") {
+			issues = append(issues, fmt.Sprintf("%s: equals cannot be combined with contains or matches", at))
+		}
+		for _, pattern := range []string{check.Matches, check.Regex} {
+			if pattern == "" {
+				continue
+			}
+			if _, err := regexp.Compile(pattern); err != nil {
+				issues = append(issues, fmt.Sprintf("%s: invalid regex %q: %v", at, pattern, err))
+			}
+		}
+		if check.MinBodySize < 0 || check.MaxBodySize < 0 {
+			issues = append(issues, fmt.Sprintf("%s: body size bounds must be >= 0", at))
+		} else if check.MaxBodySize > 0 && check.MinBodySize > check.MaxBodySize {
+			issues = append(issues, fmt.Sprintf("%s: min_body_size must be <= max_body_size", at))
 		}
 	}
 	return issues
 }
 
-func validateExtractors(endpointIdx int, extractors []Extractor) []string {
+func validateExtractors(prefix string, extractors []Extractor) []string {
 	var issues []string
 	for idx, ext := range extractors {
 		// Check that exactly one of JSONPath or Regex is provided
@@ -416,23 +1180,78 @@ func validateExtractors(endpointIdx int, extractors []Extractor) []string {
 		hasRegex := strings.TrimSpace(ext.Regex) != ""
 
 		if !hasJSONPath && !hasRegex {
-			issues = append(issues, fmt.Sprintf("endpoints[%d].extractors[%d]: either jsonpath or regex is required", endpointIdx, idx))
+			issues = append(issues, fmt.Sprintf("%s[%d]: either jsonpath or regex is required", prefix, idx))
 		}
 		if hasJSONPath && hasRegex {
-			issues = append(issues, fmt.Sprintf("endpoints[%d].extractors[%d]: either jsonpath or regex must be specified, not both", endpointIdx, idx))
+			issues = append(issues, fmt.Sprintf("%s[%d]: either jsonpath or regex must be specified, not both", prefix, idx))
 		}
 
 		// Check that Variable is provided and valid
 		varName := strings.TrimSpace(ext.Variable)
 		if varName == "" {
-			issues = append(issues, fmt.Sprintf("endpoints[%d].extractors[%d]: var is required", endpointIdx, idx))
+			issues = append(issues, fmt.Sprintf("%s[%d]: var is required", prefix, idx))
 		} else if !isValidIdentifier(varName) {
-			issues = append(issues, fmt.Sprintf("endpoints[%d].extractors[%d]: var must be a valid identifier (letters, numbers, underscores; cannot start with number)", endpointIdx, idx))
+			issues = append(issues, fmt.Sprintf("%s[%d]: var must be a valid identifier (letters, numbers, underscores; cannot start with number)", prefix, idx))
 		}
 	}
 	return issues
 }
 
+func validateWebSocketSteps(steps []WebSocketStep) []string {
+	var issues []string
+	for idx, step := range steps {
+		prefix := fmt.Sprintf("websocket: steps[%d]", idx)
+		actions := 0
+		for _, payload := range []string{step.Send, step.SendBinary, step.SendFile} {
+			if payload != "" {
+				actions++
+			}
+		}
+		if step.Expect != nil || len(step.Extract) > 0 {
+			actions++
+		}
+		if step.Sleep != 0 {
+			actions++
+		}
+		if step.Close != 0 {
+			actions++
+		}
+		if actions == 0 {
+			issues = append(issues, prefix+": one of send, send_binary, send_file, expect, extract, sleep or close is required")
+		} else if actions > 1 {
+			issues = append(issues, prefix+": send, send_binary, send_file, expect/extract, sleep and close are mutually exclusive")
+		}
+		if step.SendBinary != "" {
+			if _, err := base64.StdEncoding.DecodeString(step.SendBinary); err != nil {
+				issues = append(issues, fmt.Sprintf("%s: send_binary: invalid base64: %v", prefix, err))
+			}
+		}
+		if step.Sleep < 0 {
+			issues = append(issues, prefix+": sleep must be >= 0")
+		}
+		if step.Timeout < 0 {
+			issues = append(issues, prefix+": timeout must be >= 0")
+		}
+		if step.Close != 0 && (step.Close < 1000 || step.Close > 4999) {
+			issues = append(issues, prefix+": close code must be between 1000 and 4999")
+		}
+		if exp := step.Expect; exp != nil {
+			hasJSONPath := strings.TrimSpace(exp.JSONPath) != ""
+			hasRegex := strings.TrimSpace(exp.Regex) != ""
+			if hasJSONPath == hasRegex {
+				issues = append(issues, prefix+".expect: exactly one of jsonpath or regex is required")
+			}
+			if hasRegex {
+				if _, err := regexp.Compile(exp.Regex); err != nil {
+					issues = append(issues, fmt.Sprintf("%s.expect: invalid regex: %v", prefix, err))
+				}
+			}
+		}
+		issues = append(issues, validateExtractors(prefix+".extract", step.Extract)...)
+	}
+	return issues
+}
+
 func isValidIdentifier(s string) bool {
 	if len(s) == 0 {
 		return false
@@ -542,6 +1361,18 @@ func validateProtocolConfig(protocol Protocol, ws WebSocketConfig, sse SSEConfig
 		if ws.HandshakeTimeout < 0 {
 			issues = append(issues, "websocket: handshake_timeout must be >= 0")
 		}
+		if len(ws.Steps) > 0 && (len(ws.Messages) > 0 || len(ws.BinaryMessages) > 0 || len(ws.MessageFiles) > 0) {
+			issues = append(issues, "websocket: messages and steps are mutually exclusive")
+		}
+		for idx, msg := range ws.BinaryMessages {
+			if _, err := base64.StdEncoding.DecodeString(msg); err != nil {
+				issues = append(issues, fmt.Sprintf("websocket: binary_messages[%d]: invalid base64: %v", idx, err))
+			}
+		}
+		if ws.CompressionLevel < -2 || ws.CompressionLevel > 9 {
+			issues = append(issues, "websocket: compression_level must be between -2 and 9")
+		}
+		issues = append(issues, validateWebSocketSteps(ws.Steps)...)
 	}
 
 	if protocol == ProtocolSSE {
@@ -551,6 +1382,17 @@ func validateProtocolConfig(protocol Protocol, ws WebSocketConfig, sse SSEConfig
 		if sse.MaxEvents < 0 {
 			issues = append(issues, "sse: max_events must be >= 0")
 		}
+		if sse.ReconnectDelay < 0 {
+			issues = append(issues, "sse: reconnect_delay must be >= 0")
+		}
+		if sse.ReconnectDelay > 0 && !sse.Subscribe {
+			issues = append(issues, "sse: reconnect_delay requires subscribe")
+		}
+		for idx, eventType := range sse.EventTypes {
+			if strings.TrimSpace(eventType) == "" {
+				issues = append(issues, fmt.Sprintf("sse: event_types[%d] must not be empty", idx))
+			}
+		}
 	}
 
 	if protocol == ProtocolGRPC {
@@ -563,6 +1405,31 @@ func validateProtocolConfig(protocol Protocol, ws WebSocketConfig, sse SSEConfig
 		if grpc.Timeout < 0 {
 			issues = append(issues, "grpc: timeout must be >= 0")
 		}
+		if grpc.MessageInterval < 0 {
+			issues = append(issues, "grpc: message_interval must be >= 0")
+		}
+		if grpc.ExpectResponses < 0 {
+			issues = append(issues, "grpc: expect_responses must be >= 0")
+		}
+		if grpc.StreamTimeout < 0 {
+			issues = append(issues, "grpc: stream_timeout must be >= 0")
+		}
+		sources := 0
+		if strings.TrimSpace(grpc.ProtoFile) != "" {
+			sources++
+		}
+		if len(grpc.Protosets) > 0 {
+			sources++
+		}
+		if grpc.Reflection {
+			sources++
+		}
+		if sources > 1 {
+			issues = append(issues, "grpc: proto_file, protosets and reflection are mutually exclusive")
+		}
+		if strings.TrimSpace(grpc.ReflectionCache) != "" && !grpc.Reflection {
+			issues = append(issues, "grpc: reflection_cache requires reflection")
+		}
 	}
 
 	return issues
@@ -3,6 +3,7 @@ package config_test
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -1019,248 +1020,1639 @@ func TestProtocolValidation(t *testing.T) {
 			wantErr: "websocket: handshake_timeout must be >= 0",
 		},
 		{
-			name: "sse negative read timeout",
+			name: "websocket invalid binary message",
 			config: config.Config{
-				TargetURL: "http://example.com",
-				Protocol:  config.ProtocolSSE,
-				SSE: config.SSEConfig{
-					ReadTimeout: -1 * time.Second,
+				TargetURL: "ws://example.com",
+				Protocol:  config.ProtocolWebSocket,
+				WebSocket: config.WebSocketConfig{
+					BinaryMessages: []string{"not base64!"},
 				},
 			},
-			wantErr: "sse: read_timeout must be >= 0",
+			wantErr: "websocket: binary_messages[0]: invalid base64",
 		},
 		{
-			name: "sse negative max events",
+			name: "websocket compression level out of range",
 			config: config.Config{
-				TargetURL: "http://example.com",
-				Protocol:  config.ProtocolSSE,
-				SSE: config.SSEConfig{
-					MaxEvents: -1,
+				TargetURL: "ws://example.com",
+				Protocol:  config.ProtocolWebSocket,
+				WebSocket: config.WebSocketConfig{
+					Compression:      true,
+					CompressionLevel: 12,
 				},
 			},
-			wantErr: "sse: max_events must be >= 0",
+			wantErr: "websocket: compression_level must be between -2 and 9",
 		},
 		{
-			name: "grpc missing service",
+			name: "websocket step invalid send_binary",
 			config: config.Config{
-				TargetURL: "grpc://example.com",
-				Protocol:  config.ProtocolGRPC,
-				GRPC: config.GRPCConfig{
-					Method: "SayHello",
+				TargetURL: "ws://example.com",
+				Protocol:  config.ProtocolWebSocket,
+				WebSocket: config.WebSocketConfig{
+					Steps: []config.WebSocketStep{{SendBinary: "%%%"}},
 				},
 			},
-			wantErr: "grpc: service is required",
+			wantErr: "websocket: steps[0]: send_binary: invalid base64",
 		},
 		{
-			name: "grpc missing method",
+			name: "websocket steps and messages",
 			config: config.Config{
-				TargetURL: "grpc://example.com",
-				Protocol:  config.ProtocolGRPC,
-				GRPC: config.GRPCConfig{
-					Service: "Greeter",
+				TargetURL: "ws://example.com",
+				Protocol:  config.ProtocolWebSocket,
+				WebSocket: config.WebSocketConfig{
+					Messages: []string{"hi"},
+					Steps:    []config.WebSocketStep{{Send: "hi"}},
 				},
 			},
-			wantErr: "grpc: method is required",
+			wantErr: "websocket: messages and steps are mutually exclusive",
 		},
 		{
-			name: "grpc negative timeout",
+			name: "websocket step without action",
 			config: config.Config{
-				TargetURL: "grpc://example.com",
-				Protocol:  config.ProtocolGRPC,
-				GRPC: config.GRPCConfig{
-					Service: "Greeter",
-					Method:  "SayHello",
-					Timeout: -1 * time.Second,
+				TargetURL: "ws://example.com",
+				Protocol:  config.ProtocolWebSocket,
+				WebSocket: config.WebSocketConfig{
+					Steps: []config.WebSocketStep{{Name: "idle"}},
 				},
 			},
-			wantErr: "grpc: timeout must be >= 0",
+			wantErr: "websocket: steps[0]: one of send, send_binary, send_file, expect, extract, sleep or close is required",
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			// Set defaults for required fields to avoid noise
-			if tt.config.Concurrency == 0 {
-				tt.config.Concurrency = 1
-			}
-
-			err := tt.config.Validate()
-			if err == nil {
-				t.Errorf("Validate() expected error containing %q, got nil", tt.wantErr)
-				return
-			}
-			if !strings.Contains(err.Error(), tt.wantErr) {
-				t.Errorf("Validate() error = %q, want substring %q", err.Error(), tt.wantErr)
-			}
-		})
-	}
-}
-
-func TestEndpointDuplicateNames(t *testing.T) {
-	cfg := config.Config{
-		TargetURL:   "http://example.com",
-		Concurrency: 1,
-		Endpoints: []config.Endpoint{
-			{Name: "login", URL: "http://example.com/login", Weight: 1},
-			{Name: "Login", URL: "http://example.com/login", Weight: 1}, // Case-insensitive duplicate
+		{
+			name: "websocket step with two actions",
+			config: config.Config{
+				TargetURL: "ws://example.com",
+				Protocol:  config.ProtocolWebSocket,
+				WebSocket: config.WebSocketConfig{
+					Steps: []config.WebSocketStep{{Send: "hi", Close: 1000}},
+				},
+			},
+			wantErr: "websocket: steps[0]: send, send_binary, send_file, expect/extract, sleep and close are mutually exclusive",
 		},
-	}
-
-	err := cfg.Validate()
-	if err == nil {
-		t.Fatal("Validate() expected error for duplicate endpoint names, got nil")
-	}
-	if !strings.Contains(err.Error(), "duplicate name") {
-		t.Errorf("Validate() error = %q, want substring 'duplicate name'", err.Error())
-	}
-}
-
-func TestValidationErrorMethods(t *testing.T) {
-	// Create a config that produces multiple errors
-	cfg := config.Config{
-		TargetURL:   "", // Missing target
-		Concurrency: -1, // Invalid concurrency
-	}
-
-	err := cfg.Validate()
-	if err == nil {
-		t.Fatal("Validate() expected error, got nil")
-	}
-
-	// Assert it implements ValidationError interface implicitly or check type
-	valErr, ok := err.(config.ValidationError)
-	if !ok {
-		t.Fatalf("Expected error to be of type ValidationError, got %T", err)
-	}
-
-	issues := valErr.Issues()
-	if len(issues) < 2 {
-		t.Errorf("Expected at least 2 issues, got %d", len(issues))
-	}
-
-	msg := valErr.Error()
-	if !strings.Contains(msg, "validation failed:") {
-		t.Errorf("Error() message should start with 'validation failed:', got %q", msg)
-	}
-	if !strings.Contains(msg, "target is required") {
-		t.Errorf("Error() message should contain 'target is required'")
-	}
-	if !strings.Contains(msg, "concurrency must be >=") {
-		t.Errorf("Error() message should contain 'concurrency must be >='")
-	}
-}
-
-func TestArrivalModelValidation(t *testing.T) {
-	cfg := config.Config{
-		TargetURL:   "http://example.com",
-		Concurrency: 1,
-		Arrival: config.ArrivalConfig{
-			Model: "invalid-model",
+		{
+			name: "websocket step invalid close code",
+			config: config.Config{
+				TargetURL: "ws://example.com",
+				Protocol:  config.ProtocolWebSocket,
+				WebSocket: config.WebSocketConfig{
+					Steps: []config.WebSocketStep{{Close: 99}},
+				},
+			},
+			wantErr: "websocket: steps[0]: close code must be between 1000 and 4999",
 		},
-	}
-
-	err := cfg.Validate()
-	if err == nil {
-		t.Fatal("Validate() expected error for invalid arrival model, got nil")
-	}
-	if !strings.Contains(err.Error(), "arrival model \"invalid-model\" is not supported") {
-		t.Errorf("Validate() error = %q, want substring 'arrival model \"invalid-model\" is not supported'", err.Error())
-	}
-}
-
-func TestLoadPatternValidation(t *testing.T) {
-	tests := []struct {
-		name    string
-		pattern config.LoadPattern
-		wantErr string
-	}{
 		{
-			name: "missing type",
-			pattern: config.LoadPattern{
-				Type: "",
+			name: "websocket expect needs one predicate",
+			config: config.Config{
+				TargetURL: "ws://example.com",
+				Protocol:  config.ProtocolWebSocket,
+				WebSocket: config.WebSocketConfig{
+					Steps: []config.WebSocketStep{{Expect: &config.WebSocketExpect{}}},
+				},
 			},
-			wantErr: "type is required",
+			wantErr: "websocket: steps[0].expect: exactly one of jsonpath or regex is required",
 		},
 		{
-			name: "unsupported type",
-			pattern: config.LoadPattern{
-				Type: "unknown",
+			name: "websocket expect invalid regex",
+			config: config.Config{
+				TargetURL: "ws://example.com",
+				Protocol:  config.ProtocolWebSocket,
+				WebSocket: config.WebSocketConfig{
+					Steps: []config.WebSocketStep{{Expect: &config.WebSocketExpect{Regex: "("}}},
+				},
 			},
-			wantErr: "unsupported type",
+			wantErr: "websocket: steps[0].expect: invalid regex",
 		},
 		{
-			name: "ramp negative from_rps",
-			pattern: config.LoadPattern{
-				Type:     config.LoadPatternTypeRamp,
-				FromRPS:  -1,
-				ToRPS:    10,
-				Duration: 10 * time.Second,
+			name: "websocket extract missing var",
+			config: config.Config{
+				TargetURL: "ws://example.com",
+				Protocol:  config.ProtocolWebSocket,
+				WebSocket: config.WebSocketConfig{
+					Steps: []config.WebSocketStep{{Extract: []config.Extractor{{JSONPath: "$.id"}}}},
+				},
 			},
-			wantErr: "from_rps and to_rps must be >= 0",
+			wantErr: "websocket: steps[0].extract[0]: var is required",
 		},
 		{
-			name: "step missing steps",
-			pattern: config.LoadPattern{
-				Type:  config.LoadPatternTypeStep,
-				Steps: []config.LoadStep{},
+			name: "endpoint check without kind",
+			config: config.Config{
+				TargetURL: "http://example.com",
+				Endpoints: []config.Endpoint{{Weight: 1, Checks: []config.Check{{Name: "empty"}}}},
 			},
-			wantErr: "steps are required",
+			wantErr: "endpoints[0].checks[0]: one of status, header, jsonpath, regex, min_body_size or max_body_size is required",
 		},
 		{
-			name: "step negative rps",
-			pattern: config.LoadPattern{
-				Type: config.LoadPatternTypeStep,
-				Steps: []config.LoadStep{
-					{RPS: -1, Duration: 10 * time.Second},
-				},
+			name: "endpoint check with two kinds",
+			config: config.Config{
+				TargetURL: "http://example.com",
+				Endpoints: []config.Endpoint{{Weight: 1, Checks: []config.Check{{Status: []int{200}, Regex: "ok"}}}},
 			},
-			wantErr: "rps must be >= 0",
+			wantErr: "endpoints[0].checks[0]: status, header, jsonpath, regex and body size checks are mutually exclusive",
 		},
 		{
-			name: "step non-positive duration",
-			pattern: config.LoadPattern{
-				Type: config.LoadPatternTypeStep,
-				Steps: []config.LoadStep{
-					{RPS: 10, Duration: 0},
-				},
+			name: "endpoint check invalid status",
+			config: config.Config{
+				TargetURL: "http://example.com",
+				Endpoints: []config.Endpoint{{Weight: 1, Checks: []config.Check{{Status: []int{700}}}}},
 			},
-			wantErr: "duration must be > 0",
+			wantErr: "endpoints[0].checks[0]: status 700 is not a valid HTTP status code",
 		},
 		{
-			name: "spike non-positive rps",
-			pattern: config.LoadPattern{
-				Type:     config.LoadPatternTypeSpike,
-				RPS:      0,
-				Duration: 10 * time.Second,
+			name: "endpoint check contains without jsonpath",
+			config: config.Config{
+				TargetURL: "http://example.com",
+				Endpoints: []config.Endpoint{{Weight: 1, Checks: []config.Check{{Header: "Server", Contains: "nginx"}}}},
 			},
-			wantErr: "rps must be > 0",
+			wantErr: "endpoints[0].checks[0]: contains requires jsonpath",
 		},
 		{
-			name: "spike non-positive duration",
-			pattern: config.LoadPattern{
-				Type:     config.LoadPatternTypeSpike,
-				RPS:      100,
-				Duration: 0,
+			name: "endpoint check invalid header pattern",
+			config: config.Config{
+				TargetURL: "http://example.com",
+				Endpoints: []config.Endpoint{{Weight: 1, Checks: []config.Check{{Header: "Server", Matches: "("}}}},
 			},
-			wantErr: "duration must be > 0",
+			wantErr: "endpoints[0].checks[0]: invalid regex",
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			cfg := config.Config{
-				TargetURL:    "http://example.com",
-				Concurrency:  1,
-				LoadPatterns: []config.LoadPattern{tt.pattern},
-			}
-			err := cfg.Validate()
-			if err == nil {
-				t.Errorf("Validate() expected error containing %q, got nil", tt.wantErr)
-				return
-			}
-			if !strings.Contains(err.Error(), tt.wantErr) {
-				t.Errorf("Validate() error = %q, want substring %q", err.Error(), tt.wantErr)
-			}
-		})
+		{
+			name: "endpoint check inverted body bounds",
+			config: config.Config{
+				TargetURL: "http://example.com",
+				Endpoints: []config.Endpoint{{Weight: 1, Checks: []config.Check{{MinBodySize: 100, MaxBodySize: 10}}}},
+			},
+			wantErr: "endpoints[0].checks[0]: min_body_size must be <= max_body_size",
+		},
+		{
+			name: "scenarios with endpoints",
+			config: config.Config{
+				TargetURL: "http://example.com",
+				Endpoints: []config.Endpoint{{Weight: 1, Path: "/a"}},
+				Scenarios: []config.Scenario{{Name: "s", Weight: 1, Steps: []config.ScenarioStep{{Endpoint: config.Endpoint{Path: "/b"}}}}},
+			},
+			wantErr: "scenarios and endpoints are mutually exclusive",
+		},
+		{
+			name: "scenarios on websocket",
+			config: config.Config{
+				TargetURL: "ws://example.com",
+				Protocol:  config.ProtocolWebSocket,
+				Scenarios: []config.Scenario{{Name: "s", Weight: 1, Steps: []config.ScenarioStep{{Endpoint: config.Endpoint{Path: "/b"}}}}},
+			},
+			wantErr: "scenarios are only supported for the http protocol",
+		},
+		{
+			name: "scenario without name or steps",
+			config: config.Config{
+				TargetURL: "http://example.com",
+				Scenarios: []config.Scenario{{Weight: 1}},
+			},
+			wantErr: "scenarios[0]: name is required",
+		},
+		{
+			name: "scenario without steps",
+			config: config.Config{
+				TargetURL: "http://example.com",
+				Scenarios: []config.Scenario{{Name: "s", Weight: 1}},
+			},
+			wantErr: "scenarios[0]: at least one step is required",
+		},
+		{
+			name: "scenario step negative think time",
+			config: config.Config{
+				TargetURL: "http://example.com",
+				Scenarios: []config.Scenario{{Name: "s", Weight: 1, Steps: []config.ScenarioStep{{
+					Endpoint:  config.Endpoint{Path: "/b"},
+					ThinkTime: durationPtr(-time.Second),
+				}}}},
+			},
+			wantErr: "scenarios[0].steps[0]: think_time must be >= 0",
+		},
+		{
+			name: "scenario step invalid extractor",
+			config: config.Config{
+				TargetURL: "http://example.com",
+				Scenarios: []config.Scenario{{Name: "s", Weight: 1, Steps: []config.ScenarioStep{{
+					Endpoint: config.Endpoint{Path: "/b", Extractors: []config.Extractor{{JSONPath: "id"}}},
+				}}}},
+			},
+			wantErr: "scenarios[0].steps[0].extractors[0]: var is required",
+		},
+		{
+			name: "closed model with rate",
+			config: config.Config{
+				TargetURL: "http://example.com",
+				Rate:      100,
+				Arrival:   config.ArrivalConfig{Model: config.ArrivalModelClosed},
+			},
+			wantErr: "arrival: rate is not supported by the closed model",
+		},
+		{
+			name: "think time on open model",
+			config: config.Config{
+				TargetURL: "http://example.com",
+				Arrival:   config.ArrivalConfig{Model: config.ArrivalModelUniform, Pacing: time.Second},
+			},
+			wantErr: "arrival: think_time and pacing require the closed model",
+		},
+		{
+			name: "uniform think time inverted bounds",
+			config: config.Config{
+				TargetURL: "http://example.com",
+				Arrival: config.ArrivalConfig{
+					Model:     config.ArrivalModelClosed,
+					ThinkTime: config.ThinkTimeConfig{Distribution: config.ThinkTimeUniform, Min: 2 * time.Second, Max: time.Second},
+				},
+			},
+			wantErr: "arrival: think_time max must be >= min",
+		},
+		{
+			name: "unknown think time distribution",
+			config: config.Config{
+				TargetURL: "http://example.com",
+				Arrival: config.ArrivalConfig{
+					Model:     config.ArrivalModelClosed,
+					ThinkTime: config.ThinkTimeConfig{Distribution: "gaussian", Duration: time.Second},
+				},
+			},
+			wantErr: `arrival: think_time distribution "gaussian" is not supported`,
+		},
+		{
+			name: "empty agent address",
+			config: config.Config{
+				TargetURL: "http://example.com",
+				Agents:    []string{"10.0.0.1:7070", " "},
+			},
+			wantErr: "agents[1]: address is required",
+		},
+		{
+			name: "duplicate agent address",
+			config: config.Config{
+				TargetURL: "http://example.com",
+				Agents:    []string{"10.0.0.1:7070", "10.0.0.1:7070"},
+			},
+			wantErr: `agents[1]: duplicate address "10.0.0.1:7070"`,
+		},
+		{
+			name: "dashboard with agents",
+			config: config.Config{
+				TargetURL: "http://example.com",
+				Dashboard: true,
+				Agents:    []string{"10.0.0.1:7070"},
+			},
+			wantErr: "dashboard is not supported with agents",
+		},
+		{
+			name: "web dashboard with agents",
+			config: config.Config{
+				TargetURL:    "http://example.com",
+				WebDashboard: ":8089",
+				Agents:       []string{"10.0.0.1:7070"},
+			},
+			wantErr: "web_dashboard is not supported with agents",
+		},
+		{
+			name: "unknown http version",
+			config: config.Config{
+				TargetURL:   "https://example.com",
+				HTTPVersion: "1.0",
+			},
+			wantErr: `http_version must be one of 1.1, 2, h2c or 3, got "1.0"`,
+		},
+		{
+			name: "http version with grpc",
+			config: config.Config{
+				TargetURL:   "https://example.com",
+				Protocol:    config.ProtocolGRPC,
+				HTTPVersion: config.HTTPVersion2,
+				GRPC:        config.GRPCConfig{ProtoFile: "hello.proto", Service: "helloworld.Greeter", Method: "SayHello"},
+			},
+			wantErr: `http_version: not supported with protocol "grpc"`,
+		},
+		{
+			name: "http3 with plain http target",
+			config: config.Config{
+				TargetURL:   "http://example.com",
+				HTTPVersion: config.HTTPVersion3,
+			},
+			wantErr: "http_version 3 requires an https target",
+		},
+		{
+			name: "h2c with https target",
+			config: config.Config{
+				TargetURL:   "https://example.com",
+				HTTPVersion: config.HTTPVersionH2C,
+			},
+			wantErr: "http_version h2c requires an http target",
+		},
+		{
+			name: "tls cert without key",
+			config: config.Config{
+				TargetURL: "https://example.com",
+				TLS:       config.TLSConfig{CertFile: "client.pem"},
+			},
+			wantErr: "tls: cert_file and key_file must be set together",
+		},
+		{
+			name: "tls unsupported version",
+			config: config.Config{
+				TargetURL: "https://example.com",
+				TLS:       config.TLSConfig{MinVersion: "1.4"},
+			},
+			wantErr: `tls: min_version: unsupported version "1.4"`,
+		},
+		{
+			name: "tls min above max",
+			config: config.Config{
+				TargetURL: "https://example.com",
+				TLS:       config.TLSConfig{MinVersion: "1.3", MaxVersion: "1.2"},
+			},
+			wantErr: "tls: min_version must not exceed max_version",
+		},
+		{
+			name: "tls unknown cipher",
+			config: config.Config{
+				TargetURL: "https://example.com",
+				TLS:       config.TLSConfig{CipherSuites: []string{"TLS_FAST"}},
+			},
+			wantErr: `tls: cipher_suites: unknown cipher suite "TLS_FAST"`,
+		},
+		{
+			name: "dns resolve key without port",
+			config: config.Config{
+				TargetURL: "https://example.com",
+				DNS:       config.DNSConfig{Resolve: map[string][]string{"example.com": {"10.0.0.1:443"}}},
+			},
+			wantErr: `dns: resolve: key "example.com" must be host:port`,
+		},
+		{
+			name: "dns resolve host name address",
+			config: config.Config{
+				TargetURL: "https://example.com",
+				DNS:       config.DNSConfig{Resolve: map[string][]string{"example.com:443": {"canary.example.com:443"}}},
+			},
+			wantErr: `dns: resolve[example.com:443]: address "canary.example.com:443" must be ip:port`,
+		},
+		{
+			name: "dns resolve without addresses",
+			config: config.Config{
+				TargetURL: "https://example.com",
+				DNS:       config.DNSConfig{Resolve: map[string][]string{"example.com:443": nil}},
+			},
+			wantErr: "dns: resolve[example.com:443]: at least one address is required",
+		},
+		{
+			name: "dns server host name",
+			config: config.Config{
+				TargetURL: "https://example.com",
+				DNS:       config.DNSConfig{Server: "ns1.example.com"},
+			},
+			wantErr: `dns: server: "ns1.example.com" must be an IP address`,
+		},
+		{
+			name: "cookies with websocket",
+			config: config.Config{
+				TargetURL: "ws://example.com",
+				Protocol:  config.ProtocolWebSocket,
+				Cookies:   config.CookiesConfig{Jar: true},
+			},
+			wantErr: `cookies: not supported with protocol "websocket"`,
+		},
+		{
+			name: "cookie seed invalid name",
+			config: config.Config{
+				TargetURL: "http://example.com",
+				Cookies:   config.CookiesConfig{Seed: []config.Cookie{{Name: "bad name", Value: "x"}}},
+			},
+			wantErr: `cookies: seed[0]: invalid name "bad name"`,
+		},
+		{
+			name: "cookie seed relative path",
+			config: config.Config{
+				TargetURL: "http://example.com",
+				Cookies:   config.CookiesConfig{Seed: []config.Cookie{{Name: "sid", Value: "x", Path: "api"}}},
+			},
+			wantErr: "cookies: seed[0]: path must start with /",
+		},
+		{
+			name: "cookie extract without var",
+			config: config.Config{
+				TargetURL: "http://example.com",
+				Cookies:   config.CookiesConfig{Extract: []config.CookieExtract{{Cookie: "sid"}}},
+			},
+			wantErr: "cookies: extract[0]: var is required",
+		},
+		{
+			name: "prometheus with agents",
+			config: config.Config{
+				TargetURL:  "http://example.com",
+				Agents:     []string{"10.0.0.1:7070"},
+				Prometheus: config.PrometheusConfig{Listen: ":9464"},
+			},
+			wantErr: "prometheus is not supported with agents",
+		},
+		{
+			name: "prometheus invalid label name",
+			config: config.Config{
+				TargetURL:  "http://example.com",
+				Prometheus: config.PrometheusConfig{Listen: ":9464", Labels: map[string]string{"test-name": "x"}},
+			},
+			wantErr: `prometheus: invalid label name "test-name"`,
+		},
+		{
+			name: "prometheus reserved label name",
+			config: config.Config{
+				TargetURL:  "http://example.com",
+				Prometheus: config.PrometheusConfig{Listen: ":9464", Labels: map[string]string{"endpoint": "x"}},
+			},
+			wantErr: `prometheus: label "endpoint" is used by crankfire's own series`,
+		},
+		{
+			name: "otlp metrics with agents",
+			config: config.Config{
+				TargetURL:   "http://example.com",
+				Agents:      []string{"10.0.0.1:7070"},
+				OTLPMetrics: config.OTLPMetricsConfig{Endpoint: "localhost:4317"},
+			},
+			wantErr: "otlp_metrics is not supported with agents",
+		},
+		{
+			name: "otlp metrics unsupported protocol",
+			config: config.Config{
+				TargetURL:   "http://example.com",
+				OTLPMetrics: config.OTLPMetricsConfig{Endpoint: "localhost:4317", Protocol: "thrift"},
+			},
+			wantErr: `otlp_metrics: unsupported protocol "thrift"`,
+		},
+		{
+			name: "otlp metrics negative interval",
+			config: config.Config{
+				TargetURL:   "http://example.com",
+				OTLPMetrics: config.OTLPMetricsConfig{Endpoint: "localhost:4317", Interval: -time.Second},
+			},
+			wantErr: "otlp_metrics: interval must be non-negative",
+		},
+		{
+			name: "otlp metrics attributes without endpoint",
+			config: config.Config{
+				TargetURL:   "http://example.com",
+				OTLPMetrics: config.OTLPMetricsConfig{Attributes: map[string]string{"team": "payments"}},
+			},
+			wantErr: "otlp_metrics: attributes require endpoint",
+		},
+		{
+			name: "prometheus labels without listen",
+			config: config.Config{
+				TargetURL:  "http://example.com",
+				Prometheus: config.PrometheusConfig{Labels: map[string]string{"test": "x"}},
+			},
+			wantErr: "prometheus: labels require listen",
+		},
+		{
+			name: "sse negative read timeout",
+			config: config.Config{
+				TargetURL: "http://example.com",
+				Protocol:  config.ProtocolSSE,
+				SSE: config.SSEConfig{
+					ReadTimeout: -1 * time.Second,
+				},
+			},
+			wantErr: "sse: read_timeout must be >= 0",
+		},
+		{
+			name: "sse reconnect delay without subscribe",
+			config: config.Config{
+				TargetURL: "http://example.com",
+				Protocol:  config.ProtocolSSE,
+				SSE: config.SSEConfig{
+					ReconnectDelay: time.Second,
+				},
+			},
+			wantErr: "sse: reconnect_delay requires subscribe",
+		},
+		{
+			name: "sse empty event type",
+			config: config.Config{
+				TargetURL: "http://example.com",
+				Protocol:  config.ProtocolSSE,
+				SSE: config.SSEConfig{
+					EventTypes: []string{"update", " "},
+				},
+			},
+			wantErr: "sse: event_types[1] must not be empty",
+		},
+		{
+			name: "sse negative max events",
+			config: config.Config{
+				TargetURL: "http://example.com",
+				Protocol:  config.ProtocolSSE,
+				SSE: config.SSEConfig{
+					MaxEvents: -1,
+				},
+			},
+			wantErr: "sse: max_events must be >= 0",
+		},
+		{
+			name: "grpc missing service",
+			config: config.Config{
+				TargetURL: "grpc://example.com",
+				Protocol:  config.ProtocolGRPC,
+				GRPC: config.GRPCConfig{
+					Method: "SayHello",
+				},
+			},
+			wantErr: "grpc: service is required",
+		},
+		{
+			name: "grpc missing method",
+			config: config.Config{
+				TargetURL: "grpc://example.com",
+				Protocol:  config.ProtocolGRPC,
+				GRPC: config.GRPCConfig{
+					Service: "Greeter",
+				},
+			},
+			wantErr: "grpc: method is required",
+		},
+		{
+			name: "grpc negative timeout",
+			config: config.Config{
+				TargetURL: "grpc://example.com",
+				Protocol:  config.ProtocolGRPC,
+				GRPC: config.GRPCConfig{
+					Service: "Greeter",
+					Method:  "SayHello",
+					Timeout: -1 * time.Second,
+				},
+			},
+			wantErr: "grpc: timeout must be >= 0",
+		},
+		{
+			name: "grpc negative expect responses",
+			config: config.Config{
+				TargetURL: "grpc://example.com",
+				Protocol:  config.ProtocolGRPC,
+				GRPC: config.GRPCConfig{
+					Service:         "Greeter",
+					Method:          "Chat",
+					ExpectResponses: -1,
+				},
+			},
+			wantErr: "grpc: expect_responses must be >= 0",
+		},
+		{
+			name: "grpc negative stream timeout",
+			config: config.Config{
+				TargetURL: "grpc://example.com",
+				Protocol:  config.ProtocolGRPC,
+				GRPC: config.GRPCConfig{
+					Service:       "Greeter",
+					Method:        "Chat",
+					StreamTimeout: -1 * time.Second,
+				},
+			},
+			wantErr: "grpc: stream_timeout must be >= 0",
+		},
+		{
+			name: "grpc proto file and reflection",
+			config: config.Config{
+				TargetURL: "grpc://example.com",
+				Protocol:  config.ProtocolGRPC,
+				GRPC: config.GRPCConfig{
+					Service:    "Greeter",
+					Method:     "SayHello",
+					ProtoFile:  "greeter.proto",
+					Reflection: true,
+				},
+			},
+			wantErr: "grpc: proto_file, protosets and reflection are mutually exclusive",
+		},
+		{
+			name: "grpc reflection cache without reflection",
+			config: config.Config{
+				TargetURL: "grpc://example.com",
+				Protocol:  config.ProtocolGRPC,
+				GRPC: config.GRPCConfig{
+					Service:         "Greeter",
+					Method:          "SayHello",
+					Protosets:       []string{"greeter.pb"},
+					ReflectionCache: "cache.pb",
+				},
+			},
+			wantErr: "grpc: reflection_cache requires reflection",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Set defaults for required fields to avoid noise
+			if tt.config.Concurrency == 0 {
+				tt.config.Concurrency = 1
+			}
+
+			err := tt.config.Validate()
+			if err == nil {
+				t.Errorf("Validate() expected error containing %q, got nil", tt.wantErr)
+				return
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %q, want substring %q", err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEndpointDuplicateNames(t *testing.T) {
+	cfg := config.Config{
+		TargetURL:   "http://example.com",
+		Concurrency: 1,
+		Endpoints: []config.Endpoint{
+			{Name: "login", URL: "http://example.com/login", Weight: 1},
+			{Name: "Login", URL: "http://example.com/login", Weight: 1}, // Case-insensitive duplicate
+		},
+	}
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("Validate() expected error for duplicate endpoint names, got nil")
+	}
+	if !strings.Contains(err.Error(), "duplicate name") {
+		t.Errorf("Validate() error = %q, want substring 'duplicate name'", err.Error())
+	}
+}
+
+func TestValidationErrorMethods(t *testing.T) {
+	// Create a config that produces multiple errors
+	cfg := config.Config{
+		TargetURL:   "", // Missing target
+		Concurrency: -1, // Invalid concurrency
+	}
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("Validate() expected error, got nil")
+	}
+
+	// Assert it implements ValidationError interface implicitly or check type
+	valErr, ok := err.(config.ValidationError)
+	if !ok {
+		t.Fatalf("Expected error to be of type ValidationError, got %T", err)
+	}
+
+	issues := valErr.Issues()
+	if len(issues) < 2 {
+		t.Errorf("Expected at least 2 issues, got %d", len(issues))
+	}
+
+	msg := valErr.Error()
+	if !strings.Contains(msg, "validation failed:") {
+		t.Errorf("Error() message should start with 'validation failed:', got %q", msg)
+	}
+	if !strings.Contains(msg, "target is required") {
+		t.Errorf("Error() message should contain 'target is required'")
+	}
+	if !strings.Contains(msg, "concurrency must be >=") {
+		t.Errorf("Error() message should contain 'concurrency must be >='")
+	}
+}
+
+func TestArrivalModelValidation(t *testing.T) {
+	cfg := config.Config{
+		TargetURL:   "http://example.com",
+		Concurrency: 1,
+		Arrival: config.ArrivalConfig{
+			Model: "invalid-model",
+		},
+	}
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("Validate() expected error for invalid arrival model, got nil")
+	}
+	if !strings.Contains(err.Error(), "arrival model \"invalid-model\" is not supported") {
+		t.Errorf("Validate() error = %q, want substring 'arrival model \"invalid-model\" is not supported'", err.Error())
+	}
+}
+
+func TestLoadPatternValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern config.LoadPattern
+		wantErr string
+	}{
+		{
+			name: "missing type",
+			pattern: config.LoadPattern{
+				Type: "",
+			},
+			wantErr: "type is required",
+		},
+		{
+			name: "unsupported type",
+			pattern: config.LoadPattern{
+				Type: "unknown",
+			},
+			wantErr: "unsupported type",
+		},
+		{
+			name: "ramp negative from_rps",
+			pattern: config.LoadPattern{
+				Type:     config.LoadPatternTypeRamp,
+				FromRPS:  -1,
+				ToRPS:    10,
+				Duration: 10 * time.Second,
+			},
+			wantErr: "from_rps and to_rps must be >= 0",
+		},
+		{
+			name: "step missing steps",
+			pattern: config.LoadPattern{
+				Type:  config.LoadPatternTypeStep,
+				Steps: []config.LoadStep{},
+			},
+			wantErr: "steps are required",
+		},
+		{
+			name: "step negative rps",
+			pattern: config.LoadPattern{
+				Type: config.LoadPatternTypeStep,
+				Steps: []config.LoadStep{
+					{RPS: -1, Duration: 10 * time.Second},
+				},
+			},
+			wantErr: "rps must be >= 0",
+		},
+		{
+			name: "step non-positive duration",
+			pattern: config.LoadPattern{
+				Type: config.LoadPatternTypeStep,
+				Steps: []config.LoadStep{
+					{RPS: 10, Duration: 0},
+				},
+			},
+			wantErr: "duration must be > 0",
+		},
+		{
+			name: "spike non-positive rps",
+			pattern: config.LoadPattern{
+				Type:     config.LoadPatternTypeSpike,
+				RPS:      0,
+				Duration: 10 * time.Second,
+			},
+			wantErr: "rps must be > 0",
+		},
+		{
+			name: "spike non-positive duration",
+			pattern: config.LoadPattern{
+				Type:     config.LoadPatternTypeSpike,
+				RPS:      100,
+				Duration: 0,
+			},
+			wantErr: "duration must be > 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Config{
+				TargetURL:    "http://example.com",
+				Concurrency:  1,
+				LoadPatterns: []config.LoadPattern{tt.pattern},
+			}
+			err := cfg.Validate()
+			if err == nil {
+				t.Errorf("Validate() expected error containing %q, got nil", tt.wantErr)
+				return
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %q, want substring %q", err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadGRPCStreamingConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "grpc.yaml")
+	if err := os.WriteFile(path, []byte(`
+target: localhost:50051
+protocol: grpc
+grpc:
+  proto_file: ./chat.proto
+  service: chat.Chat
+  method: Talk
+  messages:
+    - '{"text":"hello"}'
+    - '{"text":"bye"}'
+  message_interval: 50ms
+  expect_responses: 2
+  stream_timeout: 3s
+`), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	loader := config.NewLoader()
+	cfg, err := loader.Load([]string{
+		"--config", path,
+		"--grpc-stream-message", `{"text":"a,b"}`,
+		"--grpc-expect-responses", "4",
+	})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if len(cfg.GRPC.Messages) != 1 || cfg.GRPC.Messages[0] != `{"text":"a,b"}` {
+		t.Errorf("GRPC.Messages = %v, want flag override kept intact", cfg.GRPC.Messages)
+	}
+	if cfg.GRPC.MessageInterval != 50*time.Millisecond {
+		t.Errorf("GRPC.MessageInterval = %v, want 50ms", cfg.GRPC.MessageInterval)
+	}
+	if cfg.GRPC.ExpectResponses != 4 {
+		t.Errorf("GRPC.ExpectResponses = %d, want 4", cfg.GRPC.ExpectResponses)
+	}
+	if cfg.GRPC.StreamTimeout != 3*time.Second {
+		t.Errorf("GRPC.StreamTimeout = %v, want 3s", cfg.GRPC.StreamTimeout)
+	}
+}
+
+func TestLoadClosedArrivalModel(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "closed.yaml")
+	if err := os.WriteFile(path, []byte(`
+target: http://localhost:8080
+concurrency: 50
+arrival:
+  model: closed
+  think_time:
+    distribution: uniform
+    min: 1s
+    max: 3s
+  pacing: 5s
+load_patterns:
+  - type: ramp
+    from_vus: 0
+    to_vus: 50
+    duration: 1m
+  - type: step
+    steps:
+      - vus: 20
+        duration: 30s
+`), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	loader := config.NewLoader()
+	cfg, err := loader.Load([]string{"--config", path})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	arrival := cfg.Arrival
+	if arrival.Model != config.ArrivalModelClosed || arrival.Pacing != 5*time.Second {
+		t.Errorf("Arrival = %+v", arrival)
+	}
+	want := config.ThinkTimeConfig{Distribution: config.ThinkTimeUniform, Min: time.Second, Max: 3 * time.Second}
+	if arrival.ThinkTime != want {
+		t.Errorf("ThinkTime = %+v, want %+v", arrival.ThinkTime, want)
+	}
+	if p := cfg.LoadPatterns[0]; p.FromRPS != 0 || p.ToRPS != 50 {
+		t.Errorf("LoadPatterns[0] = %+v, want vus aliases mapped to 0..50", p)
+	}
+	if step := cfg.LoadPatterns[1].Steps[0]; step.RPS != 20 {
+		t.Errorf("LoadPatterns[1].Steps[0].RPS = %d, want 20", step.RPS)
+	}
+
+	// A plain duration is a constant think time, and flags override the file.
+	cfg, err = loader.Load([]string{"--target", "http://localhost", "--arrival-model", "closed", "--think-time", "750ms", "--think-time-distribution", "exponential"})
+	if err != nil {
+		t.Fatalf("Load() with flags error = %v", err)
+	}
+	if cfg.Arrival.ThinkTime.Duration != 750*time.Millisecond || cfg.Arrival.ThinkTime.Distribution != config.ThinkTimeExponential {
+		t.Errorf("ThinkTime from flags = %+v", cfg.Arrival.ThinkTime)
+	}
+}
+
+func durationPtr(d time.Duration) *time.Duration {
+	return &d
+}
+
+func TestLoadScenarios(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "scenarios.yaml")
+	if err := os.WriteFile(path, []byte(`
+target: http://localhost:8080
+scenarios:
+  - name: checkout
+    weight: 3
+    think_time: 500ms
+    steps:
+      - name: login
+        method: post
+        path: /login
+        body: '{"user":"{{user}}"}'
+        extractors:
+          - jsonpath: $.token
+            var: token
+        think_time: 0s
+      - path: /cart
+        headers:
+          authorization: Bearer {{token}}
+        checks:
+          - status: 200
+  - name: browse
+    steps:
+      - path: /
+`), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	loader := config.NewLoader()
+	cfg, err := loader.Load([]string{"--config", path})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if len(cfg.Scenarios) != 2 {
+		t.Fatalf("len(Scenarios) = %d, want 2", len(cfg.Scenarios))
+	}
+	checkout := cfg.Scenarios[0]
+	if checkout.Name != "checkout" || checkout.Weight != 3 || checkout.ThinkTime != 500*time.Millisecond {
+		t.Errorf("Scenarios[0] = %+v", checkout)
+	}
+	if len(checkout.Steps) != 2 {
+		t.Fatalf("len(Steps) = %d, want 2", len(checkout.Steps))
+	}
+	login := checkout.Steps[0]
+	if login.Name != "login" || login.Method != "POST" || login.Extractors[0].Variable != "token" {
+		t.Errorf("Steps[0] = %+v", login)
+	}
+	if login.ThinkTime == nil || *login.ThinkTime != 0 {
+		t.Errorf("Steps[0].ThinkTime = %v, want explicit 0", login.ThinkTime)
+	}
+	cart := checkout.Steps[1]
+	if cart.ThinkTime != nil || cart.Headers["Authorization"] != "Bearer {{token}}" || len(cart.Checks) != 1 {
+		t.Errorf("Steps[1] = %+v", cart)
+	}
+	if cfg.Scenarios[1].Weight != 1 {
+		t.Errorf("Scenarios[1].Weight = %d, want default 1", cfg.Scenarios[1].Weight)
+	}
+}
+
+func TestLoadEndpointChecks(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "checks.yaml")
+	if err := os.WriteFile(path, []byte(`
+target: http://localhost:8080
+endpoints:
+  - name: login
+    path: /login
+    checks:
+      - name: accepted
+        status: [200, 201]
+      - header: content-type
+        matches: ^application/json
+      - jsonpath: $.token
+      - jsonpath: $.user.role
+        equals: admin
+      - regex: '"token":"[A-Za-z0-9]+"'
+      - max_body_size: 4096
+`), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	loader := config.NewLoader()
+	cfg, err := loader.Load([]string{"--config", path})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	checks := cfg.Endpoints[0].Checks
+	wantKinds := []string{"status", "header", "jsonpath", "jsonpath", "regex", "body_size"}
+	if len(checks) != len(wantKinds) {
+		t.Fatalf("len(Checks) = %d, want %d", len(checks), len(wantKinds))
+	}
+	for i, want := range wantKinds {
+		if got := checks[i].Kind(); got != want {
+			t.Errorf("Checks[%d].Kind() = %q, want %q", i, got, want)
+		}
+	}
+	if checks[0].Name != "accepted" || len(checks[0].Status) != 2 || checks[0].Status[1] != 201 {
+		t.Errorf("Checks[0] = %+v", checks[0])
+	}
+	if checks[1].Header != "content-type" || checks[1].Matches != "^application/json" {
+		t.Errorf("Checks[1] = %+v", checks[1])
+	}
+	if checks[3].Equals != "admin" || checks[5].MaxBodySize != 4096 {
+		t.Errorf("Checks = %+v", checks)
+	}
+}
+
+func TestLoadWebSocketSteps(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "ws.yaml")
+	if err := os.WriteFile(path, []byte(`
+target: ws://localhost:8080/quotes
+protocol: websocket
+websocket:
+  receive_timeout: 2s
+  steps:
+    - name: subscribe
+      send: '{"op":"subscribe","symbol":"{{symbol}}"}'
+    - name: quote
+      expect:
+        jsonpath: $.type
+        equals: quote
+      timeout: 500ms
+      extract:
+        - jsonpath: $.id
+          var: quote_id
+    - sleep: 250ms
+    - close: 1000
+`), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	loader := config.NewLoader()
+	cfg, err := loader.Load([]string{"--config", path})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	steps := cfg.WebSocket.Steps
+	if len(steps) != 4 {
+		t.Fatalf("len(Steps) = %d, want 4", len(steps))
+	}
+	wantActions := []string{"send", "expect", "sleep", "close"}
+	for i, want := range wantActions {
+		if got := steps[i].Action(); got != want {
+			t.Errorf("Steps[%d].Action() = %q, want %q", i, got, want)
+		}
+	}
+	quote := steps[1]
+	if quote.Expect.JSONPath != "$.type" || quote.Expect.Equals != "quote" {
+		t.Errorf("Steps[1].Expect = %+v", quote.Expect)
+	}
+	if quote.Timeout != 500*time.Millisecond {
+		t.Errorf("Steps[1].Timeout = %v, want 500ms", quote.Timeout)
+	}
+	if len(quote.Extract) != 1 || quote.Extract[0].Variable != "quote_id" {
+		t.Errorf("Steps[1].Extract = %+v", quote.Extract)
+	}
+	if steps[2].Sleep != 250*time.Millisecond || steps[3].Close != 1000 {
+		t.Errorf("Steps[2:] = %+v", steps[2:])
+	}
+}
+
+func TestLoadWebSocketBinaryAndCompression(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "ws.yaml")
+	if err := os.WriteFile(path, []byte(`
+target: ws://localhost:8080/feed
+protocol: websocket
+websocket:
+  binary_messages:
+    - AAEC
+  message_files:
+    - ./frame.bin
+  compression: true
+  compression_level: 6
+`), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	loader := config.NewLoader()
+	cfg, err := loader.Load([]string{
+		"--config", path,
+		"--ws-binary-message", "/w==",
+		"--ws-compression-level", "9",
+	})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	ws := cfg.WebSocket
+	if len(ws.BinaryMessages) != 1 || ws.BinaryMessages[0] != "/w==" {
+		t.Errorf("BinaryMessages = %v, want flag override", ws.BinaryMessages)
+	}
+	if len(ws.MessageFiles) != 1 || ws.MessageFiles[0] != "./frame.bin" {
+		t.Errorf("MessageFiles = %v", ws.MessageFiles)
+	}
+	if !ws.Compression || ws.CompressionLevel != 9 {
+		t.Errorf("Compression = %v level %d, want true level 9", ws.Compression, ws.CompressionLevel)
+	}
+}
+
+func TestLoadSSESubscriberConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sse.yaml")
+	if err := os.WriteFile(path, []byte(`
+target: http://localhost:8080/notifications
+protocol: sse
+sse:
+  read_timeout: 10s
+  subscribe: true
+  reconnect_delay: 500ms
+  event_types: [order.created, order.updated]
+  lag_field: $.published_at
+`), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	loader := config.NewLoader()
+	cfg, err := loader.Load([]string{"--config", path, "--sse-event-type", "order.deleted"})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	sse := cfg.SSE
+	if !sse.Subscribe || sse.ReconnectDelay != 500*time.Millisecond {
+		t.Errorf("Subscribe = %v, ReconnectDelay = %v; want true, 500ms", sse.Subscribe, sse.ReconnectDelay)
+	}
+	if len(sse.EventTypes) != 1 || sse.EventTypes[0] != "order.deleted" {
+		t.Errorf("EventTypes = %v, want flag override", sse.EventTypes)
+	}
+	if sse.LagField != "$.published_at" {
+		t.Errorf("LagField = %q", sse.LagField)
+	}
+}
+
+func TestLoadGRPCDescriptorSources(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "grpc.yaml")
+	if err := os.WriteFile(path, []byte(`
+target: localhost:50051
+protocol: grpc
+grpc:
+  service: orders.OrderService
+  method: CreateOrder
+  reflection: true
+  reflection_cache: ./orders.protoset
+`), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	loader := config.NewLoader()
+	cfg, err := loader.Load([]string{"--config", path})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !cfg.GRPC.Reflection {
+		t.Error("GRPC.Reflection = false, want true")
+	}
+	if cfg.GRPC.ReflectionCache != "./orders.protoset" {
+		t.Errorf("GRPC.ReflectionCache = %q, want ./orders.protoset", cfg.GRPC.ReflectionCache)
+	}
+
+	cfg, err = loader.Load([]string{
+		"--target", "localhost:50051",
+		"--protocol", "grpc",
+		"--grpc-protoset", "a.protoset",
+		"--grpc-protoset", "b.pb",
+	})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(cfg.GRPC.Protosets) != 2 || cfg.GRPC.Protosets[1] != "b.pb" {
+		t.Errorf("GRPC.Protosets = %v, want [a.protoset b.pb]", cfg.GRPC.Protosets)
+	}
+}
+
+func TestLoadAgents(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "distributed.yaml")
+	if err := os.WriteFile(path, []byte(`
+target: http://localhost:8080
+agents:
+  - 10.0.0.1:7070
+  - 10.0.0.2:7070
+`), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	cfg, err := config.NewLoader().Load([]string{"--config", path})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if strings.Join(cfg.Agents, ",") != "10.0.0.1:7070,10.0.0.2:7070" {
+		t.Errorf("Agents = %v", cfg.Agents)
+	}
+
+	cfg, err = config.NewLoader().Load([]string{"--config", path, "--agents", "127.0.0.1:7070,127.0.0.1:7071"})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if strings.Join(cfg.Agents, ",") != "127.0.0.1:7070,127.0.0.1:7071" {
+		t.Errorf("Agents with flag override = %v", cfg.Agents)
+	}
+}
+
+func TestLoadPrometheus(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "prometheus.yaml")
+	if err := os.WriteFile(path, []byte(`
+target: http://localhost:8080
+prometheus:
+  listen: ":9464"
+  labels:
+    test: checkout
+`), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	cfg, err := config.NewLoader().Load([]string{"--config", path})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Prometheus.Listen != ":9464" || cfg.Prometheus.Labels["test"] != "checkout" {
+		t.Errorf("Prometheus = %+v", cfg.Prometheus)
+	}
+
+	cfg, err = config.NewLoader().Load([]string{"--config", path, "--prometheus-listen", "127.0.0.1:9000", "--prometheus-label", "env=staging"})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Prometheus.Listen != "127.0.0.1:9000" {
+		t.Errorf("Listen with flag override = %q", cfg.Prometheus.Listen)
+	}
+	if len(cfg.Prometheus.Labels) != 1 || cfg.Prometheus.Labels["env"] != "staging" {
+		t.Errorf("Labels with flag override = %v", cfg.Prometheus.Labels)
+	}
+}
+
+func TestLoadOTLPMetrics(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "otlp.yaml")
+	if err := os.WriteFile(path, []byte(`
+target: http://localhost:8080
+otlp_metrics:
+  endpoint: collector:4318
+  protocol: HTTP
+  insecure: true
+  interval: 5s
+  attributes:
+    team: payments
+`), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	cfg, err := config.NewLoader().Load([]string{"--config", path})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := config.OTLPMetricsConfig{
+		Endpoint:   "collector:4318",
+		Protocol:   "http",
+		Insecure:   true,
+		Interval:   5 * time.Second,
+		Attributes: map[string]string{"team": "payments"},
+	}
+	if !reflect.DeepEqual(cfg.OTLPMetrics, want) {
+		t.Errorf("OTLPMetrics = %+v, want %+v", cfg.OTLPMetrics, want)
+	}
+
+	cfg, err = config.NewLoader().Load([]string{"--config", path,
+		"--otlp-metrics-endpoint", "localhost:4317",
+		"--otlp-metrics-protocol", "grpc",
+		"--otlp-metrics-interval", "1s",
+		"--otlp-metrics-attribute", "env=staging",
+	})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.OTLPMetrics.Endpoint != "localhost:4317" || cfg.OTLPMetrics.Protocol != "grpc" || cfg.OTLPMetrics.Interval != time.Second {
+		t.Errorf("OTLPMetrics with flag override = %+v", cfg.OTLPMetrics)
+	}
+	if len(cfg.OTLPMetrics.Attributes) != 1 || cfg.OTLPMetrics.Attributes["env"] != "staging" {
+		t.Errorf("Attributes with flag override = %v", cfg.OTLPMetrics.Attributes)
+	}
+}
+
+func TestOTLPMetricsWithAttributesCopies(t *testing.T) {
+	base := config.OTLPMetricsConfig{Endpoint: "localhost:4317", Attributes: map[string]string{"team": "payments"}}
+	got := base.WithAttributes(config.ResourceSessionID, "checkout")
+
+	if got.Attributes["team"] != "payments" || got.Attributes[config.ResourceSessionID] != "checkout" {
+		t.Errorf("Attributes = %v", got.Attributes)
+	}
+	if _, ok := base.Attributes[config.ResourceSessionID]; ok {
+		t.Error("WithAttributes modified the receiver's map")
+	}
+}
+
+func TestJUnitOutput(t *testing.T) {
+	cfg, err := config.NewLoader().Load([]string{"--target", "http://example.com", "--junit-output", "results/junit.xml"})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.JUnitOutput != "results/junit.xml" {
+		t.Errorf("JUnitOutput = %q, want results/junit.xml", cfg.JUnitOutput)
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "junit.yaml")
+	if err := os.WriteFile(path, []byte("target: http://example.com\njunit_output: out/junit.xml\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	cfg, err = config.NewLoader().Load([]string{"--config", path})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.JUnitOutput != "out/junit.xml" {
+		t.Errorf("JUnitOutput = %q, want out/junit.xml", cfg.JUnitOutput)
+	}
+}
+
+func TestWebDashboard(t *testing.T) {
+	cfg, err := config.NewLoader().Load([]string{"--target", "http://example.com", "--web-dashboard", ":8089"})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.WebDashboard != ":8089" {
+		t.Errorf("WebDashboard = %q, want :8089", cfg.WebDashboard)
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "web.yaml")
+	if err := os.WriteFile(path, []byte("target: http://example.com\nweb_dashboard: 127.0.0.1:9000\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	cfg, err = config.NewLoader().Load([]string{"--config", path})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.WebDashboard != "127.0.0.1:9000" {
+		t.Errorf("WebDashboard = %q, want 127.0.0.1:9000", cfg.WebDashboard)
+	}
+}
+
+func TestTLSOptions(t *testing.T) {
+	cfg, err := config.NewLoader().Load([]string{
+		"--target", "https://example.com",
+		"--tls-cert", "client.pem", "--tls-key", "client.key", "--tls-ca", "ca.pem",
+		"--tls-server-name", "api.internal", "--tls-min-version", "1.2", "--tls-max-version", "1.3",
+		"--tls-cipher", "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
+		"--tls-alpn", "h2", "--tls-alpn", "http/1.1",
+	})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := config.TLSConfig{
+		CertFile:     "client.pem",
+		KeyFile:      "client.key",
+		CAFile:       "ca.pem",
+		ServerName:   "api.internal",
+		MinVersion:   "1.2",
+		MaxVersion:   "1.3",
+		CipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"},
+		ALPN:         []string{"h2", "http/1.1"},
+	}
+	if !reflect.DeepEqual(cfg.TLS, want) {
+		t.Errorf("TLS = %+v, want %+v", cfg.TLS, want)
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tls.yaml")
+	content := `target: https://example.com
+protocol: grpc
+grpc:
+  service: helloworld.Greeter
+  method: SayHello
+  protofiles: [hello.proto]
+tls:
+  cert_file: client.pem
+  key_file: client.key
+  ca_file: ca.pem
+  min_version: "1.3"
+  alpn: [h2]
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	cfg, err = config.NewLoader().Load([]string{"--config", path})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want = config.TLSConfig{CertFile: "client.pem", KeyFile: "client.key", CAFile: "ca.pem", MinVersion: "1.3", ALPN: []string{"h2"}}
+	if !reflect.DeepEqual(cfg.TLS, want) {
+		t.Errorf("TLS = %+v, want %+v", cfg.TLS, want)
+	}
+	if !cfg.TLS.Enabled() {
+		t.Error("Enabled() = false with TLS settings")
+	}
+}
+
+func TestHTTPVersion(t *testing.T) {
+	cfg, err := config.NewLoader().Load([]string{"--target", "http://example.com", "--http-version", "H2C"})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.HTTPVersion != config.HTTPVersionH2C {
+		t.Errorf("HTTPVersion = %q, want %q", cfg.HTTPVersion, config.HTTPVersionH2C)
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "http3.yaml")
+	content := `target: https://example.com
+http_version: "3"
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	cfg, err = config.NewLoader().Load([]string{"--config", path})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.HTTPVersion != config.HTTPVersion3 {
+		t.Errorf("HTTPVersion = %q, want %q", cfg.HTTPVersion, config.HTTPVersion3)
+	}
+}
+
+func TestDNSConfig(t *testing.T) {
+	cfg, err := config.NewLoader().Load([]string{
+		"--target", "https://api.example.com",
+		"--resolve", "API.example.com:443:10.0.0.1,10.0.0.2",
+		"--resolve", "[::1]:8443:[fd00::1]",
+		"--dns-server", "10.0.0.53",
+		"--dns-round-robin",
+	})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := config.DNSConfig{
+		Resolve: map[string][]string{
+			"api.example.com:443": {"10.0.0.1:443", "10.0.0.2:443"},
+			"[::1]:8443":          {"[fd00::1]:8443"},
+		},
+		Server:     "10.0.0.53",
+		RoundRobin: true,
+	}
+	if !reflect.DeepEqual(cfg.DNS, want) {
+		t.Errorf("DNS = %+v, want %+v", cfg.DNS, want)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	if _, err := config.NewLoader().Load([]string{"--target", "http://example.com", "--resolve", "example.com:443"}); err == nil {
+		t.Error("Load() accepted --resolve without addresses")
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "dns.yaml")
+	content := `target: https://api.example.com
+dns:
+  resolve:
+    api.example.com:443: 10.0.0.1
+    grpc.example.com:50051:
+      - 10.0.0.2
+      - 10.0.0.3:9000
+  server: "10.0.0.53:5353"
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	cfg, err = config.NewLoader().Load([]string{"--config", path})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want = config.DNSConfig{
+		Resolve: map[string][]string{
+			"api.example.com:443":    {"10.0.0.1:443"},
+			"grpc.example.com:50051": {"10.0.0.2:50051", "10.0.0.3:9000"},
+		},
+		Server: "10.0.0.53:5353",
+	}
+	if !reflect.DeepEqual(cfg.DNS, want) {
+		t.Errorf("DNS = %+v, want %+v", cfg.DNS, want)
+	}
+}
+
+func TestCookies(t *testing.T) {
+	cfg, err := config.NewLoader().Load([]string{"--target", "http://example.com", "--cookie", "sid=abc", "--cookie", "theme=dark=1"})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := []config.Cookie{{Name: "sid", Value: "abc"}, {Name: "theme", Value: "dark=1"}}
+	if !reflect.DeepEqual(cfg.Cookies.Seed, want) {
+		t.Errorf("Seed = %+v, want %+v", cfg.Cookies.Seed, want)
+	}
+	if !cfg.Cookies.Enabled() {
+		t.Error("Enabled() = false with seeded cookies")
+	}
+	if _, err := config.NewLoader().Load([]string{"--target", "http://example.com", "--cookie", "sid"}); err == nil {
+		t.Error("Load() accepted --cookie without a value")
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cookies.yaml")
+	content := `target: http://example.com
+cookies:
+  jar: true
+  seed:
+    - name: consent
+      value: "yes"
+      domain: .example.com
+      path: /app
+      secure: true
+  extract:
+    - cookie: session_id
+      var: session
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	cfg, err = config.NewLoader().Load([]string{"--config", path})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	wantCfg := config.CookiesConfig{
+		Jar:     true,
+		Seed:    []config.Cookie{{Name: "consent", Value: "yes", Domain: ".example.com", Path: "/app", Secure: true}},
+		Extract: []config.CookieExtract{{Cookie: "session_id", Variable: "session"}},
+	}
+	if !reflect.DeepEqual(cfg.Cookies, wantCfg) {
+		t.Errorf("Cookies = %+v, want %+v", cfg.Cookies, wantCfg)
+	}
+}
+
+func TestThresholdAbortOptions(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "thresholds.yaml")
+	content := `target: http://example.com
+thresholds:
+  - "http_req_duration:p95 < 500"
+  - threshold: "http_req_failed:rate < 0.05"
+    abort_on_fail: true
+    delay: 30s
+    window: 10s
+  - threshold: "http_requests:rate > 10"
+    abort_on_fail: false
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	cfg, err := config.NewLoader().Load([]string{"--config", path})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := []string{
+		"http_req_duration:p95 < 500",
+		"http_req_failed:rate < 0.05 abort_on_fail delay=30s window=10s",
+		"http_requests:rate > 10",
+	}
+	if !reflect.DeepEqual(cfg.Thresholds, want) {
+		t.Errorf("Thresholds = %q, want %q", cfg.Thresholds, want)
+	}
+
+	if err := os.WriteFile(path, []byte("target: http://example.com\nthresholds:\n  - abort_on_fail: true\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if _, err := config.NewLoader().Load([]string{"--config", path}); err == nil {
+		t.Error("expected an error for a threshold object without a threshold")
+	}
+}
+
+func TestThresholdFlagKeepsCommas(t *testing.T) {
+	cfg, err := config.NewLoader().Load([]string{
+		"--target", "http://example.com",
+		"--threshold", "status_buckets{protocol=http,status=503}:count < 5",
+		"--threshold", "http_req_failed:rate < 0.1 abort_on_fail",
+	})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := []string{"status_buckets{protocol=http,status=503}:count < 5", "http_req_failed:rate < 0.1 abort_on_fail"}
+	if !reflect.DeepEqual(cfg.Thresholds, want) {
+		t.Errorf("Thresholds = %q, want %q", cfg.Thresholds, want)
+	}
+}
+
+func TestBaselineConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "baseline.yaml")
+	content := `target: http://example.com
+baseline:
+  run: sess/run1
+  latency_tolerance: 0.2
+  error_rate_tolerance: 0.05
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	cfg, err := config.NewLoader().Load([]string{"--config", path, "--baseline-latency-tolerance", "0.3"})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := config.BaselineConfig{Run: "sess/run1", LatencyTolerance: 0.3, ErrorRateTolerance: 0.05}
+	if cfg.Baseline != want {
+		t.Errorf("Baseline = %+v, want %+v", cfg.Baseline, want)
+	}
+
+	cfg, err = config.NewLoader().Load([]string{"--target", "http://example.com", "--baseline", "report.json"})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !cfg.Baseline.Enabled() || cfg.Baseline.Run != "report.json" {
+		t.Errorf("Baseline = %+v, want run report.json", cfg.Baseline)
+	}
+
+	cfg = &config.Config{TargetURL: "http://example.com", Concurrency: 1, Baseline: config.BaselineConfig{LatencyTolerance: 0.1}}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "baseline") {
+		t.Errorf("Validate() error = %v, want a baseline error for tolerance without run", err)
 	}
 }
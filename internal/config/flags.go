@@ -45,13 +45,20 @@ func configureFlags(flags *pflag.FlagSet) {
 	flags.Duration("timeout", 30*time.Second, "Per-request timeout")
 	flags.Duration("graceful-shutdown", 5*time.Second, "Max time to wait for in-flight requests after test ends (0=default, negative=cancel immediately)")
 	flags.Int("retries", 0, "Number of retries per request")
-	flags.String("arrival-model", string(ArrivalModelUniform), "Arrival model to use when pacing requests (uniform or poisson)")
+	flags.String("arrival-model", string(ArrivalModelUniform), "Arrival model to use when pacing requests (uniform, poisson or closed)")
+	flags.Duration("think-time", 0, "Closed model: pause after each iteration (constant value or exponential mean)")
+	flags.String("think-time-distribution", "", "Closed model: think time distribution (constant, uniform or exponential)")
+	flags.Duration("think-time-min", 0, "Closed model: lower bound for uniform think time")
+	flags.Duration("think-time-max", 0, "Closed model: upper bound for uniform think time")
+	flags.Duration("pacing", 0, "Closed model: minimum time between iteration starts per virtual user")
 
 	// Output flags
 	flags.Bool("json-output", false, "Emit JSON formatted output")
 	flags.Bool("dashboard", false, "Show live terminal dashboard with metrics")
+	flags.String("web-dashboard", "", "Serve a live web dashboard on this address (e.g., :8089)")
 	flags.Bool("log-errors", false, "Log each failed request to stderr")
 	flags.String("html-output", "", "Generate HTML report to the specified file path")
+	flags.String("junit-output", "", "Write thresholds as a JUnit XML report to the specified file path")
 	flags.String("config", "", "Path to configuration file (JSON or YAML)")
 
 	// Feeder flags
@@ -60,29 +67,50 @@ func configureFlags(flags *pflag.FlagSet) {
 
 	// Protocol flags
 	flags.String("protocol", "http", "Protocol mode: 'http', 'websocket', 'sse', or 'grpc'")
+	flags.String("http-version", "", "Force the HTTP version: '1.1', '2', 'h2c' or '3' (default negotiates 1.1 or 2)")
 
 	// WebSocket flags
 	flags.StringSlice("ws-messages", nil, "WebSocket messages to send (repeatable)")
 	flags.Duration("ws-message-interval", 0, "Interval between WebSocket messages")
 	flags.Duration("ws-receive-timeout", 10*time.Second, "WebSocket receive timeout")
 	flags.Duration("ws-handshake-timeout", 30*time.Second, "WebSocket handshake timeout")
+	flags.StringArray("ws-binary-message", nil, "Base64-encoded binary WebSocket frame to send (repeatable)")
+	flags.StringArray("ws-message-file", nil, "File to send as a binary WebSocket frame (repeatable)")
+	flags.Bool("ws-compression", false, "Negotiate permessage-deflate compression")
+	flags.Int("ws-compression-level", 0, "Deflate level for WebSocket compression (-2..9, 0=default)")
 
 	// SSE flags
 	flags.Duration("sse-read-timeout", 30*time.Second, "SSE read timeout")
 	flags.Int("sse-max-events", 0, "Max SSE events to read (0=unlimited)")
+	flags.Bool("sse-subscribe", false, "Keep SSE streams open and reconnect with Last-Event-ID")
+	flags.Duration("sse-reconnect-delay", 0, "SSE reconnect delay until the server sends retry (0=3s)")
+	flags.StringSlice("sse-event-type", nil, "Only count SSE events of these types (repeatable)")
+	flags.String("sse-lag-field", "", "JSONPath to the publish timestamp in SSE event data")
 
 	// gRPC flags
 	flags.String("grpc-proto-file", "", "Path to .proto file for gRPC")
+	flags.StringSlice("grpc-protoset", nil, "Precompiled FileDescriptorSet file (.protoset/.pb) for gRPC (repeatable)")
+	flags.Bool("grpc-reflection", false, "Fetch gRPC descriptors from the server reflection service")
+	flags.String("grpc-reflection-cache", "", "File to cache reflected gRPC descriptors in (reused when present)")
 	flags.String("grpc-service", "", "gRPC service name (e.g., helloworld.Greeter)")
 	flags.String("grpc-method", "", "gRPC method name (e.g., SayHello)")
 	flags.String("grpc-message", "", "gRPC message payload (JSON format)")
+	// StringArray rather than StringSlice: JSON payloads contain commas.
+	flags.StringArray("grpc-stream-message", nil, "gRPC message to send on a client/bidi stream (repeatable, JSON format)")
+	flags.Duration("grpc-message-interval", 0, "Interval between streamed gRPC messages")
+	flags.Int("grpc-expect-responses", 0, "Responses to receive before ending a gRPC stream (0=until server closes)")
+	flags.Duration("grpc-stream-timeout", 0, "Per-stream deadline for gRPC streaming calls (0=use grpc-timeout)")
 	flags.StringToString("grpc-metadata", nil, "gRPC metadata key=value pairs")
 	flags.Duration("grpc-timeout", 30*time.Second, "gRPC per-call timeout")
 	flags.Bool("grpc-tls", false, "Use TLS for gRPC connection")
 	flags.Bool("grpc-insecure", false, "Skip TLS verification for gRPC")
 
 	// Threshold flags
-	flags.StringSlice("threshold", nil, "Performance thresholds (repeatable, e.g., 'http_req_duration:p95 < 500')")
+	// StringArray rather than StringSlice: selectors such as {protocol=http,status=503} contain commas.
+	flags.StringArray("threshold", nil, "Performance thresholds (repeatable, e.g., 'http_req_duration:p95 < 500', append 'abort_on_fail delay=30s' to stop the run early)")
+
+	// Distributed flags
+	flags.StringSlice("agents", nil, "Agent addresses (host:port) to split the test across (repeatable or comma-separated)")
 
 	// HAR import flags
 	flags.String("har", "", "Path to HAR file to import as endpoints")
@@ -94,6 +122,41 @@ func configureFlags(flags *pflag.FlagSet) {
 	flags.String("tracing-service-name", "crankfire", "OpenTelemetry service name")
 	flags.Float64("tracing-sample-rate", 1.0, "Trace sampling rate (0.0 to 1.0)")
 	flags.Bool("tracing-insecure", false, "Skip TLS for OTLP exporter connection")
+
+	// OTLP metrics flags
+	flags.String("otlp-metrics-endpoint", "", "OTLP endpoint for metrics export (e.g., localhost:4317)")
+	flags.String("otlp-metrics-protocol", "grpc", "OTLP metrics transport protocol: 'grpc' or 'http'")
+	flags.Bool("otlp-metrics-insecure", false, "Skip TLS for OTLP metrics exporter connection")
+	flags.Duration("otlp-metrics-interval", DefaultOTLPMetricsInterval, "How often metrics are exported over OTLP")
+	flags.StringToString("otlp-metrics-attribute", nil, "Resource attribute added to exported metrics (key=value, repeatable)")
+
+	// Prometheus flags
+	flags.String("prometheus-listen", "", "Serve live metrics for Prometheus on this address (e.g., :9464)")
+	flags.StringToString("prometheus-label", nil, "Constant label added to every Prometheus series (key=value, repeatable)")
+
+	// TLS flags
+	flags.String("tls-cert", "", "PEM client certificate for mutual TLS (requires --tls-key)")
+	flags.String("tls-key", "", "PEM private key of the client certificate")
+	flags.String("tls-ca", "", "PEM CA bundle trusted instead of the system roots")
+	flags.String("tls-server-name", "", "Server name sent as SNI and verified in the certificate")
+	flags.String("tls-min-version", "", "Minimum TLS version: 1.0, 1.1, 1.2 or 1.3")
+	flags.String("tls-max-version", "", "Maximum TLS version: 1.0, 1.1, 1.2 or 1.3")
+	flags.StringSlice("tls-cipher", nil, "Allowed TLS 1.0-1.2 cipher suite, by crypto/tls name (repeatable or comma-separated)")
+	flags.StringSlice("tls-alpn", nil, "ALPN protocol offered during the TLS handshake (repeatable or comma-separated)")
+
+	// DNS flags
+	flags.StringArray("resolve", nil, "Connect to host:port at the given addresses instead of resolving it, in host:port:addr[,addr] form (repeatable)")
+	flags.String("dns-server", "", "DNS server used to resolve target hosts, as ip or ip:port")
+	flags.Bool("dns-round-robin", false, "Rotate new connections through every address a host resolves to")
+
+	// Cookie flags
+	flags.Bool("cookie-jar", false, "Give each virtual user a cookie jar that is reset every iteration")
+	flags.StringArray("cookie", nil, "Cookie every iteration starts with, in name=value form (repeatable, implies --cookie-jar)")
+
+	// Baseline flags
+	flags.String("baseline", "", "Compare against a session run ID or JSON report file and fail on regression")
+	flags.Float64("baseline-latency-tolerance", 0, "Allowed relative latency percentile increase over the baseline (default 0.10)")
+	flags.Float64("baseline-error-tolerance", 0, "Allowed absolute error rate increase over the baseline (default 0.01)")
 }
 
 // displayHelp prints the help message for a command.
@@ -194,6 +257,41 @@ func applyFlagOverrides(cfg *Config, fs *pflag.FlagSet) error {
 		}
 		cfg.Arrival.Model = ArrivalModel(strings.ToLower(strings.TrimSpace(val)))
 	}
+	if fs.Changed("think-time") {
+		val, err := fs.GetDuration("think-time")
+		if err != nil {
+			return err
+		}
+		cfg.Arrival.ThinkTime.Duration = val
+	}
+	if fs.Changed("think-time-distribution") {
+		val, err := fs.GetString("think-time-distribution")
+		if err != nil {
+			return err
+		}
+		cfg.Arrival.ThinkTime.Distribution = ThinkTimeDistribution(strings.ToLower(strings.TrimSpace(val)))
+	}
+	if fs.Changed("think-time-min") {
+		val, err := fs.GetDuration("think-time-min")
+		if err != nil {
+			return err
+		}
+		cfg.Arrival.ThinkTime.Min = val
+	}
+	if fs.Changed("think-time-max") {
+		val, err := fs.GetDuration("think-time-max")
+		if err != nil {
+			return err
+		}
+		cfg.Arrival.ThinkTime.Max = val
+	}
+	if fs.Changed("pacing") {
+		val, err := fs.GetDuration("pacing")
+		if err != nil {
+			return err
+		}
+		cfg.Arrival.Pacing = val
+	}
 	if fs.Changed("json-output") {
 		val, err := fs.GetBool("json-output")
 		if err != nil {
@@ -208,6 +306,13 @@ func applyFlagOverrides(cfg *Config, fs *pflag.FlagSet) error {
 		}
 		cfg.Dashboard = val
 	}
+	if fs.Changed("web-dashboard") {
+		val, err := fs.GetString("web-dashboard")
+		if err != nil {
+			return err
+		}
+		cfg.WebDashboard = strings.TrimSpace(val)
+	}
 	if fs.Changed("log-errors") {
 		val, err := fs.GetBool("log-errors")
 		if err != nil {
@@ -222,6 +327,13 @@ func applyFlagOverrides(cfg *Config, fs *pflag.FlagSet) error {
 		}
 		cfg.HTMLOutput = strings.TrimSpace(val)
 	}
+	if fs.Changed("junit-output") {
+		val, err := fs.GetString("junit-output")
+		if err != nil {
+			return err
+		}
+		cfg.JUnitOutput = strings.TrimSpace(val)
+	}
 
 	vals, err := fs.GetStringSlice("header")
 	if err != nil {
@@ -266,6 +378,13 @@ func applyFlagOverrides(cfg *Config, fs *pflag.FlagSet) error {
 		}
 		cfg.Protocol = Protocol(strings.ToLower(strings.TrimSpace(val)))
 	}
+	if fs.Changed("http-version") {
+		val, err := fs.GetString("http-version")
+		if err != nil {
+			return err
+		}
+		cfg.HTTPVersion = strings.ToLower(strings.TrimSpace(val))
+	}
 	if fs.Changed("ws-messages") {
 		val, err := fs.GetStringSlice("ws-messages")
 		if err != nil {
@@ -294,6 +413,34 @@ func applyFlagOverrides(cfg *Config, fs *pflag.FlagSet) error {
 		}
 		cfg.WebSocket.HandshakeTimeout = val
 	}
+	if fs.Changed("ws-binary-message") {
+		val, err := fs.GetStringArray("ws-binary-message")
+		if err != nil {
+			return err
+		}
+		cfg.WebSocket.BinaryMessages = val
+	}
+	if fs.Changed("ws-message-file") {
+		val, err := fs.GetStringArray("ws-message-file")
+		if err != nil {
+			return err
+		}
+		cfg.WebSocket.MessageFiles = val
+	}
+	if fs.Changed("ws-compression") {
+		val, err := fs.GetBool("ws-compression")
+		if err != nil {
+			return err
+		}
+		cfg.WebSocket.Compression = val
+	}
+	if fs.Changed("ws-compression-level") {
+		val, err := fs.GetInt("ws-compression-level")
+		if err != nil {
+			return err
+		}
+		cfg.WebSocket.CompressionLevel = val
+	}
 	if fs.Changed("sse-read-timeout") {
 		val, err := fs.GetDuration("sse-read-timeout")
 		if err != nil {
@@ -308,6 +455,34 @@ func applyFlagOverrides(cfg *Config, fs *pflag.FlagSet) error {
 		}
 		cfg.SSE.MaxEvents = val
 	}
+	if fs.Changed("sse-subscribe") {
+		val, err := fs.GetBool("sse-subscribe")
+		if err != nil {
+			return err
+		}
+		cfg.SSE.Subscribe = val
+	}
+	if fs.Changed("sse-reconnect-delay") {
+		val, err := fs.GetDuration("sse-reconnect-delay")
+		if err != nil {
+			return err
+		}
+		cfg.SSE.ReconnectDelay = val
+	}
+	if fs.Changed("sse-event-type") {
+		val, err := fs.GetStringSlice("sse-event-type")
+		if err != nil {
+			return err
+		}
+		cfg.SSE.EventTypes = val
+	}
+	if fs.Changed("sse-lag-field") {
+		val, err := fs.GetString("sse-lag-field")
+		if err != nil {
+			return err
+		}
+		cfg.SSE.LagField = val
+	}
 	if fs.Changed("grpc-proto-file") {
 		val, err := fs.GetString("grpc-proto-file")
 		if err != nil {
@@ -315,6 +490,27 @@ func applyFlagOverrides(cfg *Config, fs *pflag.FlagSet) error {
 		}
 		cfg.GRPC.ProtoFile = val
 	}
+	if fs.Changed("grpc-protoset") {
+		val, err := fs.GetStringSlice("grpc-protoset")
+		if err != nil {
+			return err
+		}
+		cfg.GRPC.Protosets = val
+	}
+	if fs.Changed("grpc-reflection") {
+		val, err := fs.GetBool("grpc-reflection")
+		if err != nil {
+			return err
+		}
+		cfg.GRPC.Reflection = val
+	}
+	if fs.Changed("grpc-reflection-cache") {
+		val, err := fs.GetString("grpc-reflection-cache")
+		if err != nil {
+			return err
+		}
+		cfg.GRPC.ReflectionCache = strings.TrimSpace(val)
+	}
 	if fs.Changed("grpc-service") {
 		val, err := fs.GetString("grpc-service")
 		if err != nil {
@@ -336,6 +532,34 @@ func applyFlagOverrides(cfg *Config, fs *pflag.FlagSet) error {
 		}
 		cfg.GRPC.Message = val
 	}
+	if fs.Changed("grpc-stream-message") {
+		val, err := fs.GetStringArray("grpc-stream-message")
+		if err != nil {
+			return err
+		}
+		cfg.GRPC.Messages = val
+	}
+	if fs.Changed("grpc-message-interval") {
+		val, err := fs.GetDuration("grpc-message-interval")
+		if err != nil {
+			return err
+		}
+		cfg.GRPC.MessageInterval = val
+	}
+	if fs.Changed("grpc-expect-responses") {
+		val, err := fs.GetInt("grpc-expect-responses")
+		if err != nil {
+			return err
+		}
+		cfg.GRPC.ExpectResponses = val
+	}
+	if fs.Changed("grpc-stream-timeout") {
+		val, err := fs.GetDuration("grpc-stream-timeout")
+		if err != nil {
+			return err
+		}
+		cfg.GRPC.StreamTimeout = val
+	}
 	if fs.Changed("grpc-metadata") {
 		val, err := fs.GetStringToString("grpc-metadata")
 		if err != nil {
@@ -365,12 +589,19 @@ func applyFlagOverrides(cfg *Config, fs *pflag.FlagSet) error {
 		cfg.GRPC.Insecure = val
 	}
 	if fs.Changed("threshold") {
-		val, err := fs.GetStringSlice("threshold")
+		val, err := fs.GetStringArray("threshold")
 		if err != nil {
 			return err
 		}
 		cfg.Thresholds = val
 	}
+	if fs.Changed("agents") {
+		val, err := fs.GetStringSlice("agents")
+		if err != nil {
+			return err
+		}
+		cfg.Agents = val
+	}
 
 	if fs.Changed("har") {
 		val, err := fs.GetString("har")
@@ -424,5 +655,186 @@ func applyFlagOverrides(cfg *Config, fs *pflag.FlagSet) error {
 		cfg.Tracing.Insecure = val
 	}
 
+	// OTLP metrics flag overrides
+	if fs.Changed("otlp-metrics-endpoint") {
+		val, err := fs.GetString("otlp-metrics-endpoint")
+		if err != nil {
+			return err
+		}
+		cfg.OTLPMetrics.Endpoint = strings.TrimSpace(val)
+	}
+	if fs.Changed("otlp-metrics-protocol") {
+		val, err := fs.GetString("otlp-metrics-protocol")
+		if err != nil {
+			return err
+		}
+		cfg.OTLPMetrics.Protocol = strings.ToLower(strings.TrimSpace(val))
+	}
+	if fs.Changed("otlp-metrics-insecure") {
+		val, err := fs.GetBool("otlp-metrics-insecure")
+		if err != nil {
+			return err
+		}
+		cfg.OTLPMetrics.Insecure = val
+	}
+	if fs.Changed("otlp-metrics-interval") {
+		val, err := fs.GetDuration("otlp-metrics-interval")
+		if err != nil {
+			return err
+		}
+		cfg.OTLPMetrics.Interval = val
+	}
+	if fs.Changed("otlp-metrics-attribute") {
+		val, err := fs.GetStringToString("otlp-metrics-attribute")
+		if err != nil {
+			return err
+		}
+		cfg.OTLPMetrics.Attributes = val
+	}
+
+	// Prometheus flag overrides
+	if fs.Changed("prometheus-listen") {
+		val, err := fs.GetString("prometheus-listen")
+		if err != nil {
+			return err
+		}
+		cfg.Prometheus.Listen = strings.TrimSpace(val)
+	}
+	if fs.Changed("prometheus-label") {
+		val, err := fs.GetStringToString("prometheus-label")
+		if err != nil {
+			return err
+		}
+		cfg.Prometheus.Labels = val
+	}
+
+	// Baseline flag overrides
+	if fs.Changed("baseline") {
+		val, err := fs.GetString("baseline")
+		if err != nil {
+			return err
+		}
+		cfg.Baseline.Run = strings.TrimSpace(val)
+	}
+	if fs.Changed("baseline-latency-tolerance") {
+		val, err := fs.GetFloat64("baseline-latency-tolerance")
+		if err != nil {
+			return err
+		}
+		cfg.Baseline.LatencyTolerance = val
+	}
+	if fs.Changed("baseline-error-tolerance") {
+		val, err := fs.GetFloat64("baseline-error-tolerance")
+		if err != nil {
+			return err
+		}
+		cfg.Baseline.ErrorRateTolerance = val
+	}
+
+	// TLS flag overrides
+	for _, f := range []struct {
+		name string
+		dst  *string
+	}{
+		{"tls-cert", &cfg.TLS.CertFile},
+		{"tls-key", &cfg.TLS.KeyFile},
+		{"tls-ca", &cfg.TLS.CAFile},
+		{"tls-server-name", &cfg.TLS.ServerName},
+		{"tls-min-version", &cfg.TLS.MinVersion},
+		{"tls-max-version", &cfg.TLS.MaxVersion},
+	} {
+		if fs.Changed(f.name) {
+			val, err := fs.GetString(f.name)
+			if err != nil {
+				return err
+			}
+			*f.dst = strings.TrimSpace(val)
+		}
+	}
+	if fs.Changed("tls-cipher") {
+		vals, err := fs.GetStringSlice("tls-cipher")
+		if err != nil {
+			return err
+		}
+		cfg.TLS.CipherSuites = vals
+	}
+	if fs.Changed("tls-alpn") {
+		vals, err := fs.GetStringSlice("tls-alpn")
+		if err != nil {
+			return err
+		}
+		cfg.TLS.ALPN = vals
+	}
+
+	// DNS flag overrides
+	if fs.Changed("resolve") {
+		vals, err := fs.GetStringArray("resolve")
+		if err != nil {
+			return err
+		}
+		for _, v := range vals {
+			key, addrs, err := parseResolveFlag(v)
+			if err != nil {
+				return err
+			}
+			cfg.DNS.AddResolve(key, addrs...)
+		}
+	}
+	if fs.Changed("dns-server") {
+		val, err := fs.GetString("dns-server")
+		if err != nil {
+			return err
+		}
+		cfg.DNS.Server = strings.TrimSpace(val)
+	}
+	if fs.Changed("dns-round-robin") {
+		val, err := fs.GetBool("dns-round-robin")
+		if err != nil {
+			return err
+		}
+		cfg.DNS.RoundRobin = val
+	}
+
+	// Cookie flag overrides
+	if fs.Changed("cookie-jar") {
+		val, err := fs.GetBool("cookie-jar")
+		if err != nil {
+			return err
+		}
+		cfg.Cookies.Jar = val
+	}
+	if fs.Changed("cookie") {
+		vals, err := fs.GetStringArray("cookie")
+		if err != nil {
+			return err
+		}
+		for _, v := range vals {
+			name, value, ok := strings.Cut(v, "=")
+			if !ok {
+				return fmt.Errorf("invalid cookie %q: expected name=value", v)
+			}
+			cfg.Cookies.Seed = append(cfg.Cookies.Seed, Cookie{Name: strings.TrimSpace(name), Value: value})
+		}
+	}
+
 	return nil
 }
+
+// parseResolveFlag splits a curl style host:port:addr[,addr] override into
+// its host:port key and addresses. IPv6 hosts and addresses are bracketed.
+func parseResolveFlag(v string) (string, []string, error) {
+	rest := strings.TrimSpace(v)
+	hostEnd := strings.Index(rest, ":")
+	if strings.HasPrefix(rest, "[") {
+		hostEnd = strings.Index(rest, "]:") + 1
+	}
+	if hostEnd <= 0 {
+		return "", nil, fmt.Errorf("invalid resolve %q: expected host:port:addr[,addr]", v)
+	}
+	host := rest[:hostEnd]
+	port, addrList, ok := strings.Cut(rest[hostEnd+1:], ":")
+	if !ok || port == "" || addrList == "" {
+		return "", nil, fmt.Errorf("invalid resolve %q: expected host:port:addr[,addr]", v)
+	}
+	return host + ":" + port, strings.Split(addrList, ","), nil
+}
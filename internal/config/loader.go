@@ -221,6 +221,14 @@ func applyConfigSettings(cfg *Config, settings map[string]interface{}) error {
 		cfg.Dashboard = val
 	}
 
+	if raw, ok := lookupSetting(settings, "webdashboard", "web_dashboard", "web-dashboard"); ok {
+		val, err := asString(raw)
+		if err != nil {
+			return fmt.Errorf("webDashboard: %w", err)
+		}
+		cfg.WebDashboard = strings.TrimSpace(val)
+	}
+
 	if raw, ok := lookupSetting(settings, "logerrors", "log_errors", "log-errors"); ok {
 		val, err := asBool(raw)
 		if err != nil {
@@ -237,6 +245,14 @@ func applyConfigSettings(cfg *Config, settings map[string]interface{}) error {
 		cfg.HTMLOutput = strings.TrimSpace(val)
 	}
 
+	if raw, ok := lookupSetting(settings, "junitoutput", "junit_output", "junit-output"); ok {
+		val, err := asString(raw)
+		if err != nil {
+			return fmt.Errorf("junitOutput: %w", err)
+		}
+		cfg.JUnitOutput = strings.TrimSpace(val)
+	}
+
 	if raw, ok := lookupSetting(settings, "loadpatterns", "load_patterns", "load-patterns"); ok {
 		patterns, err := parseLoadPatterns(raw)
 		if err != nil {
@@ -271,6 +287,14 @@ func applyConfigSettings(cfg *Config, settings map[string]interface{}) error {
 		cfg.Endpoints = endpoints
 	}
 
+	if raw, ok := lookupSetting(settings, "scenarios"); ok {
+		scenarios, err := parseScenarios(raw)
+		if err != nil {
+			return fmt.Errorf("scenarios: %w", err)
+		}
+		cfg.Scenarios = scenarios
+	}
+
 	if raw, ok := lookupSetting(settings, "auth"); ok {
 		auth, err := parseAuth(raw)
 		if err != nil {
@@ -295,6 +319,14 @@ func applyConfigSettings(cfg *Config, settings map[string]interface{}) error {
 		cfg.Protocol = Protocol(strings.ToLower(strings.TrimSpace(val)))
 	}
 
+	if raw, ok := lookupSetting(settings, "http_version", "httpVersion", "http-version"); ok {
+		val, err := asString(raw)
+		if err != nil {
+			return fmt.Errorf("http_version: %w", err)
+		}
+		cfg.HTTPVersion = strings.ToLower(strings.TrimSpace(val))
+	}
+
 	if raw, ok := lookupSetting(settings, "websocket"); ok {
 		ws, err := parseWebSocketConfig(raw)
 		if err != nil {
@@ -320,13 +352,21 @@ func applyConfigSettings(cfg *Config, settings map[string]interface{}) error {
 	}
 
 	if raw, ok := lookupSetting(settings, "thresholds"); ok {
-		thresholds, err := asStringSlice(raw)
+		thresholds, err := parseThresholds(raw)
 		if err != nil {
 			return fmt.Errorf("thresholds: %w", err)
 		}
 		cfg.Thresholds = thresholds
 	}
 
+	if raw, ok := lookupSetting(settings, "agents"); ok {
+		agents, err := asStringSlice(raw)
+		if err != nil {
+			return fmt.Errorf("agents: %w", err)
+		}
+		cfg.Agents = agents
+	}
+
 	if raw, ok := lookupSetting(settings, "harfile", "har_file", "har-file"); ok {
 		val, err := asString(raw)
 		if err != nil {
@@ -351,9 +391,116 @@ func applyConfigSettings(cfg *Config, settings map[string]interface{}) error {
 		cfg.Tracing = tracing
 	}
 
+	if raw, ok := lookupSetting(settings, "otlpmetrics", "otlp_metrics", "otlp-metrics"); ok {
+		otlpMetrics, err := parseOTLPMetricsConfig(raw)
+		if err != nil {
+			return fmt.Errorf("otlp_metrics: %w", err)
+		}
+		cfg.OTLPMetrics = otlpMetrics
+	}
+
+	if raw, ok := lookupSetting(settings, "prometheus"); ok {
+		prometheus, err := parsePrometheusConfig(raw)
+		if err != nil {
+			return fmt.Errorf("prometheus: %w", err)
+		}
+		cfg.Prometheus = prometheus
+	}
+
+	if raw, ok := lookupSetting(settings, "baseline"); ok {
+		baseline, err := parseBaselineConfig(raw)
+		if err != nil {
+			return fmt.Errorf("baseline: %w", err)
+		}
+		cfg.Baseline = baseline
+	}
+
+	if raw, ok := lookupSetting(settings, "tls"); ok {
+		tlsCfg, err := parseTLSConfig(raw)
+		if err != nil {
+			return fmt.Errorf("tls: %w", err)
+		}
+		cfg.TLS = tlsCfg
+	}
+
+	if raw, ok := lookupSetting(settings, "dns"); ok {
+		dns, err := parseDNSConfig(raw)
+		if err != nil {
+			return fmt.Errorf("dns: %w", err)
+		}
+		cfg.DNS = dns
+	}
+
+	if raw, ok := lookupSetting(settings, "cookies"); ok {
+		cookies, err := parseCookiesConfig(raw)
+		if err != nil {
+			return fmt.Errorf("cookies: %w", err)
+		}
+		cfg.Cookies = cookies
+	}
+
 	return nil
 }
 
+// parseThresholds accepts threshold strings or objects with a threshold plus
+// abort_on_fail, delay and window, which are rendered as threshold string
+// options.
+func parseThresholds(value interface{}) ([]string, error) {
+	if value == nil {
+		return nil, nil
+	}
+	items, err := toInterfaceSlice(value)
+	if err != nil {
+		return asStringSlice(value)
+	}
+	result := make([]string, 0, len(items))
+	for i, item := range items {
+		if str, ok := item.(string); ok {
+			result = append(result, str)
+			continue
+		}
+		settings, err := toStringKeyMap(item)
+		if err != nil {
+			return nil, fmt.Errorf("index %d: %w", i, err)
+		}
+		raw, _ := lookupSetting(settings, "threshold")
+		expr, err := asString(raw)
+		if err != nil || strings.TrimSpace(expr) == "" {
+			return nil, fmt.Errorf("index %d: threshold is required", i)
+		}
+		expr = strings.TrimSpace(expr)
+		if raw, ok := lookupSetting(settings, "abort_on_fail", "abortOnFail", "abort-on-fail"); ok {
+			abort, err := asBool(raw)
+			if err != nil {
+				return nil, fmt.Errorf("index %d: abort_on_fail: %w", i, err)
+			}
+			if abort {
+				expr += " abort_on_fail"
+			}
+		}
+		if raw, ok := lookupSetting(settings, "delay"); ok {
+			delay, err := asDuration(raw)
+			if err != nil {
+				return nil, fmt.Errorf("index %d: delay: %w", i, err)
+			}
+			if delay > 0 {
+				expr += " delay=" + delay.String()
+			}
+		}
+		if raw, ok := lookupSetting(settings, "window"); ok {
+			window, err := asDuration(raw)
+			if err != nil {
+				return nil, fmt.Errorf("index %d: window: %w", i, err)
+			}
+			if window > 0 {
+				expr += " window=" + window.String()
+			}
+		}
+		result = append(result, expr)
+	}
+	return result, nil
+}
+
 func parseLoadPatterns(value interface{}) ([]LoadPattern, error) {
 	if value == nil {
 		return nil, nil
@@ -393,14 +540,14 @@ func buildLoadPattern(settings map[string]interface{}) (LoadPattern, error) {
 		}
 		pattern.Type = LoadPatternType(strings.ToLower(strings.TrimSpace(val)))
 	}
-	if raw, ok := lookupSetting(settings, "fromrps", "from_rps", "from-rps"); ok {
+	if raw, ok := lookupSetting(settings, "fromrps", "from_rps", "from-rps", "fromvus", "from_vus", "from-vus"); ok {
 		val, err := asInt(raw)
 		if err != nil {
 			return LoadPattern{}, fmt.Errorf("from_rps: %w", err)
 		}
 		pattern.FromRPS = val
 	}
-	if raw, ok := lookupSetting(settings, "torps", "to_rps", "to-rps"); ok {
+	if raw, ok := lookupSetting(settings, "torps", "to_rps", "to-rps", "tovus", "to_vus", "to-vus"); ok {
 		val, err := asInt(raw)
 		if err != nil {
 			return LoadPattern{}, fmt.Errorf("to_rps: %w", err)
@@ -421,7 +568,7 @@ func buildLoadPattern(settings map[string]interface{}) (LoadPattern, error) {
 		}
 		pattern.Steps = steps
 	}
-	if raw, ok := lookupSetting(settings, "rps"); ok {
+	if raw, ok := lookupSetting(settings, "rps", "vus"); ok {
 		val, err := asInt(raw)
 		if err != nil {
 			return LoadPattern{}, fmt.Errorf("rps: %w", err)
@@ -443,7 +590,7 @@ func parseLoadSteps(value interface{}) ([]LoadStep, error) {
 			return nil, fmt.Errorf("index %d: %w", idx, err)
 		}
 		var step LoadStep
-		if raw, ok := lookupSetting(entry, "rps"); ok {
+		if raw, ok := lookupSetting(entry, "rps", "vus"); ok {
 			val, err := asInt(raw)
 			if err != nil {
 				return nil, fmt.Errorf("index %d rps: %w", idx, err)
@@ -478,17 +625,81 @@ func parseArrival(value interface{}) (ArrivalConfig, error) {
 		if err != nil {
 			return ArrivalConfig{}, err
 		}
-		if raw, ok := lookupSetting(entry, "model"); ok {
-			val, err := asString(raw)
+		raw, ok := lookupSetting(entry, "model")
+		if !ok {
+			return ArrivalConfig{}, fmt.Errorf("model field is required")
+		}
+		val, err := asString(raw)
+		if err != nil {
+			return ArrivalConfig{}, fmt.Errorf("model: %w", err)
+		}
+		arrival := ArrivalConfig{Model: ArrivalModel(strings.ToLower(strings.TrimSpace(val)))}
+		if raw, ok := lookupSetting(entry, "thinktime", "think_time", "think-time"); ok {
+			think, err := parseThinkTime(raw)
 			if err != nil {
-				return ArrivalConfig{}, fmt.Errorf("model: %w", err)
+				return ArrivalConfig{}, fmt.Errorf("think_time: %w", err)
 			}
-			return ArrivalConfig{Model: ArrivalModel(strings.ToLower(strings.TrimSpace(val)))}, nil
+			arrival.ThinkTime = think
 		}
-		return ArrivalConfig{}, fmt.Errorf("model field is required")
+		if raw, ok := lookupSetting(entry, "pacing"); ok {
+			val, err := asDuration(raw)
+			if err != nil {
+				return ArrivalConfig{}, fmt.Errorf("pacing: %w", err)
+			}
+			arrival.Pacing = val
+		}
+		return arrival, nil
 	}
 }
 
+// parseThinkTime accepts either a plain duration (constant think time) or a
+// map with distribution, duration, min and max.
+func parseThinkTime(value interface{}) (ThinkTimeConfig, error) {
+	switch value.(type) {
+	case map[string]interface{}, map[interface{}]interface{}:
+	default:
+		val, err := asDuration(value)
+		if err != nil {
+			return ThinkTimeConfig{}, err
+		}
+		return ThinkTimeConfig{Duration: val}, nil
+	}
+	entry, err := toStringKeyMap(value)
+	if err != nil {
+		return ThinkTimeConfig{}, err
+	}
+	var think ThinkTimeConfig
+	if raw, ok := lookupSetting(entry, "distribution"); ok {
+		val, err := asString(raw)
+		if err != nil {
+			return ThinkTimeConfig{}, fmt.Errorf("distribution: %w", err)
+		}
+		think.Distribution = ThinkTimeDistribution(strings.ToLower(strings.TrimSpace(val)))
+	}
+	if raw, ok := lookupSetting(entry, "duration", "mean"); ok {
+		val, err := asDuration(raw)
+		if err != nil {
+			return ThinkTimeConfig{}, fmt.Errorf("duration: %w", err)
+		}
+		think.Duration = val
+	}
+	if raw, ok := lookupSetting(entry, "min"); ok {
+		val, err := asDuration(raw)
+		if err != nil {
+			return ThinkTimeConfig{}, fmt.Errorf("min: %w", err)
+		}
+		think.Min = val
+	}
+	if raw, ok := lookupSetting(entry, "max"); ok {
+		val, err := asDuration(raw)
+		if err != nil {
+			return ThinkTimeConfig{}, fmt.Errorf("max: %w", err)
+		}
+		think.Max = val
+	}
+	return think, nil
+}
+
 func parseEndpoints(value interface{}) ([]Endpoint, error) {
 	if value == nil {
 		return nil, nil
@@ -587,9 +798,199 @@ func buildEndpoint(settings map[string]interface{}) (Endpoint, error) {
 		}
 		endpoint.Extractors = extractors
 	}
+	if raw, ok := lookupSetting(settings, "checks"); ok {
+		checks, err := parseChecks(raw)
+		if err != nil {
+			return Endpoint{}, fmt.Errorf("checks: %w", err)
+		}
+		endpoint.Checks = checks
+	}
 	return endpoint, nil
 }
 
+func parseScenarios(value interface{}) ([]Scenario, error) {
+	if value == nil {
+		return nil, nil
+	}
+	items, err := toInterfaceSlice(value)
+	if err != nil {
+		return nil, err
+	}
+	scenarios := make([]Scenario, 0, len(items))
+	for idx, item := range items {
+		entry, err := toStringKeyMap(item)
+		if err != nil {
+			return nil, fmt.Errorf("index %d: %w", idx, err)
+		}
+		scenario, err := buildScenario(entry)
+		if err != nil {
+			return nil, fmt.Errorf("index %d: %w", idx, err)
+		}
+		scenarios = append(scenarios, scenario)
+	}
+	return scenarios, nil
+}
+
+func buildScenario(settings map[string]interface{}) (Scenario, error) {
+	scenario := Scenario{Weight: 1}
+	if raw, ok := lookupSetting(settings, "name"); ok {
+		val, err := asString(raw)
+		if err != nil {
+			return Scenario{}, fmt.Errorf("name: %w", err)
+		}
+		scenario.Name = strings.TrimSpace(val)
+	}
+	if raw, ok := lookupSetting(settings, "weight"); ok {
+		val, err := asInt(raw)
+		if err != nil {
+			return Scenario{}, fmt.Errorf("weight: %w", err)
+		}
+		scenario.Weight = val
+	}
+	if raw, ok := lookupSetting(settings, "thinktime", "think_time", "think-time"); ok {
+		val, err := asDuration(raw)
+		if err != nil {
+			return Scenario{}, fmt.Errorf("think_time: %w", err)
+		}
+		scenario.ThinkTime = val
+	}
+	if raw, ok := lookupSetting(settings, "steps"); ok {
+		items, err := toInterfaceSlice(raw)
+		if err != nil {
+			return Scenario{}, fmt.Errorf("steps: %w", err)
+		}
+		for idx, item := range items {
+			entry, err := toStringKeyMap(item)
+			if err != nil {
+				return Scenario{}, fmt.Errorf("steps: index %d: %w", idx, err)
+			}
+			step, err := buildScenarioStep(entry)
+			if err != nil {
+				return Scenario{}, fmt.Errorf("steps: index %d: %w", idx, err)
+			}
+			scenario.Steps = append(scenario.Steps, step)
+		}
+	}
+	return scenario, nil
+}
+
+func buildScenarioStep(settings map[string]interface{}) (ScenarioStep, error) {
+	if _, ok := lookupSetting(settings, "weight"); ok {
+		return ScenarioStep{}, fmt.Errorf("weight: not supported on scenario steps")
+	}
+	endpoint, err := buildEndpoint(settings)
+	if err != nil {
+		return ScenarioStep{}, err
+	}
+	step := ScenarioStep{Endpoint: endpoint}
+	if raw, ok := lookupSetting(settings, "thinktime", "think_time", "think-time"); ok {
+		val, err := asDuration(raw)
+		if err != nil {
+			return ScenarioStep{}, fmt.Errorf("think_time: %w", err)
+		}
+		step.ThinkTime = &val
+	}
+	return step, nil
+}
+
+func parseChecks(value interface{}) ([]Check, error) {
+	if value == nil {
+		return nil, nil
+	}
+	items, err := toInterfaceSlice(value)
+	if err != nil {
+		return nil, err
+	}
+	checks := make([]Check, 0, len(items))
+	for idx, item := range items {
+		entry, err := toStringKeyMap(item)
+		if err != nil {
+			return nil, fmt.Errorf("index %d: %w", idx, err)
+		}
+		check, err := buildCheck(entry)
+		if err != nil {
+			return nil, fmt.Errorf("index %d: %w", idx, err)
+		}
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
+
+func buildCheck(settings map[string]interface{}) (Check, error) {
+	var check Check
+	if raw, ok := lookupSetting(settings, "name"); ok {
+		val, err := asString(raw)
+		if err != nil {
+			return Check{}, fmt.Errorf("name: %w", err)
+		}
+		check.Name = strings.TrimSpace(val)
+	}
+	if raw, ok := lookupSetting(settings, "status"); ok {
+		val, err := asIntSlice(raw)
+		if err != nil {
+			return Check{}, fmt.Errorf("status: %w", err)
+		}
+		check.Status = val
+	}
+	if raw, ok := lookupSetting(settings, "header"); ok {
+		val, err := asString(raw)
+		if err != nil {
+			return Check{}, fmt.Errorf("header: %w", err)
+		}
+		check.Header = strings.TrimSpace(val)
+	}
+	if raw, ok := lookupSetting(settings, "jsonpath"); ok {
+		val, err := asString(raw)
+		if err != nil {
+			return Check{}, fmt.Errorf("jsonpath: %w", err)
+		}
+		check.JSONPath = strings.TrimSpace(val)
+	}
+	if raw, ok := lookupSetting(settings, "regex"); ok {
+		val, err := asString(raw)
+		if err != nil {
+			return Check{}, fmt.Errorf("regex: %w", err)
+		}
+		check.Regex = val
+	}
+	if raw, ok := lookupSetting(settings, "equals"); ok {
+		val, err := asString(raw)
+		if err != nil {
+			return Check{}, fmt.Errorf("equals: %w", err)
+		}
+		check.Equals = val
+	}
+	if raw, ok := lookupSetting(settings, "contains"); ok {
+		val, err := asString(raw)
+		if err != nil {
+			return Check{}, fmt.Errorf("contains: %w", err)
+		}
+		check.Contains = val
+	}
+	if raw, ok := lookupSetting(settings, "matches"); ok {
+		val, err := asString(raw)
+		if err != nil {
+			return Check{}, fmt.Errorf("matches: %w", err)
+		}
+		check.Matches = val
+	}
+	if raw, ok := lookupSetting(settings, "minbodysize", "min_body_size", "min-body-size"); ok {
+		val, err := asInt(raw)
+		if err != nil {
+			return Check{}, fmt.Errorf("min_body_size: %w", err)
+		}
+		check.MinBodySize = val
+	}
+	if raw, ok := lookupSetting(settings, "maxbodysize", "max_body_size", "max-body-size"); ok {
+		val, err := asInt(raw)
+		if err != nil {
+			return Check{}, fmt.Errorf("max_body_size: %w", err)
+		}
+		check.MaxBodySize = val
+	}
+	return check, nil
+}
+
 func parseExtractors(value interface{}) ([]Extractor, error) {
 	if value == nil {
 		return nil, nil
@@ -793,6 +1194,20 @@ func buildWebSocketConfig(settings map[string]interface{}) (WebSocketConfig, err
 		}
 		ws.Messages = messages
 	}
+	if raw, ok := lookupSetting(settings, "binarymessages", "binary_messages", "binary-messages"); ok {
+		messages, err := asStringSlice(raw)
+		if err != nil {
+			return WebSocketConfig{}, fmt.Errorf("binary_messages: %w", err)
+		}
+		ws.BinaryMessages = messages
+	}
+	if raw, ok := lookupSetting(settings, "messagefiles", "message_files", "message-files"); ok {
+		files, err := asStringSlice(raw)
+		if err != nil {
+			return WebSocketConfig{}, fmt.Errorf("message_files: %w", err)
+		}
+		ws.MessageFiles = files
+	}
 	if raw, ok := lookupSetting(settings, "messageinterval", "message_interval", "message-interval"); ok {
 		dur, err := asDuration(raw)
 		if err != nil {
@@ -814,9 +1229,151 @@ func buildWebSocketConfig(settings map[string]interface{}) (WebSocketConfig, err
 		}
 		ws.HandshakeTimeout = dur
 	}
+	if raw, ok := lookupSetting(settings, "compression"); ok {
+		val, err := asBool(raw)
+		if err != nil {
+			return WebSocketConfig{}, fmt.Errorf("compression: %w", err)
+		}
+		ws.Compression = val
+	}
+	if raw, ok := lookupSetting(settings, "compressionlevel", "compression_level", "compression-level"); ok {
+		level, err := asInt(raw)
+		if err != nil {
+			return WebSocketConfig{}, fmt.Errorf("compression_level: %w", err)
+		}
+		ws.CompressionLevel = level
+	}
+	if raw, ok := lookupSetting(settings, "steps"); ok {
+		steps, err := parseWebSocketSteps(raw)
+		if err != nil {
+			return WebSocketConfig{}, fmt.Errorf("steps: %w", err)
+		}
+		ws.Steps = steps
+	}
 	return ws, nil
 }
 
+func parseWebSocketSteps(value interface{}) ([]WebSocketStep, error) {
+	if value == nil {
+		return nil, nil
+	}
+	items, err := toInterfaceSlice(value)
+	if err != nil {
+		return nil, err
+	}
+	steps := make([]WebSocketStep, 0, len(items))
+	for idx, item := range items {
+		entry, err := toStringKeyMap(item)
+		if err != nil {
+			return nil, fmt.Errorf("index %d: %w", idx, err)
+		}
+		step, err := buildWebSocketStep(entry)
+		if err != nil {
+			return nil, fmt.Errorf("index %d: %w", idx, err)
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+func buildWebSocketStep(settings map[string]interface{}) (WebSocketStep, error) {
+	var step WebSocketStep
+	if raw, ok := lookupSetting(settings, "name"); ok {
+		val, err := asString(raw)
+		if err != nil {
+			return WebSocketStep{}, fmt.Errorf("name: %w", err)
+		}
+		step.Name = strings.TrimSpace(val)
+	}
+	if raw, ok := lookupSetting(settings, "send"); ok {
+		val, err := asString(raw)
+		if err != nil {
+			return WebSocketStep{}, fmt.Errorf("send: %w", err)
+		}
+		step.Send = val
+	}
+	if raw, ok := lookupSetting(settings, "sendbinary", "send_binary", "send-binary"); ok {
+		val, err := asString(raw)
+		if err != nil {
+			return WebSocketStep{}, fmt.Errorf("send_binary: %w", err)
+		}
+		step.SendBinary = strings.TrimSpace(val)
+	}
+	if raw, ok := lookupSetting(settings, "sendfile", "send_file", "send-file"); ok {
+		val, err := asString(raw)
+		if err != nil {
+			return WebSocketStep{}, fmt.Errorf("send_file: %w", err)
+		}
+		step.SendFile = strings.TrimSpace(val)
+	}
+	if raw, ok := lookupSetting(settings, "expect"); ok && raw != nil {
+		entry, err := toStringKeyMap(raw)
+		if err != nil {
+			return WebSocketStep{}, fmt.Errorf("expect: %w", err)
+		}
+		expect, err := buildWebSocketExpect(entry)
+		if err != nil {
+			return WebSocketStep{}, fmt.Errorf("expect: %w", err)
+		}
+		step.Expect = &expect
+	}
+	if raw, ok := lookupSetting(settings, "extract", "extractors"); ok {
+		extractors, err := parseExtractors(raw)
+		if err != nil {
+			return WebSocketStep{}, fmt.Errorf("extract: %w", err)
+		}
+		step.Extract = extractors
+	}
+	if raw, ok := lookupSetting(settings, "sleep"); ok {
+		dur, err := asDuration(raw)
+		if err != nil {
+			return WebSocketStep{}, fmt.Errorf("sleep: %w", err)
+		}
+		step.Sleep = dur
+	}
+	if raw, ok := lookupSetting(settings, "close"); ok {
+		code, err := asInt(raw)
+		if err != nil {
+			return WebSocketStep{}, fmt.Errorf("close: %w", err)
+		}
+		step.Close = code
+	}
+	if raw, ok := lookupSetting(settings, "timeout"); ok {
+		dur, err := asDuration(raw)
+		if err != nil {
+			return WebSocketStep{}, fmt.Errorf("timeout: %w", err)
+		}
+		step.Timeout = dur
+	}
+	return step, nil
+}
+
+func buildWebSocketExpect(settings map[string]interface{}) (WebSocketExpect, error) {
+	var expect WebSocketExpect
+	if raw, ok := lookupSetting(settings, "regex"); ok {
+		val, err := asString(raw)
+		if err != nil {
+			return WebSocketExpect{}, fmt.Errorf("regex: %w", err)
+		}
+		expect.Regex = strings.TrimSpace(val)
+	}
+	if raw, ok := lookupSetting(settings, "jsonpath"); ok {
+		val, err := asString(raw)
+		if err != nil {
+			return WebSocketExpect{}, fmt.Errorf("jsonpath: %w", err)
+		}
+		expect.JSONPath = strings.TrimSpace(val)
+	}
+	if raw, ok := lookupSetting(settings, "equals"); ok {
+		val, err := asString(raw)
+		if err != nil {
+			return WebSocketExpect{}, fmt.Errorf("equals: %w", err)
+		}
+		expect.Equals = val
+	}
+	return expect, nil
+}
+
 func parseSSEConfig(value interface{}) (SSEConfig, error) {
 	if value == nil {
 		return SSEConfig{}, nil
@@ -844,6 +1401,34 @@ func buildSSEConfig(settings map[string]interface{}) (SSEConfig, error) {
 		}
 		sse.MaxEvents = val
 	}
+	if raw, ok := lookupSetting(settings, "subscribe"); ok {
+		val, err := asBool(raw)
+		if err != nil {
+			return SSEConfig{}, fmt.Errorf("subscribe: %w", err)
+		}
+		sse.Subscribe = val
+	}
+	if raw, ok := lookupSetting(settings, "reconnectdelay", "reconnect_delay", "reconnect-delay"); ok {
+		dur, err := asDuration(raw)
+		if err != nil {
+			return SSEConfig{}, fmt.Errorf("reconnect_delay: %w", err)
+		}
+		sse.ReconnectDelay = dur
+	}
+	if raw, ok := lookupSetting(settings, "eventtypes", "event_types", "event-types"); ok {
+		types, err := asStringSlice(raw)
+		if err != nil {
+			return SSEConfig{}, fmt.Errorf("event_types: %w", err)
+		}
+		sse.EventTypes = types
+	}
+	if raw, ok := lookupSetting(settings, "lagfield", "lag_field", "lag-field"); ok {
+		val, err := asString(raw)
+		if err != nil {
+			return SSEConfig{}, fmt.Errorf("lag_field: %w", err)
+		}
+		sse.LagField = strings.TrimSpace(val)
+	}
 	return sse, nil
 }
 
@@ -867,6 +1452,27 @@ func buildGRPCConfig(settings map[string]interface{}) (GRPCConfig, error) {
 		}
 		grpc.ProtoFile = val
 	}
+	if raw, ok := lookupSetting(settings, "protosets", "protoset"); ok {
+		paths, err := asStringSlice(raw)
+		if err != nil {
+			return GRPCConfig{}, fmt.Errorf("protosets: %w", err)
+		}
+		grpc.Protosets = paths
+	}
+	if raw, ok := lookupSetting(settings, "reflection"); ok {
+		val, err := asBool(raw)
+		if err != nil {
+			return GRPCConfig{}, fmt.Errorf("reflection: %w", err)
+		}
+		grpc.Reflection = val
+	}
+	if raw, ok := lookupSetting(settings, "reflectioncache", "reflection_cache", "reflection-cache"); ok {
+		val, err := asString(raw)
+		if err != nil {
+			return GRPCConfig{}, fmt.Errorf("reflection_cache: %w", err)
+		}
+		grpc.ReflectionCache = strings.TrimSpace(val)
+	}
 	if raw, ok := lookupSetting(settings, "service"); ok {
 		val, err := asString(raw)
 		if err != nil {
@@ -888,6 +1494,34 @@ func buildGRPCConfig(settings map[string]interface{}) (GRPCConfig, error) {
 		}
 		grpc.Message = val
 	}
+	if raw, ok := lookupSetting(settings, "messages"); ok {
+		messages, err := asStringSlice(raw)
+		if err != nil {
+			return GRPCConfig{}, fmt.Errorf("messages: %w", err)
+		}
+		grpc.Messages = messages
+	}
+	if raw, ok := lookupSetting(settings, "messageinterval", "message_interval", "message-interval"); ok {
+		dur, err := asDuration(raw)
+		if err != nil {
+			return GRPCConfig{}, fmt.Errorf("message_interval: %w", err)
+		}
+		grpc.MessageInterval = dur
+	}
+	if raw, ok := lookupSetting(settings, "expectresponses", "expect_responses", "expect-responses"); ok {
+		val, err := asInt(raw)
+		if err != nil {
+			return GRPCConfig{}, fmt.Errorf("expect_responses: %w", err)
+		}
+		grpc.ExpectResponses = val
+	}
+	if raw, ok := lookupSetting(settings, "streamtimeout", "stream_timeout", "stream-timeout"); ok {
+		dur, err := asDuration(raw)
+		if err != nil {
+			return GRPCConfig{}, fmt.Errorf("stream_timeout: %w", err)
+		}
+		grpc.StreamTimeout = dur
+	}
 	if raw, ok := lookupSetting(settings, "metadata"); ok {
 		val, err := asStringMap(raw)
 		if err != nil {
@@ -1019,6 +1653,322 @@ func buildTracingConfig(settings map[string]interface{}) (TracingConfig, error)
 	return tracing, nil
 }
 
+func parseOTLPMetricsConfig(value interface{}) (OTLPMetricsConfig, error) {
+	if value == nil {
+		return OTLPMetricsConfig{}, nil
+	}
+	settings, err := toStringKeyMap(value)
+	if err != nil {
+		return OTLPMetricsConfig{}, err
+	}
+	var otlpMetrics OTLPMetricsConfig
+	if raw, ok := lookupSetting(settings, "endpoint"); ok {
+		val, err := asString(raw)
+		if err != nil {
+			return OTLPMetricsConfig{}, fmt.Errorf("endpoint: %w", err)
+		}
+		otlpMetrics.Endpoint = strings.TrimSpace(val)
+	}
+	if raw, ok := lookupSetting(settings, "protocol"); ok {
+		val, err := asString(raw)
+		if err != nil {
+			return OTLPMetricsConfig{}, fmt.Errorf("protocol: %w", err)
+		}
+		otlpMetrics.Protocol = strings.ToLower(strings.TrimSpace(val))
+	}
+	if raw, ok := lookupSetting(settings, "insecure"); ok {
+		val, err := asBool(raw)
+		if err != nil {
+			return OTLPMetricsConfig{}, fmt.Errorf("insecure: %w", err)
+		}
+		otlpMetrics.Insecure = val
+	}
+	if raw, ok := lookupSetting(settings, "interval"); ok {
+		val, err := asDuration(raw)
+		if err != nil {
+			return OTLPMetricsConfig{}, fmt.Errorf("interval: %w", err)
+		}
+		otlpMetrics.Interval = val
+	}
+	if raw, ok := lookupSetting(settings, "attributes"); ok {
+		val, err := asStringMap(raw)
+		if err != nil {
+			return OTLPMetricsConfig{}, fmt.Errorf("attributes: %w", err)
+		}
+		otlpMetrics.Attributes = val
+	}
+	return otlpMetrics, nil
+}
+
+func parsePrometheusConfig(value interface{}) (PrometheusConfig, error) {
+	if value == nil {
+		return PrometheusConfig{}, nil
+	}
+	settings, err := toStringKeyMap(value)
+	if err != nil {
+		return PrometheusConfig{}, err
+	}
+	var prometheus PrometheusConfig
+	if raw, ok := lookupSetting(settings, "listen"); ok {
+		val, err := asString(raw)
+		if err != nil {
+			return PrometheusConfig{}, fmt.Errorf("listen: %w", err)
+		}
+		prometheus.Listen = strings.TrimSpace(val)
+	}
+	if raw, ok := lookupSetting(settings, "labels"); ok {
+		val, err := asStringMap(raw)
+		if err != nil {
+			return PrometheusConfig{}, fmt.Errorf("labels: %w", err)
+		}
+		prometheus.Labels = val
+	}
+	return prometheus, nil
+}
+
+// parseBaselineConfig accepts a run reference or an object with run and
+// tolerances.
+func parseBaselineConfig(value interface{}) (BaselineConfig, error) {
+	if value == nil {
+		return BaselineConfig{}, nil
+	}
+	if ref, ok := value.(string); ok {
+		return BaselineConfig{Run: strings.TrimSpace(ref)}, nil
+	}
+	settings, err := toStringKeyMap(value)
+	if err != nil {
+		return BaselineConfig{}, err
+	}
+	var baseline BaselineConfig
+	if raw, ok := lookupSetting(settings, "run"); ok {
+		val, err := asString(raw)
+		if err != nil {
+			return BaselineConfig{}, fmt.Errorf("run: %w", err)
+		}
+		baseline.Run = strings.TrimSpace(val)
+	}
+	if raw, ok := lookupSetting(settings, "latency_tolerance", "latencyTolerance", "latency-tolerance"); ok {
+		val, err := asFloat64(raw)
+		if err != nil {
+			return BaselineConfig{}, fmt.Errorf("latency_tolerance: %w", err)
+		}
+		baseline.LatencyTolerance = val
+	}
+	if raw, ok := lookupSetting(settings, "error_rate_tolerance", "errorRateTolerance", "error-rate-tolerance"); ok {
+		val, err := asFloat64(raw)
+		if err != nil {
+			return BaselineConfig{}, fmt.Errorf("error_rate_tolerance: %w", err)
+		}
+		baseline.ErrorRateTolerance = val
+	}
+	return baseline, nil
+}
+
+func parseTLSConfig(value interface{}) (TLSConfig, error) {
+	if value == nil {
+		return TLSConfig{}, nil
+	}
+	settings, err := toStringKeyMap(value)
+	if err != nil {
+		return TLSConfig{}, err
+	}
+	var tlsCfg TLSConfig
+	for _, field := range []struct {
+		keys []string
+		dst  *string
+	}{
+		{[]string{"cert_file", "certFile", "cert"}, &tlsCfg.CertFile},
+		{[]string{"key_file", "keyFile", "key"}, &tlsCfg.KeyFile},
+		{[]string{"ca_file", "caFile", "ca"}, &tlsCfg.CAFile},
+		{[]string{"server_name", "serverName"}, &tlsCfg.ServerName},
+		{[]string{"min_version", "minVersion"}, &tlsCfg.MinVersion},
+		{[]string{"max_version", "maxVersion"}, &tlsCfg.MaxVersion},
+	} {
+		if raw, ok := lookupSetting(settings, field.keys...); ok {
+			val, err := asString(raw)
+			if err != nil {
+				return TLSConfig{}, fmt.Errorf("%s: %w", field.keys[0], err)
+			}
+			*field.dst = strings.TrimSpace(val)
+		}
+	}
+	if raw, ok := lookupSetting(settings, "cipher_suites", "cipherSuites", "ciphers"); ok {
+		vals, err := asStringSlice(raw)
+		if err != nil {
+			return TLSConfig{}, fmt.Errorf("cipher_suites: %w", err)
+		}
+		tlsCfg.CipherSuites = vals
+	}
+	if raw, ok := lookupSetting(settings, "alpn"); ok {
+		vals, err := asStringSlice(raw)
+		if err != nil {
+			return TLSConfig{}, fmt.Errorf("alpn: %w", err)
+		}
+		tlsCfg.ALPN = vals
+	}
+	return tlsCfg, nil
+}
+
+func parseDNSConfig(value interface{}) (DNSConfig, error) {
+	if value == nil {
+		return DNSConfig{}, nil
+	}
+	settings, err := toStringKeyMap(value)
+	if err != nil {
+		return DNSConfig{}, err
+	}
+	var dns DNSConfig
+	if raw, ok := lookupSetting(settings, "resolve"); ok && raw != nil {
+		entries, err := toStringKeyMap(raw)
+		if err != nil {
+			return DNSConfig{}, fmt.Errorf("resolve: %w", err)
+		}
+		// The config reader splits keys at dots, so host names arrive as
+		// nested maps; join them back into host:port keys.
+		flat := map[string]interface{}{}
+		if err := flattenResolveKeys("", entries, flat); err != nil {
+			return DNSConfig{}, fmt.Errorf("resolve: %w", err)
+		}
+		dns.Resolve = make(map[string][]string, len(flat))
+		for key, raw := range flat {
+			addrs, err := asStringSlice(raw)
+			if err != nil {
+				return DNSConfig{}, fmt.Errorf("resolve: %s: %w", key, err)
+			}
+			dns.AddResolve(key, addrs...)
+		}
+	}
+	if raw, ok := lookupSetting(settings, "server"); ok {
+		val, err := asString(raw)
+		if err != nil {
+			return DNSConfig{}, fmt.Errorf("server: %w", err)
+		}
+		dns.Server = strings.TrimSpace(val)
+	}
+	if raw, ok := lookupSetting(settings, "round_robin", "roundRobin"); ok {
+		val, err := asBool(raw)
+		if err != nil {
+			return DNSConfig{}, fmt.Errorf("round_robin: %w", err)
+		}
+		dns.RoundRobin = val
+	}
+	return dns, nil
+}
+
+func flattenResolveKeys(prefix string, entries map[string]interface{}, out map[string]interface{}) error {
+	for key, raw := range entries {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		switch raw.(type) {
+		case map[string]interface{}, map[interface{}]interface{}:
+			nested, err := toStringKeyMap(raw)
+			if err != nil {
+				return err
+			}
+			if err := flattenResolveKeys(key, nested, out); err != nil {
+				return err
+			}
+		default:
+			out[key] = raw
+		}
+	}
+	return nil
+}
+
+func parseCookiesConfig(value interface{}) (CookiesConfig, error) {
+	if value == nil {
+		return CookiesConfig{}, nil
+	}
+	settings, err := toStringKeyMap(value)
+	if err != nil {
+		return CookiesConfig{}, err
+	}
+	var cookies CookiesConfig
+	if raw, ok := lookupSetting(settings, "jar"); ok {
+		val, err := asBool(raw)
+		if err != nil {
+			return CookiesConfig{}, fmt.Errorf("jar: %w", err)
+		}
+		cookies.Jar = val
+	}
+	if raw, ok := lookupSetting(settings, "seed"); ok && raw != nil {
+		items, err := toInterfaceSlice(raw)
+		if err != nil {
+			return CookiesConfig{}, fmt.Errorf("seed: %w", err)
+		}
+		for idx, item := range items {
+			entry, err := toStringKeyMap(item)
+			if err != nil {
+				return CookiesConfig{}, fmt.Errorf("seed: index %d: %w", idx, err)
+			}
+			cookie, err := buildCookie(entry)
+			if err != nil {
+				return CookiesConfig{}, fmt.Errorf("seed: index %d: %w", idx, err)
+			}
+			cookies.Seed = append(cookies.Seed, cookie)
+		}
+	}
+	if raw, ok := lookupSetting(settings, "extract"); ok && raw != nil {
+		items, err := toInterfaceSlice(raw)
+		if err != nil {
+			return CookiesConfig{}, fmt.Errorf("extract: %w", err)
+		}
+		for idx, item := range items {
+			entry, err := toStringKeyMap(item)
+			if err != nil {
+				return CookiesConfig{}, fmt.Errorf("extract: index %d: %w", idx, err)
+			}
+			var ex CookieExtract
+			if raw, ok := lookupSetting(entry, "cookie"); ok {
+				val, err := asString(raw)
+				if err != nil {
+					return CookiesConfig{}, fmt.Errorf("extract: index %d: cookie: %w", idx, err)
+				}
+				ex.Cookie = strings.TrimSpace(val)
+			}
+			if raw, ok := lookupSetting(entry, "var"); ok {
+				val, err := asString(raw)
+				if err != nil {
+					return CookiesConfig{}, fmt.Errorf("extract: index %d: var: %w", idx, err)
+				}
+				ex.Variable = strings.TrimSpace(val)
+			}
+			cookies.Extract = append(cookies.Extract, ex)
+		}
+	}
+	return cookies, nil
+}
+
+func buildCookie(settings map[string]interface{}) (Cookie, error) {
+	var cookie Cookie
+	for _, field := range []struct {
+		key string
+		dst *string
+	}{
+		{"name", &cookie.Name},
+		{"value", &cookie.Value},
+		{"domain", &cookie.Domain},
+		{"path", &cookie.Path},
+	} {
+		if raw, ok := lookupSetting(settings, field.key); ok {
+			val, err := asString(raw)
+			if err != nil {
+				return Cookie{}, fmt.Errorf("%s: %w", field.key, err)
+			}
+			*field.dst = strings.TrimSpace(val)
+		}
+	}
+	if raw, ok := lookupSetting(settings, "secure"); ok {
+		val, err := asBool(raw)
+		if err != nil {
+			return Cookie{}, fmt.Errorf("secure: %w", err)
+		}
+		cookie.Secure = val
+	}
+	return cookie, nil
+}
+
 // loadHAREndpoints validates that the HAR file exists and is readable JSON.
 // The actual HAR conversion to endpoints happens separately via LoadHAREndpointsFromConfig function
 // which is called from the cmd layer to avoid circular import issues.
@@ -234,6 +234,33 @@ func asStringSlice(value interface{}) ([]string, error) {
 	}
 }
 
+// asIntSlice converts an interface value to a []int.
+// Handles []int, []interface{}, and single numeric values.
+func asIntSlice(value interface{}) ([]int, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case []int:
+		return v, nil
+	case []interface{}:
+		result := make([]int, len(v))
+		for i, item := range v {
+			n, err := asInt(item)
+			if err != nil {
+				return nil, fmt.Errorf("index %d: %w", i, err)
+			}
+			result[i] = n
+		}
+		return result, nil
+	default:
+		n, err := asInt(value)
+		if err != nil {
+			return nil, err
+		}
+		return []int{n}, nil
+	}
+}
+
 // toInterfaceSlice converts various slice types to []interface{}.
 func toInterfaceSlice(value interface{}) ([]interface{}, error) {
 	switch v := value.(type) {
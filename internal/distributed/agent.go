@@ -0,0 +1,216 @@
+package distributed
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/torosent/crankfire/internal/config"
+	"github.com/torosent/crankfire/internal/metrics"
+	"github.com/torosent/crankfire/internal/runner"
+)
+
+// BuildFunc constructs the runner and collector for one agent's share of a
+// test. The returned cleanup func releases the runner's resources.
+type BuildFunc func(ctx context.Context, cfg config.Config) (*runner.Runner, *metrics.Collector, func(), error)
+
+type startRequest struct {
+	StartAt time.Time `json:"start_at"`
+}
+
+// agentReport is what an agent returns once its share of the test finished.
+type agentReport struct {
+	Total        int64                 `json:"total"`
+	Errors       int64                 `json:"errors"`
+	Duration     time.Duration         `json:"duration"`
+	Measurements *metrics.Measurements `json:"measurements,omitempty"`
+	Error        string                `json:"error,omitempty"`
+}
+
+// Agent runs one share of a distributed test at a time on behalf of a
+// coordinator. A run is prepared, started at a wall-clock time shared by all
+// agents, then collected:
+//
+//	POST /v1/prepare  config.Config (JSON)  build the runner
+//	POST /v1/start    {"start_at": time}    start the prepared run
+//	GET  /v1/result                         wait for and return the results
+//	POST /v1/stop                           cancel the current run
+type Agent struct {
+	build BuildFunc
+	mux   *http.ServeMux
+
+	mu  sync.Mutex
+	run *agentRun
+}
+
+type agentRun struct {
+	runner    *runner.Runner
+	collector *metrics.Collector
+	cleanup   func()
+	ctx       context.Context
+	cancel    context.CancelFunc
+	started   bool
+	done      chan struct{}
+	report    agentReport
+}
+
+// NewAgent returns an agent that builds runs with build.
+func NewAgent(build BuildFunc) *Agent {
+	a := &Agent{build: build, mux: http.NewServeMux()}
+	a.mux.HandleFunc("POST /v1/prepare", a.handlePrepare)
+	a.mux.HandleFunc("POST /v1/start", a.handleStart)
+	a.mux.HandleFunc("GET /v1/result", a.handleResult)
+	a.mux.HandleFunc("POST /v1/stop", a.handleStop)
+	return a
+}
+
+// ServeHTTP implements http.Handler.
+func (a *Agent) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mux.ServeHTTP(w, r)
+}
+
+// Close cancels the current run, if any, and releases a run that was
+// prepared but never started.
+func (a *Agent) Close() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.discardLocked()
+}
+
+// discardLocked cancels the current run. A started run releases its own
+// resources when it finishes.
+func (a *Agent) discardLocked() {
+	if a.run == nil {
+		return
+	}
+	a.run.cancel()
+	if !a.run.started {
+		a.run.cleanup()
+		a.run = nil
+	}
+}
+
+func (a *Agent) handlePrepare(w http.ResponseWriter, r *http.Request) {
+	var cfg config.Config
+	if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+		http.Error(w, fmt.Sprintf("decode config: %v", err), http.StatusBadRequest)
+		return
+	}
+	if err := cfg.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.run != nil && a.run.started && !isDone(a.run.done) {
+		http.Error(w, "agent is busy with another run", http.StatusConflict)
+		return
+	}
+	a.discardLocked()
+	a.run = nil
+
+	rn, collector, cleanup, err := a.build(r.Context(), cfg)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	a.run = &agentRun{
+		runner:    rn,
+		collector: collector,
+		cleanup:   cleanup,
+		ctx:       ctx,
+		cancel:    cancel,
+		done:      make(chan struct{}),
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *Agent) handleStart(w http.ResponseWriter, r *http.Request) {
+	var req startRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("decode start request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.run == nil {
+		http.Error(w, "no run prepared", http.StatusConflict)
+		return
+	}
+	if a.run.started {
+		http.Error(w, "run already started", http.StatusConflict)
+		return
+	}
+	a.run.started = true
+	go a.run.execute(req.StartAt)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *Agent) handleResult(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	run := a.run
+	a.mu.Unlock()
+	if run == nil || !run.started {
+		http.Error(w, "no run started", http.StatusConflict)
+		return
+	}
+
+	select {
+	case <-run.done:
+	case <-r.Context().Done():
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(run.report)
+}
+
+func (a *Agent) handleStop(w http.ResponseWriter, r *http.Request) {
+	a.Close()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// execute waits for the shared start time, runs the test and records the
+// report.
+func (run *agentRun) execute(startAt time.Time) {
+	defer close(run.done)
+	defer run.cleanup()
+
+	if wait := time.Until(startAt); wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-run.ctx.Done():
+			timer.Stop()
+			run.report.Error = "run cancelled before start"
+			return
+		}
+	}
+
+	run.collector.Start()
+	result := run.runner.Run(run.ctx)
+	run.report.Total = result.Total
+	run.report.Errors = result.Errors
+	run.report.Duration = result.Duration
+
+	measurements, err := run.collector.Measurements()
+	if err != nil {
+		run.report.Error = fmt.Sprintf("export measurements: %v", err)
+		return
+	}
+	run.report.Measurements = measurements
+}
+
+func isDone(done <-chan struct{}) bool {
+	select {
+	case <-done:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,180 @@
+package distributed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/torosent/crankfire/internal/config"
+	"github.com/torosent/crankfire/internal/metrics"
+	"github.com/torosent/crankfire/internal/runner"
+)
+
+// defaultStartDelay is how far ahead of time the coordinator schedules the
+// shared start, leaving room for the start requests to reach every agent.
+const defaultStartDelay = time.Second
+
+// Coordinator splits a test across agents, starts them together and merges
+// their measurements.
+type Coordinator struct {
+	Agents     []string      // agent addresses: host:port or http(s) URLs
+	Client     *http.Client  // defaults to a client without timeout
+	StartDelay time.Duration // lead time before the shared start (default 1s)
+}
+
+// Run executes cfg across all agents and returns the combined result and a
+// collector holding the merged measurements. Cancelling ctx stops every
+// agent; the measurements taken until then are still merged.
+func (c *Coordinator) Run(ctx context.Context, cfg config.Config) (runner.Result, *metrics.Collector, error) {
+	if len(c.Agents) == 0 {
+		return runner.Result{}, nil, fmt.Errorf("no agents configured")
+	}
+	parts := make([]config.Config, len(c.Agents))
+	for i := range c.Agents {
+		part, err := Partition(cfg, i, len(c.Agents))
+		if err != nil {
+			return runner.Result{}, nil, err
+		}
+		parts[i] = part
+	}
+
+	if err := c.each(func(i int, agent string) error {
+		return c.call(ctx, agent, http.MethodPost, "/v1/prepare", parts[i], nil)
+	}); err != nil {
+		c.stopAll()
+		return runner.Result{}, nil, fmt.Errorf("prepare: %w", err)
+	}
+
+	delay := c.StartDelay
+	if delay <= 0 {
+		delay = defaultStartDelay
+	}
+	start := startRequest{StartAt: time.Now().Add(delay)}
+	if err := c.each(func(i int, agent string) error {
+		return c.call(ctx, agent, http.MethodPost, "/v1/start", start, nil)
+	}); err != nil {
+		c.stopAll()
+		return runner.Result{}, nil, fmt.Errorf("start: %w", err)
+	}
+
+	// Stop every agent on cancellation but keep waiting for their reports,
+	// which then cover the partial run.
+	finished := make(chan struct{})
+	defer close(finished)
+	go func() {
+		select {
+		case <-ctx.Done():
+			c.stopAll()
+		case <-finished:
+		}
+	}()
+
+	reports := make([]agentReport, len(c.Agents))
+	err := c.each(func(i int, agent string) error {
+		if err := c.call(context.Background(), agent, http.MethodGet, "/v1/result", nil, &reports[i]); err != nil {
+			return err
+		}
+		if reports[i].Error != "" {
+			return fmt.Errorf("agent %s: %s", agent, reports[i].Error)
+		}
+		return nil
+	})
+	if err != nil {
+		return runner.Result{}, nil, fmt.Errorf("result: %w", err)
+	}
+
+	collector := metrics.NewCollector()
+	var result runner.Result
+	for i, report := range reports {
+		if err := collector.Merge(report.Measurements); err != nil {
+			return runner.Result{}, nil, fmt.Errorf("agent %s: merge: %w", c.Agents[i], err)
+		}
+		result.Total += report.Total
+		result.Errors += report.Errors
+		if report.Duration > result.Duration {
+			result.Duration = report.Duration
+		}
+	}
+	return result, collector, nil
+}
+
+// each calls fn for every agent concurrently and joins the errors.
+func (c *Coordinator) each(fn func(i int, agent string) error) error {
+	errs := make([]error, len(c.Agents))
+	var wg sync.WaitGroup
+	for i, agent := range c.Agents {
+		wg.Add(1)
+		go func(i int, agent string) {
+			defer wg.Done()
+			errs[i] = fn(i, agent)
+		}(i, agent)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// stopAll asks every agent to cancel its run, ignoring failures.
+func (c *Coordinator) stopAll() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = c.each(func(i int, agent string) error {
+		return c.call(ctx, agent, http.MethodPost, "/v1/stop", nil, nil)
+	})
+}
+
+// call sends a JSON request to an agent and decodes the JSON response into
+// out when it is non-nil.
+func (c *Coordinator) call(ctx context.Context, agent, method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("agent %s: encode request: %w", agent, err)
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, agentURL(agent)+path, body)
+	if err != nil {
+		return fmt.Errorf("agent %s: %w", agent, err)
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("agent %s: %w", agent, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("agent %s: %s: %s", agent, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("agent %s: decode response: %w", agent, err)
+		}
+	}
+	return nil
+}
+
+// agentURL turns an agent address into a base URL.
+func agentURL(agent string) string {
+	agent = strings.TrimRight(strings.TrimSpace(agent), "/")
+	if strings.Contains(agent, "://") {
+		return agent
+	}
+	return "http://" + agent
+}
@@ -0,0 +1,162 @@
+package distributed
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/torosent/crankfire/internal/config"
+	"github.com/torosent/crankfire/internal/metrics"
+	"github.com/torosent/crankfire/internal/runner"
+)
+
+// recordingRequester records fixed-latency requests, failing every fifth.
+type recordingRequester struct {
+	collector *metrics.Collector
+	latency   time.Duration
+	mu        sync.Mutex
+	calls     int
+	first     time.Time
+}
+
+func (r *recordingRequester) Do(ctx context.Context) error {
+	r.mu.Lock()
+	r.calls++
+	n := r.calls
+	if r.first.IsZero() {
+		r.first = time.Now()
+	}
+	r.mu.Unlock()
+
+	select {
+	case <-time.After(r.latency):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	var err error
+	meta := &metrics.RequestMetadata{Endpoint: "users", Protocol: "http"}
+	if n%5 == 0 {
+		err = errors.New("server error")
+		meta.StatusCode = "500"
+	}
+	r.collector.RecordRequest(r.latency, err, meta)
+	return err
+}
+
+type fakeAgent struct {
+	mu         sync.Mutex
+	configs    []config.Config
+	requesters []*recordingRequester
+	buildErr   error
+}
+
+func (f *fakeAgent) build(ctx context.Context, cfg config.Config) (*runner.Runner, *metrics.Collector, func(), error) {
+	if f.buildErr != nil {
+		return nil, nil, nil, f.buildErr
+	}
+	collector := metrics.NewCollector()
+	req := &recordingRequester{collector: collector, latency: 2 * time.Millisecond}
+	f.mu.Lock()
+	f.configs = append(f.configs, cfg)
+	f.requesters = append(f.requesters, req)
+	f.mu.Unlock()
+	r := runner.New(runner.Options{
+		Concurrency:   cfg.Concurrency,
+		TotalRequests: cfg.Total,
+		Duration:      cfg.Duration,
+		RatePerSecond: cfg.Rate,
+		Requester:     req,
+	})
+	return r, collector, func() {}, nil
+}
+
+func startAgents(t *testing.T, fakes ...*fakeAgent) []string {
+	t.Helper()
+	addrs := make([]string, len(fakes))
+	for i, fake := range fakes {
+		agent := NewAgent(fake.build)
+		srv := httptest.NewServer(agent)
+		t.Cleanup(func() {
+			agent.Close()
+			srv.Close()
+		})
+		addrs[i] = srv.URL
+	}
+	return addrs
+}
+
+func TestCoordinatorMergesAgentResults(t *testing.T) {
+	a, b := &fakeAgent{}, &fakeAgent{}
+	coord := &Coordinator{Agents: startAgents(t, a, b), StartDelay: 200 * time.Millisecond}
+
+	cfg := config.Config{TargetURL: "http://example.com", Concurrency: 4, Total: 21}
+	result, collector, err := coord.Run(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if a.configs[0].Total != 11 || b.configs[0].Total != 10 || a.configs[0].Concurrency != 2 {
+		t.Fatalf("unexpected partitions: %+v / %+v", a.configs[0], b.configs[0])
+	}
+	if result.Total != 21 || result.Errors != 4 {
+		t.Fatalf("result = %+v, want 21 total and 4 errors", result)
+	}
+
+	stats := collector.Stats(result.Duration)
+	if stats.Total != 21 || stats.Failures != 4 || stats.StatusBuckets["http"]["500"] != 4 {
+		t.Fatalf("merged stats = total %d failures %d buckets %v", stats.Total, stats.Failures, stats.StatusBuckets)
+	}
+	if stats.Endpoints["users"].Total != 21 {
+		t.Fatalf("merged endpoint stats = %+v", stats.Endpoints["users"])
+	}
+	if stats.P99Latency < time.Millisecond {
+		t.Fatalf("expected merged histogram, got p99 %s", stats.P99Latency)
+	}
+
+	skew := a.requesters[0].first.Sub(b.requesters[0].first)
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > 50*time.Millisecond {
+		t.Fatalf("agents started %s apart", skew)
+	}
+}
+
+func TestCoordinatorStopsAgentsOnCancel(t *testing.T) {
+	a, b := &fakeAgent{}, &fakeAgent{}
+	coord := &Coordinator{Agents: startAgents(t, a, b), StartDelay: 50 * time.Millisecond}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	begin := time.Now()
+	cfg := config.Config{TargetURL: "http://example.com", Concurrency: 2, Duration: time.Minute}
+	result, _, err := coord.Run(ctx, cfg)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if elapsed := time.Since(begin); elapsed > 10*time.Second {
+		t.Fatalf("expected cancellation to stop the agents, run took %s", elapsed)
+	}
+	if result.Total == 0 {
+		t.Fatal("expected partial results from the cancelled run")
+	}
+}
+
+func TestCoordinatorReportsPrepareFailure(t *testing.T) {
+	ok, broken := &fakeAgent{}, &fakeAgent{buildErr: errors.New("feeder: partition 1 of 2 is empty")}
+	coord := &Coordinator{Agents: startAgents(t, ok, broken)}
+
+	_, _, err := coord.Run(context.Background(), config.Config{TargetURL: "http://example.com", Total: 10})
+	if err == nil || !strings.Contains(err.Error(), "partition 1 of 2 is empty") {
+		t.Fatalf("expected prepare failure, got %v", err)
+	}
+
+	// The healthy agent's prepared run was released, so it accepts a new one.
+	if _, _, err := (&Coordinator{Agents: coord.Agents[:1], StartDelay: 10 * time.Millisecond}).Run(context.Background(), config.Config{TargetURL: "http://example.com", Total: 2}); err != nil {
+		t.Fatalf("expected the healthy agent to be reusable, got %v", err)
+	}
+}
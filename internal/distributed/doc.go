@@ -0,0 +1,18 @@
+// Package distributed runs one load test across several crankfire processes.
+//
+// An [Agent] serves a small HTTP/JSON API (see `crankfire agent`) and runs
+// one share of a test at a time. A [Coordinator] splits a config.Config with
+// [Partition], dividing rate, total, concurrency and load pattern rates among
+// the agents and giving each agent a disjoint partition of the feeder's
+// records. It prepares every agent, starts them at a shared wall-clock time,
+// then merges their raw HDR histograms, status buckets, checks and protocol
+// metrics into one metrics.Collector, so reports and thresholds see a single
+// run.
+//
+//	coord := &distributed.Coordinator{Agents: []string{"10.0.0.1:7070", "10.0.0.2:7070"}}
+//	result, collector, err := coord.Run(ctx, cfg)
+//	stats := collector.Stats(result.Duration)
+//
+// Agents read body files, feeder files and proto files from their own file
+// system, and the shared start time assumes their clocks are synchronized.
+package distributed
@@ -0,0 +1,98 @@
+package distributed
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/torosent/crankfire/internal/config"
+)
+
+// Partition returns the share of cfg that agent index of count runs. Rate,
+// total, concurrency and load pattern rates are divided as evenly as
+// possible, and a configured feeder is limited to the agent's records.
+// Settings that only make sense on the coordinator (output, thresholds,
+// dashboard, HAR import) are cleared.
+func Partition(cfg config.Config, index, count int) (config.Config, error) {
+	if count < 1 {
+		return config.Config{}, fmt.Errorf("agent count must be >= 1, got %d", count)
+	}
+	if index < 0 || index >= count {
+		return config.Config{}, fmt.Errorf("agent index %d out of range [0, %d)", index, count)
+	}
+
+	part := cfg
+	part.Agents = nil
+	part.Dashboard = false
+	part.JSONOutput = false
+	part.HTMLOutput = ""
+	part.Thresholds = nil
+	part.ConfigFile = ""
+	// HAR entries were already expanded into endpoints by the coordinator.
+	part.HARFile = ""
+	part.HARFilter = ""
+
+	var err error
+	if part.Rate, err = split("rate", cfg.Rate, index, count); err != nil {
+		return config.Config{}, err
+	}
+	if part.Total, err = split("total", cfg.Total, index, count); err != nil {
+		return config.Config{}, err
+	}
+	part.Concurrency = share(cfg.Concurrency, index, count)
+	if part.Concurrency < 1 {
+		part.Concurrency = 1
+	}
+
+	if len(cfg.LoadPatterns) > 0 {
+		part.LoadPatterns = make([]config.LoadPattern, len(cfg.LoadPatterns))
+		for i, pattern := range cfg.LoadPatterns {
+			prefix := fmt.Sprintf("load_patterns[%d]", i)
+			if pattern.RPS, err = split(prefix+".rps", pattern.RPS, index, count); err != nil {
+				return config.Config{}, err
+			}
+			if pattern.FromRPS, err = split(prefix+".from_rps", pattern.FromRPS, index, count); err != nil {
+				return config.Config{}, err
+			}
+			if pattern.ToRPS, err = split(prefix+".to_rps", pattern.ToRPS, index, count); err != nil {
+				return config.Config{}, err
+			}
+			if len(pattern.Steps) > 0 {
+				steps := make([]config.LoadStep, len(pattern.Steps))
+				for j, step := range pattern.Steps {
+					if step.RPS, err = split(fmt.Sprintf("%s.steps[%d].rps", prefix, j), step.RPS, index, count); err != nil {
+						return config.Config{}, err
+					}
+					steps[j] = step
+				}
+				pattern.Steps = steps
+			}
+			part.LoadPatterns[i] = pattern
+		}
+	}
+
+	if strings.TrimSpace(cfg.Feeder.Path) != "" && count > 1 {
+		part.Feeder.Partition = index
+		part.Feeder.Partitions = count
+	}
+
+	return part, nil
+}
+
+// split divides a limit across agents. A non-zero limit must leave every
+// agent a non-zero share, since zero means "unlimited" for rates and totals.
+func split(name string, value, index, count int) (int, error) {
+	if value > 0 && value < count {
+		return 0, fmt.Errorf("%s %d cannot be split across %d agents", name, value, count)
+	}
+	return share(value, index, count), nil
+}
+
+// share returns agent index's part of value, giving the remainder to the
+// first agents.
+func share(value, index, count int) int {
+	n := value / count
+	if index < value%count {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,77 @@
+package distributed
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/torosent/crankfire/internal/config"
+)
+
+func TestPartitionSplitsLimits(t *testing.T) {
+	cfg := config.Config{
+		TargetURL:   "http://example.com",
+		Concurrency: 5,
+		Rate:        100,
+		Total:       1001,
+		Duration:    time.Minute,
+		Dashboard:   true,
+		HTMLOutput:  "report.html",
+		Thresholds:  []string{"http_req_failed:rate < 0.01"},
+		Agents:      []string{"a:1", "b:1", "c:1"},
+		Feeder:      config.FeederConfig{Path: "users.csv", Type: "csv"},
+		LoadPatterns: []config.LoadPattern{
+			{Type: config.LoadPatternTypeRamp, FromRPS: 0, ToRPS: 30, Duration: time.Minute},
+			{Type: config.LoadPatternTypeStep, Steps: []config.LoadStep{{RPS: 10, Duration: time.Second}}},
+		},
+	}
+
+	var rate, total, concurrency int
+	for i := 0; i < 3; i++ {
+		part, err := Partition(cfg, i, 3)
+		if err != nil {
+			t.Fatalf("Partition(%d) error = %v", i, err)
+		}
+		rate += part.Rate
+		total += part.Total
+		concurrency += part.Concurrency
+		if part.Duration != time.Minute {
+			t.Errorf("agent %d duration = %s, want the full duration", i, part.Duration)
+		}
+		if part.Dashboard || part.HTMLOutput != "" || len(part.Thresholds) != 0 || len(part.Agents) != 0 {
+			t.Errorf("agent %d kept coordinator-only settings: %+v", i, part)
+		}
+		if part.Feeder.Partition != i || part.Feeder.Partitions != 3 {
+			t.Errorf("agent %d feeder partition = %d/%d", i, part.Feeder.Partition, part.Feeder.Partitions)
+		}
+		if got := part.LoadPatterns[0].ToRPS; got != 10 {
+			t.Errorf("agent %d ramp to_rps = %d, want 10", i, got)
+		}
+	}
+	if rate != 100 || total != 1001 || concurrency != 5 {
+		t.Fatalf("shares do not add up: rate=%d total=%d concurrency=%d", rate, total, concurrency)
+	}
+	if cfg.LoadPatterns[0].ToRPS != 30 || cfg.LoadPatterns[1].Steps[0].RPS != 10 {
+		t.Fatal("Partition modified the original load patterns")
+	}
+}
+
+func TestPartitionRejectsLimitsBelowAgentCount(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.Config
+		want string
+	}{
+		{"rate", config.Config{Rate: 2}, "rate 2 cannot be split across 3 agents"},
+		{"total", config.Config{Total: 1}, "total 1 cannot be split across 3 agents"},
+		{"pattern", config.Config{LoadPatterns: []config.LoadPattern{{Type: config.LoadPatternTypeConstant, RPS: 2}}}, "load_patterns[0].rps"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Partition(tt.cfg, 0, 3)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Partition() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -6,6 +6,19 @@ import (
 
 // findJSONPath extracts a value from JSON using gjson with support for $.field and field syntax.
 func findJSONPath(body []byte, path string, logger Logger) string {
+	value, ok := LookupJSONPath(body, path)
+	if !ok {
+		if logger != nil {
+			logger.Warn("JSONPath not found: %s", path)
+		}
+		return ""
+	}
+	return value
+}
+
+// LookupJSONPath evaluates path against body and reports whether it matched,
+// so callers can tell a missing field apart from an empty value.
+func LookupJSONPath(body []byte, path string) (string, bool) {
 	// Strip leading $. if present, or handle bare $ to return entire JSON
 	if len(path) > 0 && path[0] == '$' {
 		if len(path) > 1 && path[1] == '.' {
@@ -17,13 +30,8 @@ func findJSONPath(body []byte, path string, logger Logger) string {
 	}
 
 	result := gjson.GetBytes(body, path)
-
 	if !result.Exists() {
-		if logger != nil {
-			logger.Warn("JSONPath not found: %s", path)
-		}
-		return ""
+		return "", false
 	}
-
-	return result.String()
+	return result.String(), true
 }
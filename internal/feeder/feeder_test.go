@@ -296,3 +296,48 @@ func TestFeederContextCancellation(t *testing.T) {
 		t.Errorf("Next() with cancelled context error = %v, want context.Canceled", err)
 	}
 }
+
+func TestPartitionedFeederSplitsRecords(t *testing.T) {
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "users.json")
+	content := `[{"id":"0"},{"id":"1"},{"id":"2"},{"id":"3"},{"id":"4"}]`
+	if err := os.WriteFile(jsonPath, []byte(content), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	ctx := context.Background()
+	want := map[int]string{0: "0,2,4,0", 1: "1,3,1,3"}
+	for index, expected := range want {
+		inner, err := NewJSONFeeder(jsonPath)
+		if err != nil {
+			t.Fatalf("NewJSONFeeder() error = %v", err)
+		}
+		feeder, err := NewPartitionedFeeder(inner, index, 2)
+		if err != nil {
+			t.Fatalf("NewPartitionedFeeder() error = %v", err)
+		}
+		var got []string
+		for i := 0; i < 4; i++ {
+			rec, err := feeder.Next(ctx)
+			if err != nil {
+				t.Fatalf("Next() error = %v", err)
+			}
+			got = append(got, rec["id"])
+		}
+		if strings.Join(got, ",") != expected {
+			t.Errorf("partition %d = %v, want %s", index, got, expected)
+		}
+		if wantLen := 3 - index; feeder.Len() != wantLen {
+			t.Errorf("partition %d Len() = %d, want %d", index, feeder.Len(), wantLen)
+		}
+		feeder.Close()
+	}
+
+	inner, err := NewJSONFeeder(jsonPath)
+	if err != nil {
+		t.Fatalf("NewJSONFeeder() error = %v", err)
+	}
+	if _, err := NewPartitionedFeeder(inner, 5, 6); err == nil {
+		t.Error("expected an error for an empty partition")
+	}
+}
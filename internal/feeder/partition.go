@@ -0,0 +1,67 @@
+package feeder
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// PartitionedFeeder exposes every count-th record of another feeder,
+// starting at index, so several processes reading the same file use disjoint
+// records. It is safe for concurrent access.
+type PartitionedFeeder struct {
+	inner    Feeder
+	index    int
+	count    int
+	position int // position of the next inner record within a pass
+	mu       sync.Mutex
+}
+
+// NewPartitionedFeeder wraps inner so that it only returns records whose
+// position modulo count equals index. The wrapper takes ownership of inner.
+func NewPartitionedFeeder(inner Feeder, index, count int) (*PartitionedFeeder, error) {
+	if count < 1 {
+		return nil, fmt.Errorf("partition count must be >= 1, got %d", count)
+	}
+	if index < 0 || index >= count {
+		return nil, fmt.Errorf("partition index %d out of range [0, %d)", index, count)
+	}
+	if index >= inner.Len() {
+		return nil, fmt.Errorf("partition %d of %d is empty: dataset has %d records", index, count, inner.Len())
+	}
+	return &PartitionedFeeder{inner: inner, index: index, count: count}, nil
+}
+
+// Next returns the next record of this partition in round-robin order.
+func (f *PartitionedFeeder) Next(ctx context.Context) (Record, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	total := f.inner.Len()
+	for {
+		record, err := f.inner.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		pos := f.position
+		f.position = (f.position + 1) % total
+		if pos%f.count == f.index {
+			return record, nil
+		}
+	}
+}
+
+// Close releases the wrapped feeder.
+func (f *PartitionedFeeder) Close() error {
+	return f.inner.Close()
+}
+
+// Len returns the number of records in this partition.
+func (f *PartitionedFeeder) Len() int {
+	total := f.inner.Len()
+	n := total / f.count
+	if f.index < total%f.count {
+		n++
+	}
+	return n
+}
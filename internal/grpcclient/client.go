@@ -3,12 +3,18 @@ package grpcclient
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
+	"io"
+	"net"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/torosent/crankfire/internal/clientmetrics"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
@@ -39,6 +45,8 @@ type Client struct {
 	callCount  int64 // Track calls separately for gRPC-specific logic
 	useTLS     bool
 	insecure   bool
+	tlsConfig  *tls.Config
+	dial       func(context.Context, string) (net.Conn, error)
 	lastStatus string
 }
 
@@ -51,6 +59,15 @@ type Config struct {
 	Timeout  time.Duration
 	UseTLS   bool
 	Insecure bool
+	// TLSConfig enables TLS with these settings, whatever UseTLS says.
+	// Insecure still skips verification.
+	TLSConfig *tls.Config
+	// OnHandshake, when set, receives the duration and state of every TLS
+	// handshake on connections made by Dial.
+	OnHandshake func(time.Duration, tls.ConnectionState)
+	// DialContext opens the TCP connection to a host:port. Targets without
+	// a scheme then reach it unresolved, so overrides see the host name.
+	DialContext func(ctx context.Context, addr string) (net.Conn, error)
 }
 
 // NewClient creates a new gRPC client with the given configuration
@@ -66,6 +83,8 @@ func NewClient(cfg Config) (*Client, error) {
 		md:         md,
 		useTLS:     cfg.UseTLS,
 		insecure:   cfg.Insecure,
+		tlsConfig:  cfg.TLSConfig,
+		dial:       cfg.DialContext,
 		lastStatus: "UNSET",
 		metrics:    clientmetrics.New(),
 	}
@@ -81,10 +100,12 @@ func (c *Client) Connect(ctx context.Context) error {
 	}
 
 	conn, err := Dial(ctx, Config{
-		Target:   c.target,
-		UseTLS:   c.useTLS,
-		Insecure: c.insecure,
-		Timeout:  30 * time.Second, // Default, though Dial context governs timeout
+		Target:      c.target,
+		UseTLS:      c.useTLS,
+		Insecure:    c.insecure,
+		TLSConfig:   c.tlsConfig,
+		DialContext: c.dial,
+		Timeout:     30 * time.Second, // Default, though Dial context governs timeout
 	})
 	if err != nil {
 		return err
@@ -96,22 +117,60 @@ func (c *Client) Connect(ctx context.Context) error {
 // Dial establishes a gRPC connection based on configuration
 func Dial(ctx context.Context, cfg Config) (*grpc.ClientConn, error) {
 	var opts []grpc.DialOption
-	if cfg.UseTLS {
-		if cfg.Insecure {
-			// Use TLS but skip certificate verification
-			creds := credentials.NewTLS(&tls.Config{InsecureSkipVerify: true})
-			opts = append(opts, grpc.WithTransportCredentials(creds))
-		} else {
-			// Use TLS with proper certificate verification
-			creds := credentials.NewClientTLSFromCert(nil, "")
-			opts = append(opts, grpc.WithTransportCredentials(creds))
-		}
-	} else {
+	var creds credentials.TransportCredentials
+	switch {
+	case cfg.TLSConfig != nil:
+		tlsCfg := cfg.TLSConfig.Clone()
+		tlsCfg.InsecureSkipVerify = cfg.Insecure
+		creds = credentials.NewTLS(tlsCfg)
+	case cfg.UseTLS && cfg.Insecure:
+		// Use TLS but skip certificate verification
+		creds = credentials.NewTLS(&tls.Config{InsecureSkipVerify: true})
+	case cfg.UseTLS:
+		// Use TLS with proper certificate verification
+		creds = credentials.NewClientTLSFromCert(nil, "")
+	}
+	if creds == nil {
 		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	} else {
+		if cfg.OnHandshake != nil {
+			creds = &handshakeTimer{TransportCredentials: creds, onHandshake: cfg.OnHandshake}
+		}
+		opts = append(opts, grpc.WithTransportCredentials(creds))
+	}
+
+	target := cfg.Target
+	if cfg.DialContext != nil {
+		opts = append(opts, grpc.WithContextDialer(cfg.DialContext))
+		if !strings.Contains(target, "://") {
+			target = "passthrough:///" + target
+		}
 	}
 
 	// grpc.NewClient is non-blocking and doesn't take a context for dialing itself
-	return grpc.NewClient(cfg.Target, opts...)
+	return grpc.NewClient(target, opts...)
+}
+
+// handshakeTimer reports the duration and state of every client TLS
+// handshake made through the wrapped credentials.
+type handshakeTimer struct {
+	credentials.TransportCredentials
+	onHandshake func(time.Duration, tls.ConnectionState)
+}
+
+func (h *handshakeTimer) ClientHandshake(ctx context.Context, authority string, rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	start := time.Now()
+	conn, info, err := h.TransportCredentials.ClientHandshake(ctx, authority, rawConn)
+	if err == nil {
+		if tlsInfo, ok := info.(credentials.TLSInfo); ok {
+			h.onHandshake(time.Since(start), tlsInfo.State)
+		}
+	}
+	return conn, info, err
+}
+
+func (h *handshakeTimer) Clone() credentials.TransportCredentials {
+	return &handshakeTimer{TransportCredentials: h.TransportCredentials.Clone(), onHandshake: h.onHandshake}
 }
 
 // NewClientWithConn creates a new gRPC client using an existing connection
@@ -128,6 +187,7 @@ func NewClientWithConn(conn *grpc.ClientConn, cfg Config) *Client {
 		md:         md,
 		useTLS:     cfg.UseTLS,
 		insecure:   cfg.Insecure,
+		tlsConfig:  cfg.TLSConfig,
 		lastStatus: "UNSET",
 		metrics:    clientmetrics.New(),
 	}
@@ -191,6 +251,162 @@ func (c *Client) Invoke(ctx context.Context, req proto.Message, resp proto.Messa
 	return nil
 }
 
+// StreamOptions describes how a streaming call is driven.
+type StreamOptions struct {
+	ClientStreams   bool          // Method accepts a stream of requests
+	ServerStreams   bool          // Method returns a stream of responses
+	Interval        time.Duration // Pause between consecutive sends
+	ExpectResponses int           // Stop after this many responses (0 = read until EOF)
+}
+
+// StreamResult summarizes a single streaming call.
+type StreamResult struct {
+	MessagesSent       int64
+	MessagesRecv       int64
+	BytesSent          int64
+	BytesRecv          int64
+	TimeToFirstMessage time.Duration
+	StatusCode         string
+}
+
+// ErrStreamIncomplete is returned when a stream ends before the expected
+// number of responses has been received.
+var ErrStreamIncomplete = errors.New("stream ended before expected responses")
+
+// Stream opens a client, server or bidirectional stream, sends reqs paced by
+// opts.Interval, and receives responses built by newResp until the server
+// closes the stream or opts.ExpectResponses messages have arrived. Sending and
+// receiving run concurrently so bidirectional methods interleave naturally.
+func (c *Client) Stream(ctx context.Context, reqs []proto.Message, newResp func() proto.Message, opts StreamOptions) (StreamResult, error) {
+	var result StreamResult
+	if newResp == nil {
+		return result, fmt.Errorf("response factory cannot be nil")
+	}
+
+	c.mu.Lock()
+	if c.conn == nil {
+		c.mu.Unlock()
+		return result, fmt.Errorf("client not connected")
+	}
+	conn := c.conn
+	c.mu.Unlock()
+
+	if len(c.md) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, c.md)
+	}
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	desc := &grpc.StreamDesc{
+		StreamName:    c.method,
+		ClientStreams: opts.ClientStreams,
+		ServerStreams: opts.ServerStreams,
+	}
+	fullMethod := fmt.Sprintf("/%s/%s", c.service, c.method)
+	start := time.Now()
+	stream, err := conn.NewStream(streamCtx, desc, fullMethod)
+	if err != nil {
+		c.finishStream(&result, err)
+		return result, fmt.Errorf("open stream: %w", err)
+	}
+
+	var sent, sentBytes int64
+	sendDone := make(chan error, 1)
+	go func() {
+		err := c.sendStream(streamCtx, stream, reqs, opts.Interval, &sent, &sentBytes)
+		if err != nil && !errors.Is(err, io.EOF) {
+			// A failed send leaves the receiver blocked until the deadline.
+			cancel()
+		}
+		sendDone <- err
+	}()
+
+	var recvErr error
+	for {
+		resp := newResp()
+		if err := stream.RecvMsg(resp); err != nil {
+			if !errors.Is(err, io.EOF) {
+				recvErr = err
+			}
+			break
+		}
+		size := int64(proto.Size(resp))
+		result.MessagesRecv++
+		result.BytesRecv += size
+		c.metrics.IncrementReceived(size)
+		if result.MessagesRecv == 1 {
+			result.TimeToFirstMessage = time.Since(start)
+		}
+		if opts.ExpectResponses > 0 && result.MessagesRecv >= int64(opts.ExpectResponses) {
+			break
+		}
+	}
+
+	// The receive side decides the outcome; stop any pending sends so an
+	// early exit (expected responses reached) does not wait for the server.
+	cancel()
+	sendErr := <-sendDone
+	result.MessagesSent = atomic.LoadInt64(&sent)
+	result.BytesSent = atomic.LoadInt64(&sentBytes)
+
+	switch {
+	case recvErr != nil:
+		err = recvErr
+		if sendErr != nil && !errors.Is(sendErr, io.EOF) && status.Code(recvErr) == codes.Canceled && ctx.Err() == nil {
+			err = sendErr
+		}
+	case opts.ExpectResponses > 0 && result.MessagesRecv < int64(opts.ExpectResponses):
+		err = fmt.Errorf("%w: got %d of %d", ErrStreamIncomplete, result.MessagesRecv, opts.ExpectResponses)
+	}
+	c.finishStream(&result, err)
+	if err != nil {
+		return result, fmt.Errorf("stream failed: %w", err)
+	}
+	return result, nil
+}
+
+// sendStream writes reqs to stream and half-closes it. An io.EOF from SendMsg
+// means the server already ended the stream; the real status surfaces on the
+// receive side.
+func (c *Client) sendStream(ctx context.Context, stream grpc.ClientStream, reqs []proto.Message, interval time.Duration, sent, sentBytes *int64) error {
+	for i, req := range reqs {
+		if i > 0 && interval > 0 {
+			timer := time.NewTimer(interval)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			}
+		}
+		if err := stream.SendMsg(req); err != nil {
+			return err
+		}
+		size := int64(proto.Size(req))
+		atomic.AddInt64(sent, 1)
+		atomic.AddInt64(sentBytes, size)
+		c.metrics.IncrementSent(size)
+	}
+	return stream.CloseSend()
+}
+
+func (c *Client) finishStream(result *StreamResult, err error) {
+	code := status.Code(err).String()
+	if errors.Is(err, ErrStreamIncomplete) {
+		code = "INCOMPLETE"
+	}
+	result.StatusCode = code
+
+	c.mu.Lock()
+	c.callCount++
+	c.lastStatus = code
+	c.mu.Unlock()
+
+	if err != nil {
+		c.metrics.IncrementErrors()
+	}
+}
+
 // Close closes the gRPC connection
 func (c *Client) Close() error {
 	c.mu.Lock()
@@ -3,6 +3,8 @@ package grpcclient
 import (
 	"context"
 	"crypto/tls"
+	"net"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -285,3 +287,33 @@ func TestClient_NilMetadata(t *testing.T) {
 		t.Error("Expected non-nil metadata map even when config has nil metadata")
 	}
 }
+
+// TestHandshakeTimer_ReportsTLSState verifies that wrapped credentials report
+// every completed client handshake.
+func TestHandshakeTimer_ReportsTLSState(t *testing.T) {
+	srv := httptest.NewTLSServer(nil)
+	defer srv.Close()
+
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+	go func() {
+		conn := tls.Server(serverConn, &tls.Config{Certificates: srv.TLS.Certificates, NextProtos: []string{"h2"}})
+		_ = conn.Handshake()
+	}()
+
+	var reported []uint16
+	creds := &handshakeTimer{
+		TransportCredentials: credentials.NewTLS(&tls.Config{InsecureSkipVerify: true}),
+		onHandshake: func(d time.Duration, state tls.ConnectionState) {
+			reported = append(reported, state.Version)
+		},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, _, err := creds.Clone().ClientHandshake(ctx, "example.com", clientConn); err != nil {
+		t.Fatalf("ClientHandshake() error = %v", err)
+	}
+	if len(reported) != 1 || reported[0] != tls.VersionTLS13 {
+		t.Fatalf("reported versions = %v, want one TLS 1.3 handshake", reported)
+	}
+}
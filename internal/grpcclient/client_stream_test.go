@@ -0,0 +1,200 @@
+package grpcclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// startStreamServer serves every method with handler and returns a connected client.
+func startStreamServer(t *testing.T, handler grpc.StreamHandler) *Client {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	srv := grpc.NewServer(grpc.UnknownServiceHandler(handler))
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	client, err := NewClient(Config{Target: lis.Addr().String(), Service: "test.Stream", Method: "Chat"})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if err := client.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// echoHandler replies to each request with `repeat` copies of it.
+func echoHandler(repeat int) grpc.StreamHandler {
+	return func(_ interface{}, stream grpc.ServerStream) error {
+		for {
+			req := &wrapperspb.StringValue{}
+			if err := stream.RecvMsg(req); err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				return err
+			}
+			for i := 0; i < repeat; i++ {
+				if err := stream.SendMsg(req); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+func stringReqs(values ...string) []proto.Message {
+	reqs := make([]proto.Message, len(values))
+	for i, v := range values {
+		reqs[i] = wrapperspb.String(v)
+	}
+	return reqs
+}
+
+func newStringResp() proto.Message { return &wrapperspb.StringValue{} }
+
+func TestClientStream(t *testing.T) {
+	tests := []struct {
+		name     string
+		handler  grpc.StreamHandler
+		reqs     []string
+		opts     StreamOptions
+		wantSent int64
+		wantRecv int64
+		wantCode string
+		wantErr  error
+	}{
+		{
+			name:     "bidi until EOF",
+			handler:  echoHandler(1),
+			reqs:     []string{"a", "b", "c"},
+			opts:     StreamOptions{ClientStreams: true, ServerStreams: true},
+			wantSent: 3,
+			wantRecv: 3,
+			wantCode: "OK",
+		},
+		{
+			name:     "server stream stops at expected responses",
+			handler:  echoHandler(10),
+			reqs:     []string{"a"},
+			opts:     StreamOptions{ServerStreams: true, ExpectResponses: 4},
+			wantSent: 1,
+			wantRecv: 4,
+			wantCode: "OK",
+		},
+		{
+			name:     "stream ends before expected responses",
+			handler:  echoHandler(2),
+			reqs:     []string{"a"},
+			opts:     StreamOptions{ServerStreams: true, ExpectResponses: 5},
+			wantSent: 1,
+			wantRecv: 2,
+			wantCode: "INCOMPLETE",
+			wantErr:  ErrStreamIncomplete,
+		},
+		{
+			name: "server error status",
+			handler: func(_ interface{}, stream grpc.ServerStream) error {
+				return status.Error(codes.ResourceExhausted, "slow down")
+			},
+			reqs:     []string{"a"},
+			opts:     StreamOptions{ClientStreams: true, ServerStreams: true},
+			wantCode: "ResourceExhausted",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := startStreamServer(t, tt.handler)
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			got, err := client.Stream(ctx, stringReqs(tt.reqs...), newStringResp, tt.opts)
+			if tt.wantCode == "OK" && err != nil {
+				t.Fatalf("Stream() error = %v", err)
+			}
+			if tt.wantCode != "OK" && err == nil {
+				t.Fatalf("Stream() error = nil, want failure with %s", tt.wantCode)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Stream() error = %v, want %v", err, tt.wantErr)
+			}
+			if got.StatusCode != tt.wantCode {
+				t.Errorf("StatusCode = %q, want %q", got.StatusCode, tt.wantCode)
+			}
+			if tt.wantCode == "OK" || tt.wantErr != nil {
+				if got.MessagesSent != tt.wantSent {
+					t.Errorf("MessagesSent = %d, want %d", got.MessagesSent, tt.wantSent)
+				}
+				if got.MessagesRecv != tt.wantRecv {
+					t.Errorf("MessagesRecv = %d, want %d", got.MessagesRecv, tt.wantRecv)
+				}
+			}
+			if got.MessagesRecv > 0 && got.TimeToFirstMessage <= 0 {
+				t.Errorf("TimeToFirstMessage = %v, want > 0", got.TimeToFirstMessage)
+			}
+		})
+	}
+}
+
+func TestClientStreamPacing(t *testing.T) {
+	client := startStreamServer(t, echoHandler(1))
+	interval := 30 * time.Millisecond
+
+	start := time.Now()
+	got, err := client.Stream(context.Background(), stringReqs("a", "b", "c"), newStringResp, StreamOptions{
+		ClientStreams: true,
+		ServerStreams: true,
+		Interval:      interval,
+	})
+	if err != nil {
+		t.Fatalf("Stream() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 2*interval {
+		t.Errorf("elapsed = %v, want >= %v for paced sends", elapsed, 2*interval)
+	}
+	if got.MessagesRecv != 3 {
+		t.Errorf("MessagesRecv = %d, want 3", got.MessagesRecv)
+	}
+}
+
+func TestClientStreamDeadline(t *testing.T) {
+	client := startStreamServer(t, func(_ interface{}, stream grpc.ServerStream) error {
+		<-stream.Context().Done()
+		return stream.Context().Err()
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	got, err := client.Stream(ctx, stringReqs("a"), newStringResp, StreamOptions{ServerStreams: true})
+	if err == nil {
+		t.Fatal("Stream() error = nil, want deadline error")
+	}
+	if got.StatusCode != codes.DeadlineExceeded.String() {
+		t.Errorf("StatusCode = %q, want DeadlineExceeded", got.StatusCode)
+	}
+}
+
+func TestClientStreamWithoutConnect(t *testing.T) {
+	client, err := NewClient(Config{Target: "localhost:50051", Service: "test.Stream", Method: "Chat"})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if _, err := client.Stream(context.Background(), nil, newStringResp, StreamOptions{ServerStreams: true}); err == nil {
+		t.Error("Stream() without connect error = nil, want error")
+	}
+}
@@ -2,6 +2,8 @@ package grpcclient
 
 import (
 	"context"
+	"errors"
+	"net"
 	"testing"
 	"time"
 
@@ -296,3 +298,33 @@ func TestClientCloseWithConnection(t *testing.T) {
 // However, we can use `grpc.NewServer()` and register a handler if we had the service definition.
 // But `Client` uses `conn.Invoke` which is generic.
 // We can try to use a standard service like `grpc.health.v1.Health`.
+
+// TestDial_ContextDialerSeesHostName verifies that targets without a scheme
+// reach a custom dialer unresolved.
+func TestDial_ContextDialerSeesHostName(t *testing.T) {
+	addrs := make(chan string, 1)
+	conn, err := Dial(context.Background(), Config{
+		Target: "grpc.example.invalid:50051",
+		DialContext: func(ctx context.Context, addr string) (net.Conn, error) {
+			select {
+			case addrs <- addr:
+			default:
+			}
+			return nil, errors.New("refused")
+		},
+	})
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer conn.Close()
+	conn.Connect()
+
+	select {
+	case addr := <-addrs:
+		if addr != "grpc.example.invalid:50051" {
+			t.Errorf("dialer addr = %q, want the host name", addr)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("custom dialer was not called")
+	}
+}
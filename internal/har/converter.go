@@ -113,6 +113,13 @@ func entryToEndpoint(entry *Entry, opts ConvertOptions) config.Endpoint {
 	// Include headers if enabled
 	if opts.IncludeHeaders && len(req.Headers) > 0 {
 		endpoint.Headers = extractHeaders(req.Headers)
+		if opts.CookieJar {
+			for name := range endpoint.Headers {
+				if strings.EqualFold(name, "cookie") {
+					delete(endpoint.Headers, name)
+				}
+			}
+		}
 	}
 
 	// Extract POST body if present
@@ -2,7 +2,10 @@ package har
 
 import (
 	"net/url"
+	"reflect"
 	"testing"
+
+	"github.com/torosent/crankfire/internal/config"
 )
 
 func TestConvert_BasicRequest(t *testing.T) {
@@ -735,3 +738,82 @@ func TestConvert_PreservesEndpointStructure(t *testing.T) {
 		t.Errorf("expected weight 1, got %d", ep.Weight)
 	}
 }
+
+func TestConvert_CookieJarDropsCookieHeader(t *testing.T) {
+	har := &HAR{
+		Log: &Log{
+			Entries: []*Entry{
+				{
+					Request: &Request{
+						Method: "GET",
+						URL:    "https://api.example.com/users",
+						Headers: []*Header{
+							{Name: "Accept", Value: "application/json"},
+							{Name: "Cookie", Value: "sid=abc"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	opts := DefaultOptions()
+	opts.CookieJar = true
+	endpoints, err := Convert(har, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := endpoints[0].Headers["Cookie"]; ok {
+		t.Error("expected Cookie header to be dropped")
+	}
+	if endpoints[0].Headers["Accept"] != "application/json" {
+		t.Errorf("expected Accept header to be kept, got %v", endpoints[0].Headers)
+	}
+}
+
+func TestCookies_InitialJarState(t *testing.T) {
+	har := &HAR{
+		Log: &Log{
+			Entries: []*Entry{
+				{
+					Request: &Request{
+						Method:  "POST",
+						URL:     "https://app.example.com/login",
+						Cookies: []*Cookie{{Name: "consent", Value: "yes"}},
+					},
+					Response: &Response{
+						Status:  200,
+						Cookies: []*Cookie{{Name: "sid", Value: "recorded"}},
+					},
+				},
+				{
+					Request: &Request{
+						Method: "GET",
+						URL:    "https://app.example.com/profile",
+						Cookies: []*Cookie{
+							{Name: "consent", Value: "later"},
+							{Name: "sid", Value: "recorded"},
+							{Name: "pref", Value: "dark", Domain: ".example.com", Path: "/app", Secure: true},
+						},
+					},
+				},
+				{
+					Request: &Request{
+						Method:  "GET",
+						URL:     "https://cdn.example.com/app.js",
+						Cookies: []*Cookie{{Name: "cdn", Value: "1"}},
+					},
+				},
+			},
+		},
+	}
+
+	got := Cookies(har, DefaultOptions())
+	want := []config.Cookie{
+		{Name: "consent", Value: "yes", Domain: "app.example.com", Path: "/"},
+		{Name: "pref", Value: "dark", Domain: ".example.com", Path: "/app", Secure: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Cookies() = %+v, want %+v", got, want)
+	}
+}
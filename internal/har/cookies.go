@@ -0,0 +1,61 @@
+package har
+
+import (
+	"net/url"
+
+	"github.com/torosent/crankfire/internal/config"
+)
+
+// Cookies returns the cookies the browser held when the recording started:
+// the request cookies of the entries Convert would include, minus those a
+// recorded response set, which the run obtains the same way. Cookies without
+// a domain belong to the host of their request; the first value recorded for
+// a name, domain and path wins.
+func Cookies(har *HAR, opts ConvertOptions) []config.Cookie {
+	if har == nil || har.Log == nil {
+		return nil
+	}
+
+	type key struct{ name, domain, path string }
+	setByResponse := make(map[string]bool)
+	seen := make(map[key]bool)
+	var cookies []config.Cookie
+
+	for _, entry := range har.Log.Entries {
+		if entry == nil || entry.Request == nil {
+			continue
+		}
+		if shouldIncludeEntry(entry, opts) {
+			host := ""
+			if u, err := url.Parse(entry.Request.URL); err == nil {
+				host = u.Hostname()
+			}
+			for _, c := range entry.Request.Cookies {
+				if c == nil || c.Name == "" || setByResponse[c.Name] {
+					continue
+				}
+				ck := config.Cookie{Name: c.Name, Value: c.Value, Domain: c.Domain, Path: c.Path, Secure: c.Secure}
+				if ck.Domain == "" {
+					ck.Domain = host
+				}
+				if ck.Path == "" {
+					ck.Path = "/"
+				}
+				k := key{ck.Name, ck.Domain, ck.Path}
+				if ck.Domain == "" || seen[k] {
+					continue
+				}
+				seen[k] = true
+				cookies = append(cookies, ck)
+			}
+		}
+		if entry.Response != nil {
+			for _, c := range entry.Response.Cookies {
+				if c != nil {
+					setByResponse[c.Name] = true
+				}
+			}
+		}
+	}
+	return cookies
+}
@@ -12,6 +12,9 @@ type ConvertOptions struct {
 	ExcludeStatic bool
 	// IncludeHeaders determines whether to include request headers in endpoints
 	IncludeHeaders bool
+	// CookieJar drops the Cookie request header from endpoints because the
+	// run sends cookies from a jar seeded with Cookies instead
+	CookieJar bool
 }
 
 // DefaultOptions returns ConvertOptions with sensible defaults.
@@ -2,16 +2,19 @@ package httpclient
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/torosent/crankfire/internal/config"
 	"github.com/torosent/crankfire/internal/placeholders"
+	"github.com/torosent/crankfire/internal/resolver"
 	"github.com/torosent/crankfire/internal/variables"
 )
 
@@ -200,19 +203,55 @@ func (b *RequestBuilder) Build(ctx context.Context) (*http.Request, error) {
 }
 
 func NewClient(timeout time.Duration) *http.Client {
+	client, _ := NewClientWithOptions(ClientOptions{Timeout: timeout}) // fails only for an unknown HTTPVersion
+	return client
+}
+
+// ClientOptions configures NewClientWithOptions.
+type ClientOptions struct {
+	Timeout time.Duration
+	// TLSConfig is the client TLS configuration; nil keeps the defaults.
+	TLSConfig *tls.Config
+	// HTTPVersion forces one of the config.HTTPVersion* versions. Empty
+	// negotiates HTTP/2 over TLS when TLSConfig offers no ALPN protocols or
+	// includes "h2", and HTTP/1.1 otherwise.
+	HTTPVersion string
+	// Resolver picks the address of every new connection; nil resolves
+	// hosts through the system resolver.
+	Resolver *resolver.Resolver
+}
+
+// NewClientWithOptions is NewClient with TLS and HTTP version control.
+// HTTP/3 uses a QUIC transport; every other version a TCP one.
+func NewClientWithOptions(opts ClientOptions) (*http.Client, error) {
+	timeout := opts.Timeout
 	if timeout < 0 {
 		timeout = 0
 	}
 
+	if opts.HTTPVersion == config.HTTPVersion3 {
+		return &http.Client{
+			Timeout:   timeout,
+			Transport: newHTTP3Transport(opts.TLSConfig, opts.Resolver),
+		}, nil
+	}
+
 	dialer := &net.Dialer{
 		Timeout:   30 * time.Second,
 		KeepAlive: 30 * time.Second,
 	}
 
+	dialContext := dialer.DialContext
+	if opts.Resolver != nil {
+		dialContext = opts.Resolver.DialContext
+	}
+
+	tlsConfig := opts.TLSConfig
 	transport := &http.Transport{
 		Proxy:                 http.ProxyFromEnvironment,
-		DialContext:           dialer.DialContext,
-		ForceAttemptHTTP2:     true,
+		DialContext:           dialContext,
+		TLSClientConfig:       tlsConfig,
+		ForceAttemptHTTP2:     tlsConfig == nil || len(tlsConfig.NextProtos) == 0 || slices.Contains(tlsConfig.NextProtos, "h2"),
 		MaxIdleConns:          256,
 		MaxIdleConnsPerHost:   32,
 		IdleConnTimeout:       90 * time.Second,
@@ -220,8 +259,23 @@ func NewClient(timeout time.Duration) *http.Client {
 		ExpectContinueTimeout: 1 * time.Second,
 	}
 
+	if opts.HTTPVersion != "" {
+		var protocols http.Protocols
+		switch opts.HTTPVersion {
+		case config.HTTPVersion1:
+			protocols.SetHTTP1(true)
+		case config.HTTPVersion2:
+			protocols.SetHTTP2(true)
+		case config.HTTPVersionH2C:
+			protocols.SetUnencryptedHTTP2(true)
+		default:
+			return nil, fmt.Errorf("unsupported HTTP version %q", opts.HTTPVersion)
+		}
+		transport.Protocols = &protocols
+	}
+
 	return &http.Client{
 		Timeout:   timeout,
 		Transport: transport,
-	}
+	}, nil
 }
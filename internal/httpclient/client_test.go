@@ -12,6 +12,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/quic-go/quic-go/http3"
 	"github.com/torosent/crankfire/internal/config"
 	"github.com/torosent/crankfire/internal/placeholders"
 )
@@ -676,3 +677,96 @@ func TestRequestBuilder_Build_BodyError(t *testing.T) {
 		t.Error("Build(body error) error = nil, want error")
 	}
 }
+
+func TestNewClientWithOptions_HTTPVersions(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.Proto)
+	})
+	tlsServer := httptest.NewUnstartedServer(handler)
+	tlsServer.EnableHTTP2 = true
+	tlsServer.StartTLS()
+	defer tlsServer.Close()
+	roots := tlsServer.Client().Transport.(*http.Transport).TLSClientConfig
+
+	h2cServer := httptest.NewUnstartedServer(handler)
+	h2cServer.Config.Protocols = new(http.Protocols)
+	h2cServer.Config.Protocols.SetHTTP1(true)
+	h2cServer.Config.Protocols.SetUnencryptedHTTP2(true)
+	h2cServer.Start()
+	defer h2cServer.Close()
+
+	tests := []struct {
+		version string
+		url     string
+		want    string
+	}{
+		{"", tlsServer.URL, "HTTP/2.0"},
+		{config.HTTPVersion1, tlsServer.URL, "HTTP/1.1"},
+		{config.HTTPVersion2, tlsServer.URL, "HTTP/2.0"},
+		{config.HTTPVersionH2C, h2cServer.URL, "HTTP/2.0"},
+	}
+	for _, tt := range tests {
+		t.Run("version "+tt.version, func(t *testing.T) {
+			client, err := NewClientWithOptions(ClientOptions{
+				Timeout:     5 * time.Second,
+				TLSConfig:   roots.Clone(),
+				HTTPVersion: tt.version,
+			})
+			if err != nil {
+				t.Fatalf("NewClientWithOptions() error = %v", err)
+			}
+			if got := getProto(t, client, tt.url); got != tt.want {
+				t.Errorf("server saw %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := NewClientWithOptions(ClientOptions{HTTPVersion: "1.0"}); err == nil {
+		t.Error("NewClientWithOptions() accepted HTTP version 1.0")
+	}
+}
+
+func TestNewClientWithOptions_HTTP3(t *testing.T) {
+	tlsServer := httptest.NewTLSServer(http.NotFoundHandler())
+	defer tlsServer.Close()
+	roots := tlsServer.Client().Transport.(*http.Transport).TLSClientConfig
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("udp unavailable: %v", err)
+	}
+	server := &http3.Server{
+		TLSConfig: http3.ConfigureTLSConfig(tlsServer.TLS.Clone()),
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.WriteString(w, r.Proto)
+		}),
+	}
+	go func() { _ = server.Serve(conn) }()
+	defer server.Close()
+
+	client, err := NewClientWithOptions(ClientOptions{
+		Timeout:     5 * time.Second,
+		TLSConfig:   roots.Clone(),
+		HTTPVersion: config.HTTPVersion3,
+	})
+	if err != nil {
+		t.Fatalf("NewClientWithOptions() error = %v", err)
+	}
+	if got := getProto(t, client, "https://"+conn.LocalAddr().String()); got != "HTTP/3.0" {
+		t.Errorf("server saw %s, want HTTP/3.0", got)
+	}
+}
+
+func getProto(t *testing.T, client *http.Client, url string) string {
+	t.Helper()
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read body: %v", err)
+	}
+	return string(body)
+}
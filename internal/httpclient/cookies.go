@@ -0,0 +1,82 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+
+	"github.com/torosent/crankfire/internal/config"
+)
+
+type cookieJarContextKey struct{}
+
+// WithCookieJar returns a context whose HTTP requests send and store cookies
+// through jar.
+func WithCookieJar(ctx context.Context, jar http.CookieJar) context.Context {
+	return context.WithValue(ctx, cookieJarContextKey{}, jar)
+}
+
+// CookieJarFromContext returns the jar attached by WithCookieJar, or nil.
+func CookieJarFromContext(ctx context.Context) http.CookieJar {
+	jar, _ := ctx.Value(cookieJarContextKey{}).(http.CookieJar)
+	return jar
+}
+
+// CookieJars creates the per-iteration cookie jars of a run.
+type CookieJars struct {
+	seeds []seededCookie
+}
+
+type seededCookie struct {
+	url    *url.URL
+	cookie *http.Cookie
+}
+
+// NewCookieJars prepares jars that start with seed. Seeded cookies without a
+// domain are host-only cookies for defaultURL, which is then required.
+func NewCookieJars(seed []config.Cookie, defaultURL string) (*CookieJars, error) {
+	var def *url.URL
+	if defaultURL != "" {
+		u, err := url.Parse(defaultURL)
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("cookies: invalid target URL %q", defaultURL)
+		}
+		def = u
+	}
+	jars := &CookieJars{}
+	for _, c := range seed {
+		path := c.Path
+		if path == "" {
+			path = "/"
+		}
+		cookie := &http.Cookie{Name: c.Name, Value: c.Value, Path: path, Secure: c.Secure}
+		scheme := "http"
+		if c.Secure {
+			scheme = "https"
+		}
+		var u *url.URL
+		if c.Domain != "" {
+			cookie.Domain = c.Domain
+			u = &url.URL{Scheme: scheme, Host: strings.TrimPrefix(c.Domain, "."), Path: path}
+		} else {
+			if def == nil {
+				return nil, fmt.Errorf("cookies: cookie %s needs a domain without a target URL", c.Name)
+			}
+			u = &url.URL{Scheme: scheme, Host: def.Host, Path: path}
+		}
+		jars.seeds = append(jars.seeds, seededCookie{url: u, cookie: cookie})
+	}
+	return jars, nil
+}
+
+// New returns a jar holding only the seeded cookies.
+func (j *CookieJars) New() http.CookieJar {
+	jar, _ := cookiejar.New(nil) // never fails without options
+	for _, s := range j.seeds {
+		jar.SetCookies(s.url, []*http.Cookie{s.cookie})
+	}
+	return jar
+}
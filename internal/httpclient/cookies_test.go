@@ -0,0 +1,73 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/torosent/crankfire/internal/config"
+)
+
+func TestCookieJarsSeedEveryJar(t *testing.T) {
+	jars, err := NewCookieJars([]config.Cookie{
+		{Name: "sid", Value: "abc"},
+		{Name: "consent", Value: "yes", Domain: ".example.com"},
+		{Name: "scoped", Value: "1", Path: "/admin"},
+	}, "http://api.example.com:8080/v1")
+	if err != nil {
+		t.Fatalf("NewCookieJars() error = %v", err)
+	}
+
+	first := jars.New()
+	api, _ := url.Parse("http://api.example.com/")
+	first.SetCookies(api, []*http.Cookie{{Name: "late", Value: "x"}})
+	if got := cookieValues(first.Cookies(api)); got["late"] != "x" || got["sid"] != "abc" || got["consent"] != "yes" {
+		t.Fatalf("first jar cookies = %v", got)
+	}
+	if _, ok := cookieValues(first.Cookies(api))["scoped"]; ok {
+		t.Error("cookie scoped to /admin sent to /")
+	}
+
+	got := cookieValues(jars.New().Cookies(api))
+	if _, ok := got["late"]; ok {
+		t.Error("cookie set in one jar leaked into the next")
+	}
+	if got["sid"] != "abc" {
+		t.Errorf("new jar sid = %q, want abc", got["sid"])
+	}
+
+	www, _ := url.Parse("http://www.example.com/")
+	got = cookieValues(jars.New().Cookies(www))
+	if got["consent"] != "yes" {
+		t.Error("domain cookie not sent to a subdomain")
+	}
+	if _, ok := got["sid"]; ok {
+		t.Error("host-only cookie sent to another host")
+	}
+}
+
+func TestNewCookieJarsRequiresDomainWithoutTarget(t *testing.T) {
+	if _, err := NewCookieJars([]config.Cookie{{Name: "sid", Value: "abc"}}, ""); err == nil {
+		t.Fatal("NewCookieJars() accepted a cookie without domain or target URL")
+	}
+}
+
+func TestCookieJarContext(t *testing.T) {
+	if CookieJarFromContext(context.Background()) != nil {
+		t.Fatal("CookieJarFromContext() returned a jar for a bare context")
+	}
+	jars, _ := NewCookieJars(nil, "")
+	jar := jars.New()
+	if CookieJarFromContext(WithCookieJar(context.Background(), jar)) != jar {
+		t.Error("CookieJarFromContext() did not return the attached jar")
+	}
+}
+
+func cookieValues(cookies []*http.Cookie) map[string]string {
+	values := make(map[string]string, len(cookies))
+	for _, c := range cookies {
+		values[c.Name] = c.Value
+	}
+	return values
+}
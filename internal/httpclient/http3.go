@@ -0,0 +1,61 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http/httptrace"
+	"time"
+
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/http3"
+	"github.com/torosent/crankfire/internal/resolver"
+)
+
+// newHTTP3Transport returns an HTTP/3 round tripper. QUIC connections are
+// kept alive between requests so they are reused like pooled TCP ones. A
+// non-nil res picks the address of every new connection.
+func newHTTP3Transport(tlsConfig *tls.Config, res *resolver.Resolver) *http3.Transport {
+	t := &http3.Transport{
+		TLSClientConfig: tlsConfig,
+		QUICConfig: &quic.Config{
+			HandshakeIdleTimeout: 10 * time.Second,
+			MaxIdleTimeout:       90 * time.Second,
+			KeepAlivePeriod:      30 * time.Second,
+		},
+	}
+	if res != nil {
+		t.Dial = func(ctx context.Context, addr string, tlsCfg *tls.Config, cfg *quic.Config) (*quic.Conn, error) {
+			return dialHTTP3(ctx, res, addr, tlsCfg, cfg)
+		}
+	}
+	return t
+}
+
+// dialHTTP3 opens a QUIC connection to the address res picks for addr. The
+// TLS configuration already names the original host. Like the transport's
+// own dialer it reports the connect and handshake phases to httptrace.
+func dialHTTP3(ctx context.Context, res *resolver.Resolver, addr string, tlsCfg *tls.Config, cfg *quic.Config) (*quic.Conn, error) {
+	target, err := res.Resolve(ctx, "udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	trace := httptrace.ContextClientTrace(ctx)
+	if trace != nil && trace.ConnectStart != nil {
+		trace.ConnectStart("udp", target)
+	}
+	if trace != nil && trace.TLSHandshakeStart != nil {
+		trace.TLSHandshakeStart()
+	}
+	conn, err := quic.DialAddrEarly(ctx, target, tlsCfg, cfg)
+	var state tls.ConnectionState
+	if conn != nil {
+		state = conn.ConnectionState().TLS
+	}
+	if trace != nil && trace.TLSHandshakeDone != nil {
+		trace.TLSHandshakeDone(state, err)
+	}
+	if trace != nil && trace.ConnectDone != nil {
+		trace.ConnectDone("udp", target, err)
+	}
+	return conn, err
+}
@@ -1,8 +1,11 @@
 package metrics
 
 import (
+	"crypto/tls"
 	"math/rand"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/HdrHistogram/hdrhistogram-go"
@@ -32,6 +35,21 @@ type DataPoint struct {
 type Collector struct {
 	total     *shardedStats
 	endpoints sync.Map // map[string]*shardedStats
+	steps     sync.Map // map[string]*shardedStats
+	journeys  sync.Map // map[string]*shardedStats
+	timings   sync.Map // map[string]*shardedStats
+	checks    sync.Map // map[string]*checkCounter
+
+	// phases holds the HTTP connection phase histograms of all requests,
+	// endpointPhases those of each endpoint.
+	phases         sync.Map // map[string]*shardedStats
+	endpointPhases sync.Map // map[phaseKey]*shardedStats
+
+	// corrected mirrors total with each request's queue delay added back, so
+	// latency is measured from the intended start time (coordinated omission).
+	// queued counts requests that started behind schedule.
+	corrected *shardedStats
+	queued    atomic.Int64
 
 	// customMetrics needs its own protection or sharding.
 	// For simplicity, we'll use a mutex for custom metrics aggregation as it's less frequent/critical than latency.
@@ -47,6 +65,23 @@ type Collector struct {
 	lastSnapshot snapshotState
 }
 
+// HTTP connection phases, recorded with RecordPhase.
+const (
+	PhaseDNS      = "dns"      // resolving the host name
+	PhaseConnect  = "connect"  // establishing the TCP (or QUIC) connection
+	PhaseTLS      = "tls"      // the TLS handshake
+	PhaseTTFB     = "ttfb"     // from the request being sent to the first response byte
+	PhaseTransfer = "transfer" // from the first response byte to the end of the body
+)
+
+// Phases lists the connection phases in the order they occur.
+var Phases = []string{PhaseDNS, PhaseConnect, PhaseTLS, PhaseTTFB, PhaseTransfer}
+
+type phaseKey struct {
+	endpoint string
+	phase    string
+}
+
 type snapshotState struct {
 	timestamp     time.Time
 	totalRequests int64
@@ -60,8 +95,16 @@ type RequestMetadata struct {
 	Protocol      string                 // Protocol used (http, websocket, sse, grpc)
 	StatusCode    string                 // Exact status/close code for failures
 	CustomMetrics map[string]interface{} // Protocol-specific metrics
+	// QueueDelay is how long the request waited past its intended start time
+	// for a free worker. It is added back to the latency in the corrected
+	// histogram to account for coordinated omission.
+	QueueDelay time.Duration
 }
 
+// Gauge is a custom metric value that replaces the previous value instead of
+// being summed, for ratios and other point-in-time readings.
+type Gauge float64
+
 // EndpointStats represents aggregated metrics for a logical bucket (overall or per-endpoint).
 type EndpointStats struct {
 	Total          int64         `json:"total"`
@@ -85,21 +128,61 @@ type EndpointStats struct {
 	P95LatencyMs  float64                   `json:"p95_latency_ms"`
 	P99LatencyMs  float64                   `json:"p99_latency_ms"`
 	StatusBuckets map[string]map[string]int `json:"status_buckets,omitempty"`
+
+	// hist is the histogram the percentiles were read from, kept for
+	// Percentile. It is a private copy and is never written to.
+	hist *hdrhistogram.Histogram
+}
+
+// Percentile returns the latency at percentile q (0 < q <= 100), such as
+// 99.9. It reports false when the stats were not computed from a histogram,
+// e.g. when decoded from JSON.
+func (s EndpointStats) Percentile(q float64) (time.Duration, bool) {
+	if s.hist == nil || q <= 0 || q > 100 {
+		return 0, false
+	}
+	if s.hist.TotalCount() == 0 {
+		return 0, true
+	}
+	return time.Duration(s.hist.ValueAtQuantile(q)) * time.Microsecond, true
+}
+
+// CheckStats counts the outcomes of one response check.
+type CheckStats struct {
+	Passes   int64   `json:"passes"`
+	Fails    int64   `json:"fails"`
+	PassRate float64 `json:"pass_rate"`
+}
+
+type checkCounter struct {
+	passes atomic.Int64
+	fails  atomic.Int64
 }
 
 // Stats represents aggregated metrics, including optional breakdowns.
 type Stats struct {
 	EndpointStats
-	Duration        time.Duration                     `json:"-"`
-	DurationMs      float64                           `json:"duration_ms"`
-	Endpoints       map[string]EndpointStats          `json:"endpoints,omitempty"`
-	ProtocolMetrics map[string]map[string]interface{} `json:"protocol_metrics,omitempty"`
+	Duration   time.Duration            `json:"-"`
+	DurationMs float64                  `json:"duration_ms"`
+	Endpoints  map[string]EndpointStats `json:"endpoints,omitempty"`
+	Steps      map[string]EndpointStats `json:"steps,omitempty"`
+	Journeys   map[string]EndpointStats `json:"journeys,omitempty"`
+	Timings    map[string]EndpointStats `json:"timings,omitempty"`
+	Checks     map[string]CheckStats    `json:"checks,omitempty"`
+	// Phases breaks request latency down into connection phases, keyed by
+	// phase; EndpointPhases does the same per endpoint. Phases that did not
+	// happen, such as DNS on a reused connection, record no sample.
+	Phases          map[string]EndpointStats            `json:"phases,omitempty"`
+	EndpointPhases  map[string]map[string]EndpointStats `json:"endpoint_phases,omitempty"`
+	Corrected       *EndpointStats                      `json:"corrected,omitempty"` // latency from intended start; nil unless requests queued
+	ProtocolMetrics map[string]map[string]interface{}   `json:"protocol_metrics,omitempty"`
 }
 
 // NewCollector allocates a Collector.
 func NewCollector() *Collector {
 	return &Collector{
 		total:         newShardedStats(),
+		corrected:     newShardedStats(),
 		customMetrics: make(map[string]map[string]interface{}),
 		history:       []DataPoint{},
 	}
@@ -121,14 +204,22 @@ func (c *Collector) RecordRequest(latency time.Duration, err error, meta *Reques
 	var protocol string
 	var statusCode string
 	var customMetrics map[string]interface{}
+	var queueDelay time.Duration
 	if meta != nil {
 		endpoint = meta.Endpoint
 		protocol = meta.Protocol
 		statusCode = meta.StatusCode
 		customMetrics = meta.CustomMetrics
+		queueDelay = meta.QueueDelay
 	}
 
 	c.total.record(latency, err, protocol, statusCode)
+	if queueDelay > 0 {
+		c.queued.Add(1)
+	} else {
+		queueDelay = 0
+	}
+	c.corrected.record(latency+queueDelay, err, protocol, statusCode)
 	if endpoint != "" {
 		v, ok := c.endpoints.Load(endpoint)
 		if !ok {
@@ -150,6 +241,123 @@ func (c *Collector) RecordRequest(latency time.Duration, err error, meta *Reques
 	}
 }
 
+// RecordStep records the latency of one step inside a scripted request.
+// Steps get their own histograms and are not counted towards the request totals.
+func (c *Collector) RecordStep(step string, latency time.Duration, err error, meta *RequestMetadata) {
+	if step == "" {
+		return
+	}
+	var protocol, statusCode string
+	if meta != nil {
+		protocol = meta.Protocol
+		statusCode = meta.StatusCode
+	}
+	v, ok := c.steps.Load(step)
+	if !ok {
+		v, _ = c.steps.LoadOrStore(step, newShardedStats())
+	}
+	v.(*shardedStats).record(latency, err, protocol, statusCode)
+}
+
+// RecordJourney records one end-to-end iteration of a scenario. The requests
+// inside it are recorded separately, so journeys do not count towards the
+// request totals.
+func (c *Collector) RecordJourney(scenario string, latency time.Duration, err error, meta *RequestMetadata) {
+	if scenario == "" {
+		return
+	}
+	var protocol, statusCode string
+	if meta != nil {
+		protocol = meta.Protocol
+		statusCode = meta.StatusCode
+	}
+	v, ok := c.journeys.Load(scenario)
+	if !ok {
+		v, _ = c.journeys.LoadOrStore(scenario, newShardedStats())
+	}
+	v.(*shardedStats).record(latency, err, protocol, statusCode)
+}
+
+// RecordTiming adds a sample to a named duration histogram, such as the gap
+// between streamed events. Timings are not counted towards the request totals.
+func (c *Collector) RecordTiming(name string, d time.Duration) {
+	if name == "" {
+		return
+	}
+	if d < 0 {
+		d = 0
+	}
+	v, ok := c.timings.Load(name)
+	if !ok {
+		v, _ = c.timings.LoadOrStore(name, newShardedStats())
+	}
+	v.(*shardedStats).record(d, nil, "", "")
+}
+
+// RecordTLSHandshake records a completed TLS handshake: its duration as the
+// "tls_handshake" timing and the negotiated version as a count in the
+// protocol metrics, e.g. tls_version_1.3.
+func (c *Collector) RecordTLSHandshake(protocol string, d time.Duration, version uint16) {
+	c.RecordTiming("tls_handshake", d)
+	if protocol == "" {
+		return
+	}
+	c.customMu.Lock()
+	if c.customMetrics[protocol] == nil {
+		c.customMetrics[protocol] = make(map[string]interface{})
+	}
+	c.aggregateMetric(protocol, tlsVersionMetric(version), int64(1))
+	c.customMu.Unlock()
+}
+
+// tlsVersionMetric names the protocol metric counting handshakes that
+// negotiated version.
+func tlsVersionMetric(version uint16) string {
+	return "tls_version_" + strings.TrimPrefix(tls.VersionName(version), "TLS ")
+}
+
+// RecordPhase adds the duration of one connection phase of a request, such
+// as PhaseTTFB, to the overall phase histogram and to that of endpoint.
+// Phases are not counted towards the request totals.
+func (c *Collector) RecordPhase(endpoint, phase string, d time.Duration) {
+	if phase == "" {
+		return
+	}
+	if d < 0 {
+		d = 0
+	}
+	v, ok := c.phases.Load(phase)
+	if !ok {
+		v, _ = c.phases.LoadOrStore(phase, newShardedStats())
+	}
+	v.(*shardedStats).record(d, nil, "", "")
+	if endpoint == "" {
+		return
+	}
+	key := phaseKey{endpoint: endpoint, phase: phase}
+	v, ok = c.endpointPhases.Load(key)
+	if !ok {
+		v, _ = c.endpointPhases.LoadOrStore(key, newShardedStats())
+	}
+	v.(*shardedStats).record(d, nil, "", "")
+}
+
+// RecordCheck counts one evaluation of a named response check.
+func (c *Collector) RecordCheck(name string, passed bool) {
+	if name == "" {
+		return
+	}
+	v, ok := c.checks.Load(name)
+	if !ok {
+		v, _ = c.checks.LoadOrStore(name, &checkCounter{})
+	}
+	if passed {
+		v.(*checkCounter).passes.Add(1)
+	} else {
+		v.(*checkCounter).fails.Add(1)
+	}
+}
+
 // Stats computes and returns current aggregated statistics.
 func (c *Collector) Stats(elapsed time.Duration) Stats {
 	c.startMu.Lock()
@@ -169,6 +377,75 @@ func (c *Collector) Stats(elapsed time.Duration) Stats {
 		return true
 	})
 
+	var stepSnaps map[string]EndpointStats
+	c.steps.Range(func(key, value interface{}) bool {
+		if stepSnaps == nil {
+			stepSnaps = make(map[string]EndpointStats)
+		}
+		stepSnaps[key.(string)] = value.(*shardedStats).snapshot(actualElapsed)
+		return true
+	})
+
+	var journeySnaps map[string]EndpointStats
+	c.journeys.Range(func(key, value interface{}) bool {
+		if journeySnaps == nil {
+			journeySnaps = make(map[string]EndpointStats)
+		}
+		journeySnaps[key.(string)] = value.(*shardedStats).snapshot(actualElapsed)
+		return true
+	})
+
+	var timingSnaps map[string]EndpointStats
+	c.timings.Range(func(key, value interface{}) bool {
+		if timingSnaps == nil {
+			timingSnaps = make(map[string]EndpointStats)
+		}
+		timingSnaps[key.(string)] = value.(*shardedStats).snapshot(actualElapsed)
+		return true
+	})
+
+	var phaseSnaps map[string]EndpointStats
+	c.phases.Range(func(key, value interface{}) bool {
+		if phaseSnaps == nil {
+			phaseSnaps = make(map[string]EndpointStats)
+		}
+		phaseSnaps[key.(string)] = value.(*shardedStats).snapshot(actualElapsed)
+		return true
+	})
+
+	var endpointPhaseSnaps map[string]map[string]EndpointStats
+	c.endpointPhases.Range(func(key, value interface{}) bool {
+		k := key.(phaseKey)
+		if endpointPhaseSnaps == nil {
+			endpointPhaseSnaps = make(map[string]map[string]EndpointStats)
+		}
+		if endpointPhaseSnaps[k.endpoint] == nil {
+			endpointPhaseSnaps[k.endpoint] = make(map[string]EndpointStats)
+		}
+		endpointPhaseSnaps[k.endpoint][k.phase] = value.(*shardedStats).snapshot(actualElapsed)
+		return true
+	})
+
+	var checkSnaps map[string]CheckStats
+	c.checks.Range(func(key, value interface{}) bool {
+		if checkSnaps == nil {
+			checkSnaps = make(map[string]CheckStats)
+		}
+		counter := value.(*checkCounter)
+		snap := CheckStats{Passes: counter.passes.Load(), Fails: counter.fails.Load()}
+		if total := snap.Passes + snap.Fails; total > 0 {
+			snap.PassRate = float64(snap.Passes) / float64(total)
+		}
+		checkSnaps[key.(string)] = snap
+		return true
+	})
+
+	var corrected *EndpointStats
+	if c.queued.Load() > 0 {
+		snap := c.corrected.snapshot(actualElapsed)
+		corrected = &snap
+	}
+
 	// Copy protocol metrics
 	c.customMu.Lock()
 	protocolMetrics := make(map[string]map[string]interface{}, len(c.customMetrics))
@@ -182,6 +459,13 @@ func (c *Collector) Stats(elapsed time.Duration) Stats {
 		Duration:        actualElapsed,
 		DurationMs:      float64(actualElapsed) / float64(time.Millisecond),
 		Endpoints:       endpointSnaps,
+		Steps:           stepSnaps,
+		Journeys:        journeySnaps,
+		Timings:         timingSnaps,
+		Checks:          checkSnaps,
+		Phases:          phaseSnaps,
+		EndpointPhases:  endpointPhaseSnaps,
+		Corrected:       corrected,
 		ProtocolMetrics: protocolMetrics,
 	}
 }
@@ -212,13 +496,18 @@ func (s *shardedStats) record(latency time.Duration, err error, protocol, status
 }
 
 func (s *shardedStats) snapshot(elapsed time.Duration) EndpointStats {
+	return s.merged().snapshot(elapsed)
+}
+
+// merged returns a copy of all shards combined into one bucket.
+func (s *shardedStats) merged() *statsBucket {
 	agg := newStatsBucket()
 	for _, sh := range s.shards {
 		sh.mu.Lock()
 		agg.merge(sh.bucket)
 		sh.mu.Unlock()
 	}
-	return agg.snapshot(elapsed)
+	return agg
 }
 
 type statsBucket struct {
@@ -315,6 +604,7 @@ func (b *statsBucket) snapshot(elapsed time.Duration) EndpointStats {
 		Failures:   b.failures,
 		MinLatency: b.minLatency,
 		MaxLatency: b.maxLatency,
+		hist:       b.hist,
 	}
 
 	if total > 0 {
@@ -391,6 +681,9 @@ func (c *Collector) aggregateMetric(protocol, key string, value interface{}) {
 		} else {
 			c.customMetrics[protocol][key] = v
 		}
+	case Gauge:
+		// Gauges keep their type so exporters report them as readings.
+		c.customMetrics[protocol][key] = v
 	default:
 		// For non-numeric types, just keep the latest value
 		c.customMetrics[protocol][key] = v
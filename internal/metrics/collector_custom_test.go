@@ -187,3 +187,19 @@ func TestCollectorNoCustomMetrics(t *testing.T) {
 		t.Error("Expected no protocol metrics when none provided")
 	}
 }
+
+func TestCollectorGaugeMetricsReplace(t *testing.T) {
+	collector := NewCollector()
+
+	for _, ratio := range []Gauge{2.5, 3.0} {
+		collector.RecordRequest(time.Millisecond, nil, &RequestMetadata{
+			Protocol:      "websocket",
+			CustomMetrics: map[string]interface{}{"compression_ratio": ratio},
+		})
+	}
+
+	got := collector.Stats(time.Second).ProtocolMetrics["websocket"]["compression_ratio"]
+	if got != Gauge(3) {
+		t.Errorf("compression_ratio = %v, want latest gauge value 3", got)
+	}
+}
@@ -1,6 +1,7 @@
 package metrics_test
 
 import (
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"sync"
@@ -67,6 +68,37 @@ func TestPercentilesCalculations(t *testing.T) {
 	}
 }
 
+func TestArbitraryPercentile(t *testing.T) {
+	c := metrics.NewCollector()
+	for i := 1; i <= 1000; i++ {
+		c.RecordRequest(time.Duration(i)*time.Millisecond, nil, &metrics.RequestMetadata{Endpoint: "users"})
+	}
+
+	stats := c.Stats(0)
+	p999, ok := stats.Percentile(99.9)
+	if !ok {
+		t.Fatal("expected p99.9 to be available from collector stats")
+	}
+	if p999 < 998*time.Millisecond || p999 > 1001*time.Millisecond {
+		t.Errorf("expected P99.9 ~999ms, got %s", p999)
+	}
+	if _, ok := stats.Endpoints["users"].Percentile(99.9); !ok {
+		t.Error("expected p99.9 to be available per endpoint")
+	}
+	if _, ok := stats.Percentile(0); ok {
+		t.Error("expected p0 to be rejected")
+	}
+
+	var decoded metrics.Stats
+	data, _ := json.Marshal(stats)
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if _, ok := decoded.Percentile(99.9); ok {
+		t.Error("expected decoded stats to have no histogram")
+	}
+}
+
 func TestJSONReportSchema(t *testing.T) {
 	c := metrics.NewCollector()
 
@@ -140,6 +172,160 @@ func TestEndpointBreakdown(t *testing.T) {
 	}
 }
 
+func TestStepBreakdown(t *testing.T) {
+	c := metrics.NewCollector()
+	meta := &metrics.RequestMetadata{Protocol: "websocket"}
+	c.RecordStep("subscribe", 5*time.Millisecond, nil, meta)
+	c.RecordStep("await-quote", 40*time.Millisecond, nil, meta)
+	c.RecordStep("await-quote", 2*time.Second, errors.New("timeout"), &metrics.RequestMetadata{
+		Protocol:   "websocket",
+		StatusCode: "EXPECT_FAILED",
+	})
+	c.RecordStep("", time.Millisecond, nil, meta) // unnamed steps are ignored
+
+	stats := c.Stats(2 * time.Second)
+	if stats.Total != 0 {
+		t.Fatalf("expected steps not to count as requests, got total %d", stats.Total)
+	}
+	if len(stats.Steps) != 2 {
+		t.Fatalf("expected 2 step stats, got %d", len(stats.Steps))
+	}
+	quote := stats.Steps["await-quote"]
+	if quote.Total != 2 || quote.Failures != 1 {
+		t.Fatalf("expected await-quote total 2 / failures 1, got %d / %d", quote.Total, quote.Failures)
+	}
+	if quote.StatusBuckets["websocket"]["EXPECT_FAILED"] != 1 {
+		t.Fatalf("expected EXPECT_FAILED bucket on step, got %v", quote.StatusBuckets)
+	}
+}
+
+func TestTimingHistograms(t *testing.T) {
+	c := metrics.NewCollector()
+	for _, d := range []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 30 * time.Millisecond} {
+		c.RecordTiming("sse_inter_event", d)
+	}
+	c.RecordTiming("sse_event_lag", -time.Second) // clock skew clamps to zero
+
+	stats := c.Stats(time.Second)
+	if stats.Total != 0 {
+		t.Fatalf("expected timings not to count as requests, got total %d", stats.Total)
+	}
+	gap := stats.Timings["sse_inter_event"]
+	if gap.Total != 3 || gap.P50Latency < 19*time.Millisecond || gap.P50Latency > 21*time.Millisecond {
+		t.Fatalf("unexpected inter-event timing %+v", gap)
+	}
+	if lag := stats.Timings["sse_event_lag"]; lag.Total != 1 || lag.MaxLatency != 0 {
+		t.Fatalf("expected clamped lag sample, got %+v", lag)
+	}
+}
+
+func TestTLSHandshakes(t *testing.T) {
+	c := metrics.NewCollector()
+	c.RecordTLSHandshake("http", 5*time.Millisecond, tls.VersionTLS13)
+	c.RecordTLSHandshake("http", 7*time.Millisecond, tls.VersionTLS13)
+	c.RecordTLSHandshake("grpc", 9*time.Millisecond, tls.VersionTLS12)
+
+	stats := c.Stats(time.Second)
+	if stats.Total != 0 {
+		t.Fatalf("expected handshakes not to count as requests, got total %d", stats.Total)
+	}
+	if got := stats.Timings["tls_handshake"].Total; got != 3 {
+		t.Fatalf("expected 3 handshake samples, got %d", got)
+	}
+	if got := stats.ProtocolMetrics["http"]["tls_version_1.3"]; got != int64(2) {
+		t.Fatalf("expected 2 TLS 1.3 handshakes for http, got %v", got)
+	}
+	if got := stats.ProtocolMetrics["grpc"]["tls_version_1.2"]; got != int64(1) {
+		t.Fatalf("expected 1 TLS 1.2 handshake for grpc, got %v", got)
+	}
+}
+
+func TestConnectionPhases(t *testing.T) {
+	c := metrics.NewCollector()
+	c.RecordPhase("login", metrics.PhaseTTFB, 40*time.Millisecond)
+	c.RecordPhase("login", metrics.PhaseDNS, 2*time.Millisecond)
+	c.RecordPhase("search", metrics.PhaseTTFB, 80*time.Millisecond)
+	c.RecordPhase("", metrics.PhaseTTFB, 60*time.Millisecond)
+
+	stats := c.Stats(time.Second)
+	if stats.Total != 0 {
+		t.Fatalf("expected phases not to count as requests, got total %d", stats.Total)
+	}
+	if ttfb := stats.Phases[metrics.PhaseTTFB]; ttfb.Total != 3 || ttfb.MaxLatency < 79*time.Millisecond {
+		t.Fatalf("unexpected overall ttfb %+v", ttfb)
+	}
+	login := stats.EndpointPhases["login"]
+	if login[metrics.PhaseTTFB].Total != 1 || login[metrics.PhaseDNS].Total != 1 {
+		t.Fatalf("unexpected login phases %+v", login)
+	}
+	if _, ok := stats.EndpointPhases[""]; ok {
+		t.Fatal("expected requests without an endpoint to record only overall phases")
+	}
+}
+
+func TestJourneyBreakdown(t *testing.T) {
+	c := metrics.NewCollector()
+	c.RecordJourney("checkout", 120*time.Millisecond, nil, nil)
+	c.RecordJourney("checkout", 80*time.Millisecond, errors.New("step failed"), &metrics.RequestMetadata{Protocol: "http", StatusCode: "401"})
+
+	stats := c.Stats(time.Second)
+	if stats.Total != 0 {
+		t.Fatalf("expected journeys not to count as requests, got total %d", stats.Total)
+	}
+	journey := stats.Journeys["checkout"]
+	if journey.Total != 2 || journey.Failures != 1 || journey.StatusBuckets["http"]["401"] != 1 {
+		t.Fatalf("unexpected journey stats %+v", journey)
+	}
+}
+
+func TestCheckPassRates(t *testing.T) {
+	c := metrics.NewCollector()
+	for i := 0; i < 3; i++ {
+		c.RecordCheck("status ok", true)
+	}
+	c.RecordCheck("status ok", false)
+	c.RecordCheck("", false) // unnamed checks are ignored
+
+	stats := c.Stats(time.Second)
+	if len(stats.Checks) != 1 {
+		t.Fatalf("expected 1 check, got %v", stats.Checks)
+	}
+	got := stats.Checks["status ok"]
+	if got.Passes != 3 || got.Fails != 1 || got.PassRate != 0.75 {
+		t.Fatalf("unexpected check stats %+v", got)
+	}
+}
+
+func TestCorrectedLatencyIncludesQueueDelay(t *testing.T) {
+	c := metrics.NewCollector()
+	for i := 0; i < 99; i++ {
+		c.RecordRequest(10*time.Millisecond, nil, &metrics.RequestMetadata{Protocol: "http"})
+	}
+	c.RecordRequest(10*time.Millisecond, nil, &metrics.RequestMetadata{Protocol: "http", QueueDelay: 490 * time.Millisecond})
+
+	stats := c.Stats(time.Second)
+	if stats.MaxLatency > 11*time.Millisecond {
+		t.Fatalf("expected uncorrected latency to exclude queue delay, got max %s", stats.MaxLatency)
+	}
+	if stats.Corrected == nil {
+		t.Fatal("expected corrected stats when a request queued")
+	}
+	if stats.Corrected.Total != 100 {
+		t.Fatalf("expected corrected histogram to see every request, got %d", stats.Corrected.Total)
+	}
+	if got := stats.Corrected.MaxLatency; got < 490*time.Millisecond || got > 510*time.Millisecond {
+		t.Fatalf("expected corrected max near 500ms, got %s", got)
+	}
+}
+
+func TestCorrectedLatencyOmittedWithoutQueueing(t *testing.T) {
+	c := metrics.NewCollector()
+	c.RecordRequest(10*time.Millisecond, nil, &metrics.RequestMetadata{Protocol: "http"})
+	if stats := c.Stats(time.Second); stats.Corrected != nil {
+		t.Fatalf("expected no corrected stats, got %+v", stats.Corrected)
+	}
+}
+
 func TestCollectorTracksExactStatusBuckets(t *testing.T) {
 	c := metrics.NewCollector()
 	protocol := "http"
@@ -0,0 +1,252 @@
+package metrics
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
+)
+
+// Measurements is a serializable copy of everything a Collector recorded.
+// Unlike Stats it keeps the raw HDR histograms, so measurements taken by
+// several processes can be merged without losing percentile accuracy.
+type Measurements struct {
+	Total     BucketMeasurements            `json:"total"`
+	Corrected *BucketMeasurements           `json:"corrected,omitempty"`
+	Endpoints map[string]BucketMeasurements `json:"endpoints,omitempty"`
+	Steps     map[string]BucketMeasurements `json:"steps,omitempty"`
+	Journeys  map[string]BucketMeasurements `json:"journeys,omitempty"`
+	Timings   map[string]BucketMeasurements `json:"timings,omitempty"`
+	Checks    map[string]CheckStats         `json:"checks,omitempty"`
+	Phases    map[string]BucketMeasurements `json:"phases,omitempty"`
+	// EndpointPhases is keyed by endpoint, then phase.
+	EndpointPhases  map[string]map[string]BucketMeasurements `json:"endpoint_phases,omitempty"`
+	ProtocolMetrics map[string]map[string]interface{}        `json:"protocol_metrics,omitempty"`
+}
+
+// BucketMeasurements holds the raw state of one latency bucket. Histogram is
+// the base64 V2 compressed HDR histogram encoding, in microseconds.
+type BucketMeasurements struct {
+	Histogram     string                      `json:"histogram"`
+	Successes     int64                       `json:"successes"`
+	Failures      int64                       `json:"failures"`
+	MinLatency    time.Duration               `json:"min_latency"`
+	MaxLatency    time.Duration               `json:"max_latency"`
+	SumLatency    time.Duration               `json:"sum_latency"`
+	StatusBuckets map[string]map[string]int64 `json:"status_buckets,omitempty"`
+}
+
+// Measurements exports everything recorded so far.
+func (c *Collector) Measurements() (*Measurements, error) {
+	total, err := c.total.export()
+	if err != nil {
+		return nil, fmt.Errorf("total: %w", err)
+	}
+	m := &Measurements{Total: total}
+
+	if c.queued.Load() > 0 {
+		corrected, err := c.corrected.export()
+		if err != nil {
+			return nil, fmt.Errorf("corrected: %w", err)
+		}
+		m.Corrected = &corrected
+	}
+
+	groups := []struct {
+		name string
+		src  *sync.Map
+		dst  *map[string]BucketMeasurements
+	}{
+		{"endpoints", &c.endpoints, &m.Endpoints},
+		{"steps", &c.steps, &m.Steps},
+		{"journeys", &c.journeys, &m.Journeys},
+		{"timings", &c.timings, &m.Timings},
+		{"phases", &c.phases, &m.Phases},
+	}
+	for _, group := range groups {
+		exported, err := exportStatsMap(group.src)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", group.name, err)
+		}
+		*group.dst = exported
+	}
+
+	c.endpointPhases.Range(func(key, value interface{}) bool {
+		k := key.(phaseKey)
+		var bucket BucketMeasurements
+		if bucket, err = value.(*shardedStats).export(); err != nil {
+			err = fmt.Errorf("endpoint_phases: %s %s: %w", k.endpoint, k.phase, err)
+			return false
+		}
+		if m.EndpointPhases == nil {
+			m.EndpointPhases = make(map[string]map[string]BucketMeasurements)
+		}
+		if m.EndpointPhases[k.endpoint] == nil {
+			m.EndpointPhases[k.endpoint] = make(map[string]BucketMeasurements)
+		}
+		m.EndpointPhases[k.endpoint][k.phase] = bucket
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.checks.Range(func(key, value interface{}) bool {
+		if m.Checks == nil {
+			m.Checks = make(map[string]CheckStats)
+		}
+		counter := value.(*checkCounter)
+		m.Checks[key.(string)] = CheckStats{Passes: counter.passes.Load(), Fails: counter.fails.Load()}
+		return true
+	})
+
+	c.customMu.Lock()
+	if len(c.customMetrics) > 0 {
+		m.ProtocolMetrics = make(map[string]map[string]interface{}, len(c.customMetrics))
+		for protocol, metrics := range c.customMetrics {
+			m.ProtocolMetrics[protocol] = copyMetrics(metrics)
+		}
+	}
+	c.customMu.Unlock()
+
+	return m, nil
+}
+
+// Merge adds measurements exported by another collector to this one.
+// Protocol metrics are summed like values recorded locally.
+func (c *Collector) Merge(m *Measurements) error {
+	if m == nil {
+		return nil
+	}
+	if err := c.total.importBucket(m.Total); err != nil {
+		return fmt.Errorf("total: %w", err)
+	}
+	if m.Corrected != nil {
+		if err := c.corrected.importBucket(*m.Corrected); err != nil {
+			return fmt.Errorf("corrected: %w", err)
+		}
+		c.queued.Add(1)
+	}
+
+	groups := []struct {
+		name string
+		dst  *sync.Map
+		src  map[string]BucketMeasurements
+	}{
+		{"endpoints", &c.endpoints, m.Endpoints},
+		{"steps", &c.steps, m.Steps},
+		{"journeys", &c.journeys, m.Journeys},
+		{"timings", &c.timings, m.Timings},
+		{"phases", &c.phases, m.Phases},
+	}
+	for _, group := range groups {
+		for name, bucket := range group.src {
+			v, _ := group.dst.LoadOrStore(name, newShardedStats())
+			if err := v.(*shardedStats).importBucket(bucket); err != nil {
+				return fmt.Errorf("%s %s: %w", group.name, name, err)
+			}
+		}
+	}
+
+	for endpoint, phases := range m.EndpointPhases {
+		for phase, bucket := range phases {
+			v, _ := c.endpointPhases.LoadOrStore(phaseKey{endpoint: endpoint, phase: phase}, newShardedStats())
+			if err := v.(*shardedStats).importBucket(bucket); err != nil {
+				return fmt.Errorf("endpoint_phases %s %s: %w", endpoint, phase, err)
+			}
+		}
+	}
+
+	for name, check := range m.Checks {
+		v, _ := c.checks.LoadOrStore(name, &checkCounter{})
+		v.(*checkCounter).passes.Add(check.Passes)
+		v.(*checkCounter).fails.Add(check.Fails)
+	}
+
+	c.customMu.Lock()
+	for protocol, metrics := range m.ProtocolMetrics {
+		if c.customMetrics[protocol] == nil {
+			c.customMetrics[protocol] = make(map[string]interface{})
+		}
+		for key, value := range metrics {
+			c.aggregateMetric(protocol, key, value)
+		}
+	}
+	c.customMu.Unlock()
+
+	return nil
+}
+
+// Stats rebuilds the stats of a run from its measurements, as if they had been
+// recorded over duration. Percentiles come from the merged histograms, so
+// any quantile can be computed after the fact with EndpointStats.Percentile.
+func (m *Measurements) Stats(duration time.Duration) (Stats, error) {
+	c := NewCollector()
+	if err := c.Merge(m); err != nil {
+		return Stats{}, err
+	}
+	return c.Stats(duration), nil
+}
+
+// exportStatsMap exports a map[string]*shardedStats.
+func exportStatsMap(m *sync.Map) (map[string]BucketMeasurements, error) {
+	var out map[string]BucketMeasurements
+	var err error
+	m.Range(func(key, value interface{}) bool {
+		var bucket BucketMeasurements
+		if bucket, err = value.(*shardedStats).export(); err != nil {
+			err = fmt.Errorf("%s: %w", key, err)
+			return false
+		}
+		if out == nil {
+			out = make(map[string]BucketMeasurements)
+		}
+		out[key.(string)] = bucket
+		return true
+	})
+	return out, err
+}
+
+func (s *shardedStats) export() (BucketMeasurements, error) {
+	agg := s.merged()
+	encoded, err := agg.hist.Encode(hdrhistogram.V2CompressedEncodingCookieBase)
+	if err != nil {
+		return BucketMeasurements{}, fmt.Errorf("encode histogram: %w", err)
+	}
+	return BucketMeasurements{
+		Histogram:     string(encoded),
+		Successes:     agg.successes,
+		Failures:      agg.failures,
+		MinLatency:    agg.minLatency,
+		MaxLatency:    agg.maxLatency,
+		SumLatency:    agg.sumLatency,
+		StatusBuckets: agg.statusBuckets,
+	}, nil
+}
+
+func (s *shardedStats) importBucket(m BucketMeasurements) error {
+	bucket := &statsBucket{
+		successes:     m.Successes,
+		failures:      m.Failures,
+		minLatency:    m.MinLatency,
+		maxLatency:    m.MaxLatency,
+		sumLatency:    m.SumLatency,
+		statusBuckets: m.StatusBuckets,
+	}
+	if m.Histogram == "" {
+		bucket.hist = newStatsBucket().hist
+	} else {
+		hist, err := hdrhistogram.Decode([]byte(m.Histogram))
+		if err != nil {
+			return fmt.Errorf("decode histogram: %w", err)
+		}
+		bucket.hist = hist
+	}
+
+	sh := s.shards[0]
+	sh.mu.Lock()
+	sh.bucket.merge(bucket)
+	sh.mu.Unlock()
+	return nil
+}
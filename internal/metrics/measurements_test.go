@@ -0,0 +1,101 @@
+package metrics_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/torosent/crankfire/internal/metrics"
+)
+
+func TestMergeMeasurementsMatchesSingleCollector(t *testing.T) {
+	combined := metrics.NewCollector()
+	parts := []*metrics.Collector{metrics.NewCollector(), metrics.NewCollector()}
+	record := func(i int, latency time.Duration, err error, meta *metrics.RequestMetadata) {
+		combined.RecordRequest(latency, err, meta)
+		parts[i%2].RecordRequest(latency, err, meta)
+	}
+	for i := 1; i <= 200; i++ {
+		meta := &metrics.RequestMetadata{Endpoint: "users", Protocol: "http", CustomMetrics: map[string]interface{}{"bytes": int64(10)}}
+		var err error
+		if i%25 == 0 {
+			err = errors.New("boom")
+			meta.StatusCode = "503"
+		}
+		record(i, time.Duration(i)*time.Millisecond, err, meta)
+		combined.RecordPhase("users", metrics.PhaseTTFB, time.Duration(i)*time.Millisecond)
+		parts[i%2].RecordPhase("users", metrics.PhaseTTFB, time.Duration(i)*time.Millisecond)
+	}
+	parts[0].RecordCheck("status ok", true)
+	parts[1].RecordCheck("status ok", false)
+
+	merged := metrics.NewCollector()
+	for _, part := range parts {
+		exported, err := part.Measurements()
+		if err != nil {
+			t.Fatalf("Measurements() error = %v", err)
+		}
+		// Round-trip through JSON as agents do.
+		data, err := json.Marshal(exported)
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+		var decoded metrics.Measurements
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+		if err := merged.Merge(&decoded); err != nil {
+			t.Fatalf("Merge() error = %v", err)
+		}
+	}
+
+	want := combined.Stats(time.Second)
+	got := merged.Stats(time.Second)
+	if got.Total != want.Total || got.Failures != want.Failures {
+		t.Fatalf("totals = %d/%d, want %d/%d", got.Total, got.Failures, want.Total, want.Failures)
+	}
+	if got.P50Latency != want.P50Latency || got.P99Latency != want.P99Latency || got.MeanLatency != want.MeanLatency {
+		t.Fatalf("latency = p50 %s p99 %s mean %s, want p50 %s p99 %s mean %s",
+			got.P50Latency, got.P99Latency, got.MeanLatency, want.P50Latency, want.P99Latency, want.MeanLatency)
+	}
+	if got.MinLatency != time.Millisecond || got.MaxLatency != 200*time.Millisecond {
+		t.Fatalf("min/max = %s/%s", got.MinLatency, got.MaxLatency)
+	}
+	if got.StatusBuckets["http"]["503"] != 8 {
+		t.Fatalf("status buckets = %v", got.StatusBuckets)
+	}
+	if got.Endpoints["users"].Total != 200 {
+		t.Fatalf("endpoint stats = %+v", got.Endpoints["users"])
+	}
+	if ttfb := got.EndpointPhases["users"][metrics.PhaseTTFB]; ttfb.Total != 200 || ttfb.P99Latency != want.Phases[metrics.PhaseTTFB].P99Latency {
+		t.Fatalf("endpoint ttfb = %+v", ttfb)
+	}
+	if got.Phases[metrics.PhaseTTFB].Total != 200 {
+		t.Fatalf("phases = %+v", got.Phases)
+	}
+	if check := got.Checks["status ok"]; check.Passes != 1 || check.Fails != 1 {
+		t.Fatalf("check stats = %+v", check)
+	}
+	if bytes := got.ProtocolMetrics["http"]["bytes"]; bytes != float64(2000) {
+		t.Fatalf("protocol metrics bytes = %v", bytes)
+	}
+}
+
+func TestMergeMeasurementsKeepsCorrectedLatency(t *testing.T) {
+	agent := metrics.NewCollector()
+	agent.RecordRequest(10*time.Millisecond, nil, &metrics.RequestMetadata{QueueDelay: 90 * time.Millisecond})
+	exported, err := agent.Measurements()
+	if err != nil {
+		t.Fatalf("Measurements() error = %v", err)
+	}
+
+	merged := metrics.NewCollector()
+	if err := merged.Merge(exported); err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	stats := merged.Stats(time.Second)
+	if stats.Corrected == nil || stats.Corrected.MaxLatency != 100*time.Millisecond {
+		t.Fatalf("expected corrected latency to survive the merge, got %+v", stats.Corrected)
+	}
+}
@@ -0,0 +1,227 @@
+package metrics
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// otlpScope identifies crankfire as the producer of exported metrics.
+var otlpScope = instrumentation.Scope{Name: "github.com/torosent/crankfire"}
+
+// otlpSeries describes how a per-name breakdown is exported.
+type otlpSeries struct {
+	prefix string // metric name prefix, e.g. "crankfire.endpoint"
+	attr   string // attribute carrying the breakdown name
+	noun   string // used in descriptions
+	src    *sync.Map
+}
+
+// Produce returns the current measurements as cumulative OpenTelemetry
+// metrics, so the collector can back an OTLP periodic reader. It implements
+// go.opentelemetry.io/otel/sdk/metric.Producer and is safe to call while
+// requests are being recorded.
+func (c *Collector) Produce(context.Context) ([]metricdata.ScopeMetrics, error) {
+	now := time.Now()
+	c.startMu.Lock()
+	start := now
+	if c.started {
+		start = c.startTime
+	}
+	c.startMu.Unlock()
+
+	o := &otlpBuilder{start: start, now: now}
+
+	total := c.total.merged()
+	o.counter("crankfire.requests", "Requests completed, by result.", "{request}",
+		o.resultPoints(nil, total)...)
+	o.histogram("crankfire.request.duration", "Request latency.", o.histogramPoint(nil, total))
+
+	if len(total.statusBuckets) > 0 {
+		var points []metricdata.DataPoint[int64]
+		for _, protocol := range sortedKeys(total.statusBuckets) {
+			buckets := total.statusBuckets[protocol]
+			for _, status := range sortedKeys(buckets) {
+				points = append(points, o.point(buckets[status],
+					attribute.String("protocol", protocol), attribute.String("status", status)))
+			}
+		}
+		o.counter("crankfire.failures", "Failed requests by protocol and status code.", "{request}", points...)
+	}
+
+	if c.queued.Load() > 0 {
+		o.histogram("crankfire.request.corrected_duration",
+			"Request latency measured from the intended start time (corrected for coordinated omission).",
+			o.histogramPoint(nil, c.corrected.merged()))
+	}
+
+	series := []otlpSeries{
+		{"crankfire.endpoint", "endpoint", "endpoint", &c.endpoints},
+		{"crankfire.step", "step", "scenario step", &c.steps},
+		{"crankfire.journey", "scenario", "scenario iteration", &c.journeys},
+	}
+	for _, s := range series {
+		buckets := mergedStatsMap(s.src)
+		if len(buckets) == 0 {
+			continue
+		}
+		var counts []metricdata.DataPoint[int64]
+		var durations []metricdata.HistogramDataPoint[float64]
+		for _, name := range sortedKeys(buckets) {
+			attr := attribute.String(s.attr, name)
+			counts = append(counts, o.resultPoints([]attribute.KeyValue{attr}, buckets[name])...)
+			durations = append(durations, o.histogramPoint([]attribute.KeyValue{attr}, buckets[name]))
+		}
+		o.counter(s.prefix+".requests", "Completed requests per "+s.noun+", by result.", "{request}", counts...)
+		o.histogram(s.prefix+".duration", "Latency per "+s.noun+".", durations...)
+	}
+
+	if timings := mergedStatsMap(&c.timings); len(timings) > 0 {
+		var points []metricdata.HistogramDataPoint[float64]
+		for _, name := range sortedKeys(timings) {
+			points = append(points, o.histogramPoint([]attribute.KeyValue{attribute.String("timing", name)}, timings[name]))
+		}
+		o.histogram("crankfire.timing", "Named timings reported by protocol clients.", points...)
+	}
+
+	var checks []string
+	counters := make(map[string]*checkCounter)
+	c.checks.Range(func(key, value interface{}) bool {
+		checks = append(checks, key.(string))
+		counters[key.(string)] = value.(*checkCounter)
+		return true
+	})
+	if len(checks) > 0 {
+		sort.Strings(checks)
+		var points []metricdata.DataPoint[int64]
+		for _, name := range checks {
+			check := attribute.String("check", name)
+			points = append(points,
+				o.point(counters[name].passes.Load(), check, attribute.String("result", "pass")),
+				o.point(counters[name].fails.Load(), check, attribute.String("result", "fail")))
+		}
+		o.counter("crankfire.checks", "Response check outcomes, by result.", "{check}", points...)
+	}
+
+	c.customMu.Lock()
+	protocolMetrics := make(map[string]map[string]interface{}, len(c.customMetrics))
+	for protocol, metrics := range c.customMetrics {
+		protocolMetrics[protocol] = copyMetrics(metrics)
+	}
+	c.customMu.Unlock()
+	for _, protocol := range sortedKeys(protocolMetrics) {
+		metrics := protocolMetrics[protocol]
+		for _, key := range sortedKeys(metrics) {
+			o.protocolMetric("crankfire."+protocol+"."+key, metrics[key])
+		}
+	}
+
+	return []metricdata.ScopeMetrics{{Scope: otlpScope, Metrics: o.metrics}}, nil
+}
+
+// otlpBuilder accumulates the metrics of one Produce call.
+type otlpBuilder struct {
+	start, now time.Time
+	metrics    []metricdata.Metrics
+}
+
+func (o *otlpBuilder) point(value int64, attrs ...attribute.KeyValue) metricdata.DataPoint[int64] {
+	return metricdata.DataPoint[int64]{
+		Attributes: attribute.NewSet(attrs...),
+		StartTime:  o.start,
+		Time:       o.now,
+		Value:      value,
+	}
+}
+
+// resultPoints splits b's request count into success and failure points.
+func (o *otlpBuilder) resultPoints(attrs []attribute.KeyValue, b *statsBucket) []metricdata.DataPoint[int64] {
+	success := append(attrs[:len(attrs):len(attrs)], attribute.String("result", "success"))
+	failure := append(attrs[:len(attrs):len(attrs)], attribute.String("result", "failure"))
+	return []metricdata.DataPoint[int64]{
+		o.point(b.successes, success...),
+		o.point(b.failures, failure...),
+	}
+}
+
+func (o *otlpBuilder) histogramPoint(attrs []attribute.KeyValue, b *statsBucket) metricdata.HistogramDataPoint[float64] {
+	point := metricdata.HistogramDataPoint[float64]{
+		Attributes:   attribute.NewSet(attrs...),
+		StartTime:    o.start,
+		Time:         o.now,
+		Count:        uint64(b.hist.TotalCount()),
+		Bounds:       prometheusBuckets,
+		BucketCounts: b.bucketCounts(prometheusBuckets),
+		Sum:          b.sumLatency.Seconds(),
+	}
+	if point.Count > 0 {
+		point.Min = metricdata.NewExtrema(b.minLatency.Seconds())
+		point.Max = metricdata.NewExtrema(b.maxLatency.Seconds())
+	}
+	return point
+}
+
+func (o *otlpBuilder) counter(name, description, unit string, points ...metricdata.DataPoint[int64]) {
+	o.metrics = append(o.metrics, metricdata.Metrics{
+		Name:        name,
+		Description: description,
+		Unit:        unit,
+		Data: metricdata.Sum[int64]{
+			DataPoints:  points,
+			Temporality: metricdata.CumulativeTemporality,
+			IsMonotonic: true,
+		},
+	})
+}
+
+func (o *otlpBuilder) histogram(name, description string, points ...metricdata.HistogramDataPoint[float64]) {
+	o.metrics = append(o.metrics, metricdata.Metrics{
+		Name:        name,
+		Description: description,
+		Unit:        "s",
+		Data: metricdata.Histogram[float64]{
+			DataPoints:  points,
+			Temporality: metricdata.CumulativeTemporality,
+		},
+	})
+}
+
+// protocolMetric exports a protocol-specific metric: [Gauge]s are the latest
+// reading, other numbers running totals.
+func (o *otlpBuilder) protocolMetric(name string, value interface{}) {
+	metric := metricdata.Metrics{Name: name, Description: "Protocol-specific metric."}
+	switch v := value.(type) {
+	case Gauge:
+		metric.Data = metricdata.Gauge[float64]{DataPoints: []metricdata.DataPoint[float64]{{
+			StartTime: o.start, Time: o.now, Value: float64(v),
+		}}}
+	case float64:
+		metric.Data = metricdata.Sum[float64]{
+			DataPoints: []metricdata.DataPoint[float64]{{
+				StartTime: o.start, Time: o.now, Value: v,
+			}},
+			Temporality: metricdata.CumulativeTemporality,
+			IsMonotonic: true,
+		}
+	case int:
+		metric.Data = o.total(int64(v))
+	case int64:
+		metric.Data = o.total(v)
+	default:
+		return
+	}
+	o.metrics = append(o.metrics, metric)
+}
+
+func (o *otlpBuilder) total(value int64) metricdata.Sum[int64] {
+	return metricdata.Sum[int64]{
+		DataPoints:  []metricdata.DataPoint[int64]{o.point(value)},
+		Temporality: metricdata.CumulativeTemporality,
+		IsMonotonic: true,
+	}
+}
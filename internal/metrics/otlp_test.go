@@ -0,0 +1,98 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/torosent/crankfire/internal/metrics"
+)
+
+func TestProduceExportsCollectorMetrics(t *testing.T) {
+	c := metrics.NewCollector()
+	c.Start()
+	c.RecordRequest(2*time.Millisecond, nil, &metrics.RequestMetadata{Endpoint: "list", Protocol: "http"})
+	c.RecordRequest(3*time.Second, errors.New("boom"), &metrics.RequestMetadata{
+		Endpoint:   "list",
+		Protocol:   "http",
+		StatusCode: "503",
+	})
+	c.RecordRequest(time.Millisecond, nil, &metrics.RequestMetadata{
+		Protocol: "websocket",
+		CustomMetrics: map[string]interface{}{
+			"messages_sent":     int64(3),
+			"compression_ratio": metrics.Gauge(0.5),
+		},
+	})
+
+	scopes, err := c.Produce(context.Background())
+	if err != nil {
+		t.Fatalf("Produce: %v", err)
+	}
+	if len(scopes) != 1 {
+		t.Fatalf("got %d scopes, want 1", len(scopes))
+	}
+	byName := make(map[string]metricdata.Metrics)
+	for _, m := range scopes[0].Metrics {
+		byName[m.Name] = m
+	}
+
+	requests := byName["crankfire.requests"].Data.(metricdata.Sum[int64])
+	if !requests.IsMonotonic || requests.Temporality != metricdata.CumulativeTemporality {
+		t.Errorf("crankfire.requests = %+v, want cumulative monotonic sum", requests)
+	}
+	if got := sumValue(requests, attribute.String("result", "success")); got != 2 {
+		t.Errorf("successes = %d, want 2", got)
+	}
+	if got := sumValue(requests, attribute.String("result", "failure")); got != 1 {
+		t.Errorf("failures = %d, want 1", got)
+	}
+
+	duration := byName["crankfire.request.duration"].Data.(metricdata.Histogram[float64])
+	point := duration.DataPoints[0]
+	if point.Count != 3 {
+		t.Errorf("duration count = %d, want 3", point.Count)
+	}
+	if len(point.BucketCounts) != len(point.Bounds)+1 {
+		t.Errorf("got %d bucket counts for %d bounds", len(point.BucketCounts), len(point.Bounds))
+	}
+	if max, ok := point.Max.Value(); !ok || max != 3 {
+		t.Errorf("duration max = %v, want 3", max)
+	}
+
+	failures := byName["crankfire.failures"].Data.(metricdata.Sum[int64])
+	if got := sumValue(failures, attribute.String("protocol", "http"), attribute.String("status", "503")); got != 1 {
+		t.Errorf("http 503 failures = %d, want 1", got)
+	}
+
+	endpoint := byName["crankfire.endpoint.requests"].Data.(metricdata.Sum[int64])
+	if got := sumValue(endpoint, attribute.String("endpoint", "list"), attribute.String("result", "success")); got != 1 {
+		t.Errorf("endpoint successes = %d, want 1", got)
+	}
+	if _, ok := byName["crankfire.endpoint.duration"].Data.(metricdata.Histogram[float64]); !ok {
+		t.Error("missing crankfire.endpoint.duration histogram")
+	}
+
+	sent := byName["crankfire.websocket.messages_sent"].Data.(metricdata.Sum[int64])
+	if sent.DataPoints[0].Value != 3 {
+		t.Errorf("websocket messages_sent = %d, want 3", sent.DataPoints[0].Value)
+	}
+	ratio := byName["crankfire.websocket.compression_ratio"].Data.(metricdata.Gauge[float64])
+	if ratio.DataPoints[0].Value != 0.5 {
+		t.Errorf("websocket compression_ratio = %v, want 0.5", ratio.DataPoints[0].Value)
+	}
+}
+
+func sumValue(sum metricdata.Sum[int64], attrs ...attribute.KeyValue) int64 {
+	want := attribute.NewSet(attrs...)
+	for _, dp := range sum.DataPoints {
+		if dp.Attributes.Equals(&want) {
+			return dp.Value
+		}
+	}
+	return -1
+}
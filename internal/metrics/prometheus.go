@@ -0,0 +1,268 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PrometheusContentType is the media type of the output of
+// [Collector.WritePrometheus].
+const PrometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// prometheusBuckets are the latency histogram bucket upper bounds in seconds.
+var prometheusBuckets = []float64{
+	0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60,
+}
+
+// promGroup describes how a per-name breakdown is exposed.
+type promGroup struct {
+	prefix string // metric name prefix, e.g. "crankfire_endpoint"
+	label  string // label carrying the breakdown name
+	noun   string // used in HELP texts
+	src    *sync.Map
+}
+
+// WritePrometheus writes the current measurements in the Prometheus text
+// exposition format. Every series carries constLabels in addition to its own
+// labels. It is safe to call while requests are being recorded.
+func (c *Collector) WritePrometheus(w io.Writer, constLabels map[string]string) error {
+	p := &promWriter{w: bufio.NewWriter(w), constLabels: sortedLabels(constLabels)}
+
+	c.startMu.Lock()
+	var elapsed time.Duration
+	if c.started {
+		elapsed = time.Since(c.startTime)
+	}
+	c.startMu.Unlock()
+
+	p.family("crankfire_elapsed_seconds", "gauge", "Time since the test started.")
+	p.sample("crankfire_elapsed_seconds", nil, elapsed.Seconds())
+
+	total := c.total.merged()
+	p.family("crankfire_requests_total", "counter", "Requests completed, by result.")
+	p.sample("crankfire_requests_total", []string{"result", "success"}, float64(total.successes))
+	p.sample("crankfire_requests_total", []string{"result", "failure"}, float64(total.failures))
+	p.family("crankfire_request_duration_seconds", "histogram", "Request latency.")
+	p.histogram("crankfire_request_duration_seconds", nil, total)
+
+	if len(total.statusBuckets) > 0 {
+		p.family("crankfire_failures_by_status_total", "counter", "Failed requests by protocol and status code.")
+		for _, protocol := range sortedKeys(total.statusBuckets) {
+			buckets := total.statusBuckets[protocol]
+			for _, status := range sortedKeys(buckets) {
+				p.sample("crankfire_failures_by_status_total", []string{"protocol", protocol, "status", status}, float64(buckets[status]))
+			}
+		}
+	}
+
+	if c.queued.Load() > 0 {
+		p.family("crankfire_corrected_request_duration_seconds", "histogram", "Request latency measured from the intended start time (corrected for coordinated omission).")
+		p.histogram("crankfire_corrected_request_duration_seconds", nil, c.corrected.merged())
+	}
+
+	groups := []promGroup{
+		{"crankfire_endpoint", "endpoint", "endpoint", &c.endpoints},
+		{"crankfire_step", "step", "scenario step", &c.steps},
+		{"crankfire_journey", "scenario", "scenario iteration", &c.journeys},
+	}
+	for _, group := range groups {
+		buckets := mergedStatsMap(group.src)
+		if len(buckets) == 0 {
+			continue
+		}
+		names := sortedKeys(buckets)
+		p.family(group.prefix+"_requests_total", "counter", fmt.Sprintf("Completed requests per %s, by result.", group.noun))
+		for _, name := range names {
+			p.sample(group.prefix+"_requests_total", []string{group.label, name, "result", "success"}, float64(buckets[name].successes))
+			p.sample(group.prefix+"_requests_total", []string{group.label, name, "result", "failure"}, float64(buckets[name].failures))
+		}
+		p.family(group.prefix+"_duration_seconds", "histogram", fmt.Sprintf("Latency per %s.", group.noun))
+		for _, name := range names {
+			p.histogram(group.prefix+"_duration_seconds", []string{group.label, name}, buckets[name])
+		}
+	}
+
+	if timings := mergedStatsMap(&c.timings); len(timings) > 0 {
+		p.family("crankfire_timing_seconds", "histogram", "Named timings reported by protocol clients.")
+		for _, name := range sortedKeys(timings) {
+			p.histogram("crankfire_timing_seconds", []string{"timing", name}, timings[name])
+		}
+	}
+
+	var checks []string
+	counters := make(map[string]*checkCounter)
+	c.checks.Range(func(key, value interface{}) bool {
+		checks = append(checks, key.(string))
+		counters[key.(string)] = value.(*checkCounter)
+		return true
+	})
+	if len(checks) > 0 {
+		sort.Strings(checks)
+		p.family("crankfire_checks_total", "counter", "Response check outcomes, by result.")
+		for _, name := range checks {
+			p.sample("crankfire_checks_total", []string{"check", name, "result", "pass"}, float64(counters[name].passes.Load()))
+			p.sample("crankfire_checks_total", []string{"check", name, "result", "fail"}, float64(counters[name].fails.Load()))
+		}
+	}
+
+	c.customMu.Lock()
+	protocolMetrics := make(map[string]map[string]interface{}, len(c.customMetrics))
+	for protocol, metrics := range c.customMetrics {
+		protocolMetrics[protocol] = copyMetrics(metrics)
+	}
+	c.customMu.Unlock()
+	p.protocolMetrics("crankfire_protocol_metric", "untyped", "Protocol-specific metrics, totalled across requests.", protocolMetrics, false)
+	p.protocolMetrics("crankfire_protocol_gauge", "gauge", "Protocol-specific readings, such as ratios; the latest value.", protocolMetrics, true)
+
+	return p.w.Flush()
+}
+
+// mergedStatsMap combines the shards of every entry in a
+// map[string]*shardedStats.
+func mergedStatsMap(m *sync.Map) map[string]*statsBucket {
+	var out map[string]*statsBucket
+	m.Range(func(key, value interface{}) bool {
+		if out == nil {
+			out = make(map[string]*statsBucket)
+		}
+		out[key.(string)] = value.(*shardedStats).merged()
+		return true
+	})
+	return out
+}
+
+func numericValue(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	case Gauge:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedLabels(labels map[string]string) []string {
+	var pairs []string
+	for _, name := range sortedKeys(labels) {
+		pairs = append(pairs, name, labels[name])
+	}
+	return pairs
+}
+
+// protocolMetrics writes the numeric protocol metrics that are gauges, or
+// those that are not, as one family. It writes nothing if there are none.
+func (p *promWriter) protocolMetrics(name, kind, help string, protocolMetrics map[string]map[string]interface{}, gauges bool) {
+	wrote := false
+	for _, protocol := range sortedKeys(protocolMetrics) {
+		metrics := protocolMetrics[protocol]
+		for _, key := range sortedKeys(metrics) {
+			_, isGauge := metrics[key].(Gauge)
+			value, ok := numericValue(metrics[key])
+			if !ok || isGauge != gauges {
+				continue
+			}
+			if !wrote {
+				p.family(name, kind, help)
+				wrote = true
+			}
+			p.sample(name, []string{"protocol", protocol, "metric", key}, value)
+		}
+	}
+}
+
+// promWriter formats samples; labels are passed as name/value pairs.
+type promWriter struct {
+	w           *bufio.Writer
+	constLabels []string
+}
+
+func (p *promWriter) family(name, kind, help string) {
+	fmt.Fprintf(p.w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func (p *promWriter) sample(name string, labels []string, value float64) {
+	p.w.WriteString(name)
+	all := append(append([]string(nil), p.constLabels...), labels...)
+	if len(all) > 0 {
+		p.w.WriteByte('{')
+		for i := 0; i < len(all); i += 2 {
+			if i > 0 {
+				p.w.WriteByte(',')
+			}
+			fmt.Fprintf(p.w, "%s=\"%s\"", all[i], escapeLabelValue(all[i+1]))
+		}
+		p.w.WriteByte('}')
+	}
+	p.w.WriteByte(' ')
+	p.w.WriteString(formatPromValue(value))
+	p.w.WriteByte('\n')
+}
+
+// histogram writes the cumulative buckets, sum and count of b's latencies.
+func (p *promWriter) histogram(name string, labels []string, b *statsBucket) {
+	counts := b.bucketCounts(prometheusBuckets)
+	var cumulative uint64
+	for i, bound := range prometheusBuckets {
+		cumulative += counts[i]
+		p.sample(name+"_bucket", append(labels[:len(labels):len(labels)], "le", formatPromValue(bound)), float64(cumulative))
+	}
+	count := b.hist.TotalCount()
+	p.sample(name+"_bucket", append(labels[:len(labels):len(labels)], "le", "+Inf"), float64(count))
+	p.sample(name+"_sum", labels, b.sumLatency.Seconds())
+	p.sample(name+"_count", labels, float64(count))
+}
+
+// bucketCounts distributes b's latencies over the given upper bounds in
+// seconds. The result has one more entry than bounds, for values above the
+// last bound.
+func (b *statsBucket) bucketCounts(bounds []float64) []uint64 {
+	counts := make([]uint64, len(bounds)+1)
+	for _, bar := range b.hist.Distribution() {
+		if bar.Count == 0 {
+			continue
+		}
+		upper := float64(bar.To) / 1e6 // microseconds to seconds
+		i := sort.SearchFloat64s(bounds, upper)
+		counts[i] += uint64(bar.Count)
+	}
+	return counts
+}
+
+func formatPromValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(v string) string {
+	return labelValueEscaper.Replace(v)
+}
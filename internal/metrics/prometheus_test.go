@@ -0,0 +1,58 @@
+package metrics_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/torosent/crankfire/internal/metrics"
+)
+
+func TestWritePrometheusExposesSeries(t *testing.T) {
+	c := metrics.NewCollector()
+	c.Start()
+	c.RecordRequest(2*time.Millisecond, nil, &metrics.RequestMetadata{Endpoint: "list", Protocol: "http"})
+	c.RecordRequest(40*time.Millisecond, nil, &metrics.RequestMetadata{Endpoint: "list", Protocol: "http"})
+	c.RecordRequest(3*time.Second, errors.New("boom"), &metrics.RequestMetadata{
+		Endpoint:      `say "hi"`,
+		Protocol:      "http",
+		StatusCode:    "503",
+		CustomMetrics: map[string]interface{}{"bytes": int64(512), "reuse": metrics.Gauge(0.75)},
+	})
+	c.RecordCheck("status 200", true)
+
+	var buf strings.Builder
+	if err := c.WritePrometheus(&buf, map[string]string{"test": "smoke"}); err != nil {
+		t.Fatalf("WritePrometheus: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"# TYPE crankfire_requests_total counter\n",
+		`crankfire_requests_total{test="smoke",result="success"} 2` + "\n",
+		`crankfire_requests_total{test="smoke",result="failure"} 1` + "\n",
+		"# TYPE crankfire_request_duration_seconds histogram\n",
+		`crankfire_request_duration_seconds_bucket{test="smoke",le="0.0025"} 1` + "\n",
+		`crankfire_request_duration_seconds_bucket{test="smoke",le="0.05"} 2` + "\n",
+		`crankfire_request_duration_seconds_bucket{test="smoke",le="2.5"} 2` + "\n",
+		`crankfire_request_duration_seconds_bucket{test="smoke",le="5"} 3` + "\n",
+		`crankfire_request_duration_seconds_bucket{test="smoke",le="+Inf"} 3` + "\n",
+		`crankfire_request_duration_seconds_count{test="smoke"} 3` + "\n",
+		`crankfire_request_duration_seconds_sum{test="smoke"} 3.042` + "\n",
+		`crankfire_failures_by_status_total{test="smoke",protocol="http",status="503"} 1` + "\n",
+		`crankfire_endpoint_requests_total{test="smoke",endpoint="list",result="success"} 2` + "\n",
+		`crankfire_endpoint_duration_seconds_count{test="smoke",endpoint="say \"hi\""} 1` + "\n",
+		`crankfire_checks_total{test="smoke",check="status 200",result="pass"} 1` + "\n",
+		`crankfire_protocol_metric{test="smoke",protocol="http",metric="bytes"} 512` + "\n",
+		"# TYPE crankfire_protocol_gauge gauge\n",
+		`crankfire_protocol_gauge{test="smoke",protocol="http",metric="reuse"} 0.75` + "\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "crankfire_corrected_request_duration_seconds") {
+		t.Errorf("corrected histogram should be omitted without queueing\n%s", out)
+	}
+}
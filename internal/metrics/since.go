@@ -0,0 +1,134 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
+)
+
+// Since returns what was recorded between prev and s, two Stats of the same
+// collector taken in that order, as if it had been recorded over
+// s.Duration - prev.Duration. Counts, checks and protocol totals are
+// differences, and percentiles come from the difference of the histograms.
+// Gauges keep their latest value. A zero prev returns everything in s.
+func (s Stats) Since(prev Stats) Stats {
+	elapsed := s.Duration - prev.Duration
+	out := Stats{
+		EndpointStats:   s.EndpointStats.since(prev.EndpointStats, elapsed),
+		Duration:        elapsed,
+		DurationMs:      float64(elapsed) / float64(time.Millisecond),
+		Endpoints:       sinceMap(s.Endpoints, prev.Endpoints, elapsed),
+		Steps:           sinceMap(s.Steps, prev.Steps, elapsed),
+		Journeys:        sinceMap(s.Journeys, prev.Journeys, elapsed),
+		Timings:         sinceMap(s.Timings, prev.Timings, elapsed),
+		Phases:          sinceMap(s.Phases, prev.Phases, elapsed),
+		ProtocolMetrics: sinceProtocolMetrics(s.ProtocolMetrics, prev.ProtocolMetrics),
+	}
+	if len(s.EndpointPhases) > 0 {
+		out.EndpointPhases = make(map[string]map[string]EndpointStats, len(s.EndpointPhases))
+		for endpoint, phases := range s.EndpointPhases {
+			out.EndpointPhases[endpoint] = sinceMap(phases, prev.EndpointPhases[endpoint], elapsed)
+		}
+	}
+	if s.Corrected != nil {
+		var base EndpointStats
+		if prev.Corrected != nil {
+			base = *prev.Corrected
+		}
+		corrected := s.Corrected.since(base, elapsed)
+		out.Corrected = &corrected
+	}
+	if len(s.Checks) > 0 {
+		out.Checks = make(map[string]CheckStats, len(s.Checks))
+		for name, check := range s.Checks {
+			base := prev.Checks[name]
+			delta := CheckStats{Passes: check.Passes - base.Passes, Fails: check.Fails - base.Fails}
+			if total := delta.Passes + delta.Fails; total > 0 {
+				delta.PassRate = float64(delta.Passes) / float64(total)
+			}
+			out.Checks[name] = delta
+		}
+	}
+	return out
+}
+
+// since returns the stats of the requests recorded after prev.
+func (e EndpointStats) since(prev EndpointStats, elapsed time.Duration) EndpointStats {
+	b := newStatsBucket()
+	b.successes = e.Successes - prev.Successes
+	b.failures = e.Failures - prev.Failures
+	b.sumLatency = e.MeanLatency*time.Duration(e.Total) - prev.MeanLatency*time.Duration(prev.Total)
+	if e.hist != nil {
+		b.hist = subtractHistogram(e.hist, prev.hist)
+	}
+	// Min and max of the window are only known to histogram precision.
+	if b.hist.TotalCount() > 0 {
+		b.minLatency = time.Duration(b.hist.Min()) * time.Microsecond
+		b.maxLatency = time.Duration(b.hist.Max()) * time.Microsecond
+	}
+	for protocol, buckets := range e.StatusBuckets {
+		for status, count := range buckets {
+			if delta := count - prev.StatusBuckets[protocol][status]; delta > 0 {
+				b.recordStatusCount(protocol, status, int64(delta))
+			}
+		}
+	}
+	return b.snapshot(elapsed)
+}
+
+func sinceMap(current, prev map[string]EndpointStats, elapsed time.Duration) map[string]EndpointStats {
+	if len(current) == 0 {
+		return nil
+	}
+	out := make(map[string]EndpointStats, len(current))
+	for name, stats := range current {
+		out[name] = stats.since(prev[name], elapsed)
+	}
+	return out
+}
+
+// subtractHistogram returns a histogram of the values recorded in h but not
+// in prev, an earlier copy of the same histogram.
+func subtractHistogram(h, prev *hdrhistogram.Histogram) *hdrhistogram.Histogram {
+	snap := h.Export()
+	if prev != nil {
+		base := prev.Export()
+		for i := range snap.Counts {
+			if i < len(base.Counts) {
+				snap.Counts[i] -= base.Counts[i]
+			}
+			if snap.Counts[i] < 0 {
+				snap.Counts[i] = 0
+			}
+		}
+	}
+	return hdrhistogram.Import(snap)
+}
+
+func sinceProtocolMetrics(current, prev map[string]map[string]interface{}) map[string]map[string]interface{} {
+	if len(current) == 0 {
+		return nil
+	}
+	out := make(map[string]map[string]interface{}, len(current))
+	for protocol, metrics := range current {
+		delta := make(map[string]interface{}, len(metrics))
+		for key, value := range metrics {
+			base := prev[protocol][key]
+			switch v := value.(type) {
+			case int:
+				b, _ := base.(int)
+				delta[key] = v - b
+			case int64:
+				b, _ := base.(int64)
+				delta[key] = v - b
+			case float64:
+				b, _ := base.(float64)
+				delta[key] = v - b
+			default:
+				delta[key] = v
+			}
+		}
+		out[protocol] = delta
+	}
+	return out
+}
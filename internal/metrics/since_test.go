@@ -0,0 +1,78 @@
+package metrics_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/torosent/crankfire/internal/metrics"
+)
+
+func TestStatsSinceCoversOnlyNewRequests(t *testing.T) {
+	c := metrics.NewCollector()
+	for i := 0; i < 100; i++ {
+		c.RecordRequest(time.Millisecond, nil, &metrics.RequestMetadata{
+			Endpoint:      "users",
+			Protocol:      "http",
+			CustomMetrics: map[string]interface{}{"bytes": int64(10), "reuse": metrics.Gauge(1)},
+		})
+		c.RecordCheck("status 200", true)
+	}
+	prev := c.Stats(10 * time.Second)
+
+	for i := 0; i < 10; i++ {
+		c.RecordRequest(500*time.Millisecond, errors.New("boom"), &metrics.RequestMetadata{
+			Endpoint:      "users",
+			Protocol:      "http",
+			StatusCode:    "503",
+			CustomMetrics: map[string]interface{}{"bytes": int64(10), "reuse": metrics.Gauge(0.5)},
+		})
+		c.RecordCheck("status 200", false)
+	}
+	got := c.Stats(12 * time.Second).Since(prev)
+
+	if got.Duration != 2*time.Second {
+		t.Errorf("Duration = %s, want 2s", got.Duration)
+	}
+	if got.Total != 10 || got.Failures != 10 || got.Successes != 0 {
+		t.Errorf("total/failures/successes = %d/%d/%d, want 10/10/0", got.Total, got.Failures, got.Successes)
+	}
+	if got.RequestsPerSec != 5 {
+		t.Errorf("RequestsPerSec = %v, want 5", got.RequestsPerSec)
+	}
+	if got.P50Latency < 450*time.Millisecond || got.MinLatency < 450*time.Millisecond {
+		t.Errorf("p50/min = %s/%s, want about 500ms", got.P50Latency, got.MinLatency)
+	}
+	if got.MeanLatency.Round(time.Millisecond) != 500*time.Millisecond {
+		t.Errorf("MeanLatency = %s, want 500ms", got.MeanLatency)
+	}
+	if users := got.Endpoints["users"]; users.Total != 10 || users.Failures != 10 {
+		t.Errorf("users total/failures = %d/%d, want 10/10", users.Total, users.Failures)
+	}
+	if n := got.StatusBuckets["http"]["503"]; n != 10 {
+		t.Errorf("503 failures = %d, want 10", n)
+	}
+	if check := got.Checks["status 200"]; check.Passes != 0 || check.Fails != 10 || check.PassRate != 0 {
+		t.Errorf("check = %+v, want 10 fails", check)
+	}
+	if bytes := got.ProtocolMetrics["http"]["bytes"]; bytes != int64(100) {
+		t.Errorf("bytes = %v, want 100", bytes)
+	}
+	if reuse := got.ProtocolMetrics["http"]["reuse"]; reuse != metrics.Gauge(0.5) {
+		t.Errorf("reuse = %v, want the latest gauge value 0.5", reuse)
+	}
+}
+
+func TestStatsSinceZeroIsEverything(t *testing.T) {
+	c := metrics.NewCollector()
+	for i := 0; i < 20; i++ {
+		c.RecordRequest(time.Duration(i+1)*time.Millisecond, nil, &metrics.RequestMetadata{Endpoint: "users"})
+	}
+	all := c.Stats(time.Second)
+	got := all.Since(metrics.Stats{})
+
+	if got.Total != all.Total || got.P95Latency != all.P95Latency || got.RequestsPerSec != all.RequestsPerSec {
+		t.Errorf("Since(zero) = total %d, p95 %s, rps %v; want %d, %s, %v",
+			got.Total, got.P95Latency, got.RequestsPerSec, all.Total, all.P95Latency, all.RequestsPerSec)
+	}
+}
@@ -0,0 +1,51 @@
+package output
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/torosent/crankfire/internal/baseline"
+)
+
+// PrintComparison writes the deltas of a run against its baseline. Only
+// deltas that changed beyond the tolerance are listed individually.
+func PrintComparison(w io.Writer, c *baseline.Comparison) {
+	if c == nil {
+		return
+	}
+	fmt.Fprintf(w, "\nBaseline Comparison (vs %s):\n", c.Baseline)
+	fmt.Fprintf(w, "  Tolerance: latency +%.0f%%, error rate +%.2f pp\n", c.Tolerance.Latency*100, c.Tolerance.ErrorRate*100)
+	for _, d := range c.Deltas {
+		if d.Verdict == baseline.VerdictUnchanged {
+			continue
+		}
+		fmt.Fprintf(w, "  %s %s %s\n", deltaMark(d.Verdict), deltaScope(d), formatDelta(d))
+	}
+	fmt.Fprintf(w, "  Verdict: %s\n", c.Verdict)
+}
+
+func deltaMark(verdict string) string {
+	switch verdict {
+	case baseline.VerdictRegressed:
+		return "✗"
+	case baseline.VerdictImproved:
+		return "✓"
+	default:
+		return " "
+	}
+}
+
+func deltaScope(d baseline.Delta) string {
+	if d.Endpoint == "" {
+		return "overall " + d.Metric
+	}
+	return d.Endpoint + " " + d.Metric
+}
+
+// formatDelta renders "baseline → current (change)".
+func formatDelta(d baseline.Delta) string {
+	if d.Metric == "error_rate" {
+		return fmt.Sprintf("%.2f%% → %.2f%% (%+.2f pp)", d.Baseline*100, d.Current*100, d.Change*100)
+	}
+	return fmt.Sprintf("%.2f → %.2f (%+.1f%%)", d.Baseline, d.Current, d.Percent)
+}
@@ -8,6 +8,7 @@ import (
 	"sort"
 	"time"
 
+	"github.com/torosent/crankfire/internal/baseline"
 	"github.com/torosent/crankfire/internal/metrics"
 	"github.com/torosent/crankfire/internal/threshold"
 )
@@ -21,13 +22,19 @@ type HTMLReportData struct {
 	ThresholdSummary *ThresholdSummary
 	HistoryJSON      string
 	EndpointNames    []string
-	Metadata         ReportMetadata
+	// PhaseNames lists the recorded connection phases in the order they
+	// occur; PhaseEndpoints the endpoints with phases, busiest first.
+	PhaseNames     []string
+	PhaseEndpoints []string
+	Metadata       ReportMetadata
 }
 
 // ReportMetadata contains configuration information about the test run.
 type ReportMetadata struct {
 	TargetURL       string
 	TestedEndpoints []TestedEndpoint
+	// Baseline is the run's comparison against a baseline run, if any.
+	Baseline *baseline.Comparison
 }
 
 // TestedEndpoint represents an endpoint configuration used in the test.
@@ -39,30 +46,7 @@ type TestedEndpoint struct {
 
 // GenerateHTMLReport generates a standalone HTML report with embedded charts.
 func GenerateHTMLReport(w io.Writer, stats metrics.Stats, history []metrics.DataPoint, thresholdResults []threshold.Result, metadata ReportMetadata) error {
-	// Prepare threshold summary
-	var thresholdSummary *ThresholdSummary
-	if len(thresholdResults) > 0 {
-		thresholdSummary = &ThresholdSummary{
-			Total:   len(thresholdResults),
-			Results: make([]ThresholdResultJSON, len(thresholdResults)),
-		}
-		for i, tr := range thresholdResults {
-			thresholdSummary.Results[i] = ThresholdResultJSON{
-				Threshold: tr.Threshold.Raw,
-				Metric:    tr.Threshold.Metric,
-				Aggregate: tr.Threshold.Aggregate,
-				Operator:  tr.Threshold.Operator,
-				Expected:  tr.Threshold.Value,
-				Actual:    tr.Actual,
-				Pass:      tr.Pass,
-			}
-			if tr.Pass {
-				thresholdSummary.Passed++
-			} else {
-				thresholdSummary.Failed++
-			}
-		}
-	}
+	thresholdSummary := newThresholdSummary(thresholdResults)
 
 	// Prepare endpoint names sorted by request count
 	endpointNames := make([]string, 0, len(stats.Endpoints))
@@ -73,6 +57,19 @@ func GenerateHTMLReport(w io.Writer, stats metrics.Stats, history []metrics.Data
 		return stats.Endpoints[endpointNames[i]].Total > stats.Endpoints[endpointNames[j]].Total
 	})
 
+	var phaseNames []string
+	for _, phase := range metrics.Phases {
+		if _, ok := stats.Phases[phase]; ok {
+			phaseNames = append(phaseNames, phase)
+		}
+	}
+	var phaseEndpoints []string
+	for _, name := range endpointNames {
+		if len(stats.EndpointPhases[name]) > 0 {
+			phaseEndpoints = append(phaseEndpoints, name)
+		}
+	}
+
 	// Convert history to JSON for embedding in HTML
 	historyJSON, err := json.Marshal(history)
 	if err != nil {
@@ -87,6 +84,8 @@ func GenerateHTMLReport(w io.Writer, stats metrics.Stats, history []metrics.Data
 		ThresholdSummary: thresholdSummary,
 		HistoryJSON:      string(historyJSON),
 		EndpointNames:    endpointNames,
+		PhaseNames:       phaseNames,
+		PhaseEndpoints:   phaseEndpoints,
 		Metadata:         metadata,
 	}
 
@@ -97,6 +96,9 @@ func GenerateHTMLReport(w io.Writer, stats metrics.Stats, history []metrics.Data
 		"formatFloat": func(f float64) string {
 			return fmt.Sprintf("%.2f", f)
 		},
+		"percent": func(f float64) float64 {
+			return f * 100
+		},
 		"formatPercent": func(part, total int64) string {
 			if total == 0 {
 				return "0.0"
@@ -372,12 +374,49 @@ const htmlTemplate = `<!DOCTYPE html>
                         <div class="value">{{formatDuration .Stats.P99Latency}}</div>
                     </div>
                 </div>
+                {{with .Stats.Corrected}}
+                <h3>Corrected for Coordinated Omission</h3>
+                <p>Measured from each request's intended start time, including time spent queued behind busy workers.</p>
+                <div class="latency-grid">
+                    <div class="latency-item">
+                        <div class="label">Min</div>
+                        <div class="value">{{formatDuration .MinLatency}}</div>
+                    </div>
+                    <div class="latency-item">
+                        <div class="label">Max</div>
+                        <div class="value">{{formatDuration .MaxLatency}}</div>
+                    </div>
+                    <div class="latency-item">
+                        <div class="label">Mean</div>
+                        <div class="value">{{formatDuration .MeanLatency}}</div>
+                    </div>
+                    <div class="latency-item">
+                        <div class="label">P50</div>
+                        <div class="value">{{formatDuration .P50Latency}}</div>
+                    </div>
+                    <div class="latency-item">
+                        <div class="label">P90</div>
+                        <div class="value">{{formatDuration .P90Latency}}</div>
+                    </div>
+                    <div class="latency-item">
+                        <div class="label">P95</div>
+                        <div class="value">{{formatDuration .P95Latency}}</div>
+                    </div>
+                    <div class="latency-item">
+                        <div class="label">P99</div>
+                        <div class="value">{{formatDuration .P99Latency}}</div>
+                    </div>
+                </div>
+                {{end}}
             </div>
 
             <!-- Thresholds -->
             {{if .ThresholdSummary}}
             <div class="section">
                 <h2>Thresholds ({{.ThresholdSummary.Passed}}/{{.ThresholdSummary.Total}} Passed)</h2>
+                {{if .ThresholdSummary.AbortReason}}
+                <p><span class="badge badge-error">RUN ABORTED</span> {{.ThresholdSummary.AbortReason}}</p>
+                {{end}}
                 <table>
                     <thead>
                         <tr>
@@ -398,6 +437,8 @@ const htmlTemplate = `<!DOCTYPE html>
                             <td>
                                 {{if .Pass}}
                                 <span class="badge badge-success">✓ PASS</span>
+                                {{else if .Aborted}}
+                                <span class="badge badge-error">✗ ABORTED</span>
                                 {{else}}
                                 <span class="badge badge-error">✗ FAIL</span>
                                 {{end}}
@@ -409,6 +450,54 @@ const htmlTemplate = `<!DOCTYPE html>
             </div>
             {{end}}
 
+            <!-- Baseline Comparison -->
+            {{with .Metadata.Baseline}}
+            <div class="section">
+                <h2>Baseline Comparison
+                    {{if eq .Verdict "regressed"}}<span class="badge badge-error">REGRESSED</span>{{else if eq .Verdict "improved"}}<span class="badge badge-success">IMPROVED</span>{{else}}<span class="badge badge-success">UNCHANGED</span>{{end}}
+                </h2>
+                <p>Compared with <code>{{.Baseline}}</code>; tolerance +{{printf "%.0f" (percent .Tolerance.Latency)}}% latency, +{{printf "%.2f" (percent .Tolerance.ErrorRate)}} pp error rate.</p>
+                <table>
+                    <thead>
+                        <tr>
+                            <th>Endpoint</th>
+                            <th>Metric</th>
+                            <th>Baseline</th>
+                            <th>Current</th>
+                            <th>Change</th>
+                            <th>Verdict</th>
+                        </tr>
+                    </thead>
+                    <tbody>
+                        {{range .Deltas}}
+                        <tr>
+                            <td>{{if .Endpoint}}{{.Endpoint}}{{else}}overall{{end}}</td>
+                            <td>{{.Metric}}</td>
+                            {{if eq .Metric "error_rate"}}
+                            <td>{{printf "%.2f%%" (percent .Baseline)}}</td>
+                            <td>{{printf "%.2f%%" (percent .Current)}}</td>
+                            <td>{{printf "%+.2f pp" (percent .Change)}}</td>
+                            {{else}}
+                            <td>{{formatFloat .Baseline}}</td>
+                            <td>{{formatFloat .Current}}</td>
+                            <td>{{printf "%+.1f%%" .Percent}}</td>
+                            {{end}}
+                            <td>
+                                {{if eq .Verdict "regressed"}}
+                                <span class="badge badge-error">✗ REGRESSED</span>
+                                {{else if eq .Verdict "improved"}}
+                                <span class="badge badge-success">✓ IMPROVED</span>
+                                {{else}}
+                                unchanged
+                                {{end}}
+                            </td>
+                        </tr>
+                        {{end}}
+                    </tbody>
+                </table>
+            </div>
+            {{end}}
+
             <!-- Endpoint Breakdown -->
             {{if .EndpointNames}}
             <div class="section">
@@ -441,6 +530,58 @@ const htmlTemplate = `<!DOCTYPE html>
             </div>
             {{end}}
 
+            <!-- Connection Phases -->
+            {{if .PhaseNames}}
+            <div class="section">
+                <h2>Connection Phases</h2>
+                <table>
+                    <thead>
+                        <tr>
+                            <th>Phase</th>
+                            <th>Samples</th>
+                            <th>P50</th>
+                            <th>P95</th>
+                            <th>P99</th>
+                            <th>Max</th>
+                        </tr>
+                    </thead>
+                    <tbody>
+                        {{range .PhaseNames}}
+                        {{$ps := index $.Stats.Phases .}}
+                        <tr>
+                            <td><strong>{{.}}</strong></td>
+                            <td>{{$ps.Total}}</td>
+                            <td>{{formatDuration $ps.P50Latency}}</td>
+                            <td>{{formatDuration $ps.P95Latency}}</td>
+                            <td>{{formatDuration $ps.P99Latency}}</td>
+                            <td>{{formatDuration $ps.MaxLatency}}</td>
+                        </tr>
+                        {{end}}
+                    </tbody>
+                </table>
+                {{if .PhaseEndpoints}}
+                <h3>P95 by Endpoint</h3>
+                <table>
+                    <thead>
+                        <tr>
+                            <th>Endpoint</th>
+                            {{range .PhaseNames}}<th>{{.}}</th>{{end}}
+                        </tr>
+                    </thead>
+                    <tbody>
+                        {{range .PhaseEndpoints}}
+                        {{$eps := index $.Stats.EndpointPhases .}}
+                        <tr>
+                            <td><strong>{{.}}</strong></td>
+                            {{range $.PhaseNames}}{{$ps := index $eps .}}<td>{{if $ps.Total}}{{formatDuration $ps.P95Latency}}{{else}}-{{end}}</td>{{end}}
+                        </tr>
+                        {{end}}
+                    </tbody>
+                </table>
+                {{end}}
+            </div>
+            {{end}}
+
             <!-- Configuration Details -->
             {{if .Metadata.TestedEndpoints}}
             <div class="section">
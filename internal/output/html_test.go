@@ -342,3 +342,69 @@ func TestGenerateHTMLReport_WithMetadata(t *testing.T) {
 		t.Errorf("HTML missing GetUsers endpoint details")
 	}
 }
+
+func TestGenerateHTMLReport_CorrectedLatency(t *testing.T) {
+	stats := metrics.Stats{
+		EndpointStats: metrics.EndpointStats{Total: 10, P99Latency: 20 * time.Millisecond},
+		Corrected:     &metrics.EndpointStats{Total: 10, P99Latency: 750 * time.Millisecond},
+		Duration:      time.Second,
+	}
+
+	var buf bytes.Buffer
+	if err := output.GenerateHTMLReport(&buf, stats, nil, nil, output.ReportMetadata{}); err != nil {
+		t.Fatalf("GenerateHTMLReport() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "Corrected for Coordinated Omission") {
+		t.Errorf("HTML missing corrected latency section")
+	}
+
+	buf.Reset()
+	stats.Corrected = nil
+	if err := output.GenerateHTMLReport(&buf, stats, nil, nil, output.ReportMetadata{}); err != nil {
+		t.Fatalf("GenerateHTMLReport() error = %v", err)
+	}
+	if strings.Contains(buf.String(), "Corrected for Coordinated Omission") {
+		t.Errorf("HTML should omit corrected latency when no request queued")
+	}
+}
+
+func TestGenerateHTMLReport_ConnectionPhases(t *testing.T) {
+	stats := metrics.Stats{
+		EndpointStats: metrics.EndpointStats{Total: 10},
+		Duration:      time.Second,
+		Endpoints: map[string]metrics.EndpointStats{
+			"login":  {Total: 6},
+			"health": {Total: 4},
+		},
+		Phases: map[string]metrics.EndpointStats{
+			metrics.PhaseConnect: {Total: 2, P95Latency: 3 * time.Millisecond},
+			metrics.PhaseTTFB:    {Total: 10, P95Latency: 42 * time.Millisecond},
+		},
+		EndpointPhases: map[string]map[string]metrics.EndpointStats{
+			"login": {metrics.PhaseTTFB: {Total: 6, P95Latency: 57 * time.Millisecond}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := output.GenerateHTMLReport(&buf, stats, nil, nil, output.ReportMetadata{}); err != nil {
+		t.Fatalf("GenerateHTMLReport() error = %v", err)
+	}
+	html := buf.String()
+	for _, want := range []string{"Connection Phases", "<th>connect</th><th>ttfb</th>", "42ms", "<td>-</td><td>57ms</td>"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("HTML missing %q", want)
+		}
+	}
+	if n := strings.Count(html, "<strong>health</strong>"); n != 1 {
+		t.Errorf("health appears %d times, want only in the endpoint breakdown", n)
+	}
+
+	buf.Reset()
+	stats.Phases, stats.EndpointPhases = nil, nil
+	if err := output.GenerateHTMLReport(&buf, stats, nil, nil, output.ReportMetadata{}); err != nil {
+		t.Fatalf("GenerateHTMLReport() error = %v", err)
+	}
+	if strings.Contains(buf.String(), "Connection Phases") {
+		t.Errorf("HTML should omit connection phases when none were recorded")
+	}
+}
@@ -0,0 +1,113 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/torosent/crankfire/internal/metrics"
+	"github.com/torosent/crankfire/internal/threshold"
+)
+
+// JUnitTestSuites is the root element of a JUnit XML report, the format CI
+// systems such as GitHub Actions, GitLab and Jenkins render as test results.
+type JUnitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr,omitempty"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Time     string           `xml:"time,attr,omitempty"`
+	Suites   []JUnitTestSuite `xml:"testsuite"`
+}
+
+// JUnitTestSuite groups related test cases.
+type JUnitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Time      string          `xml:"time,attr,omitempty"`
+	Timestamp string          `xml:"timestamp,attr,omitempty"`
+	Cases     []JUnitTestCase `xml:"testcase"`
+}
+
+// JUnitTestCase is one assertion; it passed when Failure is nil.
+type JUnitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr,omitempty"`
+	Failure   *JUnitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+// JUnitFailure explains why a test case failed.
+type JUnitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+// JUnitTime formats a duration as JUnit seconds.
+func JUnitTime(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+// WriteJUnit fills in the test and failure counts of suites and writes it as
+// an indented XML document.
+func WriteJUnit(w io.Writer, suites JUnitTestSuites) error {
+	suites.Tests, suites.Failures = 0, 0
+	for i := range suites.Suites {
+		suite := &suites.Suites[i]
+		suite.Tests, suite.Failures = len(suite.Cases), 0
+		for _, tc := range suite.Cases {
+			if tc.Failure != nil {
+				suite.Failures++
+			}
+		}
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(suites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// WriteJUnitReport writes a JUnit XML report with one test case per
+// threshold. Failed thresholds carry the actual value in the failure message.
+func WriteJUnitReport(w io.Writer, stats metrics.Stats, thresholdResults []threshold.Result) error {
+	suite := JUnitTestSuite{
+		Name: "thresholds",
+		Time: JUnitTime(stats.Duration),
+	}
+	for _, tr := range thresholdResults {
+		tc := JUnitTestCase{
+			Name:      tr.Threshold.Raw,
+			Classname: "crankfire.thresholds",
+		}
+		if !tr.Pass {
+			message := fmt.Sprintf("actual %.2f, want %s %.2f", tr.Actual, tr.Threshold.Operator, tr.Threshold.Value)
+			if tr.Err != nil {
+				message = tr.Message
+			}
+			failureType := "threshold"
+			if tr.Aborted {
+				failureType = "abort_on_fail"
+			}
+			tc.Failure = &JUnitFailure{Message: message, Type: failureType, Text: tr.Message}
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+	return WriteJUnit(w, JUnitTestSuites{
+		Name:   "crankfire",
+		Time:   suite.Time,
+		Suites: []JUnitTestSuite{suite},
+	})
+}
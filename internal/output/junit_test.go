@@ -0,0 +1,76 @@
+package output
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/torosent/crankfire/internal/metrics"
+	"github.com/torosent/crankfire/internal/threshold"
+)
+
+func TestWriteJUnitReport(t *testing.T) {
+	stats := metrics.Stats{Duration: 1500 * time.Millisecond}
+	results := []threshold.Result{
+		{
+			Threshold: threshold.Threshold{Raw: "http_req_duration:p95 < 500", Operator: "<", Value: 500},
+			Actual:    320,
+			Pass:      true,
+		},
+		{
+			Threshold: threshold.Threshold{Raw: "http_req_failed:rate < 0.01", Operator: "<", Value: 0.01},
+			Actual:    0.25,
+			Pass:      false,
+			Message:   "✗ http_req_failed:rate < 0.01: 0.25 < 0.01",
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteJUnitReport(&buf, stats, results); err != nil {
+		t.Fatalf("WriteJUnitReport: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), xml.Header) {
+		t.Errorf("report does not start with the XML header:\n%s", buf.String())
+	}
+
+	var got JUnitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, buf.String())
+	}
+	if got.Tests != 2 || got.Failures != 1 || len(got.Suites) != 1 {
+		t.Fatalf("testsuites = %+v", got)
+	}
+	suite := got.Suites[0]
+	if suite.Tests != 2 || suite.Failures != 1 || suite.Time != "1.500" {
+		t.Errorf("testsuite = %+v", suite)
+	}
+	if suite.Cases[0].Name != "http_req_duration:p95 < 500" || suite.Cases[0].Failure != nil {
+		t.Errorf("passing case = %+v", suite.Cases[0])
+	}
+	failure := suite.Cases[1].Failure
+	if failure == nil {
+		t.Fatalf("failing case has no failure: %+v", suite.Cases[1])
+	}
+	if failure.Message != "actual 0.25, want < 0.01" {
+		t.Errorf("failure message = %q", failure.Message)
+	}
+}
+
+func TestWriteJUnitReportEvaluationError(t *testing.T) {
+	results := []threshold.Result{{
+		Threshold: threshold.Threshold{Raw: "checks{name:missing}:rate > 0.9"},
+		Message:   "error: no check named missing",
+		Err:       errors.New("no check named missing"),
+	}}
+
+	var buf bytes.Buffer
+	if err := WriteJUnitReport(&buf, metrics.Stats{}, results); err != nil {
+		t.Fatalf("WriteJUnitReport: %v", err)
+	}
+	if !strings.Contains(buf.String(), `message="error: no check named missing"`) {
+		t.Errorf("expected evaluation error as failure message:\n%s", buf.String())
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/torosent/crankfire/internal/baseline"
 	"github.com/torosent/crankfire/internal/metrics"
 	"github.com/torosent/crankfire/internal/threshold"
 )
@@ -20,13 +21,11 @@ func PrintReport(w io.Writer, stats metrics.Stats, thresholdResults []threshold.
 	fmt.Fprintf(w, "Duration:          %s\n", stats.Duration)
 	fmt.Fprintf(w, "Requests/sec:      %.2f\n", stats.RequestsPerSec)
 	fmt.Fprintln(w, "\nLatency:")
-	fmt.Fprintf(w, "  Min:             %s\n", stats.MinLatency)
-	fmt.Fprintf(w, "  Max:             %s\n", stats.MaxLatency)
-	fmt.Fprintf(w, "  Mean:            %s\n", stats.MeanLatency)
-	fmt.Fprintf(w, "  P50:             %s\n", stats.P50Latency)
-	fmt.Fprintf(w, "  P90:             %s\n", stats.P90Latency)
-	fmt.Fprintf(w, "  P95:             %s\n", stats.P95Latency)
-	fmt.Fprintf(w, "  P99:             %s\n", stats.P99Latency)
+	writeLatency(w, stats.EndpointStats)
+	if stats.Corrected != nil {
+		fmt.Fprintln(w, "\nLatency (corrected for coordinated omission):")
+		writeLatency(w, *stats.Corrected)
+	}
 	if len(stats.StatusBuckets) > 0 {
 		fmt.Fprintln(w, "\nStatus Buckets:")
 		writeStatusBuckets(w, stats.StatusBuckets, "  ")
@@ -78,6 +77,64 @@ func PrintReport(w io.Writer, stats metrics.Stats, thresholdResults []threshold.
 		}
 	}
 
+	writeBreakdown(w, "Journey Breakdown:", stats.Journeys)
+	writeBreakdown(w, "Step Breakdown:", stats.Steps)
+
+	if len(stats.Timings) > 0 {
+		fmt.Fprintln(w, "\nTimings:")
+		names := make([]string, 0, len(stats.Timings))
+		for name := range stats.Timings {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			timing := stats.Timings[name]
+			fmt.Fprintf(
+				w,
+				"  - %s: samples=%d, p50=%s, p95=%s, p99=%s, max=%s\n",
+				name,
+				timing.Total,
+				timing.P50Latency,
+				timing.P95Latency,
+				timing.P99Latency,
+				timing.MaxLatency,
+			)
+		}
+	}
+
+	if len(stats.Phases) > 0 {
+		fmt.Fprintln(w, "\nConnection Phases:")
+		for _, phase := range metrics.Phases {
+			timing, ok := stats.Phases[phase]
+			if !ok {
+				continue
+			}
+			fmt.Fprintf(
+				w,
+				"  - %s: samples=%d, p50=%s, p95=%s, p99=%s, max=%s\n",
+				phase,
+				timing.Total,
+				timing.P50Latency,
+				timing.P95Latency,
+				timing.P99Latency,
+				timing.MaxLatency,
+			)
+		}
+	}
+
+	if len(stats.Checks) > 0 {
+		fmt.Fprintln(w, "\nChecks:")
+		names := make([]string, 0, len(stats.Checks))
+		for name := range stats.Checks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			check := stats.Checks[name]
+			fmt.Fprintf(w, "  - %s: passes=%d, fails=%d, pass_rate=%.2f%%\n", name, check.Passes, check.Fails, check.PassRate*100)
+		}
+	}
+
 	if len(stats.ProtocolMetrics) > 0 {
 		fmt.Fprintln(w, "\nProtocol Metrics:")
 		protocols := make([]string, 0, len(stats.ProtocolMetrics))
@@ -102,19 +159,22 @@ func PrintReport(w io.Writer, stats metrics.Stats, thresholdResults []threshold.
 
 // ThresholdResultJSON represents a threshold result in JSON format.
 type ThresholdResultJSON struct {
-	Threshold string  `json:"threshold"`
-	Metric    string  `json:"metric"`
-	Aggregate string  `json:"aggregate"`
-	Operator  string  `json:"operator"`
-	Expected  float64 `json:"expected"`
-	Actual    float64 `json:"actual"`
-	Pass      bool    `json:"pass"`
+	Threshold string            `json:"threshold"`
+	Metric    string            `json:"metric"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Aggregate string            `json:"aggregate"`
+	Operator  string            `json:"operator"`
+	Expected  float64           `json:"expected"`
+	Actual    float64           `json:"actual"`
+	Pass      bool              `json:"pass"`
+	Aborted   bool              `json:"aborted,omitempty"`
 }
 
 // JSONReport wraps stats and threshold results for JSON output.
 type JSONReport struct {
 	metrics.Stats
-	Thresholds *ThresholdSummary `json:"thresholds,omitempty"`
+	Thresholds *ThresholdSummary    `json:"thresholds,omitempty"`
+	Baseline   *baseline.Comparison `json:"baseline,omitempty"`
 }
 
 // ThresholdSummary contains threshold evaluation results.
@@ -123,38 +183,52 @@ type ThresholdSummary struct {
 	Passed  int                   `json:"passed"`
 	Failed  int                   `json:"failed"`
 	Results []ThresholdResultJSON `json:"results"`
+	// AbortReason explains which abort-on-fail threshold stopped the run.
+	AbortReason string `json:"abort_reason,omitempty"`
 }
 
-// PrintJSONReport outputs a JSON-formatted report.
-func PrintJSONReport(w io.Writer, stats metrics.Stats, thresholdResults []threshold.Result) error {
-	report := JSONReport{
-		Stats: stats,
+func newThresholdSummary(thresholdResults []threshold.Result) *ThresholdSummary {
+	if len(thresholdResults) == 0 {
+		return nil
 	}
-
-	if len(thresholdResults) > 0 {
-		summary := &ThresholdSummary{
-			Total:   len(thresholdResults),
-			Results: make([]ThresholdResultJSON, len(thresholdResults)),
+	summary := &ThresholdSummary{
+		Total:   len(thresholdResults),
+		Results: make([]ThresholdResultJSON, len(thresholdResults)),
+	}
+	for i, tr := range thresholdResults {
+		summary.Results[i] = ThresholdResultJSON{
+			Threshold: tr.Threshold.Raw,
+			Metric:    tr.Threshold.Metric,
+			Labels:    tr.Threshold.Labels,
+			Aggregate: tr.Threshold.Aggregate,
+			Operator:  tr.Threshold.Operator,
+			Expected:  tr.Threshold.Value,
+			Actual:    tr.Actual,
+			Pass:      tr.Pass,
+			Aborted:   tr.Aborted,
 		}
-		for i, tr := range thresholdResults {
-			summary.Results[i] = ThresholdResultJSON{
-				Threshold: tr.Threshold.Raw,
-				Metric:    tr.Threshold.Metric,
-				Aggregate: tr.Threshold.Aggregate,
-				Operator:  tr.Threshold.Operator,
-				Expected:  tr.Threshold.Value,
-				Actual:    tr.Actual,
-				Pass:      tr.Pass,
-			}
-			if tr.Pass {
-				summary.Passed++
-			} else {
-				summary.Failed++
-			}
+		if tr.Aborted {
+			summary.AbortReason = strings.TrimPrefix(tr.Message, "✗ ")
 		}
-		report.Thresholds = summary
+		if tr.Pass {
+			summary.Passed++
+		} else {
+			summary.Failed++
+		}
+	}
+	return summary
+}
+
+// PrintJSONReport outputs a JSON-formatted report. comparison is the
+// baseline comparison of the run, if any.
+func PrintJSONReport(w io.Writer, stats metrics.Stats, thresholdResults []threshold.Result, comparison *baseline.Comparison) error {
+	report := JSONReport{
+		Stats:    stats,
+		Baseline: comparison,
 	}
 
+	report.Thresholds = newThresholdSummary(thresholdResults)
+
 	enc := json.NewEncoder(w)
 	enc.SetIndent("", "  ")
 	return enc.Encode(report)
@@ -177,3 +251,44 @@ func writeStatusBuckets(w io.Writer, buckets map[string]map[string]int, indent s
 		)
 	}
 }
+
+// writeLatency prints the latency distribution of a bucket.
+func writeLatency(w io.Writer, stats metrics.EndpointStats) {
+	fmt.Fprintf(w, "  Min:             %s\n", stats.MinLatency)
+	fmt.Fprintf(w, "  Max:             %s\n", stats.MaxLatency)
+	fmt.Fprintf(w, "  Mean:            %s\n", stats.MeanLatency)
+	fmt.Fprintf(w, "  P50:             %s\n", stats.P50Latency)
+	fmt.Fprintf(w, "  P90:             %s\n", stats.P90Latency)
+	fmt.Fprintf(w, "  P95:             %s\n", stats.P95Latency)
+	fmt.Fprintf(w, "  P99:             %s\n", stats.P99Latency)
+}
+
+// writeBreakdown prints one line per named series, sorted by name.
+func writeBreakdown(w io.Writer, title string, series map[string]metrics.EndpointStats) {
+	if len(series) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "\n"+title)
+	names := make([]string, 0, len(series))
+	for name := range series {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		entry := series[name]
+		fmt.Fprintf(
+			w,
+			"  - %s: total=%d, failures=%d, p50=%s, p95=%s, p99=%s\n",
+			name,
+			entry.Total,
+			entry.Failures,
+			entry.P50Latency,
+			entry.P95Latency,
+			entry.P99Latency,
+		)
+		if len(entry.StatusBuckets) > 0 {
+			fmt.Fprintln(w, "    Status Buckets:")
+			writeStatusBuckets(w, entry.StatusBuckets, "      ")
+		}
+	}
+}
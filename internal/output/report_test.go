@@ -2,11 +2,14 @@ package output
 
 import (
 	"bytes"
+	"encoding/json"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/torosent/crankfire/internal/baseline"
 	"github.com/torosent/crankfire/internal/metrics"
+	"github.com/torosent/crankfire/internal/threshold"
 )
 
 func TestPrintReportBasic(t *testing.T) {
@@ -101,7 +104,7 @@ func TestPrintJSONReportWithProtocolMetrics(t *testing.T) {
 	}
 
 	var buf bytes.Buffer
-	err := PrintJSONReport(&buf, stats, nil)
+	err := PrintJSONReport(&buf, stats, nil, nil)
 	if err != nil {
 		t.Fatalf("PrintJSONReport failed: %v", err)
 	}
@@ -156,3 +159,158 @@ func TestPrintReportIncludesEndpointStatusBuckets(t *testing.T) {
 		t.Fatalf("expected endpoint status bucket, got %s", output)
 	}
 }
+
+func TestPrintReportIncludesStepBreakdown(t *testing.T) {
+	stats := metrics.Stats{
+		EndpointStats: metrics.EndpointStats{Total: 2, Successes: 1, Failures: 1},
+		Steps: map[string]metrics.EndpointStats{
+			"01-send": {Total: 2, P99Latency: 2 * time.Millisecond},
+			"02-expect": {
+				Total:    2,
+				Failures: 1,
+				StatusBuckets: map[string]map[string]int{
+					"websocket": {"EXPECT_FAILED": 1},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	PrintReport(&buf, stats, nil)
+	output := buf.String()
+	if !strings.Contains(output, "Step Breakdown:") {
+		t.Fatalf("expected step breakdown header, got %s", output)
+	}
+	if strings.Index(output, "01-send") > strings.Index(output, "02-expect") {
+		t.Fatalf("expected steps in script order, got %s", output)
+	}
+	if !strings.Contains(output, "WEBSOCKET EXPECT_FAILED: 1") {
+		t.Fatalf("expected step status bucket, got %s", output)
+	}
+}
+
+func TestPrintReportIncludesTimings(t *testing.T) {
+	stats := metrics.Stats{
+		Timings: map[string]metrics.EndpointStats{
+			"sse_inter_event": {Total: 12, P50Latency: 5 * time.Millisecond, MaxLatency: 40 * time.Millisecond},
+		},
+	}
+
+	var buf bytes.Buffer
+	PrintReport(&buf, stats, nil)
+	output := buf.String()
+	if !strings.Contains(output, "Timings:") || !strings.Contains(output, "sse_inter_event: samples=12") {
+		t.Fatalf("expected timings section, got %s", output)
+	}
+}
+
+func TestPrintReportIncludesPhases(t *testing.T) {
+	stats := metrics.Stats{
+		Phases: map[string]metrics.EndpointStats{
+			metrics.PhaseTTFB: {Total: 8, P95Latency: 30 * time.Millisecond},
+			metrics.PhaseDNS:  {Total: 2, P95Latency: time.Millisecond},
+		},
+	}
+
+	var buf bytes.Buffer
+	PrintReport(&buf, stats, nil)
+	output := buf.String()
+	dns := strings.Index(output, "dns: samples=2")
+	ttfb := strings.Index(output, "ttfb: samples=8, p50=0s, p95=30ms")
+	if !strings.Contains(output, "Connection Phases:") || dns < 0 || ttfb < dns {
+		t.Fatalf("expected phases in order, got %s", output)
+	}
+}
+
+func TestPrintReportIncludesChecks(t *testing.T) {
+	stats := metrics.Stats{
+		Checks: map[string]metrics.CheckStats{
+			"login status": {Passes: 99, Fails: 1, PassRate: 0.99},
+		},
+	}
+
+	var buf bytes.Buffer
+	PrintReport(&buf, stats, nil)
+	output := buf.String()
+	if !strings.Contains(output, "Checks:") || !strings.Contains(output, "login status: passes=99, fails=1, pass_rate=99.00%") {
+		t.Fatalf("expected checks section, got %s", output)
+	}
+}
+
+func TestPrintReportIncludesJourneyBreakdown(t *testing.T) {
+	stats := metrics.Stats{
+		Journeys: map[string]metrics.EndpointStats{
+			"checkout": {Total: 10, Failures: 2, P50Latency: 800 * time.Millisecond},
+		},
+	}
+
+	var buf bytes.Buffer
+	PrintReport(&buf, stats, nil)
+	output := buf.String()
+	if !strings.Contains(output, "Journey Breakdown:") || !strings.Contains(output, "checkout: total=10, failures=2") {
+		t.Fatalf("expected journey breakdown, got %s", output)
+	}
+}
+
+func TestPrintReportIncludesCorrectedLatency(t *testing.T) {
+	stats := metrics.Stats{
+		EndpointStats: metrics.EndpointStats{Total: 10, P99Latency: 20 * time.Millisecond},
+		Corrected:     &metrics.EndpointStats{Total: 10, P99Latency: 750 * time.Millisecond},
+	}
+
+	var buf bytes.Buffer
+	PrintReport(&buf, stats, nil)
+	output := buf.String()
+	if !strings.Contains(output, "Latency (corrected for coordinated omission):") || !strings.Contains(output, "750ms") {
+		t.Fatalf("expected corrected latency section, got %s", output)
+	}
+	if !strings.Contains(output, "20ms") {
+		t.Fatalf("expected uncorrected latency to remain, got %s", output)
+	}
+}
+
+func TestPrintJSONReportIncludesAbortReason(t *testing.T) {
+	abort, err := threshold.Parse("http_req_failed:rate < 0.1 abort_on_fail")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	results := []threshold.Result{{Threshold: abort, Actual: 0.05, Pass: true}}
+	breach := &threshold.Breach{Result: threshold.Result{Threshold: abort, Actual: 0.5}, Elapsed: time.Minute}
+	breach.Apply(results)
+
+	var buf bytes.Buffer
+	if err := PrintJSONReport(&buf, metrics.Stats{}, results, nil); err != nil {
+		t.Fatalf("PrintJSONReport failed: %v", err)
+	}
+	output := buf.String()
+	if !strings.Contains(output, `"aborted": true`) {
+		t.Errorf("Expected aborted result in JSON output:\n%s", output)
+	}
+	if !strings.Contains(output, `"abort_reason": "aborted after 1m0s: threshold`) {
+		t.Errorf("Expected abort_reason in JSON output:\n%s", output)
+	}
+}
+
+func TestPrintJSONReportIncludesBaseline(t *testing.T) {
+	base := baseline.Baseline{Ref: "run1", Stats: metrics.Stats{EndpointStats: metrics.EndpointStats{Total: 10, P95LatencyMs: 100}}}
+	current := metrics.Stats{EndpointStats: metrics.EndpointStats{Total: 10, P95LatencyMs: 200}}
+	comparison := baseline.Compare(base, current, baseline.Tolerance{})
+
+	var buf bytes.Buffer
+	if err := PrintJSONReport(&buf, current, nil, comparison); err != nil {
+		t.Fatalf("PrintJSONReport failed: %v", err)
+	}
+	var report JSONReport
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if report.Baseline == nil || report.Baseline.Verdict != baseline.VerdictRegressed || report.Baseline.Baseline != "run1" {
+		t.Errorf("Baseline = %+v, want a regressed comparison against run1", report.Baseline)
+	}
+
+	buf.Reset()
+	PrintComparison(&buf, comparison)
+	if !strings.Contains(buf.String(), "✗ overall p95_ms 100.00 → 200.00 (+100.0%)") {
+		t.Errorf("unexpected comparison text:\n%s", buf.String())
+	}
+}
@@ -34,6 +34,10 @@ th{background:#f7f7f7;}
 .threshold{padding:.5rem;}
 .threshold.passed{color:#155724;}
 .threshold.failed{color:#721c24;font-weight:600;}
+.badge.improved{background:#d4edda;color:#155724;}
+.badge.regressed{background:#f8d7da;color:#721c24;}
+.badge.unchanged{background:#e2e3e5;color:#383d41;}
+.badge.inconclusive{background:#d6e4f0;color:#1b4965;}
 </style>
 </head>
 <body>
@@ -55,6 +59,26 @@ th{background:#f7f7f7;}
 <p><em>No thresholds defined.</em></p>
 {{end}}
 
+{{with .Run.Regression}}
+<h2>History <span class="badge {{.Verdict}}">{{.Verdict}}</span></h2>
+<p class="meta">Each item compared with its last {{.Window}} runs; scores are robust z-scores against the median, positive when worse.</p>
+{{if .Items}}
+<table class="regression-table">
+  <thead><tr><th>Item</th><th>Verdict</th><th>Samples</th>{{with index .Items 0}}{{range .Metrics}}<th>z {{.Metric}}</th>{{end}}{{end}}</tr></thead>
+  <tbody>
+  {{range .Items}}
+  <tr>
+    <td>{{.Name}}</td>
+    <td><span class="badge {{.Verdict}}">{{.Verdict}}</span></td>
+    <td>{{.Samples}}</td>
+    {{range .Metrics}}<td>{{printf "%+.2f" .Score}}</td>{{end}}
+  </tr>
+  {{end}}
+  </tbody>
+</table>
+{{end}}
+{{end}}
+
 <h2>Stages</h2>
 {{range .Run.Stages}}
 <div class="stage">
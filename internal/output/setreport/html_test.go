@@ -82,6 +82,37 @@ func TestRenderHTMLContainsCompareTable(t *testing.T) {
 	}
 }
 
+func TestRenderIncludesRegression(t *testing.T) {
+	run := store.SetRun{
+		Stages: []store.StageResult{{Name: "s", Items: []store.ItemResult{
+			{Name: "api", Status: store.RunStatusCompleted, Summary: store.RunSummary{P95Ms: 300}},
+		}}},
+		Regression: &store.SetRegression{Window: 5, Verdict: "regressed", Items: []store.RegressionResult{{
+			Name: "api", Samples: 5, Verdict: "regressed",
+			Metrics: []store.MetricRegression{{Metric: "p95", Current: 300, Median: 100, MAD: 5, Score: 27, Verdict: "regressed"}},
+		}}},
+	}
+	out, err := setreport.Render(run)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"regression-table", "<th>z p95</th>", "27.00", "last 5 runs"} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("missing %q in HTML", want)
+		}
+	}
+
+	xml, err := setreport.RenderJUnit(run)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`<testsuite name="history" tests="1" failures="1"`, `type="regression"`, "p95=+27.00"} {
+		if !strings.Contains(string(xml), want) {
+			t.Errorf("missing %q in JUnit:\n%s", want, xml)
+		}
+	}
+}
+
 func diff(a, b string) string {
 	if len(a) > 400 {
 		a = a[:400]
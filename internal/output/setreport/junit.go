@@ -0,0 +1,128 @@
+package setreport
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/torosent/crankfire/internal/output"
+	"github.com/torosent/crankfire/internal/regression"
+	"github.com/torosent/crankfire/internal/store"
+)
+
+// RenderJUnit produces a JUnit XML report for a finished SetRun. Each stage
+// is a test suite holding one test case per item plus the thresholds scoped
+// to those items; aggregate thresholds form a final "thresholds" suite.
+func RenderJUnit(run store.SetRun) ([]byte, error) {
+	classname := "crankfire.set." + run.SetName
+	itemStage := map[string]int{}
+	var suites []output.JUnitTestSuite
+	for i, stage := range run.Stages {
+		suite := output.JUnitTestSuite{
+			Name:      stage.Name,
+			Time:      junitElapsed(stage.StartedAt, stage.EndedAt),
+			Timestamp: stage.StartedAt.Format(time.RFC3339),
+		}
+		for _, item := range stage.Items {
+			itemStage[item.Name] = i
+			suite.Cases = append(suite.Cases, itemCase(classname+"."+stage.Name, item))
+		}
+		suites = append(suites, suite)
+	}
+
+	aggregate := output.JUnitTestSuite{Name: "thresholds"}
+	for _, th := range run.Thresholds {
+		if idx, ok := itemStage[th.Scope]; ok {
+			tc := thresholdCase(classname+"."+run.Stages[idx].Name, th)
+			suites[idx].Cases = append(suites[idx].Cases, tc)
+			continue
+		}
+		aggregate.Cases = append(aggregate.Cases, thresholdCase(classname+".thresholds", th))
+	}
+	if len(aggregate.Cases) > 0 {
+		suites = append(suites, aggregate)
+	}
+	if run.Regression != nil {
+		suites = append(suites, regressionSuite(classname+".history", *run.Regression))
+	}
+
+	var buf bytes.Buffer
+	err := output.WriteJUnit(&buf, output.JUnitTestSuites{
+		Name:   run.SetName,
+		Time:   junitElapsed(run.StartedAt, run.EndedAt),
+		Suites: suites,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("junit: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func itemCase(classname string, item store.ItemResult) output.JUnitTestCase {
+	s := item.Summary
+	summary := fmt.Sprintf("requests=%d errors=%d p50=%.0fms p95=%.0fms p99=%.0fms",
+		s.TotalRequests, s.Errors, s.P50Ms, s.P95Ms, s.P99Ms)
+	tc := output.JUnitTestCase{
+		Name:      item.Name,
+		Classname: classname,
+		Time:      junitElapsed(item.StartedAt, item.EndedAt),
+		SystemOut: summary,
+	}
+	if item.Status != store.RunStatusCompleted {
+		message := string(item.Status)
+		if item.Error != "" {
+			message += ": " + item.Error
+		}
+		tc.Failure = &output.JUnitFailure{Message: message, Type: "item"}
+	}
+	return tc
+}
+
+func thresholdCase(classname string, th store.ThresholdResult) output.JUnitTestCase {
+	value := strconv.FormatFloat(th.Value, 'g', -1, 64)
+	tc := output.JUnitTestCase{
+		Name:      fmt.Sprintf("%s %s %s [%s]", th.Metric, th.Op, value, th.Scope),
+		Classname: classname,
+	}
+	if !th.Passed {
+		tc.Failure = &output.JUnitFailure{
+			Message: fmt.Sprintf("actual %.3f, want %s %s %s", th.Actual, th.Metric, th.Op, value),
+			Type:    "threshold",
+		}
+	}
+	return tc
+}
+
+// regressionSuite has one test case per item checked against the set's
+// history; only regressed items fail.
+func regressionSuite(classname string, h store.SetRegression) output.JUnitTestSuite {
+	suite := output.JUnitTestSuite{Name: "history"}
+	for _, item := range h.Items {
+		scores := make([]string, 0, len(item.Metrics))
+		for _, m := range item.Metrics {
+			scores = append(scores, fmt.Sprintf("%s=%+.2f", m.Metric, m.Score))
+		}
+		tc := output.JUnitTestCase{
+			Name:      item.Name,
+			Classname: classname,
+			SystemOut: fmt.Sprintf("verdict=%s samples=%d z: %s", item.Verdict, item.Samples, strings.Join(scores, " ")),
+		}
+		if item.Verdict == regression.VerdictRegressed {
+			tc.Failure = &output.JUnitFailure{
+				Message: fmt.Sprintf("regressed against the last %d runs", item.Samples),
+				Type:    "regression",
+			}
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+	return suite
+}
+
+func junitElapsed(start, end time.Time) string {
+	if start.IsZero() || end.Before(start) {
+		return ""
+	}
+	return output.JUnitTime(end.Sub(start))
+}
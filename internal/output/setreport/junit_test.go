@@ -0,0 +1,67 @@
+package setreport_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/torosent/crankfire/internal/output/setreport"
+	"github.com/torosent/crankfire/internal/store"
+)
+
+func TestRenderJUnitGolden(t *testing.T) {
+	start := time.Date(2026, 4, 19, 10, 0, 0, 0, time.UTC)
+	run := store.SetRun{
+		SetID:     "01HX",
+		SetName:   "auth-regression",
+		StartedAt: start,
+		EndedAt:   start.Add(5 * time.Minute),
+		Status:    store.SetRunFailed,
+		Stages: []store.StageResult{
+			{
+				Name:      "warmup",
+				StartedAt: start,
+				EndedAt:   start.Add(time.Minute),
+				Items: []store.ItemResult{
+					{Name: "warm", SessionID: "s1", Status: store.RunStatusCompleted,
+						StartedAt: start, EndedAt: start.Add(time.Minute),
+						Summary: store.RunSummary{P50Ms: 80, P95Ms: 200, P99Ms: 400, TotalRequests: 50}},
+				},
+			},
+			{
+				Name:      "load",
+				StartedAt: start.Add(time.Minute),
+				EndedAt:   start.Add(5 * time.Minute),
+				Items: []store.ItemResult{
+					{Name: "login", SessionID: "s1", Status: store.RunStatusCompleted,
+						StartedAt: start.Add(time.Minute), EndedAt: start.Add(5 * time.Minute),
+						Summary: store.RunSummary{P50Ms: 100, P95Ms: 250, P99Ms: 500, TotalRequests: 100}},
+					{Name: "search", SessionID: "s2", Status: store.RunStatusFailed, Error: "build: target URL is required",
+						StartedAt: start.Add(time.Minute), EndedAt: start.Add(time.Minute)},
+				},
+			},
+		},
+		Thresholds: []store.ThresholdResult{
+			{Threshold: store.Threshold{Metric: "p95", Op: "lt", Value: 500, Scope: "aggregate"}, Actual: 300, Passed: true},
+			{Threshold: store.Threshold{Metric: "p99", Op: "lt", Value: 450, Scope: "login"}, Actual: 500, Passed: false},
+		},
+	}
+	got, err := setreport.RenderJUnit(run)
+	if err != nil {
+		t.Fatalf("RenderJUnit: %v", err)
+	}
+	goldenPath := filepath.Join("testdata", "expected_junit.xml")
+	if *update {
+		if err := os.WriteFile(goldenPath, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(goldenPath)
+	if err != nil {
+		t.Fatalf("read golden (run with -update first time): %v", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("JUnit mismatch — re-run with -update if intentional.\n--- diff start ---\n%s\n--- diff end ---", diff(string(want), string(got)))
+	}
+}
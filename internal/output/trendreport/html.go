@@ -0,0 +1,185 @@
+// Package trendreport renders a standalone HTML report of how sessions or set
+// items performed across their stored runs.
+package trendreport
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+
+	"github.com/torosent/crankfire/internal/regression"
+)
+
+const htmlTemplate = `<!doctype html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Trend — {{.Title}}</title>
+<style>
+body{font-family:-apple-system,Segoe UI,sans-serif;margin:2rem;color:#222;}
+h1{margin-bottom:.2rem;}
+.meta{color:#666;margin-bottom:1.5rem;}
+.series{margin:1.5rem 0;border:1px solid #e0e0e0;border-radius:6px;padding:1rem;}
+.charts{display:flex;flex-wrap:wrap;gap:1rem;}
+.chart{flex:1 1 320px;}
+.chart h4{margin:.2rem 0;font-weight:600;}
+.chart svg{width:100%;height:auto;background:#fafafa;border:1px solid #eee;}
+.chart polyline{fill:none;stroke:#3b6ea5;stroke-width:2;}
+.chart circle{fill:#3b6ea5;}
+.chart circle.regressed{fill:#c0392b;}
+.chart .range{color:#666;font-size:.8rem;}
+table{border-collapse:collapse;width:100%;margin-top:1rem;}
+th,td{padding:.4rem .6rem;border:1px solid #e0e0e0;text-align:right;}
+th:first-child,td:first-child,th:nth-child(2),td:nth-child(2){text-align:left;}
+th{background:#f7f7f7;}
+tr.regressed td{background:#fbeaea;}
+.badge{display:inline-block;padding:2px 8px;border-radius:4px;font-size:.85rem;font-weight:600;}
+.badge.improved{background:#d4edda;color:#155724;}
+.badge.regressed{background:#f8d7da;color:#721c24;}
+.badge.unchanged{background:#e2e3e5;color:#383d41;}
+.badge.inconclusive{background:#d6e4f0;color:#1b4965;}
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<div class="meta">Generated {{.Generated.Format "2006-01-02 15:04:05 MST"}} · each run compared with the runs before it; regressed runs are marked in red.</div>
+
+{{range .Series}}
+<div class="series">
+  <h2>{{.Name}} {{if .Regressions}}<span class="badge regressed">{{.Regressions}} regressed</span>{{end}}</h2>
+  {{if .Charts}}
+  <div class="charts">
+    {{range .Charts}}
+    <div class="chart">
+      <h4>{{.Title}} <span class="range">{{.Range}}</span></h4>
+      <svg viewBox="0 0 {{.Width}} {{.Height}}" role="img" aria-label="{{.Title}}">
+        <polyline points="{{.Polyline}}"/>
+        {{range .Points}}<circle cx="{{printf "%.1f" .X}}" cy="{{printf "%.1f" .Y}}" r="4"{{if .Regressed}} class="regressed"{{end}}><title>{{.Label}}</title></circle>{{end}}
+      </svg>
+    </div>
+    {{end}}
+  </div>
+  <table class="trend-table">
+    <thead><tr><th>Run</th><th>Started</th><th>p95 ms</th><th>p99 ms</th><th>Error rate</th><th>RPS</th><th>Verdict</th></tr></thead>
+    <tbody>
+    {{range .Points}}
+    <tr class="{{.Verdict}}">
+      <td>{{.RunID}}</td>
+      <td>{{.StartedAt.Format "2006-01-02 15:04"}}</td>
+      <td>{{printf "%.1f" .P95Ms}}</td>
+      <td>{{printf "%.1f" .P99Ms}}</td>
+      <td>{{printf "%.2f%%" (percent .ErrorRate)}}</td>
+      <td>{{printf "%.1f" .RPS}}</td>
+      <td><span class="badge {{.Verdict}}">{{.Verdict}}</span></td>
+    </tr>
+    {{end}}
+    </tbody>
+  </table>
+  {{else}}
+  <p><em>No completed runs.</em></p>
+  {{end}}
+</div>
+{{else}}
+<p><em>No runs.</em></p>
+{{end}}
+</body>
+</html>
+`
+
+// Chart geometry in SVG user units.
+const (
+	chartWidth   = 400
+	chartHeight  = 120
+	chartPadding = 10
+)
+
+type chartPoint struct {
+	X, Y      float64
+	Regressed bool
+	Label     string
+}
+
+type chart struct {
+	Title         string
+	Range         string
+	Width, Height int
+	Polyline      string
+	Points        []chartPoint
+}
+
+type seriesData struct {
+	regression.Series
+	Charts []chart
+}
+
+type renderData struct {
+	Title     string
+	Generated time.Time
+	Series    []seriesData
+}
+
+// Render produces the report for series, one section per series with a
+// chart for p95, p99, error rate and RPS.
+func Render(title string, series []regression.Series, generated time.Time) ([]byte, error) {
+	data := renderData{Title: title, Generated: generated}
+	for _, s := range series {
+		sd := seriesData{Series: s}
+		if len(s.Points) > 0 {
+			sd.Charts = []chart{
+				newChart("p95 latency", "ms", s.Points, func(p regression.Point) float64 { return p.P95Ms }),
+				newChart("p99 latency", "ms", s.Points, func(p regression.Point) float64 { return p.P99Ms }),
+				newChart("Error rate", "%", s.Points, func(p regression.Point) float64 { return p.ErrorRate * 100 }),
+				newChart("RPS", "", s.Points, func(p regression.Point) float64 { return p.RPS }),
+			}
+		}
+		data.Series = append(data.Series, sd)
+	}
+
+	tmpl, err := template.New("trend").Funcs(template.FuncMap{
+		"percent": func(v float64) float64 { return v * 100 },
+	}).Parse(htmlTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("parse template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("execute: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// newChart scales the values of points into the chart area, oldest run on
+// the left and the largest value at the top.
+func newChart(title, unit string, points []regression.Point, value func(regression.Point) float64) chart {
+	c := chart{Title: title, Width: chartWidth, Height: chartHeight}
+	lo, hi := value(points[0]), value(points[0])
+	for _, p := range points[1:] {
+		lo, hi = min(lo, value(p)), max(hi, value(p))
+	}
+	c.Range = fmt.Sprintf("%.2f–%.2f%s", lo, hi, unit)
+
+	innerW := float64(chartWidth - 2*chartPadding)
+	innerH := float64(chartHeight - 2*chartPadding)
+	coords := make([]string, 0, len(points))
+	for i, p := range points {
+		x := chartPadding + innerW/2
+		if len(points) > 1 {
+			x = chartPadding + innerW*float64(i)/float64(len(points)-1)
+		}
+		y := chartPadding + innerH/2
+		if hi > lo {
+			y = chartPadding + innerH*(hi-value(p))/(hi-lo)
+		}
+		coords = append(coords, fmt.Sprintf("%.1f,%.1f", x, y))
+		c.Points = append(c.Points, chartPoint{
+			X:         x,
+			Y:         y,
+			Regressed: p.Verdict == regression.VerdictRegressed,
+			Label:     fmt.Sprintf("%s: %.2f%s (%s)", p.RunID, value(p), unit, p.Verdict),
+		})
+	}
+	c.Polyline = strings.Join(coords, " ")
+	return c
+}
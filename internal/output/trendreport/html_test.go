@@ -0,0 +1,45 @@
+package trendreport_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/torosent/crankfire/internal/output/trendreport"
+	"github.com/torosent/crankfire/internal/regression"
+)
+
+func TestRenderMarksRegressedRuns(t *testing.T) {
+	start := time.Date(2026, 4, 19, 10, 0, 0, 0, time.UTC)
+	series := []regression.Series{{
+		Name: "login",
+		Points: []regression.Point{
+			{RunID: "r1", StartedAt: start, P95Ms: 100, P99Ms: 150, RPS: 50, Verdict: regression.VerdictInconclusive},
+			{RunID: "r2", StartedAt: start.Add(time.Hour), P95Ms: 105, P99Ms: 160, RPS: 51, Verdict: regression.VerdictUnchanged},
+			{RunID: "r3", StartedAt: start.Add(2 * time.Hour), P95Ms: 300, P99Ms: 400, ErrorRate: 0.02, RPS: 40, Verdict: regression.VerdictRegressed},
+		},
+	}}
+	out, err := trendreport.Render("checkout", series, start)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	html := string(out)
+	for _, want := range []string{"<title>Trend — checkout</title>", "login", "1 regressed", "p95 latency", "Error rate", "RPS", `class="regressed"`, "r3: 300.00ms (regressed)", "2.00%"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("missing %q in HTML", want)
+		}
+	}
+	if got := strings.Count(html, "<polyline"); got != 4 {
+		t.Errorf("got %d charts, want 4", got)
+	}
+}
+
+func TestRenderSeriesWithoutRuns(t *testing.T) {
+	out, err := trendreport.Render("empty", []regression.Series{{Name: "idle"}}, time.Now())
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if !strings.Contains(string(out), "No completed runs.") {
+		t.Errorf("expected placeholder for a series without runs:\n%s", out)
+	}
+}
@@ -0,0 +1,139 @@
+// Package regression decides whether a run regressed compared with its recent
+// history. Each metric is scored against the median and median absolute
+// deviation (MAD) of the earlier runs, so a single noisy run neither hides nor
+// fakes a regression.
+package regression
+
+import (
+	"math"
+	"sort"
+
+	"github.com/torosent/crankfire/internal/store"
+)
+
+// Verdicts for a metric, a run and a set of runs.
+const (
+	VerdictImproved     = "improved"
+	VerdictUnchanged    = "unchanged"
+	VerdictRegressed    = "regressed"
+	VerdictInconclusive = "inconclusive"
+)
+
+const (
+	// DefaultWindow is how many previous runs are compared against.
+	DefaultWindow = 5
+	// MinSamples is the fewest previous runs that give a verdict other than
+	// inconclusive.
+	MinSamples = 3
+)
+
+const (
+	// madScale turns the MAD into an estimate of the standard deviation.
+	madScale = 1.4826
+	// Robust z-scores at or beyond significantScore are a change; scores
+	// below noiseScore are noise. Anything between is inconclusive.
+	significantScore = 3.0
+	noiseScore       = 2.0
+)
+
+type metric struct {
+	name string
+	get  func(store.RunSummary) float64
+	// minEffect is the smallest change that matters, relative to the median
+	// when relative is set and absolute otherwise.
+	minEffect float64
+	// noiseFloor bounds the spread from below, in the same unit as
+	// minEffect, so a perfectly stable history does not turn every change
+	// into an infinite score.
+	noiseFloor float64
+	relative   bool
+}
+
+var metrics = []metric{
+	{"p50", func(s store.RunSummary) float64 { return s.P50Ms }, 0.05, 0.01, true},
+	{"p95", func(s store.RunSummary) float64 { return s.P95Ms }, 0.05, 0.01, true},
+	{"p99", func(s store.RunSummary) float64 { return s.P99Ms }, 0.05, 0.01, true},
+	{"error_rate", errorRate, 0.005, 0.001, false},
+}
+
+// Detect scores current against history, the same run's earlier summaries.
+// With fewer than MinSamples earlier runs every verdict is inconclusive.
+func Detect(current store.RunSummary, history []store.RunSummary) store.RegressionResult {
+	res := store.RegressionResult{Samples: len(history)}
+	verdicts := make([]string, 0, len(metrics))
+	for _, m := range metrics {
+		values := make([]float64, len(history))
+		for i, h := range history {
+			values[i] = m.get(h)
+		}
+		mr := m.detect(m.get(current), values)
+		res.Metrics = append(res.Metrics, mr)
+		verdicts = append(verdicts, mr.Verdict)
+	}
+	res.Verdict = Combine(verdicts...)
+	return res
+}
+
+func (m metric) detect(current float64, history []float64) store.MetricRegression {
+	r := store.MetricRegression{Metric: m.name, Current: current, Verdict: VerdictInconclusive}
+	if len(history) == 0 {
+		return r
+	}
+	r.Median = median(history)
+	deviations := make([]float64, len(history))
+	for i, v := range history {
+		deviations[i] = math.Abs(v - r.Median)
+	}
+	r.MAD = median(deviations)
+
+	scale := 1.0
+	if m.relative {
+		scale = math.Abs(r.Median)
+	}
+	change := current - r.Median
+	if sigma := math.Max(madScale*r.MAD, m.noiseFloor*scale); sigma > 0 {
+		r.Score = change / sigma
+	}
+	if len(history) < MinSamples {
+		return r
+	}
+	switch {
+	case math.Abs(change) <= m.minEffect*scale || math.Abs(r.Score) < noiseScore:
+		r.Verdict = VerdictUnchanged
+	case r.Score >= significantScore:
+		r.Verdict = VerdictRegressed
+	case r.Score <= -significantScore:
+		r.Verdict = VerdictImproved
+	}
+	return r
+}
+
+// Combine merges verdicts: any regression wins, then any inconclusive
+// result, then any improvement.
+func Combine(verdicts ...string) string {
+	rank := map[string]int{VerdictUnchanged: 0, VerdictImproved: 1, VerdictInconclusive: 2, VerdictRegressed: 3}
+	out := VerdictUnchanged
+	for _, v := range verdicts {
+		if rank[v] > rank[out] {
+			out = v
+		}
+	}
+	return out
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+func errorRate(s store.RunSummary) float64 {
+	if s.TotalRequests == 0 {
+		return 0
+	}
+	return float64(s.Errors) / float64(s.TotalRequests)
+}
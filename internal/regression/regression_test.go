@@ -0,0 +1,86 @@
+package regression
+
+import (
+	"testing"
+
+	"github.com/torosent/crankfire/internal/store"
+)
+
+func summary(p95 float64, errors int64) store.RunSummary {
+	return store.RunSummary{TotalRequests: 1000, Errors: errors, P50Ms: 50, P95Ms: p95, P99Ms: p95 * 2}
+}
+
+func history(p95s ...float64) []store.RunSummary {
+	out := make([]store.RunSummary, len(p95s))
+	for i, p := range p95s {
+		out[i] = summary(p, 0)
+	}
+	return out
+}
+
+func metricVerdict(t *testing.T, r store.RegressionResult, name string) store.MetricRegression {
+	t.Helper()
+	for _, m := range r.Metrics {
+		if m.Metric == name {
+			return m
+		}
+	}
+	t.Fatalf("no metric %s in %+v", name, r.Metrics)
+	return store.MetricRegression{}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name    string
+		current store.RunSummary
+		history []store.RunSummary
+		want    string
+	}{
+		{"too few samples", summary(300, 0), history(100, 100), VerdictInconclusive},
+		{"within noise", summary(108, 0), history(90, 110, 100, 95, 105), VerdictUnchanged},
+		{"below minimum effect", summary(104, 0), history(100, 100, 100), VerdictUnchanged},
+		{"clear regression", summary(150, 0), history(98, 102, 100, 99, 101), VerdictRegressed},
+		{"clear improvement", summary(70, 0), history(98, 102, 100, 99, 101), VerdictImproved},
+		{"noisy history", summary(130, 0), history(80, 120, 100, 90, 110), VerdictInconclusive},
+		{"error rate regression", summary(100, 50), history(100, 100, 100, 100), VerdictRegressed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Detect(tt.current, tt.history)
+			if got.Verdict != tt.want {
+				t.Errorf("Verdict = %s, want %s (metrics %+v)", got.Verdict, tt.want, got.Metrics)
+			}
+			if got.Samples != len(tt.history) {
+				t.Errorf("Samples = %d, want %d", got.Samples, len(tt.history))
+			}
+		})
+	}
+}
+
+func TestDetectScoresAgainstMedianAndMAD(t *testing.T) {
+	got := Detect(summary(120, 0), history(90, 100, 110, 100, 1000))
+	m := metricVerdict(t, got, "p95")
+	if m.Median != 100 || m.MAD != 10 {
+		t.Errorf("median/MAD = %v/%v, want 100/10 despite the outlier", m.Median, m.MAD)
+	}
+	if want := 20 / (madScale * 10); m.Score != want {
+		t.Errorf("Score = %v, want %v", m.Score, want)
+	}
+}
+
+func TestCombine(t *testing.T) {
+	tests := []struct {
+		in   []string
+		want string
+	}{
+		{nil, VerdictUnchanged},
+		{[]string{VerdictUnchanged, VerdictImproved}, VerdictImproved},
+		{[]string{VerdictImproved, VerdictInconclusive}, VerdictInconclusive},
+		{[]string{VerdictInconclusive, VerdictRegressed, VerdictImproved}, VerdictRegressed},
+	}
+	for _, tt := range tests {
+		if got := Combine(tt.in...); got != tt.want {
+			t.Errorf("Combine(%v) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
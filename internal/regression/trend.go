@@ -0,0 +1,102 @@
+package regression
+
+import (
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/torosent/crankfire/internal/store"
+)
+
+// DefaultTrendRuns is how many runs a trend shows by default.
+const DefaultTrendRuns = 20
+
+// Sample is one completed run of a session or set item.
+type Sample struct {
+	RunID     string
+	StartedAt time.Time
+	Summary   store.RunSummary
+}
+
+// Point is one run in a trend.
+type Point struct {
+	RunID     string    `json:"run_id"`
+	StartedAt time.Time `json:"started_at"`
+	P95Ms     float64   `json:"p95_ms"`
+	P99Ms     float64   `json:"p99_ms"`
+	ErrorRate float64   `json:"error_rate"`
+	RPS       float64   `json:"rps"`
+	// Verdict is the run's result against the runs before it, as given by
+	// Detect.
+	Verdict string `json:"verdict"`
+}
+
+// Series is the trend of one session or set item, oldest run first.
+type Series struct {
+	Name   string  `json:"name"`
+	Points []Point `json:"points"`
+}
+
+// Regressions returns the number of points that regressed.
+func (s Series) Regressions() int {
+	n := 0
+	for _, p := range s.Points {
+		if p.Verdict == VerdictRegressed {
+			n++
+		}
+	}
+	return n
+}
+
+// Trend returns the last n of samples as points, oldest first. Each point's
+// verdict compares it with up to window samples before it, including samples
+// older than the n shown. n <= 0 uses DefaultTrendRuns and window <= 0 uses
+// DefaultWindow.
+func Trend(name string, samples []Sample, n, window int) Series {
+	if n <= 0 {
+		n = DefaultTrendRuns
+	}
+	if window <= 0 {
+		window = DefaultWindow
+	}
+	sorted := append([]Sample(nil), samples...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].StartedAt.Before(sorted[j].StartedAt) })
+
+	s := Series{Name: name}
+	for i := max(0, len(sorted)-n); i < len(sorted); i++ {
+		history := make([]store.RunSummary, 0, window)
+		for j := i - 1; j >= 0 && len(history) < window; j-- {
+			history = append(history, sorted[j].Summary)
+		}
+		cur := sorted[i]
+		s.Points = append(s.Points, Point{
+			RunID:     cur.RunID,
+			StartedAt: cur.StartedAt,
+			P95Ms:     cur.Summary.P95Ms,
+			P99Ms:     cur.Summary.P99Ms,
+			ErrorRate: errorRate(cur.Summary),
+			RPS:       rps(cur.Summary),
+			Verdict:   Detect(cur.Summary, history).Verdict,
+		})
+	}
+	return s
+}
+
+// SessionSamples returns the completed runs among runs that sent requests.
+func SessionSamples(runs []store.Run) []Sample {
+	var out []Sample
+	for _, r := range runs {
+		if r.Status != store.RunStatusCompleted || r.Summary.TotalRequests == 0 {
+			continue
+		}
+		out = append(out, Sample{RunID: filepath.Base(r.Dir), StartedAt: r.StartedAt, Summary: r.Summary})
+	}
+	return out
+}
+
+func rps(s store.RunSummary) float64 {
+	if s.DurationSec <= 0 {
+		return 0
+	}
+	return float64(s.TotalRequests) / s.DurationSec
+}
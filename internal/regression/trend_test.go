@@ -0,0 +1,59 @@
+package regression
+
+import (
+	"testing"
+	"time"
+
+	"github.com/torosent/crankfire/internal/store"
+)
+
+func samples(p95s ...float64) []Sample {
+	out := make([]Sample, len(p95s))
+	for i, p := range p95s {
+		s := summary(p, 0)
+		s.DurationSec = 10
+		out[i] = Sample{RunID: string(rune('a' + i)), StartedAt: time.Date(2026, 4, 19, 10, i, 0, 0, time.UTC), Summary: s}
+	}
+	return out
+}
+
+func TestTrend(t *testing.T) {
+	in := samples(100, 102, 98, 101, 180, 99)
+	// Shuffle so Trend has to order by start time.
+	in[0], in[5] = in[5], in[0]
+
+	got := Trend("api", in, 3, 0)
+	if got.Name != "api" || len(got.Points) != 3 {
+		t.Fatalf("got %s with %d points, want api with 3", got.Name, len(got.Points))
+	}
+	wantIDs := []string{"d", "e", "f"}
+	wantVerdicts := []string{VerdictUnchanged, VerdictRegressed, VerdictUnchanged}
+	for i, p := range got.Points {
+		if p.RunID != wantIDs[i] || p.Verdict != wantVerdicts[i] {
+			t.Errorf("point %d = %s %s, want %s %s", i, p.RunID, p.Verdict, wantIDs[i], wantVerdicts[i])
+		}
+	}
+	if p := got.Points[1]; p.P95Ms != 180 || p.RPS != 100 || p.ErrorRate != 0 {
+		t.Errorf("point values = %+v", p)
+	}
+	if got.Regressions() != 1 {
+		t.Errorf("Regressions() = %d, want 1", got.Regressions())
+	}
+
+	first := Trend("api", in, 0, 0).Points[0]
+	if first.RunID != "a" || first.Verdict != VerdictInconclusive {
+		t.Errorf("first point = %s %s, want a inconclusive", first.RunID, first.Verdict)
+	}
+}
+
+func TestSessionSamplesSkipsIncompleteRuns(t *testing.T) {
+	runs := []store.Run{
+		{Dir: "/data/runs/s/r3", Status: store.RunStatusCompleted, Summary: summary(100, 0)},
+		{Dir: "/data/runs/s/r2", Status: store.RunStatusFailed, Summary: summary(100, 0)},
+		{Dir: "/data/runs/s/r1", Status: store.RunStatusCompleted},
+	}
+	got := SessionSamples(runs)
+	if len(got) != 1 || got[0].RunID != "r3" {
+		t.Errorf("SessionSamples = %+v, want only r3", got)
+	}
+}
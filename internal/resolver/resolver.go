@@ -0,0 +1,125 @@
+// Package resolver turns the dns block of the configuration into the dialing
+// hook shared by the HTTP, WebSocket, SSE and gRPC clients: host:port
+// overrides in the style of curl's --resolve, a custom DNS server and
+// round-robin across the addresses of a host.
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/torosent/crankfire/internal/config"
+)
+
+const defaultDNSPort = "53"
+
+// Resolver picks the address each new connection is made to. Connections
+// keep the original host name for the Host header, SNI and gRPC authority.
+type Resolver struct {
+	overrides  map[string][]string
+	dns        *net.Resolver
+	roundRobin bool
+	dialer     *net.Dialer
+	next       sync.Map // host:port -> *atomic.Uint64
+}
+
+// New builds the resolver of c. It returns nil when c is not enabled, so
+// callers keep the default dialers of their transport.
+func New(c config.DNSConfig) (*Resolver, error) {
+	if !c.Enabled() {
+		return nil, nil
+	}
+	r := &Resolver{
+		overrides:  make(map[string][]string, len(c.Resolve)),
+		dns:        net.DefaultResolver,
+		roundRobin: c.RoundRobin,
+	}
+	for key, addrs := range c.Resolve {
+		if len(addrs) == 0 {
+			return nil, fmt.Errorf("dns: resolve %s: no addresses", key)
+		}
+		r.overrides[strings.ToLower(key)] = addrs
+	}
+	if c.Server != "" {
+		server := c.Server
+		if _, _, err := net.SplitHostPort(server); err != nil {
+			server = net.JoinHostPort(strings.Trim(server, "[]"), defaultDNSPort)
+		}
+		r.dns = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, network, server)
+			},
+		}
+	}
+	r.dialer = &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Resolver:  r.dns,
+	}
+	return r, nil
+}
+
+// Resolve returns the ip:port a new connection to addr should use: the next
+// override of addr, or one of the addresses its host resolves to, rotating
+// when round-robin is enabled. IP literals are returned unchanged.
+func (r *Resolver) Resolve(ctx context.Context, network, addr string) (string, error) {
+	key := strings.ToLower(addr)
+	if addrs, ok := r.overrides[key]; ok {
+		return addrs[r.pick(key, len(addrs))], nil
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", err
+	}
+	if net.ParseIP(host) != nil {
+		return addr, nil
+	}
+	ips, err := r.dns.LookupNetIP(ctx, ipNetwork(network), host)
+	if err != nil {
+		return "", err
+	}
+	if len(ips) == 0 {
+		return "", fmt.Errorf("dns: no addresses for %s", host)
+	}
+	idx := 0
+	if r.roundRobin {
+		idx = r.pick(key, len(ips))
+	}
+	return net.JoinHostPort(ips[idx].Unmap().String(), port), nil
+}
+
+// DialContext connects to addr through Resolve. Without an override or
+// round-robin the dialer resolves the host itself, so it can still fall back
+// across addresses.
+func (r *Resolver) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	if _, ok := r.overrides[strings.ToLower(addr)]; !ok && !r.roundRobin {
+		return r.dialer.DialContext(ctx, network, addr)
+	}
+	target, err := r.Resolve(ctx, network, addr)
+	if err != nil {
+		return nil, err
+	}
+	return r.dialer.DialContext(ctx, network, target)
+}
+
+func (r *Resolver) pick(key string, n int) int {
+	v, _ := r.next.LoadOrStore(key, new(atomic.Uint64))
+	return int((v.(*atomic.Uint64).Add(1) - 1) % uint64(n))
+}
+
+func ipNetwork(network string) string {
+	switch network {
+	case "tcp4", "udp4":
+		return "ip4"
+	case "tcp6", "udp6":
+		return "ip6"
+	}
+	return "ip"
+}
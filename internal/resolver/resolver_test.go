@@ -0,0 +1,138 @@
+package resolver
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/torosent/crankfire/internal/config"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func TestNew_Disabled(t *testing.T) {
+	r, err := New(config.DNSConfig{})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if r != nil {
+		t.Errorf("New() = %v, want nil for the defaults", r)
+	}
+}
+
+func TestResolve_OverridesRotate(t *testing.T) {
+	r, err := New(config.DNSConfig{Resolve: map[string][]string{
+		"api.example.com:443": {"10.0.0.1:443", "10.0.0.2:8443"},
+	}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	want := []string{"10.0.0.1:443", "10.0.0.2:8443", "10.0.0.1:443"}
+	for i, w := range want {
+		got, err := r.Resolve(context.Background(), "tcp", "API.example.com:443")
+		if err != nil {
+			t.Fatalf("Resolve() error = %v", err)
+		}
+		if got != w {
+			t.Errorf("Resolve() #%d = %q, want %q", i, got, w)
+		}
+	}
+	got, err := r.Resolve(context.Background(), "tcp", "127.0.0.1:80")
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if got != "127.0.0.1:80" {
+		t.Errorf("Resolve() = %q, want the IP literal unchanged", got)
+	}
+}
+
+func TestDialContext_Override(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	defer ln.Close()
+	go func() {
+		if conn, err := ln.Accept(); err == nil {
+			conn.Close()
+		}
+	}()
+
+	r, err := New(config.DNSConfig{Resolve: map[string][]string{
+		"backend.invalid:80": {ln.Addr().String()},
+	}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	conn, err := r.DialContext(context.Background(), "tcp", "backend.invalid:80")
+	if err != nil {
+		t.Fatalf("DialContext() error = %v", err)
+	}
+	defer conn.Close()
+	if got := conn.RemoteAddr().String(); got != ln.Addr().String() {
+		t.Errorf("RemoteAddr() = %q, want %q", got, ln.Addr().String())
+	}
+}
+
+func TestResolve_ServerRoundRobin(t *testing.T) {
+	server := serveDNS(t, [][4]byte{{10, 0, 0, 1}, {10, 0, 0, 2}})
+	r, err := New(config.DNSConfig{Server: server, RoundRobin: true})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	seen := map[string]int{}
+	for range 4 {
+		got, err := r.Resolve(context.Background(), "tcp4", "api.example.com:443")
+		if err != nil {
+			t.Fatalf("Resolve() error = %v", err)
+		}
+		seen[got]++
+	}
+	if seen["10.0.0.1:443"] != 2 || seen["10.0.0.2:443"] != 2 {
+		t.Errorf("Resolve() addresses = %v, want both answers twice", seen)
+	}
+}
+
+// serveDNS answers every A query over UDP with ips and returns the server's
+// address.
+func serveDNS(t *testing.T, ips [][4]byte) string {
+	t.Helper()
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ListenPacket() error = %v", err)
+	}
+	t.Cleanup(func() { pc.Close() })
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := pc.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			var p dnsmessage.Parser
+			hdr, err := p.Start(buf[:n])
+			if err != nil {
+				continue
+			}
+			q, err := p.Question()
+			if err != nil {
+				continue
+			}
+			b := dnsmessage.NewBuilder(nil, dnsmessage.Header{ID: hdr.ID, Response: true, Authoritative: true})
+			_ = b.StartQuestions()
+			_ = b.Question(q)
+			_ = b.StartAnswers()
+			if q.Type == dnsmessage.TypeA {
+				for _, ip := range ips {
+					rh := dnsmessage.ResourceHeader{Name: q.Name, Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET, TTL: 60}
+					_ = b.AResource(rh, dnsmessage.AResource{A: ip})
+				}
+			}
+			msg, err := b.Finish()
+			if err != nil {
+				continue
+			}
+			_, _ = pc.WriteTo(msg, addr)
+		}
+	}()
+	return pc.LocalAddr().String()
+}
@@ -10,8 +10,11 @@ import (
 	"golang.org/x/time/rate"
 )
 
+// arrivalController paces permits. Wait blocks until the next permit is due
+// and returns the time it was intended to start, or the zero time when the
+// arrival rate is unbounded and there is no schedule to fall behind.
 type arrivalController interface {
-	Wait(ctx context.Context) error
+	Wait(ctx context.Context) (time.Time, error)
 	SetRate(rps float64)
 }
 
@@ -26,6 +29,14 @@ func newArrivalController(opt Options, plan *patternPlan) arrivalController {
 	}
 
 	switch opt.ArrivalModel {
+	case ArrivalModelClosed:
+		active := float64(opt.Concurrency)
+		if plan != nil {
+			active = baseRate
+		}
+		ctrl := newClosedArrival()
+		ctrl.SetRate(active)
+		return ctrl
 	case ArrivalModelPoisson:
 		var sampler func() float64
 		if opt.PoissonSampler != nil {
@@ -50,13 +61,46 @@ func newArrivalController(opt Options, plan *patternPlan) arrivalController {
 // uniformArrival delegates pacing to a rate.Limiter (uniform spacing).
 type uniformArrival struct {
 	limiter *rate.Limiter
+	// next is the intended start of the next permit on the schedule, one
+	// interval after the previous one. The limiter only decides when permits
+	// are issued: once the scheduler has fallen behind (e.g. it was blocked on
+	// busy workers), every permit keeps the slot it should have had, however
+	// far back, rather than the time it was finally issued.
+	next time.Time
 }
 
-func (u *uniformArrival) Wait(ctx context.Context) error {
+func (u *uniformArrival) Wait(ctx context.Context) (time.Time, error) {
 	if u == nil || u.limiter == nil {
-		return nil
+		return time.Time{}, nil
+	}
+	limit := u.limiter.Limit()
+	if limit == rate.Inf || limit <= 0 {
+		u.next = time.Time{}
+		return time.Time{}, u.limiter.Wait(ctx)
+	}
+
+	now := time.Now()
+	res := u.limiter.ReserveN(now, 1)
+	if !res.OK() {
+		return time.Time{}, u.limiter.Wait(ctx)
+	}
+	issued := now
+	if delay := res.DelayFrom(now); delay > 0 {
+		if !sleepCtx(ctx, delay) {
+			res.Cancel()
+			return time.Time{}, ctx.Err()
+		}
+		issued = now.Add(delay)
+	}
+
+	// The burst lets permits run ahead of the schedule; they never start
+	// before they are issued.
+	intended := issued
+	if !u.next.IsZero() && u.next.Before(issued) {
+		intended = u.next
 	}
-	return u.limiter.Wait(ctx)
+	u.next = intended.Add(time.Duration(float64(time.Second) / float64(limit)))
+	return intended, nil
 }
 
 func (u *uniformArrival) SetRate(rps float64) {
@@ -81,22 +125,28 @@ type poissonArrival struct {
 	mu     sync.Mutex
 	rate   float64
 	sample func() float64
+	// next is the intended start of the latest arrival. Each sampled gap is
+	// added to it rather than to the time Wait was called, so arrivals the
+	// scheduler could not hand out while workers were busy keep their slot.
+	next time.Time
 }
 
-func (p *poissonArrival) Wait(ctx context.Context) error {
+func (p *poissonArrival) Wait(ctx context.Context) (time.Time, error) {
 	delay := p.nextDelay()
 	if delay <= 0 {
-		return nil
+		p.next = time.Time{}
+		return time.Time{}, nil
 	}
-	timer := time.NewTimer(delay)
-	defer timer.Stop()
 
-	select {
-	case <-ctx.Done():
-		return ctx.Err()
-	case <-timer.C:
-		return nil
+	now := time.Now()
+	if p.next.IsZero() {
+		p.next = now
+	}
+	p.next = p.next.Add(delay)
+	if wait := p.next.Sub(now); wait > 0 && !sleepCtx(ctx, wait) {
+		return time.Time{}, ctx.Err()
 	}
+	return p.next, nil
 }
 
 func (p *poissonArrival) SetRate(rps float64) {
@@ -129,3 +179,53 @@ func (p *poissonArrival) nextDelay() time.Duration {
 	}
 	return time.Duration(delay)
 }
+
+// closedArrival gates virtual users in the closed model. SetRate sets how many
+// virtual users are active; Wait is a no-op because users pace themselves.
+type closedArrival struct {
+	mu      sync.Mutex
+	active  int
+	changed chan struct{}
+}
+
+func newClosedArrival() *closedArrival {
+	return &closedArrival{changed: make(chan struct{})}
+}
+
+func (c *closedArrival) Wait(ctx context.Context) (time.Time, error) {
+	return time.Time{}, nil
+}
+
+func (c *closedArrival) SetRate(vus float64) {
+	active := int(math.Round(vus))
+	if active < 0 {
+		active = 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if active == c.active {
+		return
+	}
+	c.active = active
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+// waitActive blocks until virtual user idx is within the active count.
+func (c *closedArrival) waitActive(ctx context.Context, idx int) error {
+	for {
+		c.mu.Lock()
+		if idx < c.active {
+			c.mu.Unlock()
+			return nil
+		}
+		changed := c.changed
+		c.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"testing"
 	"time"
+
+	"golang.org/x/time/rate"
 )
 
 func TestPoissonArrivalNextDelayUsesSampler(t *testing.T) {
@@ -21,7 +23,36 @@ func TestPoissonArrivalWaitCancelledContext(t *testing.T) {
 	ctrl.SetRate(0.000001)
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel()
-	if err := ctrl.Wait(ctx); err == nil {
+	if _, err := ctrl.Wait(ctx); err == nil {
 		t.Fatalf("expected context error when cancelled")
 	}
 }
+
+func TestUniformArrivalStampsMissedSlots(t *testing.T) {
+	ctrl := &uniformArrival{limiter: rate.NewLimiter(100, 100)}
+	first, err := ctrl.Wait(context.Background())
+	if err != nil || first.IsZero() {
+		t.Fatalf("expected intended start, got %v (%v)", first, err)
+	}
+
+	// Simulate the scheduler being blocked on busy workers: the next permits
+	// are granted immediately but belong to the slots that were missed.
+	time.Sleep(50 * time.Millisecond)
+	for i := 1; i <= 3; i++ {
+		intended, err := ctrl.Wait(context.Background())
+		if err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+		if want := first.Add(time.Duration(i) * 10 * time.Millisecond); !intended.Equal(want) {
+			t.Fatalf("permit %d intended %s after first, want %s", i, intended.Sub(first), want.Sub(first))
+		}
+	}
+}
+
+func TestUniformArrivalUnlimitedHasNoIntendedStart(t *testing.T) {
+	ctrl := &uniformArrival{limiter: rate.NewLimiter(rate.Inf, 0)}
+	intended, err := ctrl.Wait(context.Background())
+	if err != nil || !intended.IsZero() {
+		t.Fatalf("expected zero intended start without a rate, got %v (%v)", intended, err)
+	}
+}
@@ -4,7 +4,7 @@
 //   - Configurable concurrency levels
 //   - Rate limiting (requests per second)
 //   - Duration-based and count-based test termination
-//   - Multiple arrival models (uniform, Poisson)
+//   - Multiple arrival models (uniform, Poisson, closed virtual users)
 //   - Dynamic load patterns (constant, ramp-up, step)
 //
 // # Basic Usage
@@ -36,6 +36,8 @@
 // The runner supports different arrival models for request pacing:
 //   - [ArrivalModelUniform]: Requests at fixed intervals
 //   - [ArrivalModelPoisson]: Requests following Poisson distribution for realistic traffic
+//   - [ArrivalModelClosed]: Concurrency virtual users each loop through iterations,
+//     pausing for [ThinkTime] and Pacing; load patterns set the virtual user count
 //
 // # Load Patterns
 //
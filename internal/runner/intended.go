@@ -0,0 +1,31 @@
+package runner
+
+import (
+	"context"
+	"time"
+)
+
+type intendedStartKey struct{}
+
+// WithIntendedStart returns a context carrying the time the arrival schedule
+// intended the request to start.
+func WithIntendedStart(ctx context.Context, t time.Time) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, intendedStartKey{}, t)
+}
+
+// IntendedStartFromContext returns the intended start time attached by the
+// runner. It reports false for unpaced runs and the closed model, where
+// requests have no schedule to fall behind.
+func IntendedStartFromContext(ctx context.Context) (time.Time, bool) {
+	if ctx == nil {
+		return time.Time{}, false
+	}
+	t, ok := ctx.Value(intendedStartKey{}).(time.Time)
+	if !ok || t.IsZero() {
+		return time.Time{}, false
+	}
+	return t, true
+}
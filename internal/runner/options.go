@@ -23,7 +23,9 @@ type Options struct {
 	Requester        Requester                   // request executor (required)
 	LimiterFactory   func(rps int) *rate.Limiter // optional injection for tests
 	LoadPatterns     []LoadPattern               // optional pattern schedule
-	ArrivalModel     ArrivalModel                // pacing model (uniform or poisson)
+	ArrivalModel     ArrivalModel                // pacing model (uniform, poisson or closed)
+	ThinkTime        ThinkTime                   // closed model: pause after each iteration
+	Pacing           time.Duration               // closed model: minimum time between iteration starts per VU
 	RandomSeed       int64                       // seed used for stochastic models (0 => auto)
 	PoissonSampler   func() float64              // optional sampler override for tests (returns Exp(1))
 }
@@ -86,4 +88,7 @@ type ArrivalModel string
 const (
 	ArrivalModelUniform ArrivalModel = "uniform"
 	ArrivalModelPoisson ArrivalModel = "poisson"
+	// ArrivalModelClosed runs Concurrency virtual users that each loop
+	// independently. Load pattern rates are read as virtual user counts.
+	ArrivalModelClosed ArrivalModel = "closed"
 )
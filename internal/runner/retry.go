@@ -52,6 +52,11 @@ func (r *retryRequester) Do(ctx context.Context) error {
 		if ctx.Err() != nil {
 			return ctx.Err()
 		}
+		if attempt == 2 {
+			// Retries run on the policy's schedule, not the arrival schedule,
+			// so they carry no queueing delay.
+			ctx = WithIntendedStart(ctx, time.Time{})
+		}
 
 		lastErr = r.inner.Do(ctx)
 		if lastErr == nil {
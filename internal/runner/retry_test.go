@@ -130,3 +130,30 @@ type testLogger struct {
 func (l *testLogger) LogFailure(err error) {
 	l.count++
 }
+
+func TestRetryDropsIntendedStartForRetries(t *testing.T) {
+	var scheduled []bool
+	requester := requesterFunc(func(ctx context.Context) error {
+		_, ok := runner.IntendedStartFromContext(ctx)
+		scheduled = append(scheduled, ok)
+		if len(scheduled) < 3 {
+			return errors.New("boom")
+		}
+		return nil
+	})
+
+	policy := runner.RetryPolicy{MaxAttempts: 3}
+	ctx := runner.WithIntendedStart(context.Background(), time.Now())
+	if err := runner.WithRetry(requester, policy).Do(ctx); err != nil {
+		t.Fatalf("expected success on third attempt, got %v", err)
+	}
+	if len(scheduled) != 3 || !scheduled[0] || scheduled[1] || scheduled[2] {
+		t.Fatalf("expected only the first attempt to carry an intended start, got %v", scheduled)
+	}
+}
+
+type requesterFunc func(ctx context.Context) error
+
+func (f requesterFunc) Do(ctx context.Context) error {
+	return f(ctx)
+}
@@ -2,6 +2,7 @@ package runner
 
 import (
 	"context"
+	"math/rand"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -59,7 +60,60 @@ func (r *Runner) Run(ctx context.Context) Result {
 		go r.runPatternController(patternCtx, patternCancel)
 	}
 
-	permits := make(chan struct{}, r.opt.Concurrency)
+	var wg sync.WaitGroup
+	var scheduleDone <-chan struct{}
+	if closed, ok := r.arrival.(*closedArrival); ok {
+		scheduleDone = r.startVirtualUsers(schedulerCtx, runCtx, closed, &wg, &total, &errs)
+	} else {
+		scheduleDone = r.startWorkers(schedulerCtx, runCtx, &wg, &total, &errs)
+	}
+
+	// Wait until scheduling is complete (duration/total/pattern end or cancellation)
+	// before applying the graceful shutdown window.
+	<-scheduleDone
+
+	workersDone := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(workersDone)
+	}()
+
+	// If GracefulShutdown is negative, cancel immediately after scheduling stops.
+	// Otherwise, allow in-flight requests to finish for up to the configured window,
+	// then force cancel.
+	if r.opt.GracefulShutdown < 0 {
+		runCancel()
+		<-workersDone
+	} else {
+		timer := time.NewTimer(r.opt.GracefulShutdown)
+		defer timer.Stop()
+		select {
+		case <-workersDone:
+			// All workers finished within grace.
+		case <-runCtx.Done():
+			// Parent cancellation (Ctrl+C): just wait for workers to exit.
+			<-workersDone
+		case <-timer.C:
+			// Grace elapsed: force cancel in-flight requests.
+			runCancel()
+			<-workersDone
+		}
+	}
+
+	return Result{
+		Total:    atomic.LoadInt64(&total),
+		Errors:   atomic.LoadInt64(&errs),
+		Duration: time.Since(start),
+	}
+}
+
+// startWorkers runs the open model: a scheduler hands out permits at the
+// arrival rate and Concurrency workers execute one request per permit. Each
+// permit carries its intended start time so requests that queued behind busy
+// workers can be corrected for coordinated omission. The returned channel
+// closes once no more permits will be issued.
+func (r *Runner) startWorkers(schedulerCtx, runCtx context.Context, wg *sync.WaitGroup, total, errs *int64) <-chan struct{} {
+	permits := make(chan time.Time, r.opt.Concurrency)
 
 	scheduleDone := make(chan struct{})
 
@@ -71,78 +125,122 @@ func (r *Runner) Run(ctx context.Context) Result {
 			if schedulerCtx.Err() != nil {
 				return
 			}
-			current := atomic.LoadInt64(&total)
+			current := atomic.LoadInt64(total)
 			if r.opt.TotalRequests > 0 && current >= int64(r.opt.TotalRequests) {
 				return
 			}
+			var intended time.Time
 			if r.arrival != nil {
-				if err := r.arrival.Wait(schedulerCtx); err != nil {
+				var err error
+				if intended, err = r.arrival.Wait(schedulerCtx); err != nil {
 					return
 				}
 			}
 			// Increment total before releasing permit so workers only execute allocated slots.
-			atomic.AddInt64(&total, 1)
+			atomic.AddInt64(total, 1)
 			select {
-			case permits <- struct{}{}:
+			case permits <- intended:
 			case <-schedulerCtx.Done():
-				atomic.AddInt64(&total, -1)
+				atomic.AddInt64(total, -1)
 				return
 			}
 		}
 	}()
 
-	var wg sync.WaitGroup
 	wg.Add(r.opt.Concurrency)
 	for i := 0; i < r.opt.Concurrency; i++ {
 		go func() {
 			defer wg.Done()
-			for range permits {
+			for intended := range permits {
 				if r.opt.Requester != nil {
-					err := r.opt.Requester.Do(runCtx)
+					reqCtx := runCtx
+					if !intended.IsZero() {
+						reqCtx = WithIntendedStart(runCtx, intended)
+					}
+					err := r.opt.Requester.Do(reqCtx)
 					if err != nil {
-						atomic.AddInt64(&errs, 1)
+						atomic.AddInt64(errs, 1)
 					}
 				}
 			}
 		}()
 	}
 
-	// Wait until scheduling is complete (duration/total/pattern end or cancellation)
-	// before applying the graceful shutdown window.
-	<-scheduleDone
+	return scheduleDone
+}
 
-	workersDone := make(chan struct{})
+// startVirtualUsers runs the closed model: each virtual user loops through
+// iterations on its own, pausing for think time and pacing in between. Users
+// beyond the active count set by the arrival controller stay parked. The
+// returned channel closes once no more iterations will be started.
+func (r *Runner) startVirtualUsers(schedulerCtx, runCtx context.Context, gate *closedArrival, wg *sync.WaitGroup, total, errs *int64) <-chan struct{} {
+	vus := r.opt.Concurrency
+	if r.plan != nil {
+		vus = r.plan.maxBurst()
+	}
+
+	usersDone := make(chan struct{})
+	wg.Add(vus)
+	for i := 0; i < vus; i++ {
+		rnd := rand.New(rand.NewSource(r.opt.RandomSeed + int64(i)))
+		go func(idx int) {
+			defer wg.Done()
+			for {
+				if err := gate.waitActive(schedulerCtx, idx); err != nil {
+					return
+				}
+				if schedulerCtx.Err() != nil {
+					return
+				}
+				// Claim the iteration before running it so TotalRequests is exact.
+				if n := atomic.AddInt64(total, 1); r.opt.TotalRequests > 0 && n > int64(r.opt.TotalRequests) {
+					atomic.AddInt64(total, -1)
+					return
+				}
+				start := time.Now()
+				if r.opt.Requester != nil {
+					if err := r.opt.Requester.Do(runCtx); err != nil {
+						atomic.AddInt64(errs, 1)
+					}
+				}
+				pause := r.opt.ThinkTime.sample(rnd)
+				if remaining := r.opt.Pacing - time.Since(start); remaining > pause {
+					pause = remaining
+				}
+				if !sleepCtx(schedulerCtx, pause) {
+					return
+				}
+			}
+		}(i)
+	}
 	go func() {
 		wg.Wait()
-		close(workersDone)
+		close(usersDone)
 	}()
 
-	// If GracefulShutdown is negative, cancel immediately after scheduling stops.
-	// Otherwise, allow in-flight requests to finish for up to the configured window,
-	// then force cancel.
-	if r.opt.GracefulShutdown < 0 {
-		runCancel()
-		<-workersDone
-	} else {
-		timer := time.NewTimer(r.opt.GracefulShutdown)
-		defer timer.Stop()
+	scheduleDone := make(chan struct{})
+	go func() {
+		defer close(scheduleDone)
 		select {
-		case <-workersDone:
-			// All workers finished within grace.
-		case <-runCtx.Done():
-			// Parent cancellation (Ctrl+C): just wait for workers to exit.
-			<-workersDone
-		case <-timer.C:
-			// Grace elapsed: force cancel in-flight requests.
-			runCancel()
-			<-workersDone
+		case <-schedulerCtx.Done():
+		case <-usersDone:
 		}
-	}
+	}()
+	return scheduleDone
+}
 
-	return Result{
-		Total:    atomic.LoadInt64(&total),
-		Errors:   atomic.LoadInt64(&errs),
-		Duration: time.Since(start),
+// sleepCtx pauses for d and reports false if ctx ended first.
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
 	}
 }
 
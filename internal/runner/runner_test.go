@@ -2,6 +2,8 @@ package runner_test
 
 import (
 	"context"
+	"sort"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -202,3 +204,228 @@ func TestRunnerPoissonArrivalUsesSampler(t *testing.T) {
 		t.Fatalf("poisson sampler was never invoked")
 	}
 }
+
+// inflightRequester tracks how many calls run at the same time.
+type inflightRequester struct {
+	latency  time.Duration
+	calls    int64
+	inflight int64
+	peak     int64
+}
+
+func (f *inflightRequester) Do(ctx context.Context) error {
+	atomic.AddInt64(&f.calls, 1)
+	n := atomic.AddInt64(&f.inflight, 1)
+	defer atomic.AddInt64(&f.inflight, -1)
+	for {
+		peak := atomic.LoadInt64(&f.peak)
+		if n <= peak || atomic.CompareAndSwapInt64(&f.peak, peak, n) {
+			break
+		}
+	}
+	select {
+	case <-time.After(f.latency):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return nil
+}
+
+func TestRunnerClosedModelThinkTime(t *testing.T) {
+	req := &inflightRequester{latency: time.Millisecond}
+	r := runner.New(runner.Options{
+		Concurrency:  3,
+		Duration:     200 * time.Millisecond,
+		Requester:    req,
+		ArrivalModel: runner.ArrivalModelClosed,
+		ThinkTime:    runner.ThinkTime{Distribution: runner.ThinkTimeConstant, Mean: 20 * time.Millisecond},
+	})
+	res := r.Run(context.Background())
+	if peak := atomic.LoadInt64(&req.peak); peak > 3 {
+		t.Fatalf("expected at most 3 virtual users in flight, got %d", peak)
+	}
+	// 3 users x ~200ms / ~21ms per iteration.
+	if res.Total < 15 || res.Total > 40 {
+		t.Fatalf("expected think time to bound iterations, got %d", res.Total)
+	}
+}
+
+func TestRunnerClosedModelPacing(t *testing.T) {
+	req := &inflightRequester{latency: time.Millisecond}
+	r := runner.New(runner.Options{
+		Concurrency:  1,
+		Duration:     230 * time.Millisecond,
+		Requester:    req,
+		ArrivalModel: runner.ArrivalModelClosed,
+		Pacing:       50 * time.Millisecond,
+	})
+	res := r.Run(context.Background())
+	// Iterations start at 0, 50, 100, 150 and 200ms.
+	if res.Total < 4 || res.Total > 6 {
+		t.Fatalf("expected pacing to start one iteration every 50ms, got %d", res.Total)
+	}
+}
+
+func TestRunnerClosedModelRespectsTotal(t *testing.T) {
+	req := &inflightRequester{}
+	r := runner.New(runner.Options{
+		Concurrency:   4,
+		TotalRequests: 25,
+		Requester:     req,
+		ArrivalModel:  runner.ArrivalModelClosed,
+	})
+	res := r.Run(context.Background())
+	if res.Total != 25 || atomic.LoadInt64(&req.calls) != 25 {
+		t.Fatalf("expected exactly 25 iterations, got total=%d calls=%d", res.Total, req.calls)
+	}
+}
+
+func TestRunnerClosedModelRampsVirtualUsers(t *testing.T) {
+	req := &inflightRequester{latency: 20 * time.Millisecond}
+	patterns := []runner.LoadPattern{
+		{
+			Type: runner.LoadPatternTypeStep,
+			Steps: []runner.LoadStep{
+				{RPS: 1, Duration: 150 * time.Millisecond},
+				{RPS: 4, Duration: 150 * time.Millisecond},
+			},
+		},
+	}
+	r := runner.New(runner.Options{
+		Concurrency:  1,
+		Requester:    req,
+		ArrivalModel: runner.ArrivalModelClosed,
+		LoadPatterns: patterns,
+	})
+
+	done := make(chan runner.Result)
+	go func() { done <- r.Run(context.Background()) }()
+
+	time.Sleep(100 * time.Millisecond)
+	if peak := atomic.LoadInt64(&req.peak); peak != 1 {
+		t.Fatalf("expected 1 virtual user during the first step, got peak %d", peak)
+	}
+	res := <-done
+	if peak := atomic.LoadInt64(&req.peak); peak != 4 {
+		t.Fatalf("expected ramp to 4 virtual users, got peak %d", peak)
+	}
+	if res.Duration > 500*time.Millisecond {
+		t.Fatalf("expected run to end with the pattern timeline, got %s", res.Duration)
+	}
+}
+
+// queueRecorder records how far behind its intended start each call began.
+type queueRecorder struct {
+	latency time.Duration
+	mu      sync.Mutex
+	delays  []time.Duration
+	unpaced int
+}
+
+func (q *queueRecorder) Do(ctx context.Context) error {
+	intended, ok := runner.IntendedStartFromContext(ctx)
+	q.mu.Lock()
+	if ok {
+		q.delays = append(q.delays, time.Since(intended))
+	} else {
+		q.unpaced++
+	}
+	q.mu.Unlock()
+	time.Sleep(q.latency)
+	return nil
+}
+
+func TestRunnerPropagatesIntendedStartUnderSaturation(t *testing.T) {
+	req := &queueRecorder{latency: 30 * time.Millisecond}
+	r := runner.New(runner.Options{
+		Concurrency:   1,
+		TotalRequests: 8,
+		RatePerSecond: 100,
+		Requester:     req,
+	})
+	r.Run(context.Background())
+
+	if req.unpaced != 0 || len(req.delays) != 8 {
+		t.Fatalf("expected every paced request to carry an intended start, got %d paced, %d unpaced", len(req.delays), req.unpaced)
+	}
+	// One worker serving 30ms requests at 100 req/s falls further behind with
+	// every request; the last one queued for roughly 7 x 30ms.
+	if last := req.delays[len(req.delays)-1]; last < 150*time.Millisecond {
+		t.Fatalf("expected queue delay to accumulate, last request waited %s", last)
+	}
+}
+
+func TestRunnerPoissonPropagatesIntendedStartUnderSaturation(t *testing.T) {
+	req := &queueRecorder{latency: 30 * time.Millisecond}
+	r := runner.New(runner.Options{
+		Concurrency:    1,
+		TotalRequests:  8,
+		RatePerSecond:  100,
+		Requester:      req,
+		ArrivalModel:   runner.ArrivalModelPoisson,
+		PoissonSampler: func() float64 { return 1 },
+	})
+	r.Run(context.Background())
+
+	if req.unpaced != 0 || len(req.delays) != 8 {
+		t.Fatalf("expected every paced request to carry an intended start, got %d paced, %d unpaced", len(req.delays), req.unpaced)
+	}
+	// Arrivals are due every 10ms but each takes 30ms to serve, so the last
+	// one queued for roughly 7 x 20ms.
+	if last := req.delays[len(req.delays)-1]; last < 100*time.Millisecond {
+		t.Fatalf("expected queue delay to accumulate, last request waited %s", last)
+	}
+}
+
+// stallRecorder is a queueRecorder whose first call stalls.
+type stallRecorder struct {
+	queueRecorder
+	stall time.Duration
+	calls atomic.Int64
+}
+
+func (s *stallRecorder) Do(ctx context.Context) error {
+	if s.calls.Add(1) == 1 {
+		time.Sleep(s.stall)
+	}
+	return s.queueRecorder.Do(ctx)
+}
+
+func TestRunnerCorrectsStallsLongerThanBurst(t *testing.T) {
+	// At 1000 req/s with a burst of 1 the limiter can only hold 1ms of missed
+	// permits; the stall lasts 200ms.
+	req := &stallRecorder{stall: 200 * time.Millisecond}
+	r := runner.New(runner.Options{
+		Concurrency:    1,
+		TotalRequests:  500,
+		RatePerSecond:  1000,
+		Requester:      req,
+		LimiterFactory: func(rps int) *rate.Limiter { return rate.NewLimiter(rate.Limit(rps), 1) },
+	})
+	r.Run(context.Background())
+
+	if len(req.delays) != 500 {
+		t.Fatalf("expected 500 paced requests, got %d", len(req.delays))
+	}
+	corrected := append([]time.Duration(nil), req.delays...)
+	sort.Slice(corrected, func(i, j int) bool { return corrected[i] < corrected[j] })
+	// Every request after the stall is due before it can be issued, so the
+	// schedule stays about 200ms behind for the rest of the run.
+	if p99 := corrected[len(corrected)*99/100]; p99 < 150*time.Millisecond {
+		t.Fatalf("expected corrected p99 to include the stall, got %s", p99)
+	}
+}
+
+func TestRunnerUnpacedRequestsHaveNoIntendedStart(t *testing.T) {
+	req := &queueRecorder{}
+	r := runner.New(runner.Options{
+		Concurrency:   2,
+		TotalRequests: 5,
+		Requester:     req,
+	})
+	r.Run(context.Background())
+
+	if len(req.delays) != 0 || req.unpaced != 5 {
+		t.Fatalf("expected no intended start without a rate, got %d paced, %d unpaced", len(req.delays), req.unpaced)
+	}
+}
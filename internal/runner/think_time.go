@@ -0,0 +1,45 @@
+package runner
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+type ThinkTimeDistribution string
+
+const (
+	ThinkTimeConstant    ThinkTimeDistribution = "constant"
+	ThinkTimeUniform     ThinkTimeDistribution = "uniform"
+	ThinkTimeExponential ThinkTimeDistribution = "exponential"
+)
+
+// ThinkTime describes the pause a virtual user takes between iterations in
+// the closed model.
+type ThinkTime struct {
+	Distribution ThinkTimeDistribution
+	Mean         time.Duration // constant value or exponential mean
+	Min          time.Duration // uniform lower bound
+	Max          time.Duration // uniform upper bound
+}
+
+func (t ThinkTime) sample(rnd *rand.Rand) time.Duration {
+	switch t.Distribution {
+	case ThinkTimeUniform:
+		if t.Max <= t.Min {
+			return t.Min
+		}
+		return t.Min + time.Duration(rnd.Int63n(int64(t.Max-t.Min)+1))
+	case ThinkTimeExponential:
+		if t.Mean <= 0 {
+			return 0
+		}
+		delay := rnd.ExpFloat64() * float64(t.Mean)
+		if delay > math.MaxInt64 {
+			delay = math.MaxInt64
+		}
+		return time.Duration(delay)
+	default:
+		return t.Mean
+	}
+}
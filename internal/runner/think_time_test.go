@@ -0,0 +1,37 @@
+package runner
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestThinkTimeSample(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	constant := ThinkTime{Distribution: ThinkTimeConstant, Mean: 250 * time.Millisecond}
+	if got := constant.sample(rnd); got != 250*time.Millisecond {
+		t.Fatalf("constant sample = %s, want 250ms", got)
+	}
+
+	uniform := ThinkTime{Distribution: ThinkTimeUniform, Min: time.Second, Max: 2 * time.Second}
+	for i := 0; i < 1000; i++ {
+		if got := uniform.sample(rnd); got < time.Second || got > 2*time.Second {
+			t.Fatalf("uniform sample %s outside [1s, 2s]", got)
+		}
+	}
+
+	exponential := ThinkTime{Distribution: ThinkTimeExponential, Mean: 100 * time.Millisecond}
+	var sum time.Duration
+	const n = 10000
+	for i := 0; i < n; i++ {
+		sum += exponential.sample(rnd)
+	}
+	if mean := sum / n; mean < 90*time.Millisecond || mean > 110*time.Millisecond {
+		t.Fatalf("exponential mean = %s, want ~100ms", mean)
+	}
+
+	if got := (ThinkTime{}).sample(rnd); got != 0 {
+		t.Fatalf("zero think time sample = %s, want 0", got)
+	}
+}
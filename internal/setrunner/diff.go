@@ -26,6 +26,9 @@ type DiffResult struct {
 	A, B           store.SetRun
 	Rows           []DiffRow
 	OverallVerdict string
+	// History is B checked against the runs before it; nil when B has no
+	// earlier runs or history was not requested.
+	History *store.SetRegression `json:",omitempty"`
 }
 
 func Diff(a, b store.SetRun) DiffResult {
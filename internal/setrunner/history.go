@@ -0,0 +1,101 @@
+package setrunner
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/torosent/crankfire/internal/regression"
+	"github.com/torosent/crankfire/internal/store"
+)
+
+// PreviousRuns returns up to n runs from runs that started before run, newest
+// first. n <= 0 uses regression.DefaultWindow.
+func PreviousRuns(runs []store.SetRun, run store.SetRun, n int) []store.SetRun {
+	if n <= 0 {
+		n = regression.DefaultWindow
+	}
+	var prev []store.SetRun
+	for _, r := range runs {
+		if !r.StartedAt.Before(run.StartedAt) {
+			continue
+		}
+		prev = append(prev, r)
+	}
+	sort.Slice(prev, func(i, j int) bool { return prev[i].StartedAt.After(prev[j].StartedAt) })
+	if len(prev) > n {
+		prev = prev[:n]
+	}
+	return prev
+}
+
+// DetectRegression checks every completed item of run against the same item
+// in history. Items that did not complete in a history run are left out of
+// its samples. It returns nil when history is empty.
+func DetectRegression(run store.SetRun, history []store.SetRun) *store.SetRegression {
+	if len(history) == 0 {
+		return nil
+	}
+	res := &store.SetRegression{Window: len(history)}
+	past := make([]map[string]itemRef, len(history))
+	for i, h := range history {
+		past[i] = flattenItems(h)
+	}
+	var verdicts []string
+	for _, stage := range run.Stages {
+		for _, item := range stage.Items {
+			if !hasSamples(item) {
+				continue
+			}
+			var samples []store.RunSummary
+			for _, items := range past {
+				if ref, ok := items[item.Name]; ok && hasSamples(ref.it) {
+					samples = append(samples, ref.it.Summary)
+				}
+			}
+			ir := regression.Detect(item.Summary, samples)
+			ir.Name = item.Name
+			res.Items = append(res.Items, ir)
+			verdicts = append(verdicts, ir.Verdict)
+		}
+	}
+	res.Verdict = regression.Combine(verdicts...)
+	return res
+}
+
+// ItemTrends returns the trend of every item over the last n of runs, using
+// window earlier runs for each point's verdict. Items are ordered as in the
+// newest run that has them; runs where an item did not complete are left out
+// of its trend.
+func ItemTrends(runs []store.SetRun, n, window int) []regression.Series {
+	sorted := append([]store.SetRun(nil), runs...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].StartedAt.After(sorted[j].StartedAt) })
+
+	var names []string
+	samples := map[string][]regression.Sample{}
+	for _, r := range sorted {
+		for _, stage := range r.Stages {
+			for _, item := range stage.Items {
+				if _, seen := samples[item.Name]; !seen {
+					names = append(names, item.Name)
+					samples[item.Name] = nil
+				}
+				if hasSamples(item) {
+					samples[item.Name] = append(samples[item.Name], regression.Sample{
+						RunID:     filepath.Base(r.Dir),
+						StartedAt: r.StartedAt,
+						Summary:   item.Summary,
+					})
+				}
+			}
+		}
+	}
+	out := make([]regression.Series, 0, len(names))
+	for _, name := range names {
+		out = append(out, regression.Trend(name, samples[name], n, window))
+	}
+	return out
+}
+
+func hasSamples(item store.ItemResult) bool {
+	return item.Status == store.RunStatusCompleted && item.Summary.TotalRequests > 0
+}
@@ -0,0 +1,95 @@
+package setrunner_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/torosent/crankfire/internal/regression"
+	"github.com/torosent/crankfire/internal/setrunner"
+	"github.com/torosent/crankfire/internal/store"
+)
+
+func mkRunAt(day int, items ...store.ItemResult) store.SetRun {
+	r := mkRun(items...)
+	r.StartedAt = time.Date(2026, 4, day, 0, 0, 0, 0, time.UTC)
+	return r
+}
+
+func TestPreviousRunsNewestFirstBeforeRun(t *testing.T) {
+	var runs []store.SetRun
+	for day := 1; day <= 8; day++ {
+		runs = append(runs, mkRunAt(day))
+	}
+	got := setrunner.PreviousRuns(runs, runs[6], 3)
+	if len(got) != 3 {
+		t.Fatalf("len = %d, want 3", len(got))
+	}
+	for i, day := range []int{6, 5, 4} {
+		if got[i].StartedAt.Day() != day {
+			t.Errorf("run %d started on day %d, want %d", i, got[i].StartedAt.Day(), day)
+		}
+	}
+	if got := setrunner.PreviousRuns(runs, runs[7], 0); len(got) != regression.DefaultWindow {
+		t.Errorf("default window len = %d, want %d", len(got), regression.DefaultWindow)
+	}
+}
+
+func TestDetectRegressionPerItem(t *testing.T) {
+	var history []store.SetRun
+	for day, p95 := range []float64{100, 102, 98, 101} {
+		history = append(history, mkRunAt(day+1, mkItem("api", p95, 0, 1000, 10), mkItem("auth", 50, 0, 1000, 10)))
+	}
+	failed := mkItem("auth", 500, 0, 1000, 10)
+	failed.Status = store.RunStatusFailed
+	history = append(history, mkRunAt(5, mkItem("api", 99, 0, 1000, 10), failed))
+
+	run := mkRunAt(6, mkItem("api", 160, 0, 1000, 10), mkItem("auth", 50, 0, 1000, 10), mkItem("new", 10, 0, 1000, 10))
+	got := setrunner.DetectRegression(run, history)
+	if got == nil {
+		t.Fatal("DetectRegression returned nil")
+	}
+	if got.Verdict != regression.VerdictRegressed || got.Window != 5 {
+		t.Errorf("Verdict = %s, Window = %d; want regressed, 5", got.Verdict, got.Window)
+	}
+	want := map[string]struct {
+		verdict string
+		samples int
+	}{
+		"api":  {regression.VerdictRegressed, 5},
+		"auth": {regression.VerdictUnchanged, 4},
+		"new":  {regression.VerdictInconclusive, 0},
+	}
+	for _, item := range got.Items {
+		w := want[item.Name]
+		if item.Verdict != w.verdict || item.Samples != w.samples {
+			t.Errorf("%s: verdict %s samples %d, want %s %d", item.Name, item.Verdict, item.Samples, w.verdict, w.samples)
+		}
+	}
+	if setrunner.DetectRegression(run, nil) != nil {
+		t.Error("expected nil without history")
+	}
+}
+
+func TestItemTrends(t *testing.T) {
+	var runs []store.SetRun
+	for day, p95 := range []float64{100, 102, 98, 101, 180} {
+		r := mkRunAt(day+1, mkItem("api", p95, 0, 1000, 10))
+		r.Dir = "/data/runs/sets/x/run" + string(rune('1'+day))
+		runs = append(runs, r)
+	}
+	failed := mkItem("auth", 50, 0, 1000, 10)
+	failed.Status = store.RunStatusFailed
+	runs[4].Stages[0].Items = append(runs[4].Stages[0].Items, failed)
+
+	got := setrunner.ItemTrends(runs, 3, 0)
+	if len(got) != 2 || got[0].Name != "api" || got[1].Name != "auth" {
+		t.Fatalf("series = %+v, want api then auth", got)
+	}
+	api := got[0]
+	if len(api.Points) != 3 || api.Points[2].RunID != "run5" || api.Points[2].Verdict != regression.VerdictRegressed {
+		t.Errorf("api points = %+v", api.Points)
+	}
+	if len(got[1].Points) != 0 {
+		t.Errorf("auth never completed but has points: %+v", got[1].Points)
+	}
+}
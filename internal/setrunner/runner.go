@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"sync"
 	"time"
 
 	"github.com/torosent/crankfire/internal/config"
+	"github.com/torosent/crankfire/internal/metrics"
 	"github.com/torosent/crankfire/internal/store"
 )
 
@@ -33,10 +36,13 @@ type Event struct {
 
 // ItemRun is what a Builder hands back: a Run thunk that executes the
 // configured load test, a Snapshot accessor for live metrics, and a Cleanup.
+// Artifacts, if set, returns the raw histograms and time series after Run
+// so they can be stored with the item.
 type ItemRun struct {
-	Run      func(ctx context.Context) (store.RunSummary, error)
-	Snapshot func() MetricSnapshot
-	Cleanup  func()
+	Run       func(ctx context.Context) (store.RunSummary, error)
+	Snapshot  func() MetricSnapshot
+	Artifacts func() (*metrics.Measurements, []metrics.DataPoint, error)
+	Cleanup   func()
 }
 
 // Builder constructs a single item's runtime.
@@ -103,6 +109,9 @@ stageLoop:
 	}
 	run.Status = overallStatus
 	run.EndedAt = time.Now().UTC()
+	if runs, err := r.store.ListSetRuns(ctx, setID); err == nil {
+		run.Regression = DetectRegression(run, PreviousRuns(runs, run, set.RegressionWindow))
+	}
 
 	if err := r.store.FinalizeSetRun(ctx, run); err != nil {
 		return run, fmt.Errorf("finalize: %w", err)
@@ -161,6 +170,14 @@ func (r *Runner) runItem(ctx context.Context, set store.Set, item store.SetItem,
 		return res
 	}
 	cfg := ApplyOverrides(sess.Config, item.Overrides)
+	if cfg.OTLPMetrics.Enabled() {
+		cfg.OTLPMetrics = cfg.OTLPMetrics.WithAttributes(
+			config.ResourceSessionID, item.SessionID,
+			config.ResourceSetID, set.ID,
+			config.ResourceSetRunID, filepath.Base(runDir),
+			config.ResourceSetItem, item.Name,
+		)
+	}
 
 	ir, err := r.builder.Build(ctx, cfg, item.Name)
 	if err != nil {
@@ -185,10 +202,34 @@ func (r *Runner) runItem(ctx context.Context, set store.Set, item store.SetItem,
 	} else {
 		res.Status = store.RunStatusCompleted
 	}
+	if ir.Artifacts != nil && runDir != "" {
+		res.RunDir = writeItemArtifacts(runDir, item.Name, ir.Artifacts)
+	}
 	emit(events, Event{Kind: EventItemEnded, Item: item.Name, Result: &res})
 	return res
 }
 
+// writeItemArtifacts stores an item's histograms and time series under
+// <runDir>/items/<name> and returns that directory, or "" if nothing was
+// written. Failing to write them does not fail the item.
+func writeItemArtifacts(runDir, name string, artifacts func() (*metrics.Measurements, []metrics.DataPoint, error)) string {
+	if name == "" || name != filepath.Base(name) || name == "." || name == ".." {
+		return ""
+	}
+	m, series, err := artifacts()
+	if err != nil {
+		return ""
+	}
+	dir := filepath.Join(runDir, "items", name)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return ""
+	}
+	if err := store.WriteRunArtifacts(dir, m, series); err != nil {
+		return ""
+	}
+	return dir
+}
+
 func emit(ch chan<- Event, e Event) {
 	if ch == nil {
 		return
@@ -4,11 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path/filepath"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/torosent/crankfire/internal/config"
+	"github.com/torosent/crankfire/internal/metrics"
 	"github.com/torosent/crankfire/internal/setrunner"
 	"github.com/torosent/crankfire/internal/store"
 )
@@ -189,3 +192,93 @@ func TestRunnerEmitsEvents(t *testing.T) {
 	}
 	_ = fmt.Sprint
 }
+
+// configBuilder records the config each item was built with.
+type configBuilder struct {
+	fakeBuilder
+	mu   sync.Mutex
+	cfgs map[string]config.Config
+}
+
+func (c *configBuilder) Build(ctx context.Context, cfg config.Config, itemName string) (setrunner.ItemRun, error) {
+	c.mu.Lock()
+	c.cfgs[itemName] = cfg
+	c.mu.Unlock()
+	return c.fakeBuilder.Build(ctx, cfg, itemName)
+}
+
+func TestRunnerAddsOTLPResourceAttributes(t *testing.T) {
+	st, sess := newStoreWithSession(t)
+	sess.Config.OTLPMetrics = config.OTLPMetricsConfig{Endpoint: "localhost:4317"}
+	if err := st.SaveSession(context.Background(), sess); err != nil {
+		t.Fatalf("SaveSession: %v", err)
+	}
+	in := store.Set{
+		Name:   "otlp",
+		Stages: []store.Stage{{Name: "s1", Items: []store.SetItem{{Name: "a", SessionID: sess.ID}}}},
+	}
+	if err := st.SaveSet(context.Background(), in); err != nil {
+		t.Fatalf("SaveSet: %v", err)
+	}
+	list, _ := st.ListSets(context.Background())
+	b := &configBuilder{cfgs: map[string]config.Config{}}
+	run, err := setrunner.New(st, b).Run(context.Background(), list[0].ID, nil)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	attrs := b.cfgs["a"].OTLPMetrics.Attributes
+	for key, want := range map[string]string{
+		config.ResourceSessionID: sess.ID,
+		config.ResourceSetID:     list[0].ID,
+		config.ResourceSetRunID:  filepath.Base(run.Dir),
+		config.ResourceSetItem:   "a",
+	} {
+		if attrs[key] != want {
+			t.Errorf("attribute %s = %q, want %q", key, attrs[key], want)
+		}
+	}
+}
+
+type artifactBuilder struct{ fakeBuilder }
+
+func (a *artifactBuilder) Build(ctx context.Context, cfg config.Config, itemName string) (setrunner.ItemRun, error) {
+	ir, err := a.fakeBuilder.Build(ctx, cfg, itemName)
+	ir.Artifacts = func() (*metrics.Measurements, []metrics.DataPoint, error) {
+		c := metrics.NewCollector()
+		c.RecordRequest(5*time.Millisecond, nil, &metrics.RequestMetadata{Endpoint: itemName})
+		m, err := c.Measurements()
+		return m, []metrics.DataPoint{{TotalRequests: 1}}, err
+	}
+	return ir, err
+}
+
+func TestRunnerStoresItemArtifacts(t *testing.T) {
+	st, sess := newStoreWithSession(t)
+	in := store.Set{
+		Name:   "artifacts",
+		Stages: []store.Stage{{Name: "s1", Items: []store.SetItem{{Name: "a", SessionID: sess.ID}}}},
+	}
+	if err := st.SaveSet(context.Background(), in); err != nil {
+		t.Fatalf("SaveSet: %v", err)
+	}
+	list, _ := st.ListSets(context.Background())
+	run, err := setrunner.New(st, &artifactBuilder{}).Run(context.Background(), list[0].ID, nil)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	item := run.Stages[0].Items[0]
+	if want := filepath.Join(run.Dir, "items", "a"); item.RunDir != want {
+		t.Fatalf("RunDir = %q, want %q", item.RunDir, want)
+	}
+	m, err := store.ReadMeasurements(item.RunDir)
+	if err != nil {
+		t.Fatalf("ReadMeasurements: %v", err)
+	}
+	if m.Endpoints["a"].Successes != 1 {
+		t.Errorf("endpoint measurements = %+v", m.Endpoints)
+	}
+	if series, err := store.ReadTimeSeries(item.RunDir); err != nil || len(series) != 1 {
+		t.Errorf("ReadTimeSeries = %v, %v", series, err)
+	}
+}
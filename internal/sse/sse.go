@@ -3,9 +3,12 @@ package sse
 import (
 	"bufio"
 	"context"
+	"crypto/tls"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -37,23 +40,50 @@ type Metrics struct {
 	Errors             int64
 }
 
+// DefaultRetryDelay is the reconnection delay used until the server sends a
+// retry field.
+const DefaultRetryDelay = 3 * time.Second
+
 // Client represents an SSE client connection.
 type Client struct {
 	url        string
 	headers    http.Header
 	httpClient *http.Client
 	resp       *http.Response
-	reader     *bufio.Reader
+	cancel     context.CancelFunc
+	events     chan readResult
+	done       chan struct{}
 	mu         sync.Mutex
 	metrics    *clientmetrics.ClientMetrics
 	eventsRecv int64 // SSE-specific: count of complete events (not lines)
+
+	stream         bool
+	connectTimeout time.Duration
+	retryDelay     time.Duration
+	lastEventID    string
 }
 
 // Config configures the SSE client behavior.
 type Config struct {
 	URL     string
 	Headers http.Header
+	// Timeout bounds the whole response, or only connection setup and
+	// response headers when Stream is set.
 	Timeout time.Duration
+	// Stream keeps the response open indefinitely for long-lived subscribers.
+	Stream bool
+	// RetryDelay is the initial reconnection delay (default DefaultRetryDelay).
+	RetryDelay time.Duration
+	// TLSConfig is used for https:// URLs (nil keeps the defaults).
+	TLSConfig *tls.Config
+	// DialContext opens the TCP connection (nil keeps the default dialer).
+	DialContext func(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+type readResult struct {
+	event Event
+	idSet bool // the event carried an id field, possibly empty
+	err   error
 }
 
 // NewClient creates a new SSE client with the given configuration.
@@ -61,18 +91,38 @@ func NewClient(cfg Config) *Client {
 	if cfg.Timeout == 0 {
 		cfg.Timeout = 30 * time.Second
 	}
+	if cfg.RetryDelay <= 0 {
+		cfg.RetryDelay = DefaultRetryDelay
+	}
+
+	httpTimeout := cfg.Timeout
+	if cfg.Stream {
+		httpTimeout = 0
+	}
+
+	httpClient := &http.Client{Timeout: httpTimeout}
+	if cfg.TLSConfig != nil || cfg.DialContext != nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = cfg.TLSConfig
+		if cfg.DialContext != nil {
+			transport.DialContext = cfg.DialContext
+		}
+		httpClient.Transport = transport
+	}
 
 	return &Client{
-		url:     cfg.URL,
-		headers: cfg.Headers,
-		httpClient: &http.Client{
-			Timeout: cfg.Timeout,
-		},
-		metrics: clientmetrics.New(),
+		url:            cfg.URL,
+		headers:        cfg.Headers,
+		httpClient:     httpClient,
+		metrics:        clientmetrics.New(),
+		stream:         cfg.Stream,
+		connectTimeout: cfg.Timeout,
+		retryDelay:     cfg.RetryDelay,
 	}
 }
 
-// Connect establishes an SSE connection.
+// Connect establishes an SSE connection. When an event ID has been seen on a
+// previous connection it is sent as Last-Event-ID so the server can resume.
 func (c *Client) Connect(ctx context.Context) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -81,8 +131,19 @@ func (c *Client) Connect(ctx context.Context) error {
 		return fmt.Errorf("already connected")
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	// The stream outlives ctx because pooled connections serve many requests;
+	// ctx only bounds connection setup.
+	streamCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(ctx, cancel)
+	defer stop()
+	if c.stream {
+		timer := time.AfterFunc(c.connectTimeout, cancel)
+		defer timer.Stop()
+	}
+
+	req, err := http.NewRequestWithContext(streamCtx, http.MethodGet, c.url, nil)
 	if err != nil {
+		cancel()
 		c.metrics.IncrementErrors()
 		return fmt.Errorf("create request: %w", err)
 	}
@@ -98,55 +159,77 @@ func (c *Client) Connect(ctx context.Context) error {
 			req.Header.Add(key, value)
 		}
 	}
+	if c.lastEventID != "" {
+		req.Header.Set("Last-Event-ID", c.lastEventID)
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
+		cancel()
 		c.metrics.IncrementErrors()
 		return fmt.Errorf("http request: %w", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
+		cancel()
 		c.metrics.IncrementErrors()
 		resp.Body.Close()
 		return &StatusError{Code: resp.StatusCode}
 	}
 
 	c.resp = resp
-	c.reader = bufio.NewReader(resp.Body)
+	c.cancel = cancel
+	c.events = make(chan readResult)
+	c.done = make(chan struct{})
 	c.metrics.MarkConnected()
+	go c.readLoop(bufio.NewReader(resp.Body), c.events, c.done)
 
 	return nil
 }
 
-// ReadEvent reads the next SSE event from the stream.
-func (c *Client) ReadEvent(ctx context.Context) (Event, error) {
-	c.mu.Lock()
-	reader := c.reader
-	c.mu.Unlock()
-
-	if reader == nil {
-		return Event{}, fmt.Errorf("not connected")
+// Reconnect drops the current stream, waits for the reconnection delay and
+// connects again, resuming from the last event ID seen.
+func (c *Client) Reconnect(ctx context.Context) error {
+	c.Close()
+
+	timer := time.NewTimer(c.RetryDelay())
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		return ctx.Err()
 	}
+	return c.Connect(ctx)
+}
 
-	event := Event{}
-	var dataLines []string
-
+// readLoop parses events off the stream until it fails or the client closes.
+func (c *Client) readLoop(reader *bufio.Reader, events chan<- readResult, done <-chan struct{}) {
+	defer close(events)
 	for {
-		// Check context cancellation
+		result := c.parseEvent(reader)
 		select {
-		case <-ctx.Done():
-			return Event{}, ctx.Err()
-		default:
+		case events <- result:
+		case <-done:
+			return
 		}
+		if result.err != nil {
+			return
+		}
+	}
+}
 
+func (c *Client) parseEvent(reader *bufio.Reader) readResult {
+	result := readResult{}
+	var dataLines []string
+
+	for {
 		line, err := reader.ReadString('\n')
 		if err != nil {
 			c.metrics.IncrementErrors()
 			if err == io.EOF {
-
-				return Event{}, fmt.Errorf("connection closed")
+				return readResult{err: fmt.Errorf("connection closed")}
 			}
-			return Event{}, fmt.Errorf("read line: %w", err)
+			return readResult{err: fmt.Errorf("read line: %w", err)}
 		}
 
 		c.metrics.IncrementReceived(int64(len(line)))
@@ -155,12 +238,9 @@ func (c *Client) ReadEvent(ctx context.Context) (Event, error) {
 
 		// Empty line marks end of event
 		if line == "" {
-			if len(dataLines) > 0 || event.Event != "" || event.ID != "" {
-				event.Data = strings.Join(dataLines, "\n")
-				c.mu.Lock()
-				c.eventsRecv++
-				c.mu.Unlock()
-				return event, nil
+			if len(dataLines) > 0 || result.event.Event != "" || result.idSet {
+				result.event.Data = strings.Join(dataLines, "\n")
+				return result
 			}
 			continue
 		}
@@ -187,15 +267,73 @@ func (c *Client) ReadEvent(ctx context.Context) (Event, error) {
 
 		switch field {
 		case "id":
-			event.ID = value
+			result.event.ID = value
+			result.idSet = true
 		case "event":
-			event.Event = value
+			result.event.Event = value
 		case "data":
 			dataLines = append(dataLines, value)
+		case "retry":
+			// Per the spec only all-digit values change the reconnection time.
+			if ms, err := strconv.Atoi(value); err == nil && ms >= 0 && strings.Trim(value, "0123456789") == "" {
+				c.mu.Lock()
+				c.retryDelay = time.Duration(ms) * time.Millisecond
+				c.mu.Unlock()
+			}
 		}
 	}
 }
 
+// ReadEvent reads the next SSE event from the stream.
+func (c *Client) ReadEvent(ctx context.Context) (Event, error) {
+	c.mu.Lock()
+	events := c.events
+	c.mu.Unlock()
+
+	if events == nil {
+		return Event{}, fmt.Errorf("not connected")
+	}
+
+	// Check context cancellation
+	if err := ctx.Err(); err != nil {
+		return Event{}, err
+	}
+
+	select {
+	case <-ctx.Done():
+		return Event{}, ctx.Err()
+	case result, ok := <-events:
+		if !ok {
+			return Event{}, fmt.Errorf("connection closed")
+		}
+		if result.err != nil {
+			return Event{}, result.err
+		}
+		c.mu.Lock()
+		c.eventsRecv++
+		if result.idSet {
+			c.lastEventID = result.event.ID
+		}
+		c.mu.Unlock()
+		return result.event, nil
+	}
+}
+
+// LastEventID returns the ID of the most recent event that carried one.
+func (c *Client) LastEventID() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lastEventID
+}
+
+// RetryDelay returns the current reconnection delay, as last set by the
+// server's retry field.
+func (c *Client) RetryDelay() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.retryDelay
+}
+
 // Close closes the SSE connection.
 func (c *Client) Close() error {
 	c.mu.Lock()
@@ -205,9 +343,12 @@ func (c *Client) Close() error {
 		return nil
 	}
 
+	close(c.done)
+	c.cancel()
 	err := c.resp.Body.Close()
 	c.resp = nil
-	c.reader = nil
+	c.events = nil
+	c.done = nil
 
 	return err
 }
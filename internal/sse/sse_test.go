@@ -832,3 +832,91 @@ func TestSSEOnlyEventField(t *testing.T) {
 		t.Errorf("Expected empty data, got '%s'", event.Data)
 	}
 }
+
+func TestSSEReconnectResumesFromLastEventID(t *testing.T) {
+	lastIDs := make(chan string, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastIDs <- r.Header.Get("Last-Event-ID")
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		if r.Header.Get("Last-Event-ID") == "" {
+			// Tighten the reconnection delay, then drop the stream.
+			fmt.Fprintf(w, "retry: 10\nid: 41\ndata: first\n\n")
+			return
+		}
+		fmt.Fprintf(w, "id: 42\ndata: resumed\n\n")
+		if f, ok := w.(http.Flusher); ok {
+			f.Flush()
+		}
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{URL: server.URL, Stream: true})
+	ctx := context.Background()
+	if err := client.Connect(ctx); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.ReadEvent(ctx); err != nil {
+		t.Fatalf("first ReadEvent failed: %v", err)
+	}
+	if _, err := client.ReadEvent(ctx); err == nil {
+		t.Fatal("expected error once the server dropped the stream")
+	}
+	if got := client.RetryDelay(); got != 10*time.Millisecond {
+		t.Fatalf("RetryDelay() = %v, want 10ms from retry field", got)
+	}
+	if got := client.LastEventID(); got != "41" {
+		t.Fatalf("LastEventID() = %q, want 41", got)
+	}
+
+	if err := client.Reconnect(ctx); err != nil {
+		t.Fatalf("Reconnect failed: %v", err)
+	}
+	event, err := client.ReadEvent(ctx)
+	if err != nil {
+		t.Fatalf("ReadEvent after reconnect failed: %v", err)
+	}
+	if event.Data != "resumed" {
+		t.Errorf("Expected data 'resumed', got %q", event.Data)
+	}
+
+	if first, second := <-lastIDs, <-lastIDs; first != "" || second != "41" {
+		t.Errorf("Last-Event-ID headers = %q, %q; want \"\", \"41\"", first, second)
+	}
+}
+
+func TestSSEStreamOutlivesTimeout(t *testing.T) {
+	server := createTestSSEServer(func(w http.ResponseWriter) {
+		time.Sleep(150 * time.Millisecond)
+		fmt.Fprintf(w, "data: late\n\n")
+		if f, ok := w.(http.Flusher); ok {
+			f.Flush()
+		}
+		time.Sleep(100 * time.Millisecond)
+	})
+	defer server.Close()
+
+	client := NewClient(Config{URL: server.URL, Timeout: 50 * time.Millisecond, Stream: true})
+	if err := client.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer client.Close()
+
+	// A read window that ends early must not tear down the stream.
+	short, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.ReadEvent(short); err != context.DeadlineExceeded {
+		t.Fatalf("ReadEvent(short) error = %v, want deadline exceeded", err)
+	}
+
+	event, err := client.ReadEvent(context.Background())
+	if err != nil {
+		t.Fatalf("ReadEvent failed: %v", err)
+	}
+	if event.Data != "late" {
+		t.Errorf("Expected data 'late', got %q", event.Data)
+	}
+}
@@ -0,0 +1,72 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/torosent/crankfire/internal/metrics"
+)
+
+// Files holding the raw data of a finished run, next to run.json for
+// session runs and under items/<item-name>/ for set runs.
+const (
+	MeasurementsFile = "measurements.json"
+	TimeSeriesFile   = "timeseries.json"
+)
+
+// WriteRunArtifacts stores the HDR histograms of a run, overall and per
+// endpoint, and its per-second time series in dir. Either may be nil.
+func WriteRunArtifacts(dir string, m *metrics.Measurements, series []metrics.DataPoint) error {
+	if m != nil {
+		if err := writeJSON(filepath.Join(dir, MeasurementsFile), m); err != nil {
+			return err
+		}
+	}
+	if series != nil {
+		if err := writeJSON(filepath.Join(dir, TimeSeriesFile), series); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadMeasurements loads the histograms written by WriteRunArtifacts. The
+// error wraps os.ErrNotExist for runs recorded without them.
+func ReadMeasurements(dir string) (*metrics.Measurements, error) {
+	var m metrics.Measurements
+	if err := readJSON(filepath.Join(dir, MeasurementsFile), &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// ReadTimeSeries loads the time series written by WriteRunArtifacts. The
+// error wraps os.ErrNotExist for runs recorded without one.
+func ReadTimeSeries(dir string) ([]metrics.DataPoint, error) {
+	var series []metrics.DataPoint
+	if err := readJSON(filepath.Join(dir, TimeSeriesFile), &series); err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+func writeJSON(path string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("marshal %s: %w", filepath.Base(path), err)
+	}
+	return writeAtomic(path, data)
+}
+
+func readJSON(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", filepath.Base(path), err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("decode %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
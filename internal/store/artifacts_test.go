@@ -0,0 +1,61 @@
+package store
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/torosent/crankfire/internal/metrics"
+)
+
+func TestRunArtifactsRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	c := metrics.NewCollector()
+	for i := 1; i <= 1000; i++ {
+		c.RecordRequest(time.Duration(i)*time.Millisecond, nil, &metrics.RequestMetadata{Endpoint: "users"})
+	}
+	m, err := c.Measurements()
+	if err != nil {
+		t.Fatalf("Measurements: %v", err)
+	}
+	series := []metrics.DataPoint{{TotalRequests: 10, CurrentRPS: 10, P95LatencyMs: 9.5}}
+	if err := WriteRunArtifacts(dir, m, series); err != nil {
+		t.Fatalf("WriteRunArtifacts: %v", err)
+	}
+
+	got, err := ReadMeasurements(dir)
+	if err != nil {
+		t.Fatalf("ReadMeasurements: %v", err)
+	}
+	stats, err := got.Stats(time.Second)
+	if err != nil {
+		t.Fatalf("Stats: %v", err)
+	}
+	want := c.Stats(time.Second)
+	if stats.Total != 1000 || stats.P99Latency != want.P99Latency {
+		t.Errorf("Stats total=%d p99=%s, want 1000 and %s", stats.Total, stats.P99Latency, want.P99Latency)
+	}
+	p999, ok := stats.Endpoints["users"].Percentile(99.9)
+	if !ok || p999 < 995*time.Millisecond || p999 > 1001*time.Millisecond {
+		t.Errorf("users p99.9 = %s (ok=%v), want about 999ms", p999, ok)
+	}
+
+	gotSeries, err := ReadTimeSeries(dir)
+	if err != nil {
+		t.Fatalf("ReadTimeSeries: %v", err)
+	}
+	if len(gotSeries) != 1 || gotSeries[0].P95LatencyMs != 9.5 {
+		t.Errorf("series = %+v", gotSeries)
+	}
+}
+
+func TestReadRunArtifactsMissing(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := ReadMeasurements(dir); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("ReadMeasurements error = %v, want ErrNotExist", err)
+	}
+	if _, err := ReadTimeSeries(dir); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("ReadTimeSeries error = %v, want ErrNotExist", err)
+	}
+}
@@ -63,7 +63,10 @@ type Set struct {
 	CreatedAt     time.Time   `yaml:"created_at" json:"created_at"`
 	UpdatedAt     time.Time   `yaml:"updated_at" json:"updated_at"`
 	Thresholds    []Threshold `yaml:"thresholds,omitempty" json:"thresholds,omitempty"`
-	Stages        []Stage     `yaml:"stages" json:"stages"`
+	// RegressionWindow is how many previous runs each run is checked
+	// against for regressions; 0 uses the default.
+	RegressionWindow int     `yaml:"regression_window,omitempty" json:"regression_window,omitempty"`
+	Stages           []Stage `yaml:"stages" json:"stages"`
 }
 
 type SetRunStatus string
@@ -94,6 +97,14 @@ type StageResult struct {
 	Items     []ItemResult `json:"items"`
 }
 
+// SetRegression compares the items of a SetRun with the same items in the
+// previous runs of its set.
+type SetRegression struct {
+	Window  int                `json:"window"`
+	Items   []RegressionResult `json:"items"`
+	Verdict string             `json:"verdict"`
+}
+
 type ThresholdResult struct {
 	Threshold
 	Actual float64 `json:"actual"`
@@ -110,6 +121,7 @@ type SetRun struct {
 	Stages              []StageResult     `json:"stages"`
 	Thresholds          []ThresholdResult `json:"thresholds,omitempty"`
 	AllThresholdsPassed bool              `json:"all_thresholds_passed"`
+	Regression          *SetRegression    `json:"regression,omitempty"`
 	ErrorMessage        string            `json:"error_message,omitempty"`
 	Dir                 string            `json:"-"`
 }
@@ -129,6 +129,9 @@ func (s *fsStore) validateSetContents(ctx context.Context, set Set) error {
 			return fmt.Errorf("%w: threshold %d missing metric or op", ErrInvalidSet, ti)
 		}
 	}
+	if set.RegressionWindow < 0 {
+		return fmt.Errorf("%w: regression_window must be non-negative", ErrInvalidSet)
+	}
 	return nil
 }
 
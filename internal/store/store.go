@@ -50,6 +50,28 @@ type RunSummary struct {
 	ErrorMessage  string  `json:"error_message,omitempty"`
 }
 
+// RegressionResult compares one run, or one set item, with the same run in
+// its recent history.
+type RegressionResult struct {
+	Name string `json:"name,omitempty"`
+	// Samples is the number of earlier runs compared against.
+	Samples int                `json:"samples"`
+	Metrics []MetricRegression `json:"metrics"`
+	Verdict string             `json:"verdict"`
+}
+
+// MetricRegression scores one metric against the median and median absolute
+// deviation (MAD) of its history. Score is the robust z-score of Current,
+// positive when the metric got worse.
+type MetricRegression struct {
+	Metric  string  `json:"metric"`
+	Current float64 `json:"current"`
+	Median  float64 `json:"median"`
+	MAD     float64 `json:"mad"`
+	Score   float64 `json:"score"`
+	Verdict string  `json:"verdict"`
+}
+
 type Store interface {
 	ListSessions(ctx context.Context) ([]Session, error)
 	GetSession(ctx context.Context, id string) (Session, error)
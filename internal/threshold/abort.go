@@ -0,0 +1,152 @@
+package threshold
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/torosent/crankfire/internal/metrics"
+)
+
+// DefaultMonitorInterval is how often a Monitor evaluates abort-on-fail
+// thresholds.
+const DefaultMonitorInterval = time.Second
+
+// StatsSource provides live stats while a run is in progress.
+// *metrics.Collector implements it.
+type StatsSource interface {
+	Stats(elapsed time.Duration) metrics.Stats
+}
+
+// Breach records the abort-on-fail threshold that stopped a run.
+type Breach struct {
+	Result  Result
+	Elapsed time.Duration
+}
+
+// Error describes the breach; it is suitable as a run's error message.
+func (b *Breach) Error() string {
+	return fmt.Sprintf("aborted after %s: threshold %q failed (actual %.2f)",
+		b.Elapsed.Round(time.Second), b.Result.Threshold.Raw, b.Result.Actual)
+}
+
+// Apply marks the result for the breached threshold as failed with the value
+// that triggered the abort, so reports show why the run stopped even if the
+// final stats would pass.
+func (b *Breach) Apply(results []Result) {
+	if b == nil {
+		return
+	}
+	for i := range results {
+		if results[i].Threshold.Raw != b.Result.Threshold.Raw {
+			continue
+		}
+		results[i].Actual = b.Result.Actual
+		results[i].Pass = false
+		results[i].Aborted = true
+		results[i].Message = "✗ " + b.Error()
+		return
+	}
+}
+
+// Monitor periodically evaluates abort-on-fail thresholds against live stats
+// and cancels the run on the first failure. Each threshold is evaluated over
+// consecutive windows rather than the whole run, so a failure late in a long
+// run is not diluted by the requests before it.
+type Monitor struct {
+	thresholds []Threshold
+	windows    []monitorWindow // one per threshold
+	source     StatsSource
+	interval   time.Duration
+
+	mu     sync.Mutex
+	breach *Breach
+}
+
+// monitorWindow tracks the window a threshold is currently collecting.
+type monitorWindow struct {
+	base  metrics.Stats // stats at the start of the window
+	ticks int           // checks since the start of the window
+}
+
+// NewMonitor returns a Monitor for the abort-on-fail thresholds among
+// thresholds, or nil if there are none.
+func NewMonitor(thresholds []Threshold, source StatsSource) *Monitor {
+	var abort []Threshold
+	for _, t := range thresholds {
+		if t.AbortOnFail {
+			abort = append(abort, t)
+		}
+	}
+	if len(abort) == 0 {
+		return nil
+	}
+	return &Monitor{
+		thresholds: abort,
+		windows:    make([]monitorWindow, len(abort)),
+		source:     source,
+		interval:   DefaultMonitorInterval,
+	}
+}
+
+// Run evaluates the thresholds every interval until ctx is done or one fails,
+// in which case it records the breach and calls cancel.
+func (m *Monitor) Run(ctx context.Context, cancel context.CancelFunc) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if breach := m.check(); breach != nil {
+				m.mu.Lock()
+				m.breach = breach
+				m.mu.Unlock()
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+// check evaluates the thresholds whose delay has elapsed against the requests
+// of each window that is complete, then starts their next window. Windows
+// default to the check interval. Thresholds that cannot be evaluated, e.g. an
+// endpoint with no requests in the window, are skipped.
+func (m *Monitor) check() *Breach {
+	stats := m.source.Stats(0)
+	e := &Evaluator{}
+	for i, t := range m.thresholds {
+		w := &m.windows[i]
+		if stats.Duration < t.Delay {
+			// Warm-up requests never count towards a window.
+			*w = monitorWindow{base: stats}
+			continue
+		}
+		w.ticks++
+		if t.Window > 0 && time.Duration(w.ticks)*m.interval < t.Window {
+			continue
+		}
+		window := stats.Since(w.base)
+		*w = monitorWindow{base: stats}
+
+		result := e.evaluateOne(t, window)
+		if result.Pass || result.Err != nil {
+			continue
+		}
+		return &Breach{Result: result, Elapsed: stats.Duration}
+	}
+	return nil
+}
+
+// Breach returns the breach that stopped the run, or nil.
+func (m *Monitor) Breach() *Breach {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.breach
+}
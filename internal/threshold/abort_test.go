@@ -0,0 +1,238 @@
+package threshold
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/torosent/crankfire/internal/metrics"
+)
+
+type fakeSource struct {
+	mu    sync.Mutex
+	stats metrics.Stats
+}
+
+func (f *fakeSource) Stats(time.Duration) metrics.Stats {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.stats
+}
+
+func (f *fakeSource) set(stats metrics.Stats) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stats = stats
+}
+
+func mustParse(t *testing.T, s string) Threshold {
+	t.Helper()
+	th, err := Parse(s)
+	if err != nil {
+		t.Fatalf("Parse(%q) error = %v", s, err)
+	}
+	return th
+}
+
+func TestNewMonitorWithoutAbortThresholds(t *testing.T) {
+	if m := NewMonitor([]Threshold{mustParse(t, "http_req_failed:rate < 0.1")}, &fakeSource{}); m != nil {
+		t.Fatal("expected no monitor without abort_on_fail thresholds")
+	}
+	if b := (*Monitor)(nil).Breach(); b != nil {
+		t.Fatalf("nil monitor Breach() = %v, want nil", b)
+	}
+}
+
+func TestMonitorCheck(t *testing.T) {
+	failing := metrics.Stats{
+		EndpointStats: metrics.EndpointStats{Total: 100, Successes: 50, Failures: 50},
+		Duration:      10 * time.Second,
+	}
+
+	tests := []struct {
+		name       string
+		thresholds []string
+		stats      metrics.Stats
+		wantBreach string
+	}{
+		{
+			name:       "breached",
+			thresholds: []string{"http_req_failed:rate < 0.1 abort_on_fail"},
+			stats:      failing,
+			wantBreach: "http_req_failed:rate < 0.1 abort_on_fail",
+		},
+		{
+			name:       "within delay",
+			thresholds: []string{"http_req_failed:rate < 0.1 abort_on_fail delay=1m"},
+			stats:      failing,
+		},
+		{
+			name:       "passing",
+			thresholds: []string{"http_req_failed:rate < 0.9 abort_on_fail"},
+			stats:      failing,
+		},
+		{
+			name:       "not evaluable yet",
+			thresholds: []string{"http_req_duration{endpoint=login}:p95 < 100 abort_on_fail"},
+			stats:      failing,
+		},
+		{
+			name: "non-abort thresholds are ignored",
+			thresholds: []string{
+				"http_req_failed:rate < 0.1",
+				"http_requests:count > 10 abort_on_fail",
+			},
+			stats: failing,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var thresholds []Threshold
+			for _, s := range tt.thresholds {
+				thresholds = append(thresholds, mustParse(t, s))
+			}
+			m := NewMonitor(thresholds, &fakeSource{stats: tt.stats})
+			breach := m.check()
+			if tt.wantBreach == "" {
+				if breach != nil {
+					t.Fatalf("check() = %v, want no breach", breach)
+				}
+				return
+			}
+			if breach == nil {
+				t.Fatal("check() = nil, want a breach")
+			}
+			if breach.Result.Threshold.Raw != tt.wantBreach {
+				t.Errorf("breached %q, want %q", breach.Result.Threshold.Raw, tt.wantBreach)
+			}
+			if breach.Elapsed != tt.stats.Duration {
+				t.Errorf("Elapsed = %s, want %s", breach.Elapsed, tt.stats.Duration)
+			}
+		})
+	}
+}
+
+func TestMonitorCatchesSpikeAfterHealthyStretch(t *testing.T) {
+	source := &fakeSource{}
+	m := NewMonitor([]Threshold{mustParse(t, "http_req_failed:rate < 0.1 abort_on_fail")}, source)
+
+	// Ten minutes of clean traffic at 100 req/s.
+	var successes int64
+	for tick := 1; tick <= 600; tick++ {
+		successes += 100
+		source.set(metrics.Stats{
+			EndpointStats: metrics.EndpointStats{Total: successes, Successes: successes},
+			Duration:      time.Duration(tick) * time.Second,
+		})
+		if breach := m.check(); breach != nil {
+			t.Fatalf("tick %d: check() = %v, want no breach", tick, breach)
+		}
+	}
+
+	// Half of the next second fails: 0.5% of the run, 50% of the window.
+	source.set(metrics.Stats{
+		EndpointStats: metrics.EndpointStats{Total: successes + 100, Successes: successes + 50, Failures: 50},
+		Duration:      601 * time.Second,
+	})
+	breach := m.check()
+	if breach == nil {
+		t.Fatal("check() = nil, want the spike to breach")
+	}
+	if breach.Result.Actual != 0.5 {
+		t.Errorf("Actual = %v, want the window's failure rate 0.5", breach.Result.Actual)
+	}
+	if breach.Elapsed != 601*time.Second {
+		t.Errorf("Elapsed = %s, want 10m1s", breach.Elapsed)
+	}
+}
+
+func TestMonitorWindow(t *testing.T) {
+	source := &fakeSource{}
+	m := NewMonitor([]Threshold{mustParse(t, "http_req_failed:count < 5 abort_on_fail delay=2s window=3s")}, source)
+
+	// The 10 failures during the delay are never counted. After it, each
+	// window of three checks is evaluated once, when it is complete: the
+	// first sees 4 failures, the second 5, all of which arrive on its first
+	// check.
+	failures := []int64{10, 11, 12, 14, 19, 19, 19}
+	for i, failed := range failures {
+		source.set(metrics.Stats{
+			EndpointStats: metrics.EndpointStats{Total: failed, Failures: failed},
+			Duration:      time.Duration(i+1) * time.Second,
+		})
+		breach := m.check()
+		if i < len(failures)-1 {
+			if breach != nil {
+				t.Fatalf("check %d = %v, want no breach", i+1, breach)
+			}
+			continue
+		}
+		if breach == nil || breach.Result.Actual != 5 {
+			t.Fatalf("check %d = %v, want a breach with 5 failures in the window", i+1, breach)
+		}
+	}
+}
+
+func TestMonitorRunCancelsOnBreach(t *testing.T) {
+	source := &fakeSource{stats: metrics.Stats{EndpointStats: metrics.EndpointStats{Total: 10}}}
+	m := NewMonitor([]Threshold{mustParse(t, "http_req_failed:count < 5 abort_on_fail")}, source)
+	m.interval = 5 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		m.Run(ctx, cancel)
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	if m.Breach() != nil {
+		t.Fatal("unexpected breach while thresholds pass")
+	}
+	source.set(metrics.Stats{EndpointStats: metrics.EndpointStats{Total: 10, Failures: 6}, Duration: 3 * time.Second})
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("monitor did not stop after the breach")
+	}
+	if ctx.Err() == nil {
+		t.Error("expected the run context to be cancelled")
+	}
+	breach := m.Breach()
+	if breach == nil {
+		t.Fatal("Breach() = nil after abort")
+	}
+	want := `aborted after 3s: threshold "http_req_failed:count < 5 abort_on_fail" failed (actual 6.00)`
+	if breach.Error() != want {
+		t.Errorf("Error() = %q, want %q", breach.Error(), want)
+	}
+}
+
+func TestBreachApply(t *testing.T) {
+	abort := mustParse(t, "http_req_failed:rate < 0.1 abort_on_fail")
+	other := mustParse(t, "http_req_duration:p95 < 500")
+	results := []Result{
+		{Threshold: other, Actual: 100, Pass: true},
+		{Threshold: abort, Actual: 0.05, Pass: true},
+	}
+	breach := &Breach{Result: Result{Threshold: abort, Actual: 0.5}, Elapsed: 2 * time.Minute}
+	breach.Apply(results)
+
+	if !results[0].Pass || results[0].Aborted {
+		t.Errorf("unrelated result changed: %+v", results[0])
+	}
+	got := results[1]
+	if got.Pass || !got.Aborted || got.Actual != 0.5 {
+		t.Errorf("aborted result = %+v, want failed and aborted with actual 0.5", got)
+	}
+	if !strings.Contains(got.Message, "aborted after 2m0s") {
+		t.Errorf("Message = %q, want the abort reason", got.Message)
+	}
+
+	(*Breach)(nil).Apply(results)
+}
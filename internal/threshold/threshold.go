@@ -6,17 +6,27 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/torosent/crankfire/internal/metrics"
 )
 
 // Threshold represents a performance assertion that can pass or fail.
 type Threshold struct {
-	Metric    string  // e.g., "http_req_duration", "http_req_failed"
-	Aggregate string  // e.g., "p95", "p99", "avg", "max", "rate"
-	Operator  string  // e.g., "<", "<=", ">", ">=", "=="
-	Value     float64 // The threshold value to compare against
-	Raw       string  // Original threshold string for display
+	Metric    string            // e.g., "http_req_duration", "http_req_failed"
+	Selector  string            // Optional series within the metric, e.g. a check name
+	Labels    map[string]string // Optional key=value selector, e.g. {endpoint=login}
+	Aggregate string            // e.g., "p95", "p99", "avg", "max", "rate"
+	Operator  string            // e.g., "<", "<=", ">", ">=", "=="
+	Value     float64           // The threshold value to compare against
+	Raw       string            // Original threshold string for display
+
+	// AbortOnFail stops the run as soon as the threshold fails while it is
+	// still running, once Delay has elapsed since the start. Live checks
+	// cover the requests of each Window in turn (default: the check interval).
+	AbortOnFail bool
+	Delay       time.Duration
+	Window      time.Duration
 }
 
 // Result represents the outcome of evaluating a threshold.
@@ -25,6 +35,11 @@ type Result struct {
 	Actual    float64
 	Pass      bool
 	Message   string
+	// Aborted reports that the threshold failed during the run and stopped it.
+	Aborted bool
+	// Err is set when the threshold could not be evaluated, e.g. its metric
+	// was never recorded. Such a result does not pass.
+	Err error
 }
 
 // Evaluator evaluates thresholds against collected metrics.
@@ -61,6 +76,7 @@ func (e *Evaluator) evaluateOne(t Threshold, stats metrics.Stats) Result {
 			Actual:    0,
 			Pass:      false,
 			Message:   fmt.Sprintf("error: %v", err),
+			Err:       err,
 		}
 	}
 
@@ -82,29 +98,44 @@ func (e *Evaluator) evaluateOne(t Threshold, stats metrics.Stats) Result {
 // Parse parses a threshold string into a Threshold struct.
 // Supported formats:
 // - "http_req_duration:p95 < 500"     (latency percentile in ms)
+// - "http_req_duration:p99.9 < 800"   (any percentile between 0 and 100)
 // - "http_req_duration:avg < 200"     (average latency in ms)
 // - "http_req_duration:max < 1000"    (max latency in ms)
 // - "http_req_failed:rate < 0.01"     (failure rate as decimal)
 // - "http_req_failed:count < 10"      (failure count)
 // - "http_requests:rate > 100"        (requests per second)
+// - "http_req_duration{endpoint=login}:p99 < 300" (one endpoint, step or scenario)
+// - "http_req_ttfb:p95 < 200"         (connection phase: dns, connect, tls, ttfb, transfer)
+// - "http_req_dns{endpoint=login}:max < 50" (one phase of one endpoint)
+// - "status_buckets{protocol=http,status=503}:count < 5" (failures by status)
+// - "checks:rate >= 0.99"             (pass rate across all checks)
+// - "checks{login status}:rate == 1"  (pass rate of one named check)
+// - "websocket_messages_received:count > 100" (protocol metric total)
+//
+// Options may follow the value, separated by spaces:
+// - "http_req_failed:rate < 0.1 abort_on_fail"           (stop the run on failure)
+// - "http_req_failed:rate < 0.1 abort_on_fail delay=1m"  (not before 1m has elapsed)
+// - "http_req_failed:rate < 0.1 abort_on_fail window=30s" (check 30s at a time)
 func Parse(s string) (Threshold, error) {
 	s = strings.TrimSpace(s)
 	if s == "" {
 		return Threshold{}, fmt.Errorf("empty threshold string")
 	}
 
-	// Pattern: metric:aggregate operator value
+	// Pattern: metric{selector}:aggregate operator value
 	// e.g., "http_req_duration:p95 < 500"
-	pattern := regexp.MustCompile(`^([a-z_]+):([a-z0-9]+)\s*(<|<=|>|>=|==)\s*([0-9]+\.?[0-9]*|\.[0-9]+)$`)
+	pattern := regexp.MustCompile(`^([a-z][a-z0-9_]*)(?:\{([^{}]+)\})?:([a-z0-9.]+)\s*(<|<=|>|>=|==)\s*([0-9]+\.?[0-9]*|\.[0-9]+)((?:\s+\S+)*)$`)
 	matches := pattern.FindStringSubmatch(s)
 	if matches == nil {
 		return Threshold{}, fmt.Errorf("invalid threshold format: %q (expected format: metric:aggregate operator value, e.g., 'http_req_duration:p95 < 500')", s)
 	}
 
 	metric := matches[1]
-	aggregate := matches[2]
-	operator := matches[3]
-	valueStr := matches[4]
+	selector := strings.TrimSpace(matches[2])
+	aggregate := matches[3]
+	operator := matches[4]
+	valueStr := matches[5]
+	options := strings.Fields(matches[6])
 
 	value, err := strconv.ParseFloat(valueStr, 64)
 	if err != nil {
@@ -113,12 +144,21 @@ func Parse(s string) (Threshold, error) {
 
 	// Validate metric
 	if !isValidMetric(metric) {
-		return Threshold{}, fmt.Errorf("unsupported metric: %q (supported: http_req_duration, http_req_failed, http_requests)", metric)
+		return Threshold{}, fmt.Errorf("unsupported metric: %q (supported: http_req_duration, http_req_failed, http_requests, http_req_dns, http_req_connect, http_req_tls, http_req_ttfb, http_req_transfer, status_buckets, checks, or a protocol metric such as websocket_messages_received)", metric)
+	}
+
+	var labels map[string]string
+	if selector != "" && metric != "checks" {
+		labels, err = parseLabels(metric, selector)
+		if err != nil {
+			return Threshold{}, err
+		}
+		selector = ""
 	}
 
 	// Validate aggregate
 	if !isValidAggregate(aggregate) {
-		return Threshold{}, fmt.Errorf("unsupported aggregate: %q (supported: p50, p90, p95, p99, avg, min, max, rate, count)", aggregate)
+		return Threshold{}, fmt.Errorf("unsupported aggregate: %q (supported: p50, p90, p95, p99 or any pN such as p99.9, avg, min, max, rate, count, value)", aggregate)
 	}
 
 	// Validate operator
@@ -126,13 +166,59 @@ func Parse(s string) (Threshold, error) {
 		return Threshold{}, fmt.Errorf("unsupported operator: %q (supported: <, <=, >, >=, ==)", operator)
 	}
 
-	return Threshold{
+	t := Threshold{
 		Metric:    metric,
+		Selector:  selector,
+		Labels:    labels,
 		Aggregate: aggregate,
 		Operator:  operator,
 		Value:     value,
 		Raw:       s,
-	}, nil
+	}
+	if err := parseOptions(&t, options); err != nil {
+		return Threshold{}, err
+	}
+	return t, nil
+}
+
+// parseOptions applies the abort_on_fail, delay=<duration> and
+// window=<duration> options.
+func parseOptions(t *Threshold, options []string) error {
+	for _, opt := range options {
+		key, value, hasValue := strings.Cut(opt, "=")
+		switch key {
+		case "abort_on_fail":
+			t.AbortOnFail = true
+			if hasValue {
+				abort, err := strconv.ParseBool(value)
+				if err != nil {
+					return fmt.Errorf("invalid abort_on_fail value %q", value)
+				}
+				t.AbortOnFail = abort
+			}
+		case "delay":
+			d, err := time.ParseDuration(value)
+			if err != nil || d < 0 {
+				return fmt.Errorf("invalid delay %q (expected a duration such as 30s)", value)
+			}
+			t.Delay = d
+		case "window":
+			d, err := time.ParseDuration(value)
+			if err != nil || d <= 0 {
+				return fmt.Errorf("invalid window %q (expected a duration such as 30s)", value)
+			}
+			t.Window = d
+		default:
+			return fmt.Errorf("unsupported threshold option: %q (supported: abort_on_fail, delay=<duration>, window=<duration>)", opt)
+		}
+	}
+	if t.Delay > 0 && !t.AbortOnFail {
+		return fmt.Errorf("delay requires abort_on_fail")
+	}
+	if t.Window > 0 && !t.AbortOnFail {
+		return fmt.Errorf("window requires abort_on_fail")
+	}
+	return nil
 }
 
 // ParseMultiple parses multiple threshold strings.
@@ -160,24 +246,125 @@ func ParseMultiple(thresholds []string) ([]Threshold, error) {
 	return result, nil
 }
 
+// protocolMetricPrefixes are the protocols whose client metrics can be
+// thresholded as <protocol>_<metric>.
+var protocolMetricPrefixes = []string{"websocket", "sse", "grpc"}
+
+// labelKeys lists the selector labels each metric accepts.
+var labelKeys = map[string][]string{
+	"http_req_duration": {"endpoint", "step", "scenario"},
+	"http_req_failed":   {"endpoint", "step", "scenario"},
+	"http_requests":     {"endpoint", "step", "scenario"},
+	"status_buckets":    {"endpoint", "step", "scenario", "protocol", "status"},
+}
+
+// phaseMetricPrefix names connection phase metrics as http_req_<phase>, e.g.
+// http_req_ttfb for metrics.PhaseTTFB.
+const phaseMetricPrefix = "http_req_"
+
+// splitPhaseMetric returns the connection phase a metric such as
+// "http_req_ttfb" refers to.
+func splitPhaseMetric(metric string) (string, bool) {
+	phase, ok := strings.CutPrefix(metric, phaseMetricPrefix)
+	if !ok {
+		return "", false
+	}
+	return phase, containsString(metrics.Phases, phase)
+}
+
 func isValidMetric(metric string) bool {
-	valid := []string{"http_req_duration", "http_req_failed", "http_requests"}
+	valid := []string{"http_req_duration", "http_req_failed", "http_requests", "status_buckets", "checks"}
 	for _, v := range valid {
 		if metric == v {
 			return true
 		}
 	}
+	if _, ok := splitPhaseMetric(metric); ok {
+		return true
+	}
+	_, _, ok := splitProtocolMetric(metric)
+	return ok
+}
+
+// splitProtocolMetric splits a name such as "grpc_calls" into its protocol
+// and the protocol metric key.
+func splitProtocolMetric(metric string) (string, string, bool) {
+	for _, protocol := range protocolMetricPrefixes {
+		if key, ok := strings.CutPrefix(metric, protocol+"_"); ok && key != "" {
+			return protocol, key, true
+		}
+	}
+	return "", "", false
+}
+
+// parseLabels parses a "key=value,key=value" selector.
+func parseLabels(metric, selector string) (map[string]string, error) {
+	allowed := labelKeys[metric]
+	if _, ok := splitPhaseMetric(metric); ok {
+		allowed = []string{"endpoint"}
+	}
+	if len(allowed) == 0 {
+		return nil, fmt.Errorf("metric %q does not accept a {selector}", metric)
+	}
+	labels := make(map[string]string)
+	for _, pair := range strings.Split(selector, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		value = strings.Trim(strings.TrimSpace(value), `"`)
+		if !ok || key == "" || value == "" {
+			return nil, fmt.Errorf("invalid selector %q for %s (expected key=value, e.g., {endpoint=login})", pair, metric)
+		}
+		if !containsString(allowed, key) {
+			return nil, fmt.Errorf("unsupported selector label %q for %s (supported: %s)", key, metric, strings.Join(allowed, ", "))
+		}
+		if _, dup := labels[key]; dup {
+			return nil, fmt.Errorf("duplicate selector label %q", key)
+		}
+		labels[key] = value
+	}
+	scopes := 0
+	for _, key := range []string{"endpoint", "step", "scenario"} {
+		if _, ok := labels[key]; ok {
+			scopes++
+		}
+	}
+	if scopes > 1 {
+		return nil, fmt.Errorf("selector may name only one of endpoint, step or scenario")
+	}
+	return labels, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
 	return false
 }
 
 func isValidAggregate(aggregate string) bool {
-	valid := []string{"p50", "p90", "p95", "p99", "avg", "min", "max", "rate", "count"}
+	valid := []string{"avg", "min", "max", "rate", "count", "value"}
 	for _, v := range valid {
 		if aggregate == v {
 			return true
 		}
 	}
-	return false
+	_, ok := parsePercentile(aggregate)
+	return ok
+}
+
+// parsePercentile parses aggregates such as "p95" or "p99.9".
+func parsePercentile(aggregate string) (float64, bool) {
+	digits, ok := strings.CutPrefix(aggregate, "p")
+	if !ok || digits == "" {
+		return 0, false
+	}
+	q, err := strconv.ParseFloat(digits, 64)
+	if err != nil || q <= 0 || q > 100 {
+		return 0, false
+	}
+	return q, true
 }
 
 func isValidOperator(operator string) bool {
@@ -192,18 +379,60 @@ func isValidOperator(operator string) bool {
 
 func extractMetricValue(t Threshold, stats metrics.Stats) (float64, error) {
 	switch t.Metric {
-	case "http_req_duration":
-		return extractLatencyMetric(t.Aggregate, stats)
-	case "http_req_failed":
-		return extractFailureMetric(t.Aggregate, stats)
-	case "http_requests":
-		return extractRequestMetric(t.Aggregate, stats)
+	case "http_req_duration", "http_req_failed", "http_requests", "status_buckets":
+		scope, err := selectScope(t.Labels, stats)
+		if err != nil {
+			return 0, err
+		}
+		switch t.Metric {
+		case "http_req_duration":
+			return extractLatencyMetric(t.Aggregate, scope)
+		case "http_req_failed":
+			return extractFailureMetric(t.Aggregate, scope)
+		case "http_requests":
+			return extractRequestMetric(t.Aggregate, scope)
+		default:
+			return extractStatusMetric(t.Labels, t.Aggregate, scope)
+		}
+	case "checks":
+		return extractCheckMetric(t.Selector, t.Aggregate, stats)
 	default:
+		if phase, ok := splitPhaseMetric(t.Metric); ok {
+			return extractPhaseMetric(phase, t.Labels["endpoint"], t.Aggregate, stats)
+		}
+		if protocol, key, ok := splitProtocolMetric(t.Metric); ok {
+			return extractProtocolMetric(protocol, key, t.Aggregate, stats)
+		}
 		return 0, fmt.Errorf("unknown metric: %s", t.Metric)
 	}
 }
 
-func extractLatencyMetric(aggregate string, stats metrics.Stats) (float64, error) {
+// selectScope returns the stats named by an endpoint, step or scenario label,
+// or the overall stats when there is none.
+func selectScope(labels map[string]string, stats metrics.Stats) (metrics.EndpointStats, error) {
+	scopes := []struct {
+		label  string
+		series map[string]metrics.EndpointStats
+	}{
+		{"endpoint", stats.Endpoints},
+		{"step", stats.Steps},
+		{"scenario", stats.Journeys},
+	}
+	for _, scope := range scopes {
+		name, ok := labels[scope.label]
+		if !ok {
+			continue
+		}
+		es, ok := scope.series[name]
+		if !ok {
+			return metrics.EndpointStats{}, fmt.Errorf("no requests recorded for %s %q", scope.label, name)
+		}
+		return es, nil
+	}
+	return stats.EndpointStats, nil
+}
+
+func extractLatencyMetric(aggregate string, stats metrics.EndpointStats) (float64, error) {
 	switch aggregate {
 	case "p50":
 		return stats.P50LatencyMs, nil
@@ -219,12 +448,42 @@ func extractLatencyMetric(aggregate string, stats metrics.Stats) (float64, error
 		return stats.MinLatencyMs, nil
 	case "max":
 		return stats.MaxLatencyMs, nil
-	default:
-		return 0, fmt.Errorf("unsupported aggregate %q for http_req_duration", aggregate)
 	}
+	if q, ok := parsePercentile(aggregate); ok {
+		latency, ok := stats.Percentile(q)
+		if !ok {
+			return 0, fmt.Errorf("percentile %s is not available for these stats", aggregate)
+		}
+		return float64(latency) / float64(time.Millisecond), nil
+	}
+	return 0, fmt.Errorf("unsupported aggregate %q for http_req_duration", aggregate)
+}
+
+// extractPhaseMetric reads a connection phase of all requests, or of one
+// endpoint. "count" is the number of requests that went through the phase;
+// the other aggregates are durations in milliseconds.
+func extractPhaseMetric(phase, endpoint, aggregate string, stats metrics.Stats) (float64, error) {
+	series := stats.Phases
+	if endpoint != "" {
+		series = stats.EndpointPhases[endpoint]
+	}
+	ps, ok := series[phase]
+	if !ok {
+		if endpoint != "" {
+			return 0, fmt.Errorf("no %s phase recorded for endpoint %q", phase, endpoint)
+		}
+		return 0, fmt.Errorf("no %s phase recorded", phase)
+	}
+	switch aggregate {
+	case "count":
+		return float64(ps.Total), nil
+	case "rate", "value":
+		return 0, fmt.Errorf("unsupported aggregate %q for %s%s", aggregate, phaseMetricPrefix, phase)
+	}
+	return extractLatencyMetric(aggregate, ps)
 }
 
-func extractFailureMetric(aggregate string, stats metrics.Stats) (float64, error) {
+func extractFailureMetric(aggregate string, stats metrics.EndpointStats) (float64, error) {
 	switch aggregate {
 	case "count":
 		return float64(stats.Failures), nil
@@ -238,7 +497,7 @@ func extractFailureMetric(aggregate string, stats metrics.Stats) (float64, error
 	}
 }
 
-func extractRequestMetric(aggregate string, stats metrics.Stats) (float64, error) {
+func extractRequestMetric(aggregate string, stats metrics.EndpointStats) (float64, error) {
 	switch aggregate {
 	case "count":
 		return float64(stats.Total), nil
@@ -249,6 +508,95 @@ func extractRequestMetric(aggregate string, stats metrics.Stats) (float64, error
 	}
 }
 
+// extractStatusMetric counts the failures whose protocol and status match the
+// labels; an absent label matches any value.
+func extractStatusMetric(labels map[string]string, aggregate string, stats metrics.EndpointStats) (float64, error) {
+	var count int
+	for protocol, buckets := range stats.StatusBuckets {
+		if want, ok := labels["protocol"]; ok && want != protocol {
+			continue
+		}
+		for status, n := range buckets {
+			if want, ok := labels["status"]; ok && want != status {
+				continue
+			}
+			count += n
+		}
+	}
+	switch aggregate {
+	case "count":
+		return float64(count), nil
+	case "rate":
+		if stats.Total == 0 {
+			return 0, nil
+		}
+		return float64(count) / float64(stats.Total), nil
+	default:
+		return 0, fmt.Errorf("unsupported aggregate %q for status_buckets (use 'count' or 'rate')", aggregate)
+	}
+}
+
+func extractCheckMetric(selector, aggregate string, stats metrics.Stats) (float64, error) {
+	var passes, fails int64
+	if selector != "" {
+		check, ok := stats.Checks[selector]
+		if !ok {
+			return 0, fmt.Errorf("no check named %q was evaluated", selector)
+		}
+		passes, fails = check.Passes, check.Fails
+	} else {
+		for _, check := range stats.Checks {
+			passes += check.Passes
+			fails += check.Fails
+		}
+	}
+	switch aggregate {
+	case "rate":
+		if passes+fails == 0 {
+			return 0, nil
+		}
+		return float64(passes) / float64(passes+fails), nil
+	case "count":
+		return float64(fails), nil
+	default:
+		return 0, fmt.Errorf("unsupported aggregate %q for checks (use 'rate' or 'count')", aggregate)
+	}
+}
+
+// extractProtocolMetric reads an aggregated protocol metric. "count" and
+// "value" return the recorded total (or latest gauge reading), "rate" the
+// total per second of test duration.
+func extractProtocolMetric(protocol, key, aggregate string, stats metrics.Stats) (float64, error) {
+	raw, ok := stats.ProtocolMetrics[protocol][key]
+	if !ok {
+		return 0, fmt.Errorf("no %s metric %q was recorded", protocol, key)
+	}
+	var value float64
+	switch v := raw.(type) {
+	case int:
+		value = float64(v)
+	case int64:
+		value = float64(v)
+	case float64:
+		value = v
+	case metrics.Gauge:
+		value = float64(v)
+	default:
+		return 0, fmt.Errorf("%s metric %q is not numeric", protocol, key)
+	}
+	switch aggregate {
+	case "count", "value":
+		return value, nil
+	case "rate":
+		if stats.Duration <= 0 {
+			return 0, nil
+		}
+		return value / stats.Duration.Seconds(), nil
+	default:
+		return 0, fmt.Errorf("unsupported aggregate %q for %s_%s (use 'count', 'value' or 'rate')", aggregate, protocol, key)
+	}
+}
+
 func compareValues(actual float64, operator string, expected float64) bool {
 	// Handle floating point comparison with small epsilon
 	epsilon := 1e-9
@@ -1,6 +1,7 @@
 package threshold
 
 import (
+	"reflect"
 	"testing"
 	"time"
 
@@ -74,6 +75,24 @@ func TestParse(t *testing.T) {
 			},
 			wantError: false,
 		},
+		{
+			name:  "valid checks pass rate with selector",
+			input: "checks{login status}:rate >= 0.99",
+			want: Threshold{
+				Metric:    "checks",
+				Selector:  "login status",
+				Aggregate: "rate",
+				Operator:  ">=",
+				Value:     0.99,
+				Raw:       "checks{login status}:rate >= 0.99",
+			},
+			wantError: false,
+		},
+		{
+			name:      "selector on metric without series",
+			input:     "http_req_duration{login}:p95 < 500",
+			wantError: true,
+		},
 		{
 			name:      "empty string",
 			input:     "",
@@ -91,7 +110,136 @@ func TestParse(t *testing.T) {
 		},
 		{
 			name:      "invalid aggregate",
-			input:     "http_req_duration:p85 < 500",
+			input:     "http_req_duration:p101 < 500",
+			wantError: true,
+		},
+		{
+			name:  "arbitrary percentile",
+			input: "http_req_duration:p99.9 < 800",
+			want: Threshold{
+				Metric:    "http_req_duration",
+				Aggregate: "p99.9",
+				Operator:  "<",
+				Value:     800,
+				Raw:       "http_req_duration:p99.9 < 800",
+			},
+		},
+		{
+			name:  "endpoint selector",
+			input: "http_req_duration{endpoint=login}:p99 < 300",
+			want: Threshold{
+				Metric:    "http_req_duration",
+				Labels:    map[string]string{"endpoint": "login"},
+				Aggregate: "p99",
+				Operator:  "<",
+				Value:     300,
+				Raw:       "http_req_duration{endpoint=login}:p99 < 300",
+			},
+		},
+		{
+			name:  "status bucket selector",
+			input: `status_buckets{protocol=http, status="503"}:count < 5`,
+			want: Threshold{
+				Metric:    "status_buckets",
+				Labels:    map[string]string{"protocol": "http", "status": "503"},
+				Aggregate: "count",
+				Operator:  "<",
+				Value:     5,
+				Raw:       `status_buckets{protocol=http, status="503"}:count < 5`,
+			},
+		},
+		{
+			name:  "phase with endpoint selector",
+			input: "http_req_ttfb{endpoint=login}:p95 < 200",
+			want: Threshold{
+				Metric:    "http_req_ttfb",
+				Labels:    map[string]string{"endpoint": "login"},
+				Aggregate: "p95",
+				Operator:  "<",
+				Value:     200,
+				Raw:       "http_req_ttfb{endpoint=login}:p95 < 200",
+			},
+		},
+		{
+			name:      "unknown phase",
+			input:     "http_req_queue:p95 < 200",
+			wantError: true,
+		},
+		{
+			name:      "step selector on phase",
+			input:     "http_req_dns{step=a}:p95 < 20",
+			wantError: true,
+		},
+		{
+			name:  "protocol metric",
+			input: "websocket_messages_received:count > 100",
+			want: Threshold{
+				Metric:    "websocket_messages_received",
+				Aggregate: "count",
+				Operator:  ">",
+				Value:     100,
+				Raw:       "websocket_messages_received:count > 100",
+			},
+		},
+		{
+			name:  "abort on fail with delay",
+			input: "http_req_failed:rate < 0.1 abort_on_fail delay=30s",
+			want: Threshold{
+				Metric:      "http_req_failed",
+				Aggregate:   "rate",
+				Operator:    "<",
+				Value:       0.1,
+				Raw:         "http_req_failed:rate < 0.1 abort_on_fail delay=30s",
+				AbortOnFail: true,
+				Delay:       30 * time.Second,
+			},
+		},
+		{
+			name:  "abort on fail with window",
+			input: "http_req_failed:rate < 0.1 abort_on_fail window=30s",
+			want: Threshold{
+				Metric:      "http_req_failed",
+				Aggregate:   "rate",
+				Operator:    "<",
+				Value:       0.1,
+				Raw:         "http_req_failed:rate < 0.1 abort_on_fail window=30s",
+				AbortOnFail: true,
+				Window:      30 * time.Second,
+			},
+		},
+		{
+			name:      "window without abort on fail",
+			input:     "http_req_failed:rate < 0.1 window=30s",
+			wantError: true,
+		},
+		{
+			name:      "zero window",
+			input:     "http_req_failed:rate < 0.1 abort_on_fail window=0s",
+			wantError: true,
+		},
+		{
+			name:      "delay without abort on fail",
+			input:     "http_req_failed:rate < 0.1 delay=30s",
+			wantError: true,
+		},
+		{
+			name:      "unknown option",
+			input:     "http_req_failed:rate < 0.1 abort",
+			wantError: true,
+		},
+		{
+			name:      "unsupported selector label",
+			input:     "http_req_duration{status=500}:p95 < 500",
+			wantError: true,
+		},
+		{
+			name:      "more than one scope label",
+			input:     "http_req_duration{endpoint=a,step=b}:p95 < 500",
+			wantError: true,
+		},
+		{
+			name:      "selector on protocol metric",
+			input:     "grpc_calls{endpoint=a}:count > 1",
 			wantError: true,
 		},
 		{
@@ -117,6 +265,12 @@ func TestParse(t *testing.T) {
 				if got.Metric != tt.want.Metric {
 					t.Errorf("Parse() Metric = %v, want %v", got.Metric, tt.want.Metric)
 				}
+				if got.Selector != tt.want.Selector {
+					t.Errorf("Parse() Selector = %v, want %v", got.Selector, tt.want.Selector)
+				}
+				if !reflect.DeepEqual(got.Labels, tt.want.Labels) {
+					t.Errorf("Parse() Labels = %v, want %v", got.Labels, tt.want.Labels)
+				}
 				if got.Aggregate != tt.want.Aggregate {
 					t.Errorf("Parse() Aggregate = %v, want %v", got.Aggregate, tt.want.Aggregate)
 				}
@@ -129,6 +283,9 @@ func TestParse(t *testing.T) {
 				if got.Raw != tt.want.Raw {
 					t.Errorf("Parse() Raw = %v, want %v", got.Raw, tt.want.Raw)
 				}
+				if got.AbortOnFail != tt.want.AbortOnFail || got.Delay != tt.want.Delay {
+					t.Errorf("Parse() abort options = %v/%s, want %v/%s", got.AbortOnFail, got.Delay, tt.want.AbortOnFail, tt.want.Delay)
+				}
 			}
 		})
 	}
@@ -288,6 +445,23 @@ func TestEvaluator(t *testing.T) {
 	}
 }
 
+func TestEvaluatorReportsEvaluationErrors(t *testing.T) {
+	thresholds, err := ParseMultiple([]string{
+		"http_req_duration{endpoint=login}:p95 < 100",
+		"http_req_failed:rate < 0.5",
+	})
+	if err != nil {
+		t.Fatalf("ParseMultiple() error = %v", err)
+	}
+	results := NewEvaluator(thresholds).Evaluate(metrics.Stats{EndpointStats: metrics.EndpointStats{Total: 10}})
+	if results[0].Err == nil || results[0].Pass {
+		t.Errorf("missing endpoint: Err = %v, Pass = %v, want an error that does not pass", results[0].Err, results[0].Pass)
+	}
+	if results[1].Err != nil {
+		t.Errorf("evaluable threshold: Err = %v, want nil", results[1].Err)
+	}
+}
+
 func TestCompareValues(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -338,7 +512,33 @@ func TestExtractMetricValue(t *testing.T) {
 			P95LatencyMs:   300.5,
 			P99LatencyMs:   400.5,
 			RequestsPerSec: 123.45,
+			StatusBuckets: map[string]map[string]int{
+				"http": {"503": 30, "500": 10},
+				"grpc": {"UNAVAILABLE": 10},
+			},
+		},
+		Endpoints: map[string]metrics.EndpointStats{
+			"login": {Total: 200, Failures: 20, P99LatencyMs: 250, RequestsPerSec: 20},
+		},
+		Steps: map[string]metrics.EndpointStats{
+			"checkout": {Total: 100, P95LatencyMs: 150},
+		},
+		Phases: map[string]metrics.EndpointStats{
+			metrics.PhaseTTFB: {Total: 1000, P95LatencyMs: 120.5},
+			metrics.PhaseDNS:  {Total: 4, MaxLatencyMs: 8},
+		},
+		EndpointPhases: map[string]map[string]metrics.EndpointStats{
+			"login": {metrics.PhaseTTFB: {Total: 200, P95LatencyMs: 90}},
+		},
+		ProtocolMetrics: map[string]map[string]interface{}{
+			"websocket": {"messages_received": int64(400), "compression_ratio": metrics.Gauge(0.5)},
+			"grpc":      {"calls": 300.0},
 		},
+		Checks: map[string]metrics.CheckStats{
+			"status": {Passes: 90, Fails: 10},
+			"body":   {Passes: 10, Fails: 0},
+		},
+		Duration: 10 * time.Second,
 	}
 
 	tests := []struct {
@@ -402,6 +602,116 @@ func TestExtractMetricValue(t *testing.T) {
 			threshold: Threshold{Metric: "http_requests", Aggregate: "count"},
 			want:      1000,
 		},
+		{
+			name:      "checks rate across all checks",
+			threshold: Threshold{Metric: "checks", Aggregate: "rate"},
+			want:      100.0 / 110.0,
+		},
+		{
+			name:      "checks rate for one check",
+			threshold: Threshold{Metric: "checks", Selector: "status", Aggregate: "rate"},
+			want:      0.9,
+		},
+		{
+			name:      "checks count of failures",
+			threshold: Threshold{Metric: "checks", Selector: "status", Aggregate: "count"},
+			want:      10,
+		},
+		{
+			name:      "http_req_duration p99 for one endpoint",
+			threshold: Threshold{Metric: "http_req_duration", Labels: map[string]string{"endpoint": "login"}, Aggregate: "p99"},
+			want:      250,
+		},
+		{
+			name:      "http_req_failed rate for one endpoint",
+			threshold: Threshold{Metric: "http_req_failed", Labels: map[string]string{"endpoint": "login"}, Aggregate: "rate"},
+			want:      0.1,
+		},
+		{
+			name:      "http_req_duration p95 for one step",
+			threshold: Threshold{Metric: "http_req_duration", Labels: map[string]string{"step": "checkout"}, Aggregate: "p95"},
+			want:      150,
+		},
+		{
+			name:      "unknown endpoint",
+			threshold: Threshold{Metric: "http_req_duration", Labels: map[string]string{"endpoint": "missing"}, Aggregate: "p99"},
+			wantError: true,
+		},
+		{
+			name:      "arbitrary percentile without histogram",
+			threshold: Threshold{Metric: "http_req_duration", Aggregate: "p99.9"},
+			wantError: true,
+		},
+		{
+			name:      "phase p95",
+			threshold: Threshold{Metric: "http_req_ttfb", Aggregate: "p95"},
+			want:      120.5,
+		},
+		{
+			name:      "phase count",
+			threshold: Threshold{Metric: "http_req_dns", Aggregate: "count"},
+			want:      4,
+		},
+		{
+			name:      "phase p95 for one endpoint",
+			threshold: Threshold{Metric: "http_req_ttfb", Labels: map[string]string{"endpoint": "login"}, Aggregate: "p95"},
+			want:      90,
+		},
+		{
+			name:      "phase not recorded",
+			threshold: Threshold{Metric: "http_req_tls", Aggregate: "p95"},
+			wantError: true,
+		},
+		{
+			name:      "phase rate",
+			threshold: Threshold{Metric: "http_req_ttfb", Aggregate: "rate"},
+			wantError: true,
+		},
+		{
+			name:      "status_buckets count by status",
+			threshold: Threshold{Metric: "status_buckets", Labels: map[string]string{"status": "503"}, Aggregate: "count"},
+			want:      30,
+		},
+		{
+			name:      "status_buckets rate by protocol",
+			threshold: Threshold{Metric: "status_buckets", Labels: map[string]string{"protocol": "http"}, Aggregate: "rate"},
+			want:      0.04,
+		},
+		{
+			name:      "status_buckets count across all",
+			threshold: Threshold{Metric: "status_buckets", Aggregate: "count"},
+			want:      50,
+		},
+		{
+			name:      "protocol metric count",
+			threshold: Threshold{Metric: "websocket_messages_received", Aggregate: "count"},
+			want:      400,
+		},
+		{
+			name:      "protocol metric rate",
+			threshold: Threshold{Metric: "websocket_messages_received", Aggregate: "rate"},
+			want:      40,
+		},
+		{
+			name:      "protocol gauge value",
+			threshold: Threshold{Metric: "websocket_compression_ratio", Aggregate: "value"},
+			want:      0.5,
+		},
+		{
+			name:      "protocol metric decoded from JSON",
+			threshold: Threshold{Metric: "grpc_calls", Aggregate: "count"},
+			want:      300,
+		},
+		{
+			name:      "protocol metric never recorded",
+			threshold: Threshold{Metric: "sse_events_received", Aggregate: "count"},
+			wantError: true,
+		},
+		{
+			name:      "unknown check",
+			threshold: Threshold{Metric: "checks", Selector: "missing", Aggregate: "rate"},
+			wantError: true,
+		},
 		{
 			name:      "unsupported metric",
 			threshold: Threshold{Metric: "invalid_metric", Aggregate: "p95"},
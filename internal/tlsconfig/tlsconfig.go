@@ -0,0 +1,64 @@
+// Package tlsconfig turns the shared tls block of the configuration into a
+// crypto/tls client configuration used by every protocol client.
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"github.com/torosent/crankfire/internal/config"
+)
+
+// New builds the client TLS configuration for c. It returns nil when c is
+// not enabled, so callers keep the defaults of their transport.
+func New(c config.TLSConfig) (*tls.Config, error) {
+	if !c.Enabled() {
+		return nil, nil
+	}
+	out := &tls.Config{
+		ServerName: c.ServerName,
+		NextProtos: c.ALPN,
+	}
+	if c.CertFile != "" || c.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("tls: load client certificate: %w", err)
+		}
+		out.Certificates = []tls.Certificate{cert}
+	}
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("tls: read CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("tls: no certificates found in %s", c.CAFile)
+		}
+		out.RootCAs = pool
+	}
+	if c.MinVersion != "" {
+		v, ok := config.TLSVersion(c.MinVersion)
+		if !ok {
+			return nil, fmt.Errorf("tls: unsupported min_version %q", c.MinVersion)
+		}
+		out.MinVersion = v
+	}
+	if c.MaxVersion != "" {
+		v, ok := config.TLSVersion(c.MaxVersion)
+		if !ok {
+			return nil, fmt.Errorf("tls: unsupported max_version %q", c.MaxVersion)
+		}
+		out.MaxVersion = v
+	}
+	for _, name := range c.CipherSuites {
+		id, ok := config.CipherSuite(name)
+		if !ok {
+			return nil, fmt.Errorf("tls: unknown cipher suite %q", name)
+		}
+		out.CipherSuites = append(out.CipherSuites, id)
+	}
+	return out, nil
+}
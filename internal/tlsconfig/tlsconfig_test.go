@@ -0,0 +1,115 @@
+package tlsconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/torosent/crankfire/internal/config"
+)
+
+func TestNewDisabled(t *testing.T) {
+	got, err := New(config.TLSConfig{})
+	if err != nil || got != nil {
+		t.Fatalf("New() = %v, %v; want nil, nil", got, err)
+	}
+}
+
+func TestNewBuildsClientConfig(t *testing.T) {
+	certFile, keyFile := writeSelfSigned(t)
+	got, err := New(config.TLSConfig{
+		CertFile:     certFile,
+		KeyFile:      keyFile,
+		CAFile:       certFile,
+		ServerName:   "api.internal",
+		MinVersion:   "1.2",
+		MaxVersion:   "TLS1.3",
+		CipherSuites: []string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"},
+		ALPN:         []string{"h2", "http/1.1"},
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if len(got.Certificates) != 1 {
+		t.Errorf("got %d client certificates, want 1", len(got.Certificates))
+	}
+	if got.RootCAs == nil {
+		t.Error("RootCAs not set from ca_file")
+	}
+	if got.ServerName != "api.internal" {
+		t.Errorf("ServerName = %q", got.ServerName)
+	}
+	if got.MinVersion != tls.VersionTLS12 || got.MaxVersion != tls.VersionTLS13 {
+		t.Errorf("versions = %x..%x, want %x..%x", got.MinVersion, got.MaxVersion, tls.VersionTLS12, tls.VersionTLS13)
+	}
+	if len(got.CipherSuites) != 1 || got.CipherSuites[0] != tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256 {
+		t.Errorf("CipherSuites = %v", got.CipherSuites)
+	}
+	if len(got.NextProtos) != 2 || got.NextProtos[0] != "h2" {
+		t.Errorf("NextProtos = %v", got.NextProtos)
+	}
+}
+
+func TestNewReportsUnusableFiles(t *testing.T) {
+	certFile, keyFile := writeSelfSigned(t)
+	notPEM := filepath.Join(t.TempDir(), "ca.txt")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cases := map[string]config.TLSConfig{
+		"missing key":    {CertFile: certFile, KeyFile: filepath.Join(t.TempDir(), "missing.key")},
+		"swapped files":  {CertFile: keyFile, KeyFile: certFile},
+		"missing CA":     {CAFile: filepath.Join(t.TempDir(), "missing.pem")},
+		"CA without PEM": {CAFile: notPEM},
+	}
+	for name, c := range cases {
+		if _, err := New(c); err == nil {
+			t.Errorf("%s: New() succeeded", name)
+		}
+	}
+}
+
+// writeSelfSigned writes a self-signed certificate and its key as PEM files.
+func writeSelfSigned(t *testing.T) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "crankfire test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
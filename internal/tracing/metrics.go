@@ -0,0 +1,107 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/torosent/crankfire/internal/config"
+)
+
+// MetricsProvider periodically exports a run's metrics over OTLP.
+type MetricsProvider struct {
+	mp *sdkmetric.MeterProvider
+}
+
+// InitMetrics starts exporting the metrics returned by producer every
+// cfg.Interval. Returns a no-op provider if metrics export is disabled.
+// serviceName follows the same defaults as the tracing service name.
+func InitMetrics(ctx context.Context, cfg config.OTLPMetricsConfig, serviceName string, producer sdkmetric.Producer) (*MetricsProvider, error) {
+	if !cfg.Enabled() {
+		return &MetricsProvider{}, nil
+	}
+
+	attrs := []attribute.KeyValue{semconv.ServiceName(resolveServiceName(serviceName))}
+	keys := make([]string, 0, len(cfg.Attributes))
+	for k := range cfg.Attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		attrs = append(attrs, attribute.String(k, cfg.Attributes[k]))
+	}
+	res, err := resource.New(ctx, resource.WithAttributes(attrs...))
+	if err != nil {
+		return nil, fmt.Errorf("metrics resource: %w", err)
+	}
+
+	exporter, err := newMetricExporter(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("metrics exporter: %w", err)
+	}
+
+	interval := cfg.Interval
+	if interval <= 0 {
+		interval = config.DefaultOTLPMetricsInterval
+	}
+	reader := sdkmetric.NewPeriodicReader(exporter,
+		sdkmetric.WithInterval(interval),
+		sdkmetric.WithProducer(producer),
+	)
+
+	return &MetricsProvider{
+		mp: sdkmetric.NewMeterProvider(
+			sdkmetric.WithReader(reader),
+			sdkmetric.WithResource(res),
+		),
+	}, nil
+}
+
+// Shutdown exports the final measurements and shuts down the provider.
+func (p *MetricsProvider) Shutdown(ctx context.Context) error {
+	if p == nil || p.mp == nil {
+		return nil
+	}
+	return p.mp.Shutdown(ctx)
+}
+
+func newMetricExporter(ctx context.Context, cfg config.OTLPMetricsConfig) (sdkmetric.Exporter, error) {
+	protocol := strings.ToLower(cfg.Protocol)
+	if protocol == "" {
+		protocol = "grpc"
+	}
+
+	switch protocol {
+	case "grpc":
+		opts := []otlpmetricgrpc.Option{
+			otlpmetricgrpc.WithEndpoint(cfg.Endpoint),
+		}
+		if cfg.Insecure {
+			opts = append(opts, otlpmetricgrpc.WithDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())))
+			opts = append(opts, otlpmetricgrpc.WithInsecure())
+		}
+		return otlpmetricgrpc.New(ctx, opts...)
+
+	case "http":
+		opts := []otlpmetrichttp.Option{
+			otlpmetrichttp.WithEndpoint(cfg.Endpoint),
+		}
+		if cfg.Insecure {
+			opts = append(opts, otlpmetrichttp.WithInsecure())
+		}
+		return otlpmetrichttp.New(ctx, opts...)
+
+	default:
+		return nil, fmt.Errorf("unsupported OTLP protocol %q: use \"grpc\" or \"http\"", protocol)
+	}
+}
@@ -0,0 +1,117 @@
+package tracing_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/torosent/crankfire/internal/config"
+	"github.com/torosent/crankfire/internal/metrics"
+	"github.com/torosent/crankfire/internal/tracing"
+)
+
+func TestInitMetricsDisabledByDefault(t *testing.T) {
+	p, err := tracing.InitMetrics(context.Background(), config.OTLPMetricsConfig{}, "", metrics.NewCollector())
+	if err != nil {
+		t.Fatalf("InitMetrics() error = %v", err)
+	}
+	if err := p.Shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown() error = %v", err)
+	}
+
+	var nilProvider *tracing.MetricsProvider
+	if err := nilProvider.Shutdown(context.Background()); err != nil {
+		t.Errorf("nil Shutdown() error = %v", err)
+	}
+}
+
+func TestInitMetricsUnsupportedProtocol(t *testing.T) {
+	_, err := tracing.InitMetrics(context.Background(), config.OTLPMetricsConfig{
+		Endpoint: "localhost:4317",
+		Protocol: "thrift",
+	}, "", metrics.NewCollector())
+	if err == nil || !strings.Contains(err.Error(), "unsupported OTLP protocol") {
+		t.Fatalf("InitMetrics() error = %v, want unsupported protocol", err)
+	}
+}
+
+func TestInitMetricsExportsOverHTTP(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests []*colmetricpb.ExportMetricsServiceRequest
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		req := &colmetricpb.ExportMetricsServiceRequest{}
+		if err := proto.Unmarshal(body, req); err != nil {
+			t.Errorf("unmarshal export: %v", err)
+		}
+		mu.Lock()
+		requests = append(requests, req)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		resp, _ := proto.Marshal(&colmetricpb.ExportMetricsServiceResponse{})
+		_, _ = w.Write(resp)
+	}))
+	defer srv.Close()
+
+	collector := metrics.NewCollector()
+	collector.Start()
+	collector.RecordRequest(5*time.Millisecond, nil, &metrics.RequestMetadata{Endpoint: "home", Protocol: "http"})
+
+	cfg := config.OTLPMetricsConfig{
+		Endpoint: strings.TrimPrefix(srv.URL, "http://"),
+		Protocol: "http",
+		Insecure: true,
+		Interval: time.Hour,
+	}.WithAttributes(config.ResourceSessionID, "checkout", config.ResourceSetRunID, "2026-01-01T00-00-00Z")
+	p, err := tracing.InitMetrics(context.Background(), cfg, "loadgen", collector)
+	if err != nil {
+		t.Fatalf("InitMetrics() error = %v", err)
+	}
+	// Shutdown flushes the final collection even though the interval has
+	// not elapsed.
+	if err := p.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(requests) == 0 {
+		t.Fatal("no metrics exported")
+	}
+	rm := requests[len(requests)-1].GetResourceMetrics()[0]
+	attrs := make(map[string]string)
+	for _, kv := range rm.GetResource().GetAttributes() {
+		attrs[kv.GetKey()] = kv.GetValue().GetStringValue()
+	}
+	for key, want := range map[string]string{
+		"service.name":           "loadgen",
+		config.ResourceSessionID: "checkout",
+		config.ResourceSetRunID:  "2026-01-01T00-00-00Z",
+	} {
+		if attrs[key] != want {
+			t.Errorf("resource attribute %s = %q, want %q", key, attrs[key], want)
+		}
+	}
+
+	names := make(map[string]bool)
+	for _, sm := range rm.GetScopeMetrics() {
+		for _, m := range sm.GetMetrics() {
+			names[m.GetName()] = true
+		}
+	}
+	for _, want := range []string{"crankfire.requests", "crankfire.request.duration", "crankfire.endpoint.duration"} {
+		if !names[want] {
+			t.Errorf("exported metrics missing %s: %v", want, names)
+		}
+	}
+}
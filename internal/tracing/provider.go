@@ -35,14 +35,7 @@ func Init(ctx context.Context, cfg config.TracingConfig) (*Provider, error) {
 		return &Provider{propagate: false}, nil
 	}
 
-	serviceName := cfg.ServiceName
-	if serviceName == "" {
-		if envName := os.Getenv("OTEL_SERVICE_NAME"); envName != "" {
-			serviceName = envName
-		} else {
-			serviceName = "crankfire"
-		}
-	}
+	serviceName := resolveServiceName(cfg.ServiceName)
 
 	endpoint := cfg.Endpoint
 	if endpoint == "" {
@@ -120,6 +113,17 @@ func (p *Provider) Shutdown(ctx context.Context) error {
 	return p.tp.Shutdown(ctx)
 }
 
+// resolveServiceName falls back to OTEL_SERVICE_NAME, then "crankfire".
+func resolveServiceName(name string) string {
+	if name != "" {
+		return name
+	}
+	if envName := os.Getenv("OTEL_SERVICE_NAME"); envName != "" {
+		return envName
+	}
+	return "crankfire"
+}
+
 func newExporter(ctx context.Context, cfg config.TracingConfig, endpoint string) (sdktrace.SpanExporter, error) {
 	protocol := strings.ToLower(cfg.Protocol)
 	if protocol == "" {
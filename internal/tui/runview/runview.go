@@ -577,7 +577,7 @@ func (m Model) latencyStatsBody() string {
 		p95Latency = m.stats.P95LatencyMs
 		p99Latency = m.stats.P99LatencyMs
 	}
-	return fmt.Sprintf(
+	body := fmt.Sprintf(
 		"Min:  %.2fms\nMean: %.2fms\nP50:  %.2fms\nP90:  %.2fms\nP95:  %.2fms\nP99:  %.2fms",
 		minLatency,
 		meanLatency,
@@ -586,6 +586,32 @@ func (m Model) latencyStatsBody() string {
 		p95Latency,
 		p99Latency,
 	)
+	return body + m.phasesBody()
+}
+
+// phaseLabels abbreviates connection phases to fit the latency stats panel.
+var phaseLabels = map[string]string{
+	metrics.PhaseDNS:      "DNS",
+	metrics.PhaseConnect:  "Conn",
+	metrics.PhaseTLS:      "TLS",
+	metrics.PhaseTTFB:     "TTFB",
+	metrics.PhaseTransfer: "Xfer",
+}
+
+// phasesBody lists the p95 of every recorded connection phase below the
+// latency stats, or nothing when no phase was recorded.
+func (m Model) phasesBody() string {
+	if m.stats == nil || len(m.stats.Phases) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\nPhase P95:")
+	for _, phase := range metrics.Phases {
+		if ps, ok := m.stats.Phases[phase]; ok {
+			fmt.Fprintf(&b, "\n%-5s %.2fms", phaseLabels[phase]+":", ps.P95LatencyMs)
+		}
+	}
+	return b.String()
 }
 
 func (m Model) protocolPanel(width, minContentHeight int) string {
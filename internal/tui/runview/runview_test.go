@@ -1394,3 +1394,25 @@ func withLatencyTrendData(rv runview.Model) runview.Model {
 	}
 	return rv
 }
+
+func TestRunViewShowsConnectionPhases(t *testing.T) {
+	rv := runview.New(runview.Options{Title: "x"})
+	rv, _ = rv.Update(tea.WindowSizeMsg{Width: 120, Height: 60})
+	stats := &metrics.Stats{
+		Phases: map[string]metrics.EndpointStats{
+			metrics.PhaseTTFB: {P95LatencyMs: 42.5},
+			metrics.PhaseDNS:  {P95LatencyMs: 1.25},
+		},
+	}
+	out := rv.View()
+	if strings.Contains(out, "Phase P95") {
+		t.Errorf("phases shown before any were recorded:\n%s", out)
+	}
+	rv2, _ := rv.Update(runview.SnapshotMsg{Stats: stats})
+	out = rv2.View()
+	dns := strings.Index(out, "DNS:  1.25ms")
+	ttfb := strings.Index(out, "TTFB: 42.50ms")
+	if !strings.Contains(out, "Phase P95:") || dns < 0 || ttfb < dns {
+		t.Errorf("expected phase p95s in order, got:\n%s", out)
+	}
+}
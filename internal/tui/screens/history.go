@@ -2,15 +2,20 @@ package screens
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/torosent/crankfire/internal/metrics"
+	"github.com/torosent/crankfire/internal/regression"
 	"github.com/torosent/crankfire/internal/store"
+	"github.com/torosent/crankfire/internal/tui/widgets"
 )
 
 type HistoryLoadedMsg struct {
@@ -18,16 +23,68 @@ type HistoryLoadedMsg struct {
 	Err  error
 }
 
+// RunArtifactsLoadedMsg carries the stored histograms and time series of the
+// run in Dir. Either may be missing for runs recorded before they were kept.
+type RunArtifactsLoadedMsg struct {
+	Dir    string
+	Stats  *metrics.Stats
+	Series []metrics.DataPoint
+	Err    error
+}
+
 type History struct {
 	store     store.Store
 	sessionID string
 	runs      []store.Run
 	cursor    int
 	err       error
+	// details caches loaded run artifacts by run directory; they are only
+	// read when a run is expanded.
+	details map[string]RunArtifactsLoadedMsg
+	expand  bool
+	// trend replaces the run list with the session's trend across runs.
+	trend bool
 }
 
 func NewHistory(s store.Store, sessionID string) History {
-	return History{store: s, sessionID: sessionID}
+	return History{store: s, sessionID: sessionID, details: map[string]RunArtifactsLoadedMsg{}}
+}
+
+// loadRunArtifacts reads a run's stored histograms and time series.
+func loadRunArtifacts(dir string) tea.Cmd {
+	return func() tea.Msg {
+		msg := RunArtifactsLoadedMsg{Dir: dir}
+		if m, err := store.ReadMeasurements(dir); err == nil {
+			stats, err := m.Stats(0)
+			if err != nil {
+				msg.Err = err
+				return msg
+			}
+			msg.Stats = &stats
+		} else if !errors.Is(err, os.ErrNotExist) {
+			msg.Err = err
+			return msg
+		}
+		series, err := store.ReadTimeSeries(dir)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			msg.Err = err
+		}
+		msg.Series = series
+		return msg
+	}
+}
+
+// ensureDetails loads the artifacts of the selected run if it is expanded
+// and they are not cached yet.
+func (h History) ensureDetails() tea.Cmd {
+	if !h.expand || len(h.runs) == 0 {
+		return nil
+	}
+	dir := h.runs[h.cursor].Dir
+	if _, ok := h.details[dir]; ok || dir == "" {
+		return nil
+	}
+	return loadRunArtifacts(dir)
 }
 
 func (h History) Init() tea.Cmd {
@@ -43,6 +100,11 @@ func (h History) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		h.runs = m.Runs
 		h.err = m.Err
 		h.cursor = 0
+	case RunArtifactsLoadedMsg:
+		if h.details == nil {
+			h.details = map[string]RunArtifactsLoadedMsg{}
+		}
+		h.details[m.Dir] = m
 	case tea.KeyMsg:
 		switch m.String() {
 		case "esc":
@@ -51,10 +113,17 @@ func (h History) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if h.cursor > 0 {
 				h.cursor--
 			}
+			return h, h.ensureDetails()
 		case "down", "j":
 			if h.cursor < len(h.runs)-1 {
 				h.cursor++
 			}
+			return h, h.ensureDetails()
+		case "t":
+			h.trend = !h.trend
+		case "enter":
+			h.expand = !h.expand
+			return h, h.ensureDetails()
 		case "o":
 			if len(h.runs) > 0 {
 				run := h.runs[h.cursor]
@@ -95,6 +164,12 @@ func (h History) View() string {
 		return b.String()
 	}
 
+	if h.trend {
+		viewTrend(&b, regression.Trend(h.sessionID, regression.SessionSamples(h.runs), 0, 0))
+		b.WriteString("\n[t] runs  [Esc] back\n")
+		return b.String()
+	}
+
 	// Header
 	fmt.Fprintf(&b, "%-20s %-12s %-10s %-8s %-8s\n", "Started", "Status", "Total", "P95ms", "Errors")
 	b.WriteString(strings.Repeat("-", 60) + "\n")
@@ -115,6 +190,50 @@ func (h History) View() string {
 			prefix, startedStr, statusStr, totalStr, p95Str, errorStr)
 	}
 
-	b.WriteString("\n[o] open report  [Esc] back\n")
+	if h.expand {
+		h.viewDetails(&b, h.runs[h.cursor])
+	}
+
+	b.WriteString("\n[enter] details  [t] trend  [o] open report  [Esc] back\n")
 	return b.String()
 }
+
+// viewDetails renders the time series and histogram percentiles of run.
+func (h History) viewDetails(b *strings.Builder, run store.Run) {
+	b.WriteString("\n")
+	d, ok := h.details[run.Dir]
+	switch {
+	case !ok:
+		b.WriteString("loading…\n")
+		return
+	case d.Err != nil:
+		fmt.Fprintf(b, "error: %v\n", d.Err)
+		return
+	case d.Stats == nil && len(d.Series) == 0:
+		b.WriteString("(no histograms or time series stored for this run)\n")
+		return
+	}
+	if len(d.Series) > 0 {
+		p95 := make([]float64, len(d.Series))
+		rps := make([]float64, len(d.Series))
+		for i, p := range d.Series {
+			p95[i], rps[i] = p.P95LatencyMs, p.CurrentRPS
+		}
+		fmt.Fprintf(b, "P95ms %s\n", widgets.Sparkline(p95, 50))
+		fmt.Fprintf(b, "RPS   %s\n", widgets.Sparkline(rps, 50))
+	}
+	if d.Stats != nil {
+		p999, _ := d.Stats.Percentile(99.9)
+		fmt.Fprintf(b, "P90 %.1fms  P99 %.1fms  P99.9 %.1fms  Max %.1fms\n",
+			d.Stats.P90LatencyMs, d.Stats.P99LatencyMs, float64(p999.Microseconds())/1000, d.Stats.MaxLatencyMs)
+		names := make([]string, 0, len(d.Stats.Endpoints))
+		for name := range d.Stats.Endpoints {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			ep := d.Stats.Endpoints[name]
+			fmt.Fprintf(b, "  %-24s P95 %.1fms  P99 %.1fms  %d req\n", name, ep.P95LatencyMs, ep.P99LatencyMs, ep.Total)
+		}
+	}
+}
@@ -2,12 +2,15 @@ package screens_test
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/torosent/crankfire/internal/metrics"
 	"github.com/torosent/crankfire/internal/store"
 	"github.com/torosent/crankfire/internal/tui/screens"
 )
@@ -34,3 +37,65 @@ func TestHistoryShowsRuns(t *testing.T) {
 		t.Errorf("history view missing total: %s", model.View())
 	}
 }
+
+func TestHistoryEnterLoadsRunArtifacts(t *testing.T) {
+	s, _ := store.NewFS(t.TempDir())
+	ctx := context.Background()
+	_ = s.SaveSession(ctx, store.Session{Name: "x"})
+	list, _ := s.ListSessions(ctx)
+	sid := list[0].ID
+	run, _ := s.CreateRun(ctx, sid)
+	c := metrics.NewCollector()
+	for i := 1; i <= 100; i++ {
+		c.RecordRequest(time.Duration(i)*time.Millisecond, nil, &metrics.RequestMetadata{Endpoint: "users"})
+	}
+	m, _ := c.Measurements()
+	if err := store.WriteRunArtifacts(run.Dir, m, []metrics.DataPoint{{CurrentRPS: 5, P95LatencyMs: 50}, {CurrentRPS: 9, P95LatencyMs: 95}}); err != nil {
+		t.Fatal(err)
+	}
+	_ = s.FinalizeRun(ctx, run, store.RunSummary{TotalRequests: 100})
+
+	h := screens.NewHistory(s, sid)
+	var model tea.Model = h
+	model, _ = model.Update(h.Init()())
+	if strings.Contains(model.View(), "P99.9") {
+		t.Fatal("artifacts should not load before a run is expanded")
+	}
+	model, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("enter should load the run's artifacts")
+	}
+	model, _ = model.Update(cmd())
+	v := model.View()
+	for _, want := range []string{"P95ms", "RPS", "P99.9", "users"} {
+		if !strings.Contains(v, want) {
+			t.Errorf("details missing %q:\n%s", want, v)
+		}
+	}
+}
+
+func TestHistoryTrendHighlightsRegressions(t *testing.T) {
+	h := screens.NewHistory(nil, "sess")
+	var model tea.Model = h
+	var runs []store.Run
+	for i, p95 := range []float64{180, 101, 98, 102, 100} {
+		runs = append(runs, store.Run{
+			Dir:       fmt.Sprintf("/runs/sess/%d", 5-i),
+			StartedAt: time.Date(2026, 4, 19, 10, 4-i, 0, 0, time.UTC),
+			Status:    store.RunStatusCompleted,
+			Summary:   store.RunSummary{TotalRequests: 1000, DurationSec: 10, P50Ms: 50, P95Ms: p95, P99Ms: 200},
+		})
+	}
+	model, _ = model.Update(screens.HistoryLoadedMsg{Runs: runs})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	v := model.View()
+	for _, want := range []string{"5 runs, 1 regressed", "P99ms", "Err%", "RPS", "▲ regressed: 2026-04-19 10:04"} {
+		if !strings.Contains(v, want) {
+			t.Errorf("trend view missing %q:\n%s", want, v)
+		}
+	}
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	if !strings.Contains(model.View(), "Started") {
+		t.Errorf("t should toggle back to the run list:\n%s", model.View())
+	}
+}
@@ -12,10 +12,12 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/torosent/crankfire/internal/cli"
+	"github.com/torosent/crankfire/internal/config"
 	"github.com/torosent/crankfire/internal/metrics"
 	"github.com/torosent/crankfire/internal/output"
 	"github.com/torosent/crankfire/internal/runner"
 	"github.com/torosent/crankfire/internal/store"
+	"github.com/torosent/crankfire/internal/threshold"
 	"github.com/torosent/crankfire/internal/tui/runview"
 )
 
@@ -112,8 +114,21 @@ func (r *Run) Init() tea.Cmd {
 			return runStartedMsg{err: fmt.Errorf("create run: %w", err)}
 		}
 
+		cfg := r.sess.Config
+		if cfg.OTLPMetrics.Enabled() {
+			cfg.OTLPMetrics = cfg.OTLPMetrics.WithAttributes(
+				config.ResourceSessionID, r.sess.ID,
+				config.ResourceRunID, filepath.Base(run.Dir),
+			)
+		}
+
+		thresholds, err := threshold.ParseMultiple(cfg.Thresholds)
+		if err != nil {
+			return runStartedMsg{run: run, err: fmt.Errorf("thresholds: %w", err)}
+		}
+
 		runCtx, cancel := context.WithCancel(context.Background())
-		runnerInst, collector, cleanup, err := cli.BuildRunner(runCtx, r.sess.Config)
+		runnerInst, collector, cleanup, err := cli.BuildRunner(runCtx, cfg)
 		if err != nil {
 			cancel()
 			return runStartedMsg{run: run, err: fmt.Errorf("build runner: %w", err)}
@@ -122,9 +137,13 @@ func (r *Run) Init() tea.Cmd {
 		go func() {
 			defer cleanup()
 			collector.Start()
-			result := runnerInst.Run(runCtx)
+			result, breach := cli.RunWithThresholds(runCtx, runnerInst, collector, thresholds)
 			collector.Snapshot()
-			runFinishedProgram(r, result, runCtx.Err())
+			runErr := runCtx.Err()
+			if breach != nil {
+				runErr = breach
+			}
+			runFinishedProgram(r, result, runErr)
 		}()
 
 		return runStartedMsg{run: run, collector: collector, cancel: cancel}
@@ -308,20 +327,32 @@ func (r *Run) finalizeCmd(result runner.Result, runErr error) tea.Cmd {
 			run.Status = store.RunStatusFailed
 		}
 
-		// Write result.json + report.html into the run directory if we have
-		// a collector and the directory exists.
+		// Write result.json, report.html and the raw histograms and time
+		// series into the run directory if we have a collector and the
+		// directory exists.
 		if collector != nil && run.Dir != "" {
 			stats := collector.Stats(result.Duration)
+			var thresholdResults []threshold.Result
+			if thresholds, err := threshold.ParseMultiple(r.sess.Config.Thresholds); err == nil && len(thresholds) > 0 {
+				thresholdResults = threshold.NewEvaluator(thresholds).Evaluate(stats)
+				var breach *threshold.Breach
+				if errors.As(runErr, &breach) {
+					breach.Apply(thresholdResults)
+				}
+			}
 			if f, err := os.Create(filepath.Join(run.Dir, "result.json")); err == nil {
-				_ = output.PrintJSONReport(f, stats, nil)
+				_ = output.PrintJSONReport(f, stats, thresholdResults, nil)
 				_ = f.Close()
 			}
 			if f, err := os.Create(filepath.Join(run.Dir, "report.html")); err == nil {
-				_ = output.GenerateHTMLReport(f, stats, collector.History(), nil, output.ReportMetadata{
+				_ = output.GenerateHTMLReport(f, stats, collector.History(), thresholdResults, output.ReportMetadata{
 					TargetURL: r.sess.Config.TargetURL,
 				})
 				_ = f.Close()
 			}
+			if m, err := collector.Measurements(); err == nil {
+				_ = store.WriteRunArtifacts(run.Dir, m, collector.History())
+			}
 		}
 
 		if run.SessionID != "" {
@@ -116,7 +116,8 @@ func (m *SetRun) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "esc", "q":
 			m.cancel()
 			if m.finalRun != nil {
-				return NewSetCompare(m.parentCtx, m.store, *m.finalRun), nil
+				cmp := NewSetCompare(m.parentCtx, m.store, *m.finalRun)
+				return cmp, cmp.Init()
 			}
 			return NewSetsDetail(m.parentCtx, m.store, m.setID), nil
 		}
@@ -7,15 +7,25 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/torosent/crankfire/internal/metrics"
 	"github.com/torosent/crankfire/internal/setrunner"
 	"github.com/torosent/crankfire/internal/store"
 )
 
+// itemHistogramsMsg carries stats rebuilt from the stored histograms of each
+// set item, keyed by item name.
+type itemHistogramsMsg struct {
+	stats map[string]metrics.Stats
+}
+
 type SetCompare struct {
 	ctx     context.Context
 	store   store.Store
 	run     store.SetRun
 	compare setrunner.Comparison
+	// histStats holds percentiles computed from the items' histograms,
+	// loaded after the screen opens.
+	histStats map[string]metrics.Stats
 }
 
 func NewSetCompare(ctx context.Context, st store.Store, run store.SetRun) tea.Model {
@@ -26,11 +36,39 @@ func NewSetCompare(ctx context.Context, st store.Store, run store.SetRun) tea.Mo
 	return &SetCompare{ctx: ctx, store: st, run: run, compare: setrunner.Compare(items)}
 }
 
-func (m *SetCompare) Init() tea.Cmd { return nil }
+func (m *SetCompare) Init() tea.Cmd {
+	dirs := map[string]string{}
+	for _, s := range m.run.Stages {
+		for _, it := range s.Items {
+			if it.RunDir != "" {
+				dirs[it.Name] = it.RunDir
+			}
+		}
+	}
+	if len(dirs) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		out := map[string]metrics.Stats{}
+		for name, dir := range dirs {
+			meas, err := store.ReadMeasurements(dir)
+			if err != nil {
+				continue
+			}
+			if stats, err := meas.Stats(0); err == nil {
+				out[name] = stats
+			}
+		}
+		return itemHistogramsMsg{stats: out}
+	}
+}
 
 func (m *SetCompare) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	if k, ok := msg.(tea.KeyMsg); ok {
-		switch k.String() {
+	switch v := msg.(type) {
+	case itemHistogramsMsg:
+		m.histStats = v.stats
+	case tea.KeyMsg:
+		switch v.String() {
 		case "esc", "q":
 			return NewSetsDetail(m.ctx, m.store, m.run.SetID), nil
 		}
@@ -61,6 +99,21 @@ func (m *SetCompare) View() string {
 		}
 		b.WriteString("\n")
 	}
+	if len(m.histStats) > 0 {
+		for _, q := range []float64{90, 99.9} {
+			fmt.Fprintf(&b, "%-12s", fmt.Sprintf("p%g (hdr)", q))
+			for _, name := range m.compare.Items {
+				stats, ok := m.histStats[name]
+				if !ok {
+					fmt.Fprintf(&b, " %*s", colW, "—")
+					continue
+				}
+				d, _ := stats.Percentile(q)
+				fmt.Fprintf(&b, " %*.2f", colW, float64(d.Microseconds())/1000)
+			}
+			b.WriteString("\n")
+		}
+	}
 	if len(m.run.Thresholds) > 0 {
 		b.WriteString("\nThresholds:\n")
 		for _, th := range m.run.Thresholds {
@@ -11,22 +11,53 @@ import (
 	"github.com/torosent/crankfire/internal/store"
 )
 
+// diffHistoryMsg carries the regression check of the newer diffed run
+// against the runs before it.
+type diffHistoryMsg struct {
+	history *store.SetRegression
+	err     error
+}
+
 type SetsDiff struct {
-	ctx    context.Context
-	store  store.Store
-	setID  string
-	result setrunner.DiffResult
+	ctx     context.Context
+	store   store.Store
+	setID   string
+	result  setrunner.DiffResult
+	loaded  bool
+	histErr error
 }
 
 func NewSetsDiff(ctx context.Context, st store.Store, setID string, a, b store.SetRun) *SetsDiff {
 	return &SetsDiff{ctx: ctx, store: st, setID: setID, result: setrunner.Diff(a, b)}
 }
 
-func (m *SetsDiff) Init() tea.Cmd { return nil }
+func (m *SetsDiff) Init() tea.Cmd {
+	if m.store == nil {
+		return nil
+	}
+	latest := m.result.B
+	if m.result.A.StartedAt.After(latest.StartedAt) {
+		latest = m.result.A
+	}
+	return func() tea.Msg {
+		window := 0
+		if set, err := m.store.GetSet(m.ctx, m.setID); err == nil {
+			window = set.RegressionWindow
+		}
+		runs, err := m.store.ListSetRuns(m.ctx, m.setID)
+		if err != nil {
+			return diffHistoryMsg{err: err}
+		}
+		return diffHistoryMsg{history: setrunner.DetectRegression(latest, setrunner.PreviousRuns(runs, latest, window))}
+	}
+}
 
 func (m *SetsDiff) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	if k, ok := msg.(tea.KeyMsg); ok {
-		switch k.String() {
+	switch v := msg.(type) {
+	case diffHistoryMsg:
+		m.result.History, m.histErr, m.loaded = v.history, v.err, true
+	case tea.KeyMsg:
+		switch v.String() {
 		case "esc", "q":
 			return NewSetsHistory(m.ctx, m.store, m.setID), nil
 		}
@@ -54,5 +85,29 @@ func (m *SetsDiff) View() string {
 			row.P50DeltaMs, row.P95DeltaMs, row.P99DeltaMs,
 			row.ErrRateDelta, row.RPSDelta, extra)
 	}
+	m.viewHistory(&b)
 	return b.String()
 }
+
+// viewHistory renders the newer run's verdict against its set history.
+func (m *SetsDiff) viewHistory(b *strings.Builder) {
+	if !m.loaded {
+		return
+	}
+	b.WriteString("\n")
+	switch h := m.result.History; {
+	case m.histErr != nil:
+		fmt.Fprintf(b, "History: error: %v\n", m.histErr)
+	case h == nil:
+		b.WriteString("History: no earlier runs\n")
+	default:
+		fmt.Fprintf(b, "History — newer run vs last %d runs   verdict: %s\n", h.Window, h.Verdict)
+		for _, it := range h.Items {
+			fmt.Fprintf(b, "%-20s  %-12s  n=%d", it.Name, it.Verdict, it.Samples)
+			for _, metric := range it.Metrics {
+				fmt.Fprintf(b, "  z%s %+6.2f", metric.Metric, metric.Score)
+			}
+			b.WriteString("\n")
+		}
+	}
+}
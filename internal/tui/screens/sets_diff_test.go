@@ -4,6 +4,7 @@ import (
 	"context"
 	"strings"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 
@@ -75,3 +76,29 @@ func TestSetsDiffViewShowsVerdict(t *testing.T) {
 		t.Errorf("view should show item:\n%s", v)
 	}
 }
+
+func TestSetsDiffViewShowsHistoryVerdict(t *testing.T) {
+	st, _ := store.NewFS(t.TempDir())
+	ctx := context.Background()
+	setID := "01F8MECHZX3TBDSZ7XR9PFE7H2"
+	var runs []store.SetRun
+	for i, p95 := range []float64{100, 102, 98, 101, 200} {
+		r, err := st.CreateSetRun(ctx, setID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.StartedAt = time.Date(2026, 4, 19, 10, i, 0, 0, time.UTC)
+		r.Status = store.SetRunCompleted
+		r.Stages = []store.StageResult{{Name: "s", Items: []store.ItemResult{{Name: "api", Status: store.RunStatusCompleted,
+			Summary: store.RunSummary{TotalRequests: 1000, P50Ms: 50, P95Ms: p95, P99Ms: 300}}}}}
+		_ = st.FinalizeSetRun(ctx, r)
+		runs = append(runs, r)
+		time.Sleep(2 * time.Millisecond)
+	}
+	d := screens.NewSetsDiff(ctx, st, setID, runs[4], runs[3])
+	model, _ := d.Update(d.Init()())
+	v := model.View()
+	if !strings.Contains(v, "vs last 4 runs   verdict: regressed") {
+		t.Errorf("view should show the history verdict:\n%s", v)
+	}
+}
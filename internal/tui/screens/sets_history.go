@@ -7,12 +7,15 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/torosent/crankfire/internal/setrunner"
 	"github.com/torosent/crankfire/internal/store"
 )
 
 type historyLoadedMsg struct {
 	runs []store.SetRun
-	err  error
+	// window is the set's regression window, 0 for the default.
+	window int
+	err    error
 }
 
 type SetsHistory struct {
@@ -22,7 +25,10 @@ type SetsHistory struct {
 	runs   []store.SetRun
 	cursor int
 	marks  map[int]bool
-	err    error
+	window int
+	// trend replaces the run list with each item's trend across runs.
+	trend bool
+	err   error
 }
 
 func NewSetsHistory(ctx context.Context, st store.Store, setID string) tea.Model {
@@ -32,14 +38,18 @@ func NewSetsHistory(ctx context.Context, st store.Store, setID string) tea.Model
 func (m *SetsHistory) Init() tea.Cmd {
 	return func() tea.Msg {
 		runs, err := m.store.ListSetRuns(m.ctx, m.setID)
-		return historyLoadedMsg{runs: runs, err: err}
+		msg := historyLoadedMsg{runs: runs, err: err}
+		if set, err := m.store.GetSet(m.ctx, m.setID); err == nil {
+			msg.window = set.RegressionWindow
+		}
+		return msg
 	}
 }
 
 func (m *SetsHistory) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch v := msg.(type) {
 	case historyLoadedMsg:
-		m.runs, m.err = v.runs, v.err
+		m.runs, m.window, m.err = v.runs, v.window, v.err
 	case tea.KeyMsg:
 		switch v.String() {
 		case "esc", "q":
@@ -52,6 +62,8 @@ func (m *SetsHistory) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.cursor > 0 {
 				m.cursor--
 			}
+		case "t":
+			m.trend = !m.trend
 		case " ":
 			if m.marks == nil {
 				m.marks = map[int]bool{}
@@ -63,7 +75,8 @@ func (m *SetsHistory) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		case "enter":
 			if len(m.runs) > 0 {
-				return NewSetCompare(m.ctx, m.store, m.runs[m.cursor]), nil
+				cmp := NewSetCompare(m.ctx, m.store, m.runs[m.cursor])
+				return cmp, cmp.Init()
 			}
 		case "d":
 			if len(m.marks) == 2 {
@@ -73,7 +86,8 @@ func (m *SetsHistory) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						picked = append(picked, m.runs[i])
 					}
 				}
-				return NewSetsDiff(m.ctx, m.store, m.setID, picked[0], picked[1]), nil
+				diff := NewSetsDiff(m.ctx, m.store, m.setID, picked[0], picked[1])
+				return diff, diff.Init()
 			}
 		}
 	}
@@ -82,7 +96,11 @@ func (m *SetsHistory) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m *SetsHistory) View() string {
 	var b strings.Builder
-	fmt.Fprintf(&b, "Run History — enter)compare  space)mark  d)diff (need 2)  esc)back   [%d selected]\n\n", len(m.marks))
+	if m.trend {
+		b.WriteString("Run Trend — t)runs  esc)back\n\n")
+	} else {
+		fmt.Fprintf(&b, "Run History — enter)compare  space)mark  d)diff (need 2)  t)trend  esc)back   [%d selected]\n\n", len(m.marks))
+	}
 	if m.err != nil {
 		fmt.Fprintf(&b, "Error: %v\n", m.err)
 		return b.String()
@@ -91,6 +109,15 @@ func (m *SetsHistory) View() string {
 		b.WriteString("No runs yet.\n")
 		return b.String()
 	}
+	if m.trend {
+		for i, s := range setrunner.ItemTrends(m.runs, 0, m.window) {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			viewTrend(&b, s)
+		}
+		return b.String()
+	}
 	for i, r := range m.runs {
 		marker := "  "
 		if i == m.cursor {
@@ -0,0 +1,54 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/torosent/crankfire/internal/regression"
+	"github.com/torosent/crankfire/internal/tui/widgets"
+)
+
+// trendWidth is the width of the sparklines in trend views; it matches
+// regression.DefaultTrendRuns so every run gets one cell.
+const trendWidth = regression.DefaultTrendRuns
+
+// viewTrend renders p95, p99, error rate and RPS of s as sparklines, oldest
+// run on the left, with a ▲ under every run that regressed.
+func viewTrend(b *strings.Builder, s regression.Series) {
+	fmt.Fprintf(b, "%s — %d runs, %d regressed\n", s.Name, len(s.Points), s.Regressions())
+	if len(s.Points) == 0 {
+		b.WriteString("  (no completed runs)\n")
+		return
+	}
+	rows := []struct {
+		label string
+		get   func(regression.Point) float64
+	}{
+		{"P95ms", func(p regression.Point) float64 { return p.P95Ms }},
+		{"P99ms", func(p regression.Point) float64 { return p.P99Ms }},
+		{"Err%", func(p regression.Point) float64 { return p.ErrorRate * 100 }},
+		{"RPS", func(p regression.Point) float64 { return p.RPS }},
+	}
+	flags := make([]bool, len(s.Points))
+	for i, p := range s.Points {
+		flags[i] = p.Verdict == regression.VerdictRegressed
+	}
+	for _, r := range rows {
+		values := make([]float64, len(s.Points))
+		for i, p := range s.Points {
+			values[i] = r.get(p)
+		}
+		last := values[len(values)-1]
+		fmt.Fprintf(b, "  %-6s %s  %.2f\n", r.label, widgets.Sparkline(values, trendWidth), last)
+	}
+	if s.Regressions() == 0 {
+		return
+	}
+	fmt.Fprintf(b, "  %-6s %s\n", "", widgets.FlagRow(flags, trendWidth, '▲'))
+	for _, p := range s.Points {
+		if p.Verdict == regression.VerdictRegressed {
+			fmt.Fprintf(b, "  ▲ regressed: %s  p95 %.1fms  p99 %.1fms  err %.2f%%\n",
+				p.StartedAt.Format("2006-01-02 15:04"), p.P95Ms, p.P99Ms, p.ErrorRate*100)
+		}
+	}
+}
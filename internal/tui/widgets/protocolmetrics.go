@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+
+	"github.com/torosent/crankfire/internal/metrics"
 )
 
 // ProtocolMetricsBlock renders per-protocol metrics, alphabetically by
@@ -55,6 +57,8 @@ func formatMetricValue(v interface{}) string {
 			return fmt.Sprintf("%.0f", x)
 		}
 		return fmt.Sprintf("%.2f", x)
+	case metrics.Gauge:
+		return formatMetricValue(float64(x))
 	case string:
 		return x
 	default:
@@ -58,3 +58,22 @@ func TrendChart(samples []float64, width, height int, markers []Marker) string {
 	}
 	return strings.Join(out, "\n")
 }
+
+// FlagRow renders a row that lines up with a Sparkline of the same samples
+// and width: flag is drawn under every flagged sample and the rest is blank.
+func FlagRow(flags []bool, width int, flag rune) string {
+	if width <= 0 {
+		return ""
+	}
+	if len(flags) > width {
+		flags = flags[len(flags)-width:]
+	}
+	row := []rune(strings.Repeat(" ", width))
+	offset := width - len(flags)
+	for i, f := range flags {
+		if f {
+			row[offset+i] = flag
+		}
+	}
+	return string(row)
+}
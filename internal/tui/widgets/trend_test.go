@@ -41,3 +41,18 @@ func TestTrendChartPositionsMarkersRelativeToSampleRange(t *testing.T) {
 		t.Fatalf("expected midpoint marker to render above the bottom row:\n%s", got)
 	}
 }
+
+func TestFlagRowAlignsWithSparkline(t *testing.T) {
+	samples := []float64{1, 2, 3}
+	spark := []rune(widgets.Sparkline(samples, 5))
+	row := []rune(widgets.FlagRow([]bool{false, false, true}, 5, '▲'))
+	if len(row) != len(spark) {
+		t.Fatalf("row width %d, sparkline width %d", len(row), len(spark))
+	}
+	if row[4] != '▲' || spark[4] != '█' {
+		t.Fatalf("flag should sit under the last sample:\n%s\n%s", string(spark), string(row))
+	}
+	if strings.TrimSpace(string(row[:4])) != "" {
+		t.Fatalf("unflagged samples should be blank: %q", string(row))
+	}
+}
@@ -2,9 +2,13 @@ package websocket
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
+	"net"
 	"net/http"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -24,6 +28,8 @@ type Metrics struct {
 	MessagesReceived   int64
 	BytesSent          int64
 	BytesReceived      int64
+	WireBytesSent      int64 // Bytes written to the socket, including framing and compression
+	WireBytesReceived  int64 // Bytes read from the socket, including framing and compression
 	Errors             int64
 }
 
@@ -35,6 +41,11 @@ type Client struct {
 	conn    *websocket.Conn
 	mu      sync.Mutex
 	metrics *clientmetrics.ClientMetrics
+
+	compressionLevel int
+	compressed       bool // permessage-deflate was negotiated
+	wireSent         atomic.Int64
+	wireRecv         atomic.Int64
 }
 
 // Config configures the WebSocket client behavior.
@@ -45,6 +56,17 @@ type Config struct {
 	ReadTimeout      time.Duration
 	WriteTimeout     time.Duration
 	MaxMessageSize   int64
+
+	// EnableCompression offers permessage-deflate during the handshake.
+	EnableCompression bool
+	// CompressionLevel sets the flate level for outgoing messages once
+	// compression is negotiated (0 keeps the library default).
+	CompressionLevel int
+
+	// TLSConfig is used for wss:// URLs (nil keeps the defaults).
+	TLSConfig *tls.Config
+	// DialContext opens the TCP connection (nil uses a plain net.Dialer).
+	DialContext func(ctx context.Context, network, addr string) (net.Conn, error)
 }
 
 // NewClient creates a new WebSocket client with the given configuration.
@@ -57,17 +79,49 @@ func NewClient(cfg Config) *Client {
 		cfg.MaxMessageSize = 1024 * 1024 // 1MB default
 	}
 
-	dialer := &websocket.Dialer{
-		HandshakeTimeout: cfg.HandshakeTimeout,
-		Proxy:            http.ProxyFromEnvironment,
+	c := &Client{
+		url:              cfg.URL,
+		headers:          cfg.Headers,
+		metrics:          clientmetrics.New(),
+		compressionLevel: cfg.CompressionLevel,
 	}
-
-	return &Client{
-		url:     cfg.URL,
-		headers: cfg.Headers,
-		dialer:  dialer,
-		metrics: clientmetrics.New(),
+	dial := cfg.DialContext
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+	c.dialer = &websocket.Dialer{
+		HandshakeTimeout:  cfg.HandshakeTimeout,
+		Proxy:             http.ProxyFromEnvironment,
+		EnableCompression: cfg.EnableCompression,
+		TLSClientConfig:   cfg.TLSConfig,
+		NetDialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			conn, err := dial(ctx, network, addr)
+			if err != nil {
+				return nil, err
+			}
+			return &countingConn{Conn: conn, sent: &c.wireSent, recv: &c.wireRecv}, nil
+		},
 	}
+	return c
+}
+
+// countingConn tallies the raw bytes crossing the socket.
+type countingConn struct {
+	net.Conn
+	sent *atomic.Int64
+	recv *atomic.Int64
+}
+
+func (c *countingConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	c.recv.Add(int64(n))
+	return n, err
+}
+
+func (c *countingConn) Write(p []byte) (int, error) {
+	n, err := c.Conn.Write(p)
+	c.sent.Add(int64(n))
+	return n, err
 }
 
 // Connect establishes a WebSocket connection.
@@ -88,6 +142,21 @@ func (c *Client) Connect(ctx context.Context) error {
 		return fmt.Errorf("websocket dial failed: %w", err)
 	}
 
+	if c.dialer.EnableCompression && resp != nil &&
+		strings.Contains(resp.Header.Get("Sec-WebSocket-Extensions"), "permessage-deflate") {
+		c.compressed = true
+		if c.compressionLevel != 0 {
+			if err := conn.SetCompressionLevel(c.compressionLevel); err != nil {
+				conn.Close()
+				return fmt.Errorf("set compression level: %w", err)
+			}
+		}
+	}
+
+	// Drop the upgrade request and response so wire bytes cover only frames.
+	c.wireSent.Store(0)
+	c.wireRecv.Store(0)
+
 	c.conn = conn
 	c.metrics.MarkConnected()
 
@@ -148,6 +217,12 @@ func (c *Client) ReceiveMessage(ctx context.Context) (Message, error) {
 
 // Close closes the WebSocket connection gracefully.
 func (c *Client) Close() error {
+	return c.CloseWithCode(websocket.CloseNormalClosure, "")
+}
+
+// CloseWithCode sends a close frame with the given code and reason, then
+// closes the underlying connection.
+func (c *Client) CloseWithCode(code int, reason string) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -158,7 +233,7 @@ func (c *Client) Close() error {
 	// Send close frame
 	err := c.conn.WriteControl(
 		websocket.CloseMessage,
-		websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+		websocket.FormatCloseMessage(code, reason),
 		time.Now().Add(5*time.Second),
 	)
 
@@ -172,6 +247,13 @@ func (c *Client) Close() error {
 	return closeErr
 }
 
+// CompressionNegotiated reports whether the server accepted permessage-deflate.
+func (c *Client) CompressionNegotiated() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.compressed
+}
+
 // Metrics returns the current metrics snapshot.
 func (c *Client) Metrics() Metrics {
 	snapshot := c.metrics.Snapshot()
@@ -181,6 +263,8 @@ func (c *Client) Metrics() Metrics {
 		MessagesReceived:   snapshot.MessagesReceived,
 		BytesSent:          snapshot.BytesSent,
 		BytesReceived:      snapshot.BytesReceived,
+		WireBytesSent:      c.wireSent.Load(),
+		WireBytesReceived:  c.wireRecv.Load(),
 		Errors:             snapshot.Errors,
 	}
 }
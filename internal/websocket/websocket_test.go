@@ -2,6 +2,7 @@ package websocket
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -287,6 +288,156 @@ func TestWebSocketClose(t *testing.T) {
 	}
 }
 
+func TestWebSocketCloseWithCode(t *testing.T) {
+	codes := make(chan int, 1)
+	server := createTestWSServer(func(conn *websocket.Conn) {
+		_, _, err := conn.ReadMessage()
+		var closeErr *websocket.CloseError
+		if errors.As(err, &closeErr) {
+			codes <- closeErr.Code
+		}
+	})
+	defer server.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+	client := NewClient(Config{
+		URL: wsURL,
+	})
+
+	if err := client.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	if err := client.CloseWithCode(websocket.CloseGoingAway, "bye"); err != nil {
+		t.Fatalf("CloseWithCode failed: %v", err)
+	}
+
+	select {
+	case code := <-codes:
+		if code != websocket.CloseGoingAway {
+			t.Errorf("server saw close code %d, want %d", code, websocket.CloseGoingAway)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("server did not receive close frame")
+	}
+}
+
+func TestWebSocketCompression(t *testing.T) {
+	upgrader := websocket.Upgrader{EnableCompression: true}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			mt, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if err := conn.WriteMessage(mt, data); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+	payload := []byte(strings.Repeat("crankfire ", 1000))
+
+	tests := []struct {
+		name           string
+		compression    bool
+		wantCompressed bool
+	}{
+		{name: "negotiated", compression: true, wantCompressed: true},
+		{name: "disabled", compression: false, wantCompressed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(Config{
+				URL:               wsURL,
+				EnableCompression: tt.compression,
+				CompressionLevel:  9,
+			})
+			ctx := context.Background()
+			if err := client.Connect(ctx); err != nil {
+				t.Fatalf("Connect failed: %v", err)
+			}
+			defer client.Close()
+
+			if got := client.CompressionNegotiated(); got != tt.wantCompressed {
+				t.Fatalf("CompressionNegotiated() = %v, want %v", got, tt.wantCompressed)
+			}
+
+			before := client.Metrics()
+			if err := client.SendMessage(ctx, Message{Type: websocket.TextMessage, Data: payload}); err != nil {
+				t.Fatalf("SendMessage failed: %v", err)
+			}
+			if _, err := client.ReceiveMessage(ctx); err != nil {
+				t.Fatalf("ReceiveMessage failed: %v", err)
+			}
+			after := client.Metrics()
+
+			wireSent := after.WireBytesSent - before.WireBytesSent
+			wireRecv := after.WireBytesReceived - before.WireBytesReceived
+			if tt.wantCompressed {
+				if wireSent >= int64(len(payload)) || wireRecv >= int64(len(payload)) {
+					t.Errorf("wire bytes sent/received = %d/%d, want less than payload %d", wireSent, wireRecv, len(payload))
+				}
+			} else if wireSent <= int64(len(payload)) {
+				t.Errorf("wire bytes sent = %d, want framing overhead above payload %d", wireSent, len(payload))
+			}
+		})
+	}
+}
+
+func TestWebSocketWireBytesExcludeHandshake(t *testing.T) {
+	upgrader := websocket.Upgrader{EnableCompression: true}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		mt, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		_ = conn.WriteMessage(mt, data)
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{
+		URL:               "ws" + strings.TrimPrefix(server.URL, "http"),
+		EnableCompression: true,
+	})
+	ctx := context.Background()
+	if err := client.Connect(ctx); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer client.Close()
+
+	if m := client.Metrics(); m.WireBytesSent != 0 || m.WireBytesReceived != 0 {
+		t.Fatalf("wire bytes after handshake = %d/%d, want 0/0", m.WireBytesSent, m.WireBytesReceived)
+	}
+
+	payload := []byte(strings.Repeat("crankfire ", 20))
+	if err := client.SendMessage(ctx, Message{Type: websocket.TextMessage, Data: payload}); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	if _, err := client.ReceiveMessage(ctx); err != nil {
+		t.Fatalf("ReceiveMessage failed: %v", err)
+	}
+
+	m := client.Metrics()
+	ratio := float64(m.BytesSent+m.BytesReceived) / float64(m.WireBytesSent+m.WireBytesReceived)
+	if ratio <= 2 {
+		t.Errorf("compression ratio = %.2f (payload %d, wire %d), want above 2",
+			ratio, m.BytesSent+m.BytesReceived, m.WireBytesSent+m.WireBytesReceived)
+	}
+}
+
 func TestWebSocketCloseWithoutConnect(t *testing.T) {
 	client := NewClient(Config{
 		URL: "ws://localhost:8080",